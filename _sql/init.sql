@@ -6,9 +6,68 @@ CREATE TABLE IF NOT EXISTS `users`(
     `id` int NOT NULL AUTO_INCREMENT,
     `username` varchar(255) NOT NULL UNIQUE,
     `password` varchar(255) NOT NULL,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `is_private` BOOLEAN NOT NULL DEFAULT FALSE,
+    `password_reset_required` BOOLEAN NOT NULL DEFAULT FALSE,
+    `email` varchar(255) DEFAULT NULL UNIQUE,
     PRIMARY KEY (`id`)
     ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+CREATE TABLE IF NOT EXISTS `email_verification_tokens`
+(
+    `token_hash` char(64) NOT NULL,
+    `user_id` int NOT NULL REFERENCES users(id),
+    `email` varchar(255) NOT NULL,
+    `expires_at` DATETIME NOT NULL,
+    `used_at` DATETIME DEFAULT NULL,
+    INDEX `email_verification_tokens_user` (`user_id`),
+    PRIMARY KEY (`token_hash`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `password_reset_tokens`
+(
+    `token_hash` char(64) NOT NULL,
+    `user_id` int NOT NULL REFERENCES users(id),
+    `expires_at` DATETIME NOT NULL,
+    `used_at` DATETIME DEFAULT NULL,
+    INDEX `password_reset_tokens_user` (`user_id`),
+    PRIMARY KEY (`token_hash`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `role_permissions`
+(
+    `role` varchar(64) NOT NULL,
+    `permission` varchar(64) NOT NULL,
+    PRIMARY KEY (`role`, `permission`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+INSERT IGNORE INTO `role_permissions` (`role`, `permission`) VALUES
+    ('admin', 'reviews:moderate'),
+    ('admin', 'roles:manage'),
+    ('moderator', 'reviews:moderate'),
+    ('critic', 'reviews:critic');
+
+CREATE TABLE IF NOT EXISTS `user_roles`
+(
+    `user_id` int NOT NULL REFERENCES users(id),
+    `role` varchar(64) NOT NULL,
+    `granted_by` int DEFAULT NULL REFERENCES users(id),
+    `granted_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX `user_roles_role` (`role`),
+    PRIMARY KEY (`user_id`, `role`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `user_identities`
+(
+    `provider` varchar(64) NOT NULL,
+    `subject` varchar(255) NOT NULL,
+    `user_id` int NOT NULL REFERENCES users(id),
+    `email` varchar(255) DEFAULT NULL,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX `user_identities_user` (`user_id`),
+    PRIMARY KEY (`provider`, `subject`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
 
 CREATE TABLE IF NOT EXISTS `films`
 (
@@ -23,6 +82,12 @@ CREATE TABLE IF NOT EXISTS `films`
     `sum_mark`      int NOT NULL,
     `num_of_marks`    int NOT NULL,
     `rating`        DECIMAL(3, 1) NOT NULL,
+    `weighted_rating` DECIMAL(4, 2) NOT NULL DEFAULT 0,
+    `critic_sum_mark` int NOT NULL DEFAULT 0,
+    `critic_num_of_marks` int NOT NULL DEFAULT 0,
+    `critic_num_fresh` int NOT NULL DEFAULT 0,
+    `critic_rating` DECIMAL(3, 1) NOT NULL DEFAULT 0,
+    `critic_fresh_percent` DECIMAL(4, 1) NOT NULL DEFAULT 0,
     PRIMARY KEY (`id`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
@@ -73,9 +138,25 @@ CREATE TABLE IF NOT EXISTS `reviews`
     `user_id` int NOT NULL REFERENCES users(id),
     `mark` int NOT NULL,
     `comment` TEXT,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `is_edited` BOOLEAN NOT NULL DEFAULT FALSE,
+    `helpful_votes` int NOT NULL DEFAULT 0,
+    `unhelpful_votes` int NOT NULL DEFAULT 0,
+    `status` varchar(16) NOT NULL DEFAULT 'published',
+    `is_spoiler` BOOLEAN NOT NULL DEFAULT FALSE,
+    `story_mark` int DEFAULT NULL,
+    `acting_mark` int DEFAULT NULL,
+    `visuals_mark` int DEFAULT NULL,
+    `music_mark` int DEFAULT NULL,
+    `by_critic` BOOLEAN NOT NULL DEFAULT FALSE,
     FOREIGN KEY (`film_id`)  REFERENCES `films`(`id`),
     FOREIGN KEY (`user_id`)  REFERENCES `users`(`id`),
-    PRIMARY KEY (`id`)
+    PRIMARY KEY (`id`),
+    INDEX `reviews_film_created` (`film_id`, `created_at`),
+    INDEX `reviews_user_created` (`user_id`, `created_at`),
+    INDEX `reviews_status` (`status`),
+    INDEX `reviews_film_critic` (`film_id`, `by_critic`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 
@@ -89,3 +170,163 @@ CREATE TABLE IF NOT EXISTS `favourite_films`
     PRIMARY KEY (`id`)
     ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
+
+CREATE TABLE IF NOT EXISTS `calendar_tokens`
+(
+    `id` int NOT NULL AUTO_INCREMENT,
+    `user_id` int NOT NULL UNIQUE REFERENCES users(id),
+    `token` varchar(64) NOT NULL UNIQUE,
+    FOREIGN KEY (`user_id`)  REFERENCES `users`(`id`),
+    PRIMARY KEY (`id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `review_revisions`
+(
+    `id` int NOT NULL AUTO_INCREMENT,
+    `review_id` int NOT NULL,
+    `mark` int NOT NULL,
+    `comment` TEXT,
+    `created_at` DATETIME NOT NULL,
+    `replaced_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (`review_id`)  REFERENCES `reviews`(`id`) ON DELETE CASCADE,
+    PRIMARY KEY (`id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `review_votes`
+(
+    `id` int NOT NULL AUTO_INCREMENT,
+    `review_id` int NOT NULL,
+    `user_id` int NOT NULL,
+    `is_helpful` BOOLEAN NOT NULL,
+    FOREIGN KEY (`review_id`)  REFERENCES `reviews`(`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`user_id`)  REFERENCES `users`(`id`),
+    UNIQUE KEY `review_votes_review_user` (`review_id`, `user_id`),
+    PRIMARY KEY (`id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `review_comments`
+(
+    `id` int NOT NULL AUTO_INCREMENT,
+    `review_id` int NOT NULL,
+    `parent_id` int,
+    `thread_id` int,
+    `user_id` int NOT NULL,
+    `depth` int NOT NULL DEFAULT 0,
+    `text` TEXT NOT NULL,
+    `is_deleted` BOOLEAN NOT NULL DEFAULT FALSE,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    FOREIGN KEY (`review_id`)  REFERENCES `reviews`(`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`parent_id`)  REFERENCES `review_comments`(`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`thread_id`)  REFERENCES `review_comments`(`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`user_id`)  REFERENCES `users`(`id`),
+    INDEX `review_comments_review_thread` (`review_id`, `thread_id`),
+    PRIMARY KEY (`id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `review_reports`
+(
+    `id` int NOT NULL AUTO_INCREMENT,
+    `review_id` int NOT NULL,
+    `user_id` int NOT NULL,
+    `reason` TEXT NOT NULL,
+    `is_resolved` BOOLEAN NOT NULL DEFAULT FALSE,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (`review_id`)  REFERENCES `reviews`(`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`user_id`)  REFERENCES `users`(`id`),
+    UNIQUE KEY `review_reports_review_user` (`review_id`, `user_id`),
+    INDEX `review_reports_resolved` (`is_resolved`, `review_id`),
+    PRIMARY KEY (`id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `review_moderation_log`
+(
+    `id` int NOT NULL AUTO_INCREMENT,
+    `review_id` int NOT NULL,
+    `moderator_id` int NOT NULL,
+    `action` varchar(16) NOT NULL,
+    `old_status` varchar(16) NOT NULL,
+    `new_status` varchar(16) NOT NULL,
+    `reason` TEXT NOT NULL,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (`review_id`)  REFERENCES `reviews`(`id`) ON DELETE CASCADE,
+    FOREIGN KEY (`moderator_id`)  REFERENCES `users`(`id`),
+    INDEX `review_moderation_log_review` (`review_id`),
+    PRIMARY KEY (`id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `film_stats`
+(
+    `film_id` int NOT NULL,
+    `sum_mark_squares` int NOT NULL DEFAULT 0,
+    `num_with_text` int NOT NULL DEFAULT 0,
+    FOREIGN KEY (`film_id`)  REFERENCES `films`(`id`),
+    PRIMARY KEY (`film_id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `film_mark_distribution`
+(
+    `film_id` int NOT NULL,
+    `mark` int NOT NULL,
+    `num_of_marks` int NOT NULL DEFAULT 0,
+    FOREIGN KEY (`film_id`)  REFERENCES `films`(`id`),
+    PRIMARY KEY (`film_id`, `mark`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `film_review_months`
+(
+    `film_id` int NOT NULL,
+    `month` DATE NOT NULL,
+    `num_of_reviews` int NOT NULL DEFAULT 0,
+    FOREIGN KEY (`film_id`)  REFERENCES `films`(`id`),
+    PRIMARY KEY (`film_id`, `month`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `film_criteria_ratings`
+(
+    `film_id` int NOT NULL,
+    `criterion` varchar(16) NOT NULL,
+    `sum_mark` int NOT NULL DEFAULT 0,
+    `num_of_marks` int NOT NULL DEFAULT 0,
+    `rating` DECIMAL(4, 2) NOT NULL DEFAULT 0,
+    FOREIGN KEY (`film_id`)  REFERENCES `films`(`id`),
+    INDEX `film_criteria_ratings_rating` (`criterion`, `rating`),
+    PRIMARY KEY (`film_id`, `criterion`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `film_charts`
+(
+    `chart` varchar(16) NOT NULL,
+    `scope` varchar(16) NOT NULL,
+    `scope_value` varchar(255) NOT NULL,
+    `place` int NOT NULL,
+    `film_id` int NOT NULL,
+    `weighted_rating` DECIMAL(4, 2) NOT NULL,
+    `materialized_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (`film_id`)  REFERENCES `films`(`id`),
+    PRIMARY KEY (`chart`, `scope`, `scope_value`, `place`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `processed_rating_events`
+(
+    `event_id` varchar(36) NOT NULL,
+    `film_id` int NOT NULL,
+    `processed_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX `processed_rating_events_time` (`processed_at`),
+    PRIMARY KEY (`event_id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
+
+CREATE TABLE IF NOT EXISTS `review_outbox`
+(
+    `id` BIGINT NOT NULL AUTO_INCREMENT,
+    `event_id` varchar(36) NOT NULL UNIQUE,
+    `queue` varchar(255) NOT NULL,
+    `payload` TEXT NOT NULL,
+    `created_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `sent_at` DATETIME DEFAULT NULL,
+    `attempts` int NOT NULL DEFAULT 0,
+    `next_attempt_at` DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `last_error` TEXT,
+    INDEX `review_outbox_unsent` (`sent_at`, `next_attempt_at`, `id`),
+    PRIMARY KEY (`id`)
+    ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
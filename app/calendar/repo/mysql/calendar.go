@@ -0,0 +1,63 @@
+package calendarrepo
+
+import (
+	"database/sql"
+	"errors"
+	"go.uber.org/zap"
+	errorapp "kinopoisk/app/errors"
+)
+
+type CalendarRepo interface {
+	GetTokenByUserRepo(userID uint64) (string, error)
+	GetUserByTokenRepo(token string) (uint64, error)
+	SetTokenRepo(userID uint64, token string) error
+}
+
+type CalendarRepoMySQL struct {
+	db     *sql.DB
+	logger *zap.SugaredLogger
+}
+
+func NewCalendarRepoMySQL(db *sql.DB, logger *zap.SugaredLogger) *CalendarRepoMySQL {
+	return &CalendarRepoMySQL{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (r *CalendarRepoMySQL) GetTokenByUserRepo(userID uint64) (string, error) {
+	var token string
+	err := r.db.
+		QueryRow("SELECT token FROM calendar_tokens WHERE user_id = ?", userID).
+		Scan(&token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", errorapp.ErrorNoCalendarToken
+	}
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (r *CalendarRepoMySQL) GetUserByTokenRepo(token string) (uint64, error) {
+	var userID uint64
+	err := r.db.
+		QueryRow("SELECT user_id FROM calendar_tokens WHERE token = ?", token).
+		Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, errorapp.ErrorNoCalendarToken
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+func (r *CalendarRepoMySQL) SetTokenRepo(userID uint64, token string) error {
+	_, err := r.db.Exec(
+		"INSERT INTO calendar_tokens (`user_id`, `token`) VALUES (?, ?) ON DUPLICATE KEY UPDATE token = VALUES(token)",
+		userID,
+		token,
+	)
+	return err
+}
@@ -0,0 +1,108 @@
+package calendarusecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"go.uber.org/zap"
+	calendarrepo "kinopoisk/app/calendar/repo/mysql"
+	errorapp "kinopoisk/app/errors"
+	filmrepo "kinopoisk/app/films/repo/mysql"
+	"sync"
+	"time"
+)
+
+const (
+	calendarTokenLength = 32
+	favouritesFeedName  = "MOVIEWORLD: избранные релизы"
+	soonFeedName        = "MOVIEWORLD: скоро в кино"
+)
+
+type CalendarUseCase interface {
+	GetCalendarToken(userID uint64, logger *zap.SugaredLogger) (string, error)
+	ResetCalendarToken(userID uint64, logger *zap.SugaredLogger) (string, error)
+	GetFavouritesCalendar(token string, logger *zap.SugaredLogger) ([]byte, error)
+	GetSoonCalendar(logger *zap.SugaredLogger) ([]byte, error)
+}
+
+type CalendarUseCaseStruct struct {
+	mu           *sync.RWMutex
+	calendarRepo calendarrepo.CalendarRepo
+	filmRepo     filmrepo.FilmRepo
+}
+
+func NewCalendarUseCaseStruct(calendarRepo calendarrepo.CalendarRepo, filmRepo filmrepo.FilmRepo) *CalendarUseCaseStruct {
+	return &CalendarUseCaseStruct{
+		mu:           &sync.RWMutex{},
+		calendarRepo: calendarRepo,
+		filmRepo:     filmRepo,
+	}
+}
+
+func (c *CalendarUseCaseStruct) GetCalendarToken(userID uint64, logger *zap.SugaredLogger) (string, error) {
+	c.mu.RLock()
+	token, err := c.calendarRepo.GetTokenByUserRepo(userID)
+	c.mu.RUnlock()
+	if errors.Is(err, errorapp.ErrorNoCalendarToken) {
+		return c.ResetCalendarToken(userID, logger)
+	}
+	if err != nil {
+		logger.Errorf("error in getting calendar token: %s", err)
+		return "", err
+	}
+	return token, nil
+}
+
+func (c *CalendarUseCaseStruct) ResetCalendarToken(userID uint64, logger *zap.SugaredLogger) (string, error) {
+	token, err := newCalendarToken()
+	if err != nil {
+		logger.Errorf("error in generating calendar token: %s", err)
+		return "", err
+	}
+	c.mu.Lock()
+	err = c.calendarRepo.SetTokenRepo(userID, token)
+	c.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in saving calendar token: %s", err)
+		return "", err
+	}
+	return token, nil
+}
+
+func (c *CalendarUseCaseStruct) GetFavouritesCalendar(token string, logger *zap.SugaredLogger) ([]byte, error) {
+	c.mu.RLock()
+	userID, err := c.calendarRepo.GetUserByTokenRepo(token)
+	c.mu.RUnlock()
+	if err != nil {
+		if !errors.Is(err, errorapp.ErrorNoCalendarToken) {
+			logger.Errorf("error in getting user by calendar token: %s", err)
+		}
+		return nil, err
+	}
+	now := time.Now()
+	films, err := c.filmRepo.GetFavouriteSoonFilmsRepo(userID, now.Format(filmDateFormat))
+	if err != nil {
+		logger.Errorf("error in getting favourite soon films: %s", err)
+		return nil, err
+	}
+	return buildReleaseCalendar(favouritesFeedName, films, now)
+}
+
+func (c *CalendarUseCaseStruct) GetSoonCalendar(logger *zap.SugaredLogger) ([]byte, error) {
+	now := time.Now()
+	films, err := c.filmRepo.GetSoonFilmsRepo(now.Format(filmDateFormat))
+	if err != nil {
+		logger.Errorf("error in getting soon films: %s", err)
+		return nil, err
+	}
+	return buildReleaseCalendar(soonFeedName, films, now)
+}
+
+func newCalendarToken() (string, error) {
+	tokenBytes := make([]byte, calendarTokenLength)
+	_, err := rand.Read(tokenBytes)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(tokenBytes), nil
+}
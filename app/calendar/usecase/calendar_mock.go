@@ -0,0 +1,95 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: calendar.go
+
+// Package calendarusecase is a generated GoMock package.
+package calendarusecase
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	zap "go.uber.org/zap"
+)
+
+// MockCalendarUseCase is a mock of CalendarUseCase interface.
+type MockCalendarUseCase struct {
+	ctrl     *gomock.Controller
+	recorder *MockCalendarUseCaseMockRecorder
+}
+
+// MockCalendarUseCaseMockRecorder is the mock recorder for MockCalendarUseCase.
+type MockCalendarUseCaseMockRecorder struct {
+	mock *MockCalendarUseCase
+}
+
+// NewMockCalendarUseCase creates a new mock instance.
+func NewMockCalendarUseCase(ctrl *gomock.Controller) *MockCalendarUseCase {
+	mock := &MockCalendarUseCase{ctrl: ctrl}
+	mock.recorder = &MockCalendarUseCaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCalendarUseCase) EXPECT() *MockCalendarUseCaseMockRecorder {
+	return m.recorder
+}
+
+// GetCalendarToken mocks base method.
+func (m *MockCalendarUseCase) GetCalendarToken(userID uint64, logger *zap.SugaredLogger) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCalendarToken", userID, logger)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCalendarToken indicates an expected call of GetCalendarToken.
+func (mr *MockCalendarUseCaseMockRecorder) GetCalendarToken(userID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCalendarToken", reflect.TypeOf((*MockCalendarUseCase)(nil).GetCalendarToken), userID, logger)
+}
+
+// GetFavouritesCalendar mocks base method.
+func (m *MockCalendarUseCase) GetFavouritesCalendar(token string, logger *zap.SugaredLogger) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFavouritesCalendar", token, logger)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFavouritesCalendar indicates an expected call of GetFavouritesCalendar.
+func (mr *MockCalendarUseCaseMockRecorder) GetFavouritesCalendar(token, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFavouritesCalendar", reflect.TypeOf((*MockCalendarUseCase)(nil).GetFavouritesCalendar), token, logger)
+}
+
+// GetSoonCalendar mocks base method.
+func (m *MockCalendarUseCase) GetSoonCalendar(logger *zap.SugaredLogger) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSoonCalendar", logger)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSoonCalendar indicates an expected call of GetSoonCalendar.
+func (mr *MockCalendarUseCaseMockRecorder) GetSoonCalendar(logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSoonCalendar", reflect.TypeOf((*MockCalendarUseCase)(nil).GetSoonCalendar), logger)
+}
+
+// ResetCalendarToken mocks base method.
+func (m *MockCalendarUseCase) ResetCalendarToken(userID uint64, logger *zap.SugaredLogger) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetCalendarToken", userID, logger)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetCalendarToken indicates an expected call of ResetCalendarToken.
+func (mr *MockCalendarUseCaseMockRecorder) ResetCalendarToken(userID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetCalendarToken", reflect.TypeOf((*MockCalendarUseCase)(nil).ResetCalendarToken), userID, logger)
+}
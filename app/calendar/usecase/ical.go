@@ -0,0 +1,75 @@
+package calendarusecase
+
+import (
+	"fmt"
+	"kinopoisk/app/entity"
+	"strings"
+	"time"
+)
+
+// Calendar lines are folded after 75 octets and separated with CRLF (RFC 5545, section 3.1).
+const (
+	icalLineEnd       = "\r\n"
+	icalMaxLineLength = 75
+	icalDateFormat    = "20060102"
+	icalStampFormat   = "20060102T150405Z"
+	filmDateFormat    = "2006-01-02"
+	calendarProductID = "-//MOVIEWORLD//Releases//RU"
+	calendarUIDDomain = "movieworld"
+)
+
+func buildReleaseCalendar(name string, films []*entity.Film, now time.Time) ([]byte, error) {
+	builder := &strings.Builder{}
+	writeICalLine(builder, "BEGIN:VCALENDAR")
+	writeICalLine(builder, "VERSION:2.0")
+	writeICalLine(builder, "PRODID:"+calendarProductID)
+	writeICalLine(builder, "CALSCALE:GREGORIAN")
+	writeICalLine(builder, "METHOD:PUBLISH")
+	writeICalLine(builder, "X-WR-CALNAME:"+escapeICalText(name))
+	stamp := now.UTC().Format(icalStampFormat)
+	for _, film := range films {
+		releaseDate, err := time.Parse(filmDateFormat, film.DateOfRelease)
+		if err != nil {
+			return nil, fmt.Errorf("bad release date of film %d: %w", film.ID, err)
+		}
+		writeICalLine(builder, "BEGIN:VEVENT")
+		writeICalLine(builder, fmt.Sprintf("UID:film-%d@%s", film.ID, calendarUIDDomain))
+		writeICalLine(builder, "DTSTAMP:"+stamp)
+		writeICalLine(builder, "DTSTART;VALUE=DATE:"+releaseDate.Format(icalDateFormat))
+		writeICalLine(builder, "DTEND;VALUE=DATE:"+releaseDate.AddDate(0, 0, 1).Format(icalDateFormat))
+		writeICalLine(builder, "SUMMARY:"+escapeICalText(film.Name))
+		writeICalLine(builder, "DESCRIPTION:"+escapeICalText(film.Description))
+		writeICalLine(builder, "TRANSP:TRANSPARENT")
+		writeICalLine(builder, "END:VEVENT")
+	}
+	writeICalLine(builder, "END:VCALENDAR")
+	return []byte(builder.String()), nil
+}
+
+func escapeICalText(text string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(text)
+}
+
+// writeICalLine folds long content lines without splitting multibyte runes.
+func writeICalLine(builder *strings.Builder, line string) {
+	lineLength := 0
+	for _, symbol := range line {
+		symbolLength := len(string(symbol))
+		if lineLength+symbolLength > icalMaxLineLength {
+			builder.WriteString(icalLineEnd)
+			builder.WriteString(" ")
+			lineLength = 1
+		}
+		builder.WriteRune(symbol)
+		lineLength += symbolLength
+	}
+	builder.WriteString(icalLineEnd)
+}
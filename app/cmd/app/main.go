@@ -14,6 +14,8 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	actorrepo "kinopoisk/app/actors/repo/mysql"
 	actorusecase "kinopoisk/app/actors/usecase"
+	calendarrepo "kinopoisk/app/calendar/repo/mysql"
+	calendarusecase "kinopoisk/app/calendar/usecase"
 	"kinopoisk/app/delivery/handlers"
 	filmrepo "kinopoisk/app/films/repo/mysql"
 	filmusecase "kinopoisk/app/films/usecase"
@@ -24,6 +26,7 @@ import (
 	searchrepo "kinopoisk/app/search/repo/mysql"
 	searchusecase "kinopoisk/app/search/usecase"
 	userusecase "kinopoisk/app/users/usecase"
+	authpermission "kinopoisk/service_auth/permission"
 	auth "kinopoisk/service_auth/proto"
 	review "kinopoisk/service_review/proto"
 
@@ -158,6 +161,9 @@ func main() {
 	rateLimiterRepo := ratelimiterrepo.NewRateLimiterRepoRedis(redisConn, logger)
 	rateLimiterUseCase := ratelimiterusecase.NewRateLimiterUseCaseStruct(rateLimiterRepo)
 
+	calendarRepo := calendarrepo.NewCalendarRepoMySQL(mySQLDb, logger)
+	calendarUseCase := calendarusecase.NewCalendarUseCaseStruct(calendarRepo, filmRepo)
+
 	searchRepo := searchrepo.NewSearchRepoMySQL(mySQLDb, logger)
 	searchUseCase := searchusecase.NewSearchUseCaseStruct(searchRepo)
 
@@ -166,6 +172,7 @@ func main() {
 	filmHandler := handlers.NewFilmHandler(filmUseCase)
 	actorHandler := handlers.NewActorHandler(actorUseCase)
 	searchHandler := handlers.NewSearchHandler(searchUseCase)
+	calendarHandler := handlers.NewCalendarHandler(calendarUseCase)
 
 	router := mux.NewRouter()
 	router.HandleFunc("/actors", actorHandler.GetActors).Methods(http.MethodGet)
@@ -179,30 +186,118 @@ func main() {
 
 	router.HandleFunc("/film/{FILM_ID}/actors", filmHandler.GetFilmActors).Methods(http.MethodGet)
 	router.HandleFunc("/film/{FILM_ID}/genres", filmHandler.GetFilmGenres).Methods(http.MethodGet)
+	router.HandleFunc("/film/{FILM_ID}/stats", filmHandler.GetFilmStats).Methods(http.MethodGet)
+
+	router.HandleFunc("/charts/top", filmHandler.GetTopChart).Methods(http.MethodGet)
+	router.HandleFunc("/charts/bottom", filmHandler.GetBottomChart).Methods(http.MethodGet)
 
 	router.HandleFunc("/login", authHandler.Login).Methods(http.MethodPost)
 	router.HandleFunc("/register", authHandler.Register).Methods(http.MethodPost)
+	router.HandleFunc("/token/refresh", authHandler.RefreshTokens).Methods(http.MethodPost)
+	router.HandleFunc("/password/forgot", authHandler.ForgotPassword).Methods(http.MethodPost)
+	router.HandleFunc("/password/reset", authHandler.ResetPassword).Methods(http.MethodPost)
+	router.HandleFunc("/email/verify", authHandler.VerifyEmail).Methods(http.MethodPost)
+	router.HandleFunc("/oidc/{PROVIDER}/login", authHandler.StartOIDCLogin).Methods(http.MethodGet)
+	router.Handle("/oidc/{PROVIDER}/callback",
+		middleware.OptionalAuthMiddleware(authUseCase, http.HandlerFunc(authHandler.OIDCCallback))).Methods(http.MethodGet)
 
 	router.HandleFunc("/review/{FILM_ID}", reviewHandler.GetReviewsForFilm).Methods(http.MethodGet)
+	router.Handle("/review/{REVIEW_ID}/history",
+		middleware.OptionalAuthMiddleware(authUseCase, http.HandlerFunc(reviewHandler.GetReviewHistory))).Methods(http.MethodGet)
+	router.Handle("/review/{REVIEW_ID}/comments",
+		middleware.OptionalAuthMiddleware(authUseCase, http.HandlerFunc(reviewHandler.GetReviewComments))).Methods(http.MethodGet)
+
+	router.Handle("/user/{USER_ID}",
+		middleware.OptionalAuthMiddleware(authUseCase, http.HandlerFunc(reviewHandler.GetUserProfile))).Methods(http.MethodGet)
+	router.Handle("/user/{USER_ID}/reviews",
+		middleware.OptionalAuthMiddleware(authUseCase, http.HandlerFunc(reviewHandler.GetUserReviews))).Methods(http.MethodGet)
 
 	router.HandleFunc("/search/{DATA}", searchHandler.MakeSearch).Methods(http.MethodGet)
 
+	router.HandleFunc("/calendar/soon.ics", calendarHandler.GetSoonCalendar).Methods(http.MethodGet)
+	router.HandleFunc("/calendar/favourite/{TOKEN}.ics", calendarHandler.GetFavouritesCalendar).Methods(http.MethodGet)
+
 	checkAuthRouter := mux.NewRouter()
 	router.Handle("/films/favourite", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodGet)
 	router.Handle("/films/favourite/{FILM_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
 	router.Handle("/films/favourite/{FILM_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodDelete)
 
+	router.Handle("/calendar/token", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodGet)
+	router.Handle("/calendar/token", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
+
+	router.Handle("/me/privacy", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPut)
+	router.Handle("/me/password", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPut)
+	router.Handle("/me/email", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodGet)
+	router.Handle("/me/email", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPut)
+	router.Handle("/me/identities/{PROVIDER}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
+	router.Handle("/me/sessions", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodGet)
+	router.Handle("/me/sessions/{SESSION_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodDelete)
+	router.Handle("/logout", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
+	router.Handle("/logout/all", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
+
 	router.Handle("/review/{FILM_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
 	router.Handle("/review/{REVIEW_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodDelete)
 	router.Handle("/review/{REVIEW_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPut)
+	router.Handle("/review/{REVIEW_ID}/vote", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPut)
+	router.Handle("/review/{REVIEW_ID}/vote", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodDelete)
+	router.Handle("/review/{REVIEW_ID}/comments", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
+	router.Handle("/review/comment/{COMMENT_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPut)
+	router.Handle("/review/comment/{COMMENT_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodDelete)
+	router.Handle("/review/{REVIEW_ID}/report", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
+
+	router.Handle("/moderation/reviews", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodGet)
+	router.Handle("/moderation/review/{REVIEW_ID}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPost)
+	router.Handle("/moderation/review/{REVIEW_ID}/log", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodGet)
+	router.Handle("/moderation/review/{REVIEW_ID}/spoiler", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPut)
+
+	router.Handle("/admin/user/{USER_ID}/roles", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodGet)
+	router.Handle("/admin/user/{USER_ID}/roles/{ROLE}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodPut)
+	router.Handle("/admin/user/{USER_ID}/roles/{ROLE}", middleware.AuthMiddleware(authUseCase, checkAuthRouter)).Methods(http.MethodDelete)
 
 	checkAuthRouter.HandleFunc("/films/favourite", filmHandler.GetFavouriteFilms).Methods(http.MethodGet)
 	checkAuthRouter.HandleFunc("/films/favourite/{FILM_ID}", filmHandler.AddFavouriteFilm).Methods(http.MethodPost)
 	checkAuthRouter.HandleFunc("/films/favourite/{FILM_ID}", filmHandler.DeleteFavouriteFilm).Methods(http.MethodDelete)
 
-	checkAuthRouter.HandleFunc("/review/{FILM_ID}", reviewHandler.AddReview).Methods(http.MethodPost)
+	checkAuthRouter.HandleFunc("/calendar/token", calendarHandler.GetCalendarToken).Methods(http.MethodGet)
+	checkAuthRouter.HandleFunc("/calendar/token", calendarHandler.ResetCalendarToken).Methods(http.MethodPost)
+
+	checkAuthRouter.HandleFunc("/me/privacy", authHandler.SetProfilePrivacy).Methods(http.MethodPut)
+	checkAuthRouter.HandleFunc("/me/password", authHandler.ChangePassword).Methods(http.MethodPut)
+	checkAuthRouter.HandleFunc("/me/email", authHandler.GetEmail).Methods(http.MethodGet)
+	checkAuthRouter.HandleFunc("/me/email", authHandler.ChangeEmail).Methods(http.MethodPut)
+	checkAuthRouter.HandleFunc("/me/identities/{PROVIDER}", authHandler.LinkIdentity).Methods(http.MethodPost)
+	checkAuthRouter.HandleFunc("/me/sessions", authHandler.GetSessions).Methods(http.MethodGet)
+	checkAuthRouter.HandleFunc("/me/sessions/{SESSION_ID}", authHandler.DeleteSession).Methods(http.MethodDelete)
+	checkAuthRouter.HandleFunc("/logout", authHandler.Logout).Methods(http.MethodPost)
+	checkAuthRouter.HandleFunc("/logout/all", authHandler.LogoutAll).Methods(http.MethodPost)
+
+	checkAuthRouter.Handle("/review/{FILM_ID}",
+		middleware.VerifiedEmailMiddleware(authUseCase, http.HandlerFunc(reviewHandler.AddReview))).Methods(http.MethodPost)
 	checkAuthRouter.HandleFunc("/review/{REVIEW_ID}", reviewHandler.DeleteReview).Methods(http.MethodDelete)
 	checkAuthRouter.HandleFunc("/review/{REVIEW_ID}", reviewHandler.UpdateReview).Methods(http.MethodPut)
+	checkAuthRouter.HandleFunc("/review/{REVIEW_ID}/vote", reviewHandler.VoteReview).Methods(http.MethodPut)
+	checkAuthRouter.HandleFunc("/review/{REVIEW_ID}/vote", reviewHandler.DeleteVote).Methods(http.MethodDelete)
+	checkAuthRouter.Handle("/review/{REVIEW_ID}/comments",
+		middleware.VerifiedEmailMiddleware(authUseCase, http.HandlerFunc(reviewHandler.AddComment))).Methods(http.MethodPost)
+	checkAuthRouter.HandleFunc("/review/comment/{COMMENT_ID}", reviewHandler.UpdateComment).Methods(http.MethodPut)
+	checkAuthRouter.HandleFunc("/review/comment/{COMMENT_ID}", reviewHandler.DeleteComment).Methods(http.MethodDelete)
+	checkAuthRouter.HandleFunc("/review/{REVIEW_ID}/report", reviewHandler.ReportReview).Methods(http.MethodPost)
+
+	checkAuthRouter.Handle("/moderation/reviews",
+		middleware.RequirePermission(authpermission.ModerateReviews, http.HandlerFunc(reviewHandler.GetModerationQueue))).Methods(http.MethodGet)
+	checkAuthRouter.Handle("/moderation/review/{REVIEW_ID}",
+		middleware.RequirePermission(authpermission.ModerateReviews, http.HandlerFunc(reviewHandler.ModerateReview))).Methods(http.MethodPost)
+	checkAuthRouter.Handle("/moderation/review/{REVIEW_ID}/log",
+		middleware.RequirePermission(authpermission.ModerateReviews, http.HandlerFunc(reviewHandler.GetModerationLog))).Methods(http.MethodGet)
+	checkAuthRouter.Handle("/moderation/review/{REVIEW_ID}/spoiler",
+		middleware.RequirePermission(authpermission.ModerateReviews, http.HandlerFunc(reviewHandler.SetSpoilerFlag))).Methods(http.MethodPut)
+
+	checkAuthRouter.Handle("/admin/user/{USER_ID}/roles",
+		middleware.RequirePermission(authpermission.ManageRoles, http.HandlerFunc(authHandler.GetUserRoles))).Methods(http.MethodGet)
+	checkAuthRouter.Handle("/admin/user/{USER_ID}/roles/{ROLE}",
+		middleware.RequirePermission(authpermission.ManageRoles, http.HandlerFunc(authHandler.GrantRole))).Methods(http.MethodPut)
+	checkAuthRouter.Handle("/admin/user/{USER_ID}/roles/{ROLE}",
+		middleware.RequirePermission(authpermission.ManageRoles, http.HandlerFunc(authHandler.RevokeRole))).Methods(http.MethodDelete)
 
 	accessLogRouter := middleware.AccessLog(router)
 	errorLogRouter := middleware.ErrorLog(accessLogRouter)
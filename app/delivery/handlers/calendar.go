@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	calendarusecase "kinopoisk/app/calendar/usecase"
+	"kinopoisk/app/delivery"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	"kinopoisk/app/middleware"
+	"log"
+	"net/http"
+)
+
+type CalendarHandler struct {
+	CalendarUseCases calendarusecase.CalendarUseCase
+}
+
+func NewCalendarHandler(calendarUseCases calendarusecase.CalendarUseCase) *CalendarHandler {
+	return &CalendarHandler{
+		CalendarUseCases: calendarUseCases,
+	}
+}
+
+func (ch *CalendarHandler) GetCalendarToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	token, err := ch.CalendarUseCases.GetCalendarToken(user.ID, logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	writeCalendarToken(logger, w, token)
+}
+
+func (ch *CalendarHandler) ResetCalendarToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	token, err := ch.CalendarUseCases.ResetCalendarToken(user.ID, logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	writeCalendarToken(logger, w, token)
+}
+
+func (ch *CalendarHandler) GetFavouritesCalendar(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	token := vars["TOKEN"]
+	calendar, err := ch.CalendarUseCases.GetFavouritesCalendar(token, logger)
+	if errors.Is(err, errorapp.ErrorNoCalendarToken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "calendar is not found"}`), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteCalendarResponse(logger, w, calendar, "favourite.ics")
+}
+
+func (ch *CalendarHandler) GetSoonCalendar(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	calendar, err := ch.CalendarUseCases.GetSoonCalendar(logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteCalendarResponse(logger, w, calendar, "soon.ics")
+}
+
+func writeCalendarToken(logger *zap.SugaredLogger, w http.ResponseWriter, token string) {
+	resp := dto.CalendarTokenDTO{
+		Token: token,
+		URL:   "/calendar/favourite/" + token + ".ics",
+	}
+	respJSON, err := json.Marshal(&resp)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding response: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, respJSON, http.StatusOK)
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	calendarusecase "kinopoisk/app/calendar/usecase"
+	errorapp "kinopoisk/app/errors"
+	"kinopoisk/app/middleware"
+)
+
+func TestGetFavouritesCalendar(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := calendarusecase.NewMockCalendarUseCase(ctrl)
+	testHandler := NewCalendarHandler(testUseCase)
+	// unknown token
+	testUseCase.EXPECT().GetFavouritesCalendar("bad_token", logger).Return(nil, errorapp.ErrorNoCalendarToken)
+	request := httptest.NewRequest(http.MethodGet, "/calendar/favourite/bad_token.ics", nil)
+	request = mux.SetURLVars(request, map[string]string{"TOKEN": "bad_token"})
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.GetFavouritesCalendar(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 404 {
+		t.Errorf("expected status %d, got status %d", http.StatusNotFound, resp.StatusCode)
+		return
+	}
+
+	// usecase returns error
+	testUseCase.EXPECT().GetFavouritesCalendar("token", logger).Return(nil, fmt.Errorf("error"))
+	request = httptest.NewRequest(http.MethodGet, "/calendar/favourite/token.ics", nil)
+	request = mux.SetURLVars(request, map[string]string{"TOKEN": "token"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetFavouritesCalendar(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 500 {
+		t.Errorf("expected status %d, got status %d", http.StatusInternalServerError, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	calendar := []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")
+	testUseCase.EXPECT().GetFavouritesCalendar("token", logger).Return(calendar, nil)
+	request = httptest.NewRequest(http.MethodGet, "/calendar/favourite/token.ics", nil)
+	request = mux.SetURLVars(request, map[string]string{"TOKEN": "token"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetFavouritesCalendar(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/calendar; charset=utf-8" {
+		t.Errorf("expected calendar content type, got %s", contentType)
+		return
+	}
+	if string(body) != string(calendar) {
+		t.Errorf("wrong calendar: expected %s, got %s", calendar, body)
+		return
+	}
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"kinopoisk/app/delivery"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/middleware"
+	"log"
+	"net/http"
+)
+
+func (fh *FilmHandler) GetTopChart(w http.ResponseWriter, r *http.Request) {
+	fh.getChart(w, r, "top")
+}
+
+func (fh *FilmHandler) GetBottomChart(w http.ResponseWriter, r *http.Request) {
+	fh.getChart(w, r, "bottom")
+}
+
+func (fh *FilmHandler) getChart(w http.ResponseWriter, r *http.Request, chart string) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	params := r.URL.Query()
+	for key := range params {
+		if key != "genre" && key != "country" && key != "decade" {
+			errText := fmt.Sprintf(`{"message": "unknown query parameter %s"}`, key)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+			return
+		}
+	}
+	query := &dto.ChartQueryDTO{
+		Genre:   params.Get("genre"),
+		Country: params.Get("country"),
+		Decade:  params.Get("decade"),
+	}
+	if validationErrors := query.Validate(); len(validationErrors) != 0 {
+		var errorsJSON []byte
+		errorsJSON, err = json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in json decoding: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusBadRequest)
+		return
+	}
+	filmsChart, err := fh.FilmUseCases.GetChart(chart, query)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	chartJSON, err := json.Marshal(filmsChart)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding chart: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, chartJSON, http.StatusOK)
+}
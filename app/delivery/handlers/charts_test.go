@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"go.uber.org/zap"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	filmusecase "kinopoisk/app/films/usecase"
+	"kinopoisk/app/middleware"
+)
+
+func TestGetTopChart(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := filmusecase.NewMockFilmUseCase(ctrl)
+	testHandler := NewFilmHandler(testUseCase)
+
+	// two variants at once
+	request := httptest.NewRequest(http.MethodGet, "/charts/top?genre=drama&decade=1990", nil)
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.GetTopChart(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// bad decade
+	request = httptest.NewRequest(http.MethodGet, "/charts/top?decade=1995", nil)
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetTopChart(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	query := &dto.ChartQueryDTO{
+		Decade: "1990",
+	}
+	chart := &entity.Chart{
+		Chart:      "top",
+		Scope:      "decade",
+		ScopeValue: "1990",
+		Films: []*entity.ChartEntry{
+			{
+				Place:          1,
+				Film:           &entity.Film{ID: 1, Name: "Leon"},
+				WeightedRating: 8.7,
+			},
+		},
+	}
+	testUseCase.EXPECT().GetChart("top", query).Return(chart, nil)
+	request = httptest.NewRequest(http.MethodGet, "/charts/top?decade=1990", nil)
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetTopChart(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+}
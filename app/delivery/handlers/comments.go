@@ -0,0 +1,223 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"io"
+	"kinopoisk/app/delivery"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	"kinopoisk/app/middleware"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (rh *ReviewHandler) GetReviewComments(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	query, err := getPageQuery(r.URL.Query())
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of page size: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	if validationErrors := query.Validate(); len(validationErrors) != 0 {
+		var errorsJSON []byte
+		errorsJSON, err = json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in json decoding: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusBadRequest)
+		return
+	}
+	token, _ := r.Context().Value(middleware.MyTokenKey).(string)
+	comments, err := rh.ReviewUseCases.GetReviewComments(token, reviewIDInt, query, logger)
+	if errors.Is(err, errorapp.ErrorBadPageToken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "bad page token"}`), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorNoReview) {
+		errText := fmt.Sprintf(`{"message": "review with id %d is not found"}`, reviewIDInt)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	commentsJSON, err := json.Marshal(comments)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding comments: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, commentsJSON, http.StatusOK)
+}
+
+func (rh *ReviewHandler) AddComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	commentDTO := readCommentDTO(logger, w, r)
+	if commentDTO == nil {
+		return
+	}
+	addedComment, err := rh.ReviewUseCases.AddComment(reviewIDInt, commentDTO, user, logger)
+	writeComment(logger, w, addedComment, err)
+}
+
+func (rh *ReviewHandler) UpdateComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	commentID := vars["COMMENT_ID"]
+	commentIDInt, err := strconv.ParseUint(commentID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of comment id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	commentDTO := readCommentDTO(logger, w, r)
+	if commentDTO == nil {
+		return
+	}
+	updatedComment, err := rh.ReviewUseCases.UpdateComment(commentIDInt, commentDTO, user, logger)
+	writeComment(logger, w, updatedComment, err)
+}
+
+func (rh *ReviewHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	commentID := vars["COMMENT_ID"]
+	commentIDInt, err := strconv.ParseUint(commentID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of comment id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	deletedComment, err := rh.ReviewUseCases.DeleteComment(commentIDInt, user, logger)
+	writeComment(logger, w, deletedComment, err)
+}
+
+func getPageQuery(params url.Values) (*dto.PageQueryDTO, error) {
+	query := &dto.PageQueryDTO{
+		PageToken: params.Get("page_token"),
+	}
+	pageSize := params.Get("page_size")
+	if pageSize == "" {
+		return query, nil
+	}
+	pageSizeInt, err := strconv.ParseUint(pageSize, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	query.PageSize = uint32(pageSizeInt)
+	return query, nil
+}
+
+func readCommentDTO(logger *zap.SugaredLogger, w http.ResponseWriter, r *http.Request) *dto.CommentDTO {
+	commentDTO := &dto.CommentDTO{}
+	rBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in reading request body: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return nil
+	}
+	err = json.Unmarshal(rBody, commentDTO)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in decoding comment: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return nil
+	}
+	if validationErrors := commentDTO.Validate(); len(validationErrors) != 0 {
+		errorsJSON, err := json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in json decoding: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return nil
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusUnprocessableEntity)
+		return nil
+	}
+	return commentDTO
+}
+
+func writeComment(logger *zap.SugaredLogger, w http.ResponseWriter, comment *entity.Comment, err error) {
+	switch {
+	case errors.Is(err, errorapp.ErrorNoReview), errors.Is(err, errorapp.ErrorNoComment):
+		errText := fmt.Sprintf(`{"message": "%s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	case errors.Is(err, errorapp.ErrorNoParentComment), errors.Is(err, errorapp.ErrorCommentTooDeep),
+		errors.Is(err, errorapp.ErrorCommentDeleted):
+		errText := fmt.Sprintf(`{"message": "%s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusUnprocessableEntity)
+		return
+	case err != nil:
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	commentJSON, err := json.Marshal(comment)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding comment: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, commentJSON, http.StatusOK)
+}
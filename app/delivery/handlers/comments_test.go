@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	"kinopoisk/app/middleware"
+	reviewusecase "kinopoisk/app/reviews/usecase"
+)
+
+func TestAddComment(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := reviewusecase.NewMockReviewUseCase(ctrl)
+	testHandler := NewReviewHandler(testUseCase)
+	author := &entity.User{
+		ID:       1,
+		Username: "vasyan",
+	}
+	// empty text
+	request := httptest.NewRequest(http.MethodPost, "/review/1/comments", strings.NewReader(`{"text": ""}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, author)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.AddComment(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 422 {
+		t.Errorf("expected status %d, got status %d", http.StatusUnprocessableEntity, resp.StatusCode)
+		return
+	}
+
+	// reply is too deep
+	var reviewID uint64 = 1
+	reply := &dto.CommentDTO{
+		Text:     "I agree",
+		ParentID: 7,
+	}
+	testUseCase.EXPECT().AddComment(reviewID, reply, author, logger).Return(nil, errorapp.ErrorCommentTooDeep)
+	request = httptest.NewRequest(http.MethodPost, "/review/1/comments", strings.NewReader(`{"text": "I agree", "parent_id": 7}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, author)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.AddComment(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 422 {
+		t.Errorf("expected status %d, got status %d", http.StatusUnprocessableEntity, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	addedComment := &entity.Comment{
+		ID:       8,
+		ParentID: 7,
+		Author:   author,
+		Text:     "I agree",
+		Depth:    1,
+		Replies:  []*entity.Comment{},
+	}
+	testUseCase.EXPECT().AddComment(reviewID, reply, author, logger).Return(addedComment, nil)
+	request = httptest.NewRequest(http.MethodPost, "/review/1/comments", strings.NewReader(`{"text": "I agree", "parent_id": 7}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, author)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.AddComment(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/gorilla/mux"
 	"kinopoisk/app/delivery"
+	"kinopoisk/app/dto"
 	"kinopoisk/app/entity"
 	errorapp "kinopoisk/app/errors"
 	filmusecase "kinopoisk/app/films/usecase"
@@ -28,13 +29,22 @@ func NewFilmHandler(filmUseCases filmusecase.FilmUseCase) *FilmHandler {
 
 func checkUnknownParams(query url.Values) error {
 	for key := range query {
-		if key != "genre" && key != "country" && key != "director" {
+		if key != "genre" && key != "country" && key != "director" && key != "sort" && !isMinRatingParam(key) {
 			return fmt.Errorf("unknown param")
 		}
 	}
 	return nil
 }
 
+func isMinRatingParam(key string) bool {
+	for _, criterion := range entity.RatingCriteria {
+		if key == "min_"+criterion {
+			return true
+		}
+	}
+	return false
+}
+
 func (fh *FilmHandler) GetFilms(w http.ResponseWriter, r *http.Request) {
 	logger, err := middleware.GetLoggerFromContext(r.Context())
 	if err != nil {
@@ -48,10 +58,40 @@ func (fh *FilmHandler) GetFilms(w http.ResponseWriter, r *http.Request) {
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
 		return
 	}
-	genre := query.Get("genre")
-	country := query.Get("country")
-	director := query.Get("producer")
-	films, err := fh.FilmUseCases.GetFilms(genre, country, director)
+	filmsQueryDTO := &dto.FilmsQueryDTO{
+		Genre:    query.Get("genre"),
+		Country:  query.Get("country"),
+		Producer: query.Get("producer"),
+		Sort:     query.Get("sort"),
+	}
+	for _, criterion := range entity.RatingCriteria {
+		minRatingParam := query.Get("min_" + criterion)
+		if minRatingParam == "" {
+			continue
+		}
+		minRating, err := strconv.ParseFloat(minRatingParam, 64)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "bad format of min_%s: %s"}`, criterion, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+			return
+		}
+		if filmsQueryDTO.MinRatings == nil {
+			filmsQueryDTO.MinRatings = make(map[string]float64)
+		}
+		filmsQueryDTO.MinRatings[criterion] = minRating
+	}
+	validationErrors := filmsQueryDTO.Validate()
+	if len(validationErrors) != 0 {
+		errorsJSON, err := json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in decoding validation results: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusBadRequest)
+		return
+	}
+	films, err := fh.FilmUseCases.GetFilms(filmsQueryDTO)
 	if err != nil {
 		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
@@ -319,3 +359,37 @@ func (fh *FilmHandler) GetFilmGenres(w http.ResponseWriter, r *http.Request) {
 	}
 	delivery.WriteResponse(logger, w, actorsJSON, http.StatusOK)
 }
+
+func (fh *FilmHandler) GetFilmStats(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	filmID := vars["FILM_ID"]
+	filmIDint, err := strconv.ParseUint(filmID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of film id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	stats, err := fh.FilmUseCases.GetFilmStats(filmIDint)
+	if errors.Is(err, errorapp.ErrorNoFilm) {
+		errText := fmt.Sprintf(`{"message": "no film with id: %d"}`, filmIDint)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	statsJSON, err := json.Marshal(stats)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding film stats: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, statsJSON, http.StatusOK)
+}
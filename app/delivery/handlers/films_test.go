@@ -11,6 +11,7 @@ import (
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
+	"kinopoisk/app/dto"
 	"kinopoisk/app/entity"
 	errorapp "kinopoisk/app/errors"
 	filmusecase "kinopoisk/app/films/usecase"
@@ -47,7 +48,7 @@ func TestGetFilms(t *testing.T) {
 	}
 
 	// usecase returns error
-	testUseCase.EXPECT().GetFilms("Drama", "", "").Return(nil, fmt.Errorf("error"))
+	testUseCase.EXPECT().GetFilms(&dto.FilmsQueryDTO{Genre: "Drama"}).Return(nil, fmt.Errorf("error"))
 	request = httptest.NewRequest(http.MethodGet, "/films?genre=Drama", nil)
 	ctx = request.Context()
 	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
@@ -81,7 +82,7 @@ func TestGetFilms(t *testing.T) {
 			DateOfRelease: "2012-12-12",
 		},
 	}
-	testUseCase.EXPECT().GetFilms("Drama", "", "").Return(films, nil)
+	testUseCase.EXPECT().GetFilms(&dto.FilmsQueryDTO{Genre: "Drama"}).Return(films, nil)
 	request = httptest.NewRequest(http.MethodGet, "/films?genre=Drama", nil)
 	ctx = request.Context()
 	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
@@ -101,6 +102,52 @@ func TestGetFilms(t *testing.T) {
 		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
 		return
 	}
+
+	// min rating of a criterion out of range
+	request = httptest.NewRequest(http.MethodGet, "/films?min_story=11", nil)
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetFilms(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// sort and filter by criteria
+	testUseCase.EXPECT().GetFilms(&dto.FilmsQueryDTO{
+		Sort:       "acting",
+		MinRatings: map[string]float64{"story": 7.5},
+	}).Return(films, nil)
+	request = httptest.NewRequest(http.MethodGet, "/films?sort=acting&min_story=7.5", nil)
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetFilms(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
 }
 
 func TestGetFilmByID(t *testing.T) {
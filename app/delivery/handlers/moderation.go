@@ -0,0 +1,295 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"io"
+	"kinopoisk/app/delivery"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	"kinopoisk/app/middleware"
+	"log"
+	"net/http"
+	"strconv"
+)
+
+type validatedDTO interface {
+	Validate() []string
+}
+
+func (rh *ReviewHandler) ReportReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	reportDTO := &dto.ReportDTO{}
+	if !readValidatedDTO(logger, w, r, reportDTO) {
+		return
+	}
+	err = rh.ReviewUseCases.ReportReview(reviewIDInt, reportDTO, user, logger)
+	switch {
+	case errors.Is(err, errorapp.ErrorNoReview):
+		errText := fmt.Sprintf(`{"message": "review with id %d is not found"}`, reviewIDInt)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	case errors.Is(err, errorapp.ErrorReportOwnReview):
+		errText := fmt.Sprintf(`{"message": "%s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusForbidden)
+		return
+	case errors.Is(err, errorapp.ErrorAlreadyReported):
+		errText := fmt.Sprintf(`{"message": "%s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusConflict)
+		return
+	case err != nil:
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, []byte(`{"result": "success"}`), http.StatusOK)
+}
+
+func (rh *ReviewHandler) GetModerationQueue(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	query, err := getPageQuery(r.URL.Query())
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of page size: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	if validationErrors := query.Validate(); len(validationErrors) != 0 {
+		var errorsJSON []byte
+		errorsJSON, err = json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in json decoding: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusBadRequest)
+		return
+	}
+	token, ok := r.Context().Value(middleware.MyTokenKey).(string)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to token"}`), http.StatusInternalServerError)
+		return
+	}
+	queue, err := rh.ReviewUseCases.GetModerationQueue(token, query, logger)
+	if errors.Is(err, errorapp.ErrorPermissionDenied) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "no permission for this action"}`), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorBadPageToken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "bad page token"}`), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	queueJSON, err := json.Marshal(queue)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding moderation queue: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, queueJSON, http.StatusOK)
+}
+
+func (rh *ReviewHandler) ModerateReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	moderator, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	moderationDTO := &dto.ModerationDTO{}
+	if !readValidatedDTO(logger, w, r, moderationDTO) {
+		return
+	}
+	token, ok := r.Context().Value(middleware.MyTokenKey).(string)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to token"}`), http.StatusInternalServerError)
+		return
+	}
+	moderatedReview, err := rh.ReviewUseCases.ModerateReview(token, reviewIDInt, moderationDTO, moderator, logger)
+	if errors.Is(err, errorapp.ErrorPermissionDenied) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "no permission for this action"}`), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorNoReview) {
+		errText := fmt.Sprintf(`{"message": "review with id %d is not found"}`, reviewIDInt)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	reviewJSON, err := json.Marshal(moderatedReview)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding review: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, reviewJSON, http.StatusOK)
+}
+
+func (rh *ReviewHandler) SetSpoilerFlag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	moderator, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	spoilerFlagDTO := &dto.SpoilerFlagDTO{}
+	if !readValidatedDTO(logger, w, r, spoilerFlagDTO) {
+		return
+	}
+	token, ok := r.Context().Value(middleware.MyTokenKey).(string)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to token"}`), http.StatusInternalServerError)
+		return
+	}
+	flaggedReview, err := rh.ReviewUseCases.SetSpoilerFlag(token, reviewIDInt, *spoilerFlagDTO.Spoiler, moderator, logger)
+	if errors.Is(err, errorapp.ErrorPermissionDenied) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "no permission for this action"}`), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorNoReview) {
+		errText := fmt.Sprintf(`{"message": "review with id %d is not found"}`, reviewIDInt)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	reviewJSON, err := json.Marshal(flaggedReview)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding review: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, reviewJSON, http.StatusOK)
+}
+
+func (rh *ReviewHandler) GetModerationLog(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	token, ok := r.Context().Value(middleware.MyTokenKey).(string)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to token"}`), http.StatusInternalServerError)
+		return
+	}
+	entries, err := rh.ReviewUseCases.GetModerationLog(token, reviewIDInt, logger)
+	if errors.Is(err, errorapp.ErrorPermissionDenied) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "no permission for this action"}`), http.StatusForbidden)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorNoReview) {
+		errText := fmt.Sprintf(`{"message": "review with id %d is not found"}`, reviewIDInt)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	entriesJSON, err := json.Marshal(entries)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding moderation log: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, entriesJSON, http.StatusOK)
+}
+
+// readValidatedDTO decodes request body into body and writes the error response if it is not valid.
+func readValidatedDTO(logger *zap.SugaredLogger, w http.ResponseWriter, r *http.Request, body validatedDTO) bool {
+	rBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in reading request body: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return false
+	}
+	err = json.Unmarshal(rBody, body)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in decoding request body: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return false
+	}
+	if validationErrors := body.Validate(); len(validationErrors) != 0 {
+		errorsJSON, err := json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in json decoding: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return false
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusUnprocessableEntity)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	"kinopoisk/app/middleware"
+	reviewusecase "kinopoisk/app/reviews/usecase"
+)
+
+func TestReportReview(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := reviewusecase.NewMockReviewUseCase(ctrl)
+	testHandler := NewReviewHandler(testUseCase)
+	user := &entity.User{
+		ID:       2,
+		Username: "petya",
+	}
+	// no reason
+	request := httptest.NewRequest(http.MethodPost, "/review/1/report", strings.NewReader(`{}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, user)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.ReportReview(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 422 {
+		t.Errorf("expected status %d, got status %d", http.StatusUnprocessableEntity, resp.StatusCode)
+		return
+	}
+
+	// review has been already reported
+	var reviewID uint64 = 1
+	report := &dto.ReportDTO{
+		Reason: "spoilers and insults",
+	}
+	testUseCase.EXPECT().ReportReview(reviewID, report, user, logger).Return(errorapp.ErrorAlreadyReported)
+	request = httptest.NewRequest(http.MethodPost, "/review/1/report", strings.NewReader(`{"reason": "spoilers and insults"}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, user)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.ReportReview(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 409 {
+		t.Errorf("expected status %d, got status %d", http.StatusConflict, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	testUseCase.EXPECT().ReportReview(reviewID, report, user, logger).Return(nil)
+	request = httptest.NewRequest(http.MethodPost, "/review/1/report", strings.NewReader(`{"reason": "spoilers and insults"}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, user)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.ReportReview(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+}
+
+func TestModerateReview(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := reviewusecase.NewMockReviewUseCase(ctrl)
+	testHandler := NewReviewHandler(testUseCase)
+	moderator := &entity.User{
+		ID:       1,
+		Username: "admin",
+	}
+	// unknown action
+	request := httptest.NewRequest(http.MethodPost, "/moderation/review/1", strings.NewReader(`{"action": "delete"}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, moderator)
+	ctx = context.WithValue(ctx, middleware.MyTokenKey, "token")
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.ModerateReview(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 422 {
+		t.Errorf("expected status %d, got status %d", http.StatusUnprocessableEntity, resp.StatusCode)
+		return
+	}
+
+	// no review
+	var reviewID uint64 = 1
+	decision := &dto.ModerationDTO{
+		Action: "hide",
+		Reason: "insults",
+	}
+	testUseCase.EXPECT().ModerateReview("token", reviewID, decision, moderator, logger).Return(nil, errorapp.ErrorNoReview)
+	request = httptest.NewRequest(http.MethodPost, "/moderation/review/1", strings.NewReader(`{"action": "hide", "reason": "insults"}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, moderator)
+	ctx = context.WithValue(ctx, middleware.MyTokenKey, "token")
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.ModerateReview(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 404 {
+		t.Errorf("expected status %d, got status %d", http.StatusNotFound, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	hiddenReview := &entity.Review{
+		ID:   1,
+		Mark: 1,
+		Author: &entity.User{
+			ID:       2,
+			Username: "petya",
+		},
+		Status: "hidden",
+	}
+	testUseCase.EXPECT().ModerateReview("token", reviewID, decision, moderator, logger).Return(hiddenReview, nil)
+	request = httptest.NewRequest(http.MethodPost, "/moderation/review/1", strings.NewReader(`{"action": "hide", "reason": "insults"}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, moderator)
+	ctx = context.WithValue(ctx, middleware.MyTokenKey, "token")
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.ModerateReview(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+}
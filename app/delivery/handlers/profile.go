@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"kinopoisk/app/delivery"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	"kinopoisk/app/middleware"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+func (rh *ReviewHandler) GetUserProfile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	userID := vars["USER_ID"]
+	userIDInt, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of user id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	query, err := getUserReviewsQuery(r.URL.Query())
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of query parameters: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	requester, _ := ctx.Value(middleware.MyUserKey).(*entity.User)
+	profile, err := rh.ReviewUseCases.GetUserProfile(userIDInt, requester, query.RevealSpoilers, logger)
+	if writeProfileError(logger, w, userIDInt, err) {
+		return
+	}
+	profileJSON, err := json.Marshal(profile)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding user profile: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, profileJSON, http.StatusOK)
+}
+
+func (rh *ReviewHandler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	userID := vars["USER_ID"]
+	userIDInt, err := strconv.ParseUint(userID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of user id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	query, err := getUserReviewsQuery(r.URL.Query())
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of query parameters: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	if validationErrors := query.Validate(); len(validationErrors) != 0 {
+		var errorsJSON []byte
+		errorsJSON, err = json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in json decoding: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusBadRequest)
+		return
+	}
+	requester, _ := ctx.Value(middleware.MyUserKey).(*entity.User)
+	reviews, err := rh.ReviewUseCases.GetUserReviews(userIDInt, requester, query, logger)
+	if errors.Is(err, errorapp.ErrorBadPageToken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "bad page token"}`), http.StatusBadRequest)
+		return
+	}
+	if writeProfileError(logger, w, userIDInt, err) {
+		return
+	}
+	reviewsJSON, err := json.Marshal(reviews)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding reviews: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, reviewsJSON, http.StatusOK)
+}
+
+func getUserReviewsQuery(params url.Values) (*dto.UserReviewsQueryDTO, error) {
+	pageQuery, err := getPageQuery(params)
+	if err != nil {
+		return nil, err
+	}
+	query := &dto.UserReviewsQueryDTO{
+		PageSize:  pageQuery.PageSize,
+		PageToken: pageQuery.PageToken,
+	}
+	if revealSpoilers := params.Get("reveal_spoilers"); revealSpoilers != "" {
+		query.RevealSpoilers, err = strconv.ParseBool(revealSpoilers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
+
+// writeProfileError reports whether err has been written to the response.
+func writeProfileError(logger *zap.SugaredLogger, w http.ResponseWriter, userID uint64, err error) bool {
+	switch {
+	case errors.Is(err, errorapp.ErrorNoUser):
+		errText := fmt.Sprintf(`{"message": "user with id %d is not found"}`, userID)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return true
+	case errors.Is(err, errorapp.ErrorPrivateProfile):
+		errText := fmt.Sprintf(`{"message": "profile of user %d is private"}`, userID)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusForbidden)
+		return true
+	case err != nil:
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	"kinopoisk/app/middleware"
+	reviewusecase "kinopoisk/app/reviews/usecase"
+)
+
+func TestGetUserProfile(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := reviewusecase.NewMockReviewUseCase(ctrl)
+	testHandler := NewReviewHandler(testUseCase)
+	var nilUser *entity.User
+
+	// bad user id
+	request := httptest.NewRequest(http.MethodGet, "/user/abc", nil)
+	request = mux.SetURLVars(request, map[string]string{"USER_ID": "abc"})
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.GetUserProfile(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// no such user
+	var userID uint64 = 2
+	testUseCase.EXPECT().GetUserProfile(userID, nilUser, false, logger).Return(nil, errorapp.ErrorNoUser)
+	request = httptest.NewRequest(http.MethodGet, "/user/2", nil)
+	request = mux.SetURLVars(request, map[string]string{"USER_ID": "2"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetUserProfile(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 404 {
+		t.Errorf("expected status %d, got status %d", http.StatusNotFound, resp.StatusCode)
+		return
+	}
+
+	// private profile of another user
+	requester := &entity.User{
+		ID:       1,
+		Username: "vasyan",
+	}
+	testUseCase.EXPECT().GetUserProfile(userID, requester, false, logger).Return(nil, errorapp.ErrorPrivateProfile)
+	request = httptest.NewRequest(http.MethodGet, "/user/2", nil)
+	request = mux.SetURLVars(request, map[string]string{"USER_ID": "2"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, requester)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetUserProfile(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 403 {
+		t.Errorf("expected status %d, got status %d", http.StatusForbidden, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	profile := &entity.UserProfile{
+		User: &entity.User{
+			ID:       2,
+			Username: "petya",
+		},
+		JoinedAt:      "2023-01-01 10:00:00",
+		ReviewsCount:  1,
+		AverageMark:   8,
+		RecentReviews: []*entity.Review{},
+	}
+	testUseCase.EXPECT().GetUserProfile(userID, requester, true, logger).Return(profile, nil)
+	request = httptest.NewRequest(http.MethodGet, "/user/2?reveal_spoilers=true", nil)
+	request = mux.SetURLVars(request, map[string]string{"USER_ID": "2"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, requester)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetUserProfile(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+}
+
+func TestGetUserReviews(t *testing.T) {
+	t.Parallel()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := reviewusecase.NewMockReviewUseCase(ctrl)
+	testHandler := NewReviewHandler(testUseCase)
+	var nilUser *entity.User
+
+	// page size is out of range
+	request := httptest.NewRequest(http.MethodGet, "/user/2/reviews?page_size=1000", nil)
+	request = mux.SetURLVars(request, map[string]string{"USER_ID": "2"})
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.GetUserReviews(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	var userID uint64 = 2
+	query := &dto.UserReviewsQueryDTO{
+		PageSize:  10,
+		PageToken: "MTA=",
+	}
+	page := &entity.ReviewsPage{
+		Reviews:       []*entity.Review{},
+		NextPageToken: "",
+	}
+	testUseCase.EXPECT().GetUserReviews(userID, nilUser, query, logger).Return(page, nil)
+	request = httptest.NewRequest(http.MethodGet, "/user/2/reviews?page_size=10&page_token=MTA=", nil)
+	request = mux.SetURLVars(request, map[string]string{"USER_ID": "2"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetUserReviews(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+}
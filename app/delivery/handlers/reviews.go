@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"github.com/gorilla/mux"
+	"go.uber.org/zap"
 	"io"
 	"kinopoisk/app/delivery"
 	"kinopoisk/app/dto"
@@ -14,6 +15,7 @@ import (
 	reviewusecase "kinopoisk/app/reviews/usecase"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 )
 
@@ -41,7 +43,28 @@ func (rh *ReviewHandler) GetReviewsForFilm(w http.ResponseWriter, r *http.Reques
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
 		return
 	}
-	reviews, err := rh.ReviewUseCases.GetFilmReviews(filmIDInt, logger)
+	query, err := getReviewsQuery(r.URL.Query())
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of query parameters: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	if validationErrors := query.Validate(); len(validationErrors) != 0 {
+		var errorsJSON []byte
+		errorsJSON, err = json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in json decoding: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusBadRequest)
+		return
+	}
+	reviews, err := rh.ReviewUseCases.GetFilmReviews(filmIDInt, query, logger)
+	if errors.Is(err, errorapp.ErrorBadPageToken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "bad page token"}`), http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
@@ -56,6 +79,31 @@ func (rh *ReviewHandler) GetReviewsForFilm(w http.ResponseWriter, r *http.Reques
 	delivery.WriteResponse(logger, w, reviewsJSON, http.StatusOK)
 }
 
+func getReviewsQuery(params url.Values) (*dto.ReviewsQueryDTO, error) {
+	pageQuery, err := getPageQuery(params)
+	if err != nil {
+		return nil, err
+	}
+	query := &dto.ReviewsQueryDTO{
+		Sort:      params.Get("sort"),
+		PageSize:  pageQuery.PageSize,
+		PageToken: pageQuery.PageToken,
+	}
+	if revealSpoilers := params.Get("reveal_spoilers"); revealSpoilers != "" {
+		query.RevealSpoilers, err = strconv.ParseBool(revealSpoilers)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if criticsOnly := params.Get("critics_only"); criticsOnly != "" {
+		query.CriticsOnly, err = strconv.ParseBool(criticsOnly)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
+
 func (rh *ReviewHandler) AddReview(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	logger, err := middleware.GetLoggerFromContext(ctx)
@@ -224,3 +272,143 @@ func (rh *ReviewHandler) UpdateReview(w http.ResponseWriter, r *http.Request) {
 	}
 	delivery.WriteResponse(logger, w, reviewJSON, http.StatusOK)
 }
+
+func (rh *ReviewHandler) GetReviewHistory(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	revealSpoilers := false
+	if revealSpoilersParam := r.URL.Query().Get("reveal_spoilers"); revealSpoilersParam != "" {
+		revealSpoilers, err = strconv.ParseBool(revealSpoilersParam)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "bad format of reveal_spoilers: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+			return
+		}
+	}
+	token, _ := r.Context().Value(middleware.MyTokenKey).(string)
+	history, err := rh.ReviewUseCases.GetReviewHistory(token, reviewIDInt, revealSpoilers, logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	if history == nil {
+		errText := fmt.Sprintf(`{"message": "review with id %d is not found"}`, reviewIDInt)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	}
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding review history: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, historyJSON, http.StatusOK)
+}
+
+func (rh *ReviewHandler) VoteReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	voteDTO := &dto.VoteDTO{}
+	rBody, err := io.ReadAll(r.Body)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in reading request body: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	err = json.Unmarshal(rBody, voteDTO)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in decoding vote: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	if validationErrors := voteDTO.Validate(); len(validationErrors) != 0 {
+		var errorsJSON []byte
+		errorsJSON, err = json.Marshal(validationErrors)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in json decoding: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return
+		}
+		delivery.WriteResponse(logger, w, errorsJSON, http.StatusUnprocessableEntity)
+		return
+	}
+	votedReview, err := rh.ReviewUseCases.VoteReview(reviewIDInt, *voteDTO.Helpful, user, logger)
+	writeVotedReview(logger, w, votedReview, err)
+}
+
+func (rh *ReviewHandler) DeleteVote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	vars := mux.Vars(r)
+	reviewID := vars["REVIEW_ID"]
+	reviewIDInt, err := strconv.ParseUint(reviewID, 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of review id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	votedReview, err := rh.ReviewUseCases.DeleteVote(reviewIDInt, user, logger)
+	writeVotedReview(logger, w, votedReview, err)
+}
+
+func writeVotedReview(logger *zap.SugaredLogger, w http.ResponseWriter, votedReview *entity.Review, err error) {
+	switch {
+	case errors.Is(err, errorapp.ErrorNoReview), errors.Is(err, errorapp.ErrorNoVote):
+		errText := fmt.Sprintf(`{"message": "%s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	case errors.Is(err, errorapp.ErrorOwnReview):
+		errText := fmt.Sprintf(`{"message": "%s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusForbidden)
+		return
+	case err != nil:
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	reviewJSON, err := json.Marshal(votedReview)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding review: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, reviewJSON, http.StatusOK)
+}
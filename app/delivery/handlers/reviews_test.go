@@ -53,9 +53,77 @@ func TestGetReviewsForFilm(t *testing.T) {
 		return
 	}
 
-	// usecase returns error
+	// unknown sort
+	request = httptest.NewRequest(http.MethodGet, "/review/1?sort=random", nil)
+	request = mux.SetURLVars(request, map[string]string{"FILM_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetReviewsForFilm(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// bad reveal_spoilers flag
+	request = httptest.NewRequest(http.MethodGet, "/review/1?reveal_spoilers=maybe", nil)
+	request = mux.SetURLVars(request, map[string]string{"FILM_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetReviewsForFilm(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// bad page token
 	var filmID uint64 = 1
-	testUseCase.EXPECT().GetFilmReviews(filmID, logger).Return(nil, fmt.Errorf("error"))
+	query := &dto.ReviewsQueryDTO{Sort: "oldest", PageSize: 5, PageToken: "bad", RevealSpoilers: true}
+	testUseCase.EXPECT().GetFilmReviews(filmID, query, logger).Return(nil, errorapp.ErrorBadPageToken)
+	request = httptest.NewRequest(http.MethodGet, "/review/1?sort=oldest&page_size=5&page_token=bad&reveal_spoilers=true", nil)
+	request = mux.SetURLVars(request, map[string]string{"FILM_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetReviewsForFilm(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// usecase returns error
+	testUseCase.EXPECT().GetFilmReviews(filmID, &dto.ReviewsQueryDTO{}, logger).Return(nil, fmt.Errorf("error"))
 	request = httptest.NewRequest(http.MethodGet, "/review/1", nil)
 	request = mux.SetURLVars(request, map[string]string{"FILM_ID": "1"})
 	ctx = request.Context()
@@ -78,18 +146,22 @@ func TestGetReviewsForFilm(t *testing.T) {
 	}
 
 	// all is ok
-	reviews := []*entity.Review{
-		{
-			ID:      1,
-			Mark:    8,
-			Comment: "good film",
-			Author: &entity.User{
-				ID:       1,
-				Username: "vasyan",
+	reviews := &entity.ReviewsPage{
+		Reviews: []*entity.Review{
+			{
+				ID:      1,
+				Mark:    8,
+				Comment: "good film",
+				Author: &entity.User{
+					ID:       1,
+					Username: "vasyan",
+				},
+				CreatedAt: "2023-12-12 12:00:00",
+				UpdatedAt: "2023-12-12 12:00:00",
 			},
 		},
 	}
-	testUseCase.EXPECT().GetFilmReviews(filmID, logger).Return(reviews, nil)
+	testUseCase.EXPECT().GetFilmReviews(filmID, &dto.ReviewsQueryDTO{}, logger).Return(reviews, nil)
 	request = httptest.NewRequest(http.MethodGet, "/review/1", nil)
 	request = mux.SetURLVars(request, map[string]string{"FILM_ID": "1"})
 	ctx = request.Context()
@@ -399,3 +471,189 @@ func TestDeleteReview(t *testing.T) {
 		return
 	}
 }
+
+func TestGetReviewHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := reviewusecase.NewMockReviewUseCase(ctrl)
+	testHandler := NewReviewHandler(testUseCase)
+	// bad review id
+	request := httptest.NewRequest(http.MethodGet, "/review/bad_id/history", nil)
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "bad_id"})
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.GetReviewHistory(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 400 {
+		t.Errorf("expected status %d, got status %d", http.StatusBadRequest, resp.StatusCode)
+		return
+	}
+
+	// no review or it is hidden from anonymous users
+	var reviewID uint64 = 1
+	testUseCase.EXPECT().GetReviewHistory("", reviewID, false, logger).Return(nil, nil)
+	request = httptest.NewRequest(http.MethodGet, "/review/1/history", nil)
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.GetReviewHistory(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 404 {
+		t.Errorf("expected status %d, got status %d", http.StatusNotFound, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	history := &entity.ReviewHistory{
+		Review: &entity.Review{
+			ID:       reviewID,
+			Mark:     9,
+			Comment:  "even better on second watch",
+			Author:   &entity.User{ID: 1, Username: "vasyan"},
+			IsEdited: true,
+		},
+		Revisions: []*entity.ReviewRevision{
+			{
+				Mark:       7,
+				Comment:    "good film",
+				CreatedAt:  "2023-12-12 12:00:00",
+				ReplacedAt: "2023-12-13 12:00:00",
+			},
+		},
+	}
+	testUseCase.EXPECT().GetReviewHistory("token", reviewID, true, logger).Return(history, nil)
+	request = httptest.NewRequest(http.MethodGet, "/review/1/history?reveal_spoilers=true", nil)
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	ctx = context.WithValue(ctx, middleware.MyTokenKey, "token")
+	respWriter = httptest.NewRecorder()
+	testHandler.GetReviewHistory(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+}
+
+func TestVoteReview(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := reviewusecase.NewMockReviewUseCase(ctrl)
+	testHandler := NewReviewHandler(testUseCase)
+	voter := &entity.User{
+		ID:       2,
+		Username: "petya",
+	}
+	// no vote in body
+	request := httptest.NewRequest(http.MethodPut, "/review/1/vote", strings.NewReader(`{}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx := request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, voter)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter := httptest.NewRecorder()
+	testHandler.VoteReview(respWriter, request.WithContext(ctx))
+	resp := respWriter.Result()
+	_, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 422 {
+		t.Errorf("expected status %d, got status %d", http.StatusUnprocessableEntity, resp.StatusCode)
+		return
+	}
+
+	// vote for own review
+	var reviewID uint64 = 1
+	testUseCase.EXPECT().VoteReview(reviewID, false, voter, logger).Return(nil, errorapp.ErrorOwnReview)
+	request = httptest.NewRequest(http.MethodPut, "/review/1/vote", strings.NewReader(`{"helpful": false}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, voter)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.VoteReview(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 403 {
+		t.Errorf("expected status %d, got status %d", http.StatusForbidden, resp.StatusCode)
+		return
+	}
+
+	// all is ok
+	votedReview := &entity.Review{
+		ID:           reviewID,
+		Mark:         8,
+		Comment:      "good film",
+		Author:       &entity.User{ID: 1, Username: "vasyan"},
+		HelpfulVotes: 1,
+	}
+	testUseCase.EXPECT().VoteReview(reviewID, true, voter, logger).Return(votedReview, nil)
+	request = httptest.NewRequest(http.MethodPut, "/review/1/vote", strings.NewReader(`{"helpful": true}`))
+	request = mux.SetURLVars(request, map[string]string{"REVIEW_ID": "1"})
+	ctx = request.Context()
+	ctx = context.WithValue(ctx, middleware.MyUserKey, voter)
+	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
+	respWriter = httptest.NewRecorder()
+	testHandler.VoteReview(respWriter, request.WithContext(ctx))
+	resp = respWriter.Result()
+	_, err = io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("unable to read response body")
+		return
+	}
+	err = resp.Body.Close()
+	if err != nil {
+		t.Fatalf("failed to close response body")
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status %d, got status %d", http.StatusOK, resp.StatusCode)
+		return
+	}
+}
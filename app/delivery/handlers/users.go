@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 	"io"
 	"kinopoisk/app/delivery"
@@ -13,7 +14,18 @@ import (
 	"kinopoisk/app/middleware"
 	userusecase "kinopoisk/app/users/usecase"
 	"log"
+	"net"
 	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	// oidcBindingCookie keeps the login with the identity provider to the browser that has started it,
+	// it lives as long as the state of the login in service_auth
+	oidcBindingCookie     = "oidc_binding"
+	oidcBindingCookiePath = "/oidc/"
+	oidcBindingMaxAge     = 10 * 60
 )
 
 type UserHandler struct {
@@ -33,7 +45,7 @@ func (uh *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		log.Printf("can not get logger from context: %s", err)
 		middleware.WriteNoLoggerResponse(w)
 	}
-	userFromLoginForm, err := checkRequestFormat(logger, w, r)
+	userFromLoginForm, err := checkRequestFormat(logger, w, r, false)
 	if err != nil || userFromLoginForm == nil {
 		return
 	}
@@ -48,7 +60,7 @@ func (uh *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 		delivery.WriteResponse(logger, w, []byte(`{"message": "bad username or password"}`), http.StatusUnauthorized)
 		return
 	}
-	uh.HandleGetToken(w, loggedInUser, logger)
+	uh.HandleGetToken(w, r, loggedInUser, logger)
 
 }
 
@@ -59,11 +71,11 @@ func (uh *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		log.Printf("can not get logger from context: %s", err)
 		middleware.WriteNoLoggerResponse(w)
 	}
-	userFromLoginForm, err := checkRequestFormat(logger, w, r)
+	userFromLoginForm, err := checkRequestFormat(logger, w, r, true)
 	if err != nil || userFromLoginForm == nil {
 		return
 	}
-	newUser, err := uh.UserUseCases.Register(userFromLoginForm.Username, userFromLoginForm.Password, logger)
+	newUser, err := uh.UserUseCases.Register(userFromLoginForm.Username, userFromLoginForm.Password, userFromLoginForm.Email, logger)
 
 	if errors.Is(err, errorapp.ErrorUserExists) {
 		delivery.WriteResponse(logger, w, []byte(`{"message": "user already exists"}`), http.StatusUnprocessableEntity)
@@ -74,18 +86,89 @@ func (uh *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
 		return
 	}
-	uh.HandleGetToken(w, newUser, logger)
+	uh.HandleGetToken(w, r, newUser, logger)
 }
 
-func (uh *UserHandler) HandleGetToken(w http.ResponseWriter, newUser *entity.User, logger *zap.SugaredLogger) {
-	token, err := uh.UserUseCases.CreateSession(newUser, logger)
+func (uh *UserHandler) HandleGetToken(w http.ResponseWriter, r *http.Request, newUser *entity.User, logger *zap.SugaredLogger) {
+	tokenPair, err := uh.UserUseCases.CreateSession(newUser, getClientInfo(r), logger)
 	if err != nil {
 		errText := fmt.Sprintf(`{"message": "error in session creation: %s"}`, err)
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
 		return
 	}
+	writeTokenPair(logger, w, tokenPair)
+}
+
+// RefreshTokens exchanges the refresh token for a new access token and a new refresh token, the old one stops working.
+func (uh *UserHandler) RefreshTokens(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	refreshTokenDTO := &dto.RefreshTokenDTO{}
+	if !readValidatedDTO(logger, w, r, refreshTokenDTO) {
+		return
+	}
+	tokenPair, err := uh.UserUseCases.RefreshTokens(refreshTokenDTO.RefreshToken, getClientInfo(r), logger)
+	if errors.Is(err, errorapp.ErrorNoSession) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "refresh token is invalid or expired"}`), http.StatusUnauthorized)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorRefreshTokenReused) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "refresh token has already been used, log in again"}`),
+			http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in refreshing tokens: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	writeTokenPair(logger, w, tokenPair)
+}
+
+// getClientInfo takes the address of the client as the gateway sees it, the gateway is not behind a proxy.
+func getClientInfo(r *http.Request) *entity.ClientInfo {
+	clientIP, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		clientIP = r.RemoteAddr
+	}
+	userAgent := r.UserAgent()
+	return &entity.ClientInfo{
+		Device:    deviceFromUserAgent(userAgent),
+		IP:        clientIP,
+		UserAgent: userAgent,
+	}
+}
+
+// deviceFromUserAgent guesses the device good enough for the user to recognize the session.
+// The order matters: user agents of phones mention the desktop systems too.
+func deviceFromUserAgent(userAgent string) string {
+	knownDevices := []struct {
+		marker string
+		device string
+	}{
+		{marker: "iPhone", device: "iPhone"},
+		{marker: "iPad", device: "iPad"},
+		{marker: "Android", device: "Android"},
+		{marker: "Windows", device: "Windows"},
+		{marker: "Macintosh", device: "Mac"},
+		{marker: "Linux", device: "Linux"},
+	}
+	for _, knownDevice := range knownDevices {
+		if strings.Contains(userAgent, knownDevice.marker) {
+			return knownDevice.device
+		}
+	}
+	return "unknown"
+}
+
+func writeTokenPair(logger *zap.SugaredLogger, w http.ResponseWriter, tokenPair *entity.TokenPair) {
 	resp := dto.AuthResponseDTO{
-		Token: token,
+		Token:        tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    tokenPair.ExpiresIn,
 	}
 	tokenJSON, err := json.Marshal(&resp)
 	if err != nil {
@@ -93,11 +176,11 @@ func (uh *UserHandler) HandleGetToken(w http.ResponseWriter, newUser *entity.Use
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
 		return
 	}
-	logger.Infof("new token: %s", token)
 	delivery.WriteResponse(logger, w, tokenJSON, http.StatusOK)
 }
 
-func checkRequestFormat(logger *zap.SugaredLogger, w http.ResponseWriter, r *http.Request) (*dto.AuthRequestDTO, error) {
+// checkRequestFormat reads the login or, if isRegister, the register form.
+func checkRequestFormat(logger *zap.SugaredLogger, w http.ResponseWriter, r *http.Request, isRegister bool) (*dto.AuthRequestDTO, error) {
 	rBody, err := io.ReadAll(r.Body)
 	if err != nil {
 		errText := fmt.Sprintf(`{"message": "error in reading request body: %s"}`, err)
@@ -111,7 +194,11 @@ func checkRequestFormat(logger *zap.SugaredLogger, w http.ResponseWriter, r *htt
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusUnauthorized)
 		return nil, err
 	}
-	if validationErrors := userFromLoginForm.Validate(); len(validationErrors) != 0 {
+	validationErrors := userFromLoginForm.Validate()
+	if isRegister {
+		validationErrors = userFromLoginForm.ValidateRegister()
+	}
+	if len(validationErrors) != 0 {
 		errorsJSON, err := json.Marshal(validationErrors)
 		if err != nil {
 			errText := fmt.Sprintf(`{"message": "error in decoding validation errors: %s"}`, err)
@@ -138,6 +225,9 @@ func (uh *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	isDeleted, err := uh.UserUseCases.DeleteSession(token, logger)
+	if errors.Is(err, errorapp.ErrorNoSession) {
+		isDeleted, err = false, nil
+	}
 	if err != nil {
 		errText := fmt.Sprintf(`{"message": "error in logging out: %s"}`, err)
 		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
@@ -151,3 +241,463 @@ func (uh *UserHandler) Logout(w http.ResponseWriter, r *http.Request) {
 	message := `{"result":"success"}`
 	delivery.WriteResponse(logger, w, []byte(message), http.StatusOK)
 }
+
+// LogoutAll ends all the sessions of the user, the token of the request stops working too.
+func (uh *UserHandler) LogoutAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	deletedSessions, err := uh.UserUseCases.DeleteAllSessions(user.ID, logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in logging out everywhere: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	respText := fmt.Sprintf(`{"deleted": %d}`, deletedSessions)
+	delivery.WriteResponse(logger, w, []byte(respText), http.StatusOK)
+}
+
+func (uh *UserHandler) GetSessions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	token, ok := ctx.Value(middleware.MyTokenKey).(string)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to token"}`), http.StatusInternalServerError)
+		return
+	}
+	sessions, err := uh.UserUseCases.GetUserSessions(token, logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in getting sessions: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	sessionsJSON, err := json.Marshal(sessions)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding sessions: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, sessionsJSON, http.StatusOK)
+}
+
+func (uh *UserHandler) DeleteSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	sessionID := mux.Vars(r)["SESSION_ID"]
+	err = uh.UserUseCases.DeleteUserSession(user.ID, sessionID, logger)
+	if errors.Is(err, errorapp.ErrorNoSession) {
+		errText := fmt.Sprintf(`{"message": "no session with id: %s"}`, sessionID)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in deleting session: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, []byte(`{"result":"success"}`), http.StatusOK)
+}
+
+// ChangePassword sets the new password of the user, the other sessions of the user are ended.
+func (uh *UserHandler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	token, ok := ctx.Value(middleware.MyTokenKey).(string)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to token"}`), http.StatusInternalServerError)
+		return
+	}
+	passwordChangeDTO := &dto.PasswordChangeDTO{}
+	if !readValidatedDTO(logger, w, r, passwordChangeDTO) {
+		return
+	}
+	err = uh.UserUseCases.ChangePassword(token, passwordChangeDTO.OldPassword, passwordChangeDTO.NewPassword, logger)
+	if errors.Is(err, errorapp.ErrorWrongPassword) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "wrong password"}`), http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in changing password: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, []byte(`{"result":"success"}`), http.StatusOK)
+}
+
+// ForgotPassword answers the same for any valid email, so it can not be used to find out who is registered.
+func (uh *UserHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	passwordForgotDTO := &dto.PasswordForgotDTO{}
+	if !readValidatedDTO(logger, w, r, passwordForgotDTO) {
+		return
+	}
+	err = uh.UserUseCases.RequestPasswordReset(passwordForgotDTO.Email, logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in requesting password reset: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, []byte(`{"result": "if the email is registered, the reset letter is sent"}`), http.StatusAccepted)
+}
+
+// ResetPassword sets the new password by the token from the reset letter and ends all the sessions of the user.
+func (uh *UserHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	passwordResetDTO := &dto.PasswordResetDTO{}
+	if !readValidatedDTO(logger, w, r, passwordResetDTO) {
+		return
+	}
+	err = uh.UserUseCases.ResetPassword(passwordResetDTO.Token, passwordResetDTO.NewPassword, logger)
+	if errors.Is(err, errorapp.ErrorBadResetToken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "reset token is invalid, expired or already used"}`), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in resetting password: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, []byte(`{"result":"success"}`), http.StatusOK)
+}
+
+func (uh *UserHandler) GetEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	emailStatus, err := uh.UserUseCases.GetEmailStatus(user.ID, logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in getting email: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	writeEmailStatus(logger, w, emailStatus, http.StatusOK)
+}
+
+// ChangeEmail mails the verification letter to the new email, the current email stays until the new one is verified.
+func (uh *UserHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	emailDTO := &dto.EmailDTO{}
+	if !readValidatedDTO(logger, w, r, emailDTO) {
+		return
+	}
+	emailStatus, err := uh.UserUseCases.ChangeEmail(user.ID, emailDTO.Email, logger)
+	if errors.Is(err, errorapp.ErrorEmailTaken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "email is used by another user"}`), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in changing email: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	writeEmailStatus(logger, w, emailStatus, http.StatusAccepted)
+}
+
+func (uh *UserHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	emailVerificationDTO := &dto.EmailVerificationDTO{}
+	if !readValidatedDTO(logger, w, r, emailVerificationDTO) {
+		return
+	}
+	err = uh.UserUseCases.VerifyEmail(emailVerificationDTO.Token, logger)
+	if errors.Is(err, errorapp.ErrorBadVerificationToken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "verification token is invalid, expired or already used"}`),
+			http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorEmailTaken) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "email has been verified by another user"}`), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in verifying email: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, []byte(`{"result":"success"}`), http.StatusOK)
+}
+
+func writeEmailStatus(logger *zap.SugaredLogger, w http.ResponseWriter, emailStatus *entity.EmailStatus, statusCode int) {
+	emailStatusJSON, err := json.Marshal(emailStatus)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding email status: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, emailStatusJSON, statusCode)
+}
+
+// StartOIDCLogin returns the page of the identity provider the frontend sends the user to, the provider
+// redirects the user back to OIDCCallback.
+func (uh *UserHandler) StartOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	uh.writeOIDCLoginURL(logger, w, r, mux.Vars(r)["PROVIDER"], 0)
+}
+
+// LinkIdentity is StartOIDCLogin for the logged in user, the identity is linked to the user instead
+// of logging in, so the user can log in with the provider after.
+func (uh *UserHandler) LinkIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	uh.writeOIDCLoginURL(logger, w, r, mux.Vars(r)["PROVIDER"], user.ID)
+}
+
+func (uh *UserHandler) writeOIDCLoginURL(logger *zap.SugaredLogger, w http.ResponseWriter, r *http.Request, provider string,
+	linkUserID uint64) {
+	loginURL, binding, err := uh.UserUseCases.StartOIDCLogin(provider, linkUserID, logger)
+	if errors.Is(err, errorapp.ErrorNoIdentityProvider) {
+		errText := fmt.Sprintf(`{"message": "no identity provider %q"}`, provider)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in starting login with identity provider: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	loginURLJSON, err := json.Marshal(map[string]string{"url": loginURL})
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding login url: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	setOIDCBindingCookie(w, r, binding, oidcBindingMaxAge)
+	delivery.WriteResponse(logger, w, loginURLJSON, http.StatusOK)
+}
+
+// setOIDCBindingCookie sets the binding of the login, the empty binding with a negative maxAge removes it.
+// The cookie is sent on the top level redirect from the identity provider, but not on the cross site requests.
+func setOIDCBindingCookie(w http.ResponseWriter, r *http.Request, binding string, maxAge int) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcBindingCookie,
+		Value:    binding,
+		Path:     oidcBindingCookiePath,
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+// OIDCCallback is where the identity provider redirects the user with the authorization code,
+// the user gets the tokens as after the usual login. The callback is accepted only from the browser that
+// has started the login, the linking of the identity only in a session of the user who has started it.
+func (uh *UserHandler) OIDCCallback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	var callerUserID uint64
+	if caller, ok := ctx.Value(middleware.MyUserKey).(*entity.User); ok {
+		callerUserID = caller.ID
+	}
+	bindingCookie, err := r.Cookie(oidcBindingCookie)
+	if err != nil || bindingCookie.Value == "" {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "login is started in another browser, start it again"}`),
+			http.StatusBadRequest)
+		return
+	}
+	// the login is finished once whatever the result is
+	setOIDCBindingCookie(w, r, "", -1)
+	query := r.URL.Query()
+	if query.Get("error") != "" {
+		errText := fmt.Sprintf(`{"message": "identity provider refused the login: %s"}`, query.Get("error"))
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusUnauthorized)
+		return
+	}
+	if query.Get("code") == "" || query.Get("state") == "" {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "code and state are required"}`), http.StatusBadRequest)
+		return
+	}
+	loggedInUser, err := uh.UserUseCases.FinishOIDCLogin(mux.Vars(r)["PROVIDER"], query.Get("code"), query.Get("state"),
+		bindingCookie.Value, callerUserID, logger)
+	if errors.Is(err, errorapp.ErrorBadOIDCState) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "login is unknown or expired, start it again"}`), http.StatusBadRequest)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorOIDCLoginFailed) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "identity provider did not confirm the login"}`), http.StatusUnauthorized)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorIdentityLinked) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "identity is linked to another user"}`), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, errorapp.ErrorPermissionDenied) {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "identity is linked only by the user who started the linking"}`),
+			http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in login with identity provider: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	uh.HandleGetToken(w, r, loggedInUser, logger)
+}
+
+func (uh *UserHandler) SetProfilePrivacy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger, err := middleware.GetLoggerFromContext(ctx)
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	user, ok := ctx.Value(middleware.MyUserKey).(*entity.User)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+		return
+	}
+	privacyDTO := &dto.PrivacyDTO{}
+	if !readValidatedDTO(logger, w, r, privacyDTO) {
+		return
+	}
+	err = uh.UserUseCases.SetProfilePrivacy(user.ID, *privacyDTO.Private, logger)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	respText := fmt.Sprintf(`{"private": %t}`, *privacyDTO.Private)
+	delivery.WriteResponse(logger, w, []byte(respText), http.StatusOK)
+}
+
+func (uh *UserHandler) GetUserRoles(w http.ResponseWriter, r *http.Request) {
+	uh.handleRoles(w, r, uh.UserUseCases.GetUserRoles)
+}
+
+// GrantRole gives the role to the user, the user gets its permissions with the next login or token refresh.
+// The critic role is given only to a user with a verified email.
+func (uh *UserHandler) GrantRole(w http.ResponseWriter, r *http.Request) {
+	uh.handleRoles(w, r, func(token string, userID uint64, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+		return uh.UserUseCases.GrantRole(token, userID, mux.Vars(r)["ROLE"], logger)
+	})
+}
+
+// RevokeRole takes the role from the user and ends all the sessions of the user, so the role stops working at once.
+func (uh *UserHandler) RevokeRole(w http.ResponseWriter, r *http.Request) {
+	uh.handleRoles(w, r, func(token string, userID uint64, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+		return uh.UserUseCases.RevokeRole(token, userID, mux.Vars(r)["ROLE"], logger)
+	})
+}
+
+// handleRoles makes the call about the roles of the user from the path and writes the roles the user has after it.
+func (uh *UserHandler) handleRoles(w http.ResponseWriter, r *http.Request,
+	call func(token string, userID uint64, logger *zap.SugaredLogger) (*entity.UserRoles, error)) {
+	logger, err := middleware.GetLoggerFromContext(r.Context())
+	if err != nil {
+		log.Printf("can not get logger from context: %s", err)
+		middleware.WriteNoLoggerResponse(w)
+	}
+	userID, err := strconv.ParseUint(mux.Vars(r)["USER_ID"], 10, 64)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "bad format of user id: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusBadRequest)
+		return
+	}
+	token, ok := r.Context().Value(middleware.MyTokenKey).(string)
+	if !ok {
+		delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to token"}`), http.StatusInternalServerError)
+		return
+	}
+	userRoles, err := call(token, userID, logger)
+	switch {
+	case errors.Is(err, errorapp.ErrorPermissionDenied):
+		delivery.WriteResponse(logger, w, []byte(`{"message": "no permission for this action"}`), http.StatusForbidden)
+		return
+	case errors.Is(err, errorapp.ErrorNoUser):
+		errText := fmt.Sprintf(`{"message": "no user with id: %d"}`, userID)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusNotFound)
+		return
+	case errors.Is(err, errorapp.ErrorUnknownRole):
+		delivery.WriteResponse(logger, w, []byte(`{"message": "no such role"}`), http.StatusUnprocessableEntity)
+		return
+	case errors.Is(err, errorapp.ErrorLastAdmin):
+		delivery.WriteResponse(logger, w, []byte(`{"message": "last admin can not lose the admin role"}`), http.StatusConflict)
+		return
+	case errors.Is(err, errorapp.ErrorEmailNotVerified):
+		delivery.WriteResponse(logger, w, []byte(`{"message": "user has no verified email"}`), http.StatusConflict)
+		return
+	case err != nil:
+		errText := fmt.Sprintf(`{"message": "internal server error: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	userRolesJSON, err := json.Marshal(userRoles)
+	if err != nil {
+		errText := fmt.Sprintf(`{"message": "error in coding roles: %s"}`, err)
+		delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+		return
+	}
+	delivery.WriteResponse(logger, w, userRolesJSON, http.StatusOK)
+}
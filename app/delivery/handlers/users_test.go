@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 	"io"
 	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
 	"kinopoisk/app/middleware"
 	userusecase "kinopoisk/app/users/usecase"
 	"net/http"
@@ -126,7 +128,7 @@ func TestLogin(t *testing.T) {
 		Username: "some_username",
 	}
 	testUseCase.EXPECT().Login("some_username", "aaaaaaaa", logger).Return(loggedInUser, nil)
-	testUseCase.EXPECT().CreateSession(loggedInUser, logger).Return("", fmt.Errorf("error"))
+	testUseCase.EXPECT().CreateSession(loggedInUser, gomock.Any(), logger).Return(nil, fmt.Errorf("error"))
 	request = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username":"some_username", "password":"aaaaaaaa"}`))
 	ctx = request.Context()
 	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
@@ -147,7 +149,11 @@ func TestLogin(t *testing.T) {
 	}
 
 	testUseCase.EXPECT().Login("some_username", "aaaaaaaa", logger).Return(loggedInUser, nil)
-	testUseCase.EXPECT().CreateSession(loggedInUser, logger).Return("some_token", nil)
+	testUseCase.EXPECT().CreateSession(loggedInUser, gomock.Any(), logger).Return(&entity.TokenPair{
+		AccessToken:  "some_token",
+		RefreshToken: "some_refresh_token",
+		ExpiresIn:    900,
+	}, nil)
 	request = httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"username":"some_username", "password":"aaaaaaaa"}`))
 	ctx = request.Context()
 	ctx = context.WithValue(ctx, middleware.MyLoggerKey, logger)
@@ -168,3 +174,641 @@ func TestLogin(t *testing.T) {
 	}
 
 }
+
+func TestRefreshTokens(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+
+	cases := []struct {
+		body         string
+		prepare      func()
+		expectedCode int
+	}{
+		// bad json
+		{body: `{"refresh_token": `, prepare: func() {}, expectedCode: http.StatusBadRequest},
+		// no refresh token
+		{body: `{}`, prepare: func() {}, expectedCode: http.StatusUnprocessableEntity},
+		// unknown or expired refresh token
+		{body: `{"refresh_token": "old"}`, prepare: func() {
+			testUseCase.EXPECT().RefreshTokens("old", gomock.Any(), logger).Return(nil, errorapp.ErrorNoSession)
+		}, expectedCode: http.StatusUnauthorized},
+		// refresh token is replayed
+		{body: `{"refresh_token": "used"}`, prepare: func() {
+			testUseCase.EXPECT().RefreshTokens("used", gomock.Any(), logger).Return(nil, errorapp.ErrorRefreshTokenReused)
+		}, expectedCode: http.StatusUnauthorized},
+		// usecase returns error
+		{body: `{"refresh_token": "token"}`, prepare: func() {
+			testUseCase.EXPECT().RefreshTokens("token", gomock.Any(), logger).Return(nil, fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{body: `{"refresh_token": "token"}`, prepare: func() {
+			testUseCase.EXPECT().RefreshTokens("token", gomock.Any(), logger).Return(&entity.TokenPair{
+				AccessToken:  "new_token",
+				RefreshToken: "new_refresh_token",
+				ExpiresIn:    900,
+			}, nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodPost, "/token/refresh", strings.NewReader(testCase.body))
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		respWriter := httptest.NewRecorder()
+		testHandler.RefreshTokens(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestLogout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+
+	cases := []struct {
+		prepare      func()
+		expectedCode int
+	}{
+		// session has already ended
+		{prepare: func() {
+			testUseCase.EXPECT().DeleteSession("token", logger).Return(false, errorapp.ErrorNoSession)
+		}, expectedCode: http.StatusNotFound},
+		// usecase returns error
+		{prepare: func() {
+			testUseCase.EXPECT().DeleteSession("token", logger).Return(false, fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{prepare: func() {
+			testUseCase.EXPECT().DeleteSession("token", logger).Return(true, nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodPost, "/logout", nil)
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		ctx = context.WithValue(ctx, middleware.MyTokenKey, "token")
+		respWriter := httptest.NewRecorder()
+		testHandler.Logout(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestDeleteSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+	user := &entity.User{
+		ID:       1,
+		Username: "some_username",
+	}
+
+	cases := []struct {
+		prepare      func()
+		expectedCode int
+	}{
+		// no such session or it belongs to another user
+		{prepare: func() {
+			testUseCase.EXPECT().DeleteUserSession(uint64(1), "family", logger).Return(errorapp.ErrorNoSession)
+		}, expectedCode: http.StatusNotFound},
+		// usecase returns error
+		{prepare: func() {
+			testUseCase.EXPECT().DeleteUserSession(uint64(1), "family", logger).Return(fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{prepare: func() {
+			testUseCase.EXPECT().DeleteUserSession(uint64(1), "family", logger).Return(nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodDelete, "/me/sessions/family", nil)
+		request = mux.SetURLVars(request, map[string]string{"SESSION_ID": "family"})
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		ctx = context.WithValue(ctx, middleware.MyUserKey, user)
+		respWriter := httptest.NewRecorder()
+		testHandler.DeleteSession(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+
+	cases := []struct {
+		body         string
+		prepare      func()
+		expectedCode int
+	}{
+		// new password is too short
+		{body: `{"old_password": "aaaaaaaa", "new_password": "short"}`, prepare: func() {}, expectedCode: http.StatusUnprocessableEntity},
+		// old password is wrong
+		{body: `{"old_password": "aaaaaaaa", "new_password": "bbbbbbbb"}`, prepare: func() {
+			testUseCase.EXPECT().ChangePassword("token", "aaaaaaaa", "bbbbbbbb", logger).Return(errorapp.ErrorWrongPassword)
+		}, expectedCode: http.StatusForbidden},
+		// usecase returns error
+		{body: `{"old_password": "aaaaaaaa", "new_password": "bbbbbbbb"}`, prepare: func() {
+			testUseCase.EXPECT().ChangePassword("token", "aaaaaaaa", "bbbbbbbb", logger).Return(fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{body: `{"old_password": "aaaaaaaa", "new_password": "bbbbbbbb"}`, prepare: func() {
+			testUseCase.EXPECT().ChangePassword("token", "aaaaaaaa", "bbbbbbbb", logger).Return(nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodPut, "/me/password", strings.NewReader(testCase.body))
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		ctx = context.WithValue(ctx, middleware.MyTokenKey, "token")
+		respWriter := httptest.NewRecorder()
+		testHandler.ChangePassword(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+
+	cases := []struct {
+		body         string
+		prepare      func()
+		expectedCode int
+	}{
+		// no token
+		{body: `{"new_password": "bbbbbbbb"}`, prepare: func() {}, expectedCode: http.StatusUnprocessableEntity},
+		// token is expired or used
+		{body: `{"token": "reset", "new_password": "bbbbbbbb"}`, prepare: func() {
+			testUseCase.EXPECT().ResetPassword("reset", "bbbbbbbb", logger).Return(errorapp.ErrorBadResetToken)
+		}, expectedCode: http.StatusBadRequest},
+		// usecase returns error
+		{body: `{"token": "reset", "new_password": "bbbbbbbb"}`, prepare: func() {
+			testUseCase.EXPECT().ResetPassword("reset", "bbbbbbbb", logger).Return(fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{body: `{"token": "reset", "new_password": "bbbbbbbb"}`, prepare: func() {
+			testUseCase.EXPECT().ResetPassword("reset", "bbbbbbbb", logger).Return(nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodPost, "/password/reset", strings.NewReader(testCase.body))
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		respWriter := httptest.NewRecorder()
+		testHandler.ResetPassword(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestRegister(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+	newUser := &entity.User{
+		ID:       1,
+		Username: "some_username",
+	}
+
+	cases := []struct {
+		body         string
+		prepare      func()
+		expectedCode int
+	}{
+		// no email
+		{body: `{"username": "some_username", "password": "aaaaaaaa"}`, prepare: func() {}, expectedCode: http.StatusUnauthorized},
+		// bad email
+		{body: `{"username": "some_username", "password": "aaaaaaaa", "email": "not_email"}`, prepare: func() {},
+			expectedCode: http.StatusUnauthorized},
+		// user already exists
+		{body: `{"username": "some_username", "password": "aaaaaaaa", "email": "user@mail.ru"}`, prepare: func() {
+			testUseCase.EXPECT().Register("some_username", "aaaaaaaa", "user@mail.ru", logger).Return(nil, errorapp.ErrorUserExists)
+		}, expectedCode: http.StatusUnprocessableEntity},
+		// all is ok
+		{body: `{"username": "some_username", "password": "aaaaaaaa", "email": "user@mail.ru"}`, prepare: func() {
+			testUseCase.EXPECT().Register("some_username", "aaaaaaaa", "user@mail.ru", logger).Return(newUser, nil)
+			testUseCase.EXPECT().CreateSession(newUser, gomock.Any(), logger).Return(&entity.TokenPair{
+				AccessToken:  "some_token",
+				RefreshToken: "some_refresh_token",
+				ExpiresIn:    900,
+			}, nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(testCase.body))
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		respWriter := httptest.NewRecorder()
+		testHandler.Register(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+
+	cases := []struct {
+		body         string
+		prepare      func()
+		expectedCode int
+	}{
+		// no token
+		{body: `{}`, prepare: func() {}, expectedCode: http.StatusUnprocessableEntity},
+		// token is expired or used
+		{body: `{"token": "verify"}`, prepare: func() {
+			testUseCase.EXPECT().VerifyEmail("verify", logger).Return(errorapp.ErrorBadVerificationToken)
+		}, expectedCode: http.StatusBadRequest},
+		// email has been verified by another user
+		{body: `{"token": "verify"}`, prepare: func() {
+			testUseCase.EXPECT().VerifyEmail("verify", logger).Return(errorapp.ErrorEmailTaken)
+		}, expectedCode: http.StatusConflict},
+		// usecase returns error
+		{body: `{"token": "verify"}`, prepare: func() {
+			testUseCase.EXPECT().VerifyEmail("verify", logger).Return(fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{body: `{"token": "verify"}`, prepare: func() {
+			testUseCase.EXPECT().VerifyEmail("verify", logger).Return(nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodPost, "/email/verify", strings.NewReader(testCase.body))
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		respWriter := httptest.NewRecorder()
+		testHandler.VerifyEmail(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestStartOIDCLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+
+	cases := []struct {
+		prepare         func()
+		expectedCode    int
+		expectedBinding string
+	}{
+		// no such provider
+		{prepare: func() {
+			testUseCase.EXPECT().StartOIDCLogin("mock", uint64(0), logger).Return("", "", errorapp.ErrorNoIdentityProvider)
+		}, expectedCode: http.StatusNotFound},
+		// usecase returns error
+		{prepare: func() {
+			testUseCase.EXPECT().StartOIDCLogin("mock", uint64(0), logger).Return("", "", fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{prepare: func() {
+			testUseCase.EXPECT().StartOIDCLogin("mock", uint64(0), logger).Return("http://localhost:8090/authorize", "binding", nil)
+		}, expectedCode: http.StatusOK, expectedBinding: "binding"},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodGet, "/oidc/mock/login", nil)
+		request = mux.SetURLVars(request, map[string]string{"PROVIDER": "mock"})
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		respWriter := httptest.NewRecorder()
+		testHandler.StartOIDCLogin(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+		var bindingCookie *http.Cookie
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == "oidc_binding" {
+				bindingCookie = cookie
+			}
+		}
+		if testCase.expectedBinding == "" {
+			if bindingCookie != nil {
+				t.Errorf("binding cookie is set on error")
+			}
+			continue
+		}
+		if bindingCookie == nil || bindingCookie.Value != testCase.expectedBinding || !bindingCookie.HttpOnly ||
+			bindingCookie.SameSite != http.SameSiteLaxMode {
+			t.Errorf("expected http only binding cookie %q, got %+v", testCase.expectedBinding, bindingCookie)
+		}
+	}
+}
+
+func TestOIDCCallback(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+	loggedInUser := &entity.User{
+		ID:       1,
+		Username: "alice",
+	}
+
+	cases := []struct {
+		query        string
+		binding      string
+		caller       *entity.User
+		prepare      func()
+		expectedCode int
+	}{
+		// login is started in another browser
+		{query: "code=code&state=state", prepare: func() {}, expectedCode: http.StatusBadRequest},
+		// provider refused the login
+		{query: "error=access_denied&state=state", binding: "binding", prepare: func() {}, expectedCode: http.StatusUnauthorized},
+		// no state
+		{query: "code=code", binding: "binding", prepare: func() {}, expectedCode: http.StatusBadRequest},
+		// state is unknown or expired
+		{query: "code=code&state=state", binding: "binding", prepare: func() {
+			testUseCase.EXPECT().FinishOIDCLogin("mock", "code", "state", "binding", uint64(0), logger).Return(nil, errorapp.ErrorBadOIDCState)
+		}, expectedCode: http.StatusBadRequest},
+		// provider did not confirm the code
+		{query: "code=code&state=state", binding: "binding", prepare: func() {
+			testUseCase.EXPECT().FinishOIDCLogin("mock", "code", "state", "binding", uint64(0), logger).Return(nil, errorapp.ErrorOIDCLoginFailed)
+		}, expectedCode: http.StatusUnauthorized},
+		// identity is linked to another user
+		{query: "code=code&state=state", binding: "binding", prepare: func() {
+			testUseCase.EXPECT().FinishOIDCLogin("mock", "code", "state", "binding", uint64(0), logger).Return(nil, errorapp.ErrorIdentityLinked)
+		}, expectedCode: http.StatusConflict},
+		// identity is linked in a session of another user
+		{query: "code=code&state=state", binding: "binding", caller: &entity.User{ID: 2}, prepare: func() {
+			testUseCase.EXPECT().FinishOIDCLogin("mock", "code", "state", "binding", uint64(2), logger).
+				Return(nil, errorapp.ErrorPermissionDenied)
+		}, expectedCode: http.StatusForbidden},
+		// usecase returns error
+		{query: "code=code&state=state", binding: "binding", prepare: func() {
+			testUseCase.EXPECT().FinishOIDCLogin("mock", "code", "state", "binding", uint64(0), logger).Return(nil, fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{query: "code=code&state=state", binding: "binding", prepare: func() {
+			testUseCase.EXPECT().FinishOIDCLogin("mock", "code", "state", "binding", uint64(0), logger).Return(loggedInUser, nil)
+			testUseCase.EXPECT().CreateSession(loggedInUser, gomock.Any(), logger).Return(&entity.TokenPair{
+				AccessToken:  "access",
+				RefreshToken: "refresh",
+				ExpiresIn:    900,
+			}, nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodGet, "/oidc/mock/callback?"+testCase.query, nil)
+		request = mux.SetURLVars(request, map[string]string{"PROVIDER": "mock"})
+		if testCase.binding != "" {
+			request.AddCookie(&http.Cookie{Name: "oidc_binding", Value: testCase.binding})
+		}
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		if testCase.caller != nil {
+			ctx = context.WithValue(ctx, middleware.MyUserKey, testCase.caller)
+		}
+		respWriter := httptest.NewRecorder()
+		testHandler.OIDCCallback(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestGrantRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+
+	cases := []struct {
+		userID       string
+		role         string
+		prepare      func()
+		expectedCode int
+	}{
+		// bad user id
+		{userID: "bad", role: "moderator", prepare: func() {}, expectedCode: http.StatusBadRequest},
+		// no permission in the service
+		{userID: "1", role: "moderator", prepare: func() {
+			testUseCase.EXPECT().GrantRole("token", uint64(1), "moderator", logger).Return(nil, errorapp.ErrorPermissionDenied)
+		}, expectedCode: http.StatusForbidden},
+		// no such user
+		{userID: "1", role: "moderator", prepare: func() {
+			testUseCase.EXPECT().GrantRole("token", uint64(1), "moderator", logger).Return(nil, errorapp.ErrorNoUser)
+		}, expectedCode: http.StatusNotFound},
+		// no such role
+		{userID: "1", role: "moderator", prepare: func() {
+			testUseCase.EXPECT().GrantRole("token", uint64(1), "moderator", logger).Return(nil, errorapp.ErrorUnknownRole)
+		}, expectedCode: http.StatusUnprocessableEntity},
+		// the critic role for a user without a verified email
+		{userID: "1", role: "critic", prepare: func() {
+			testUseCase.EXPECT().GrantRole("token", uint64(1), "critic", logger).Return(nil, errorapp.ErrorEmailNotVerified)
+		}, expectedCode: http.StatusConflict},
+		// usecase returns error
+		{userID: "1", role: "moderator", prepare: func() {
+			testUseCase.EXPECT().GrantRole("token", uint64(1), "moderator", logger).Return(nil, fmt.Errorf("error"))
+		}, expectedCode: http.StatusInternalServerError},
+		// all is ok
+		{userID: "1", role: "moderator", prepare: func() {
+			testUseCase.EXPECT().GrantRole("token", uint64(1), "moderator", logger).Return(&entity.UserRoles{
+				UserID:      1,
+				Roles:       []string{"moderator"},
+				Permissions: []string{"reviews:moderate"},
+			}, nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodPut, "/admin/user/"+testCase.userID+"/roles/"+testCase.role, nil)
+		request = mux.SetURLVars(request, map[string]string{"USER_ID": testCase.userID, "ROLE": testCase.role})
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		ctx = context.WithValue(ctx, middleware.MyTokenKey, "token")
+		respWriter := httptest.NewRecorder()
+		testHandler.GrantRole(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
+
+func TestRevokeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	logger := zap.NewNop().Sugar()
+
+	testUseCase := userusecase.NewMockUserUseCase(ctrl)
+	testHandler := NewUserHandler(testUseCase)
+
+	cases := []struct {
+		prepare      func()
+		expectedCode int
+	}{
+		// the last admin
+		{prepare: func() {
+			testUseCase.EXPECT().RevokeRole("token", uint64(1), "admin", logger).Return(nil, errorapp.ErrorLastAdmin)
+		}, expectedCode: http.StatusConflict},
+		// all is ok
+		{prepare: func() {
+			testUseCase.EXPECT().RevokeRole("token", uint64(1), "admin", logger).Return(&entity.UserRoles{UserID: 1}, nil)
+		}, expectedCode: http.StatusOK},
+	}
+	for _, testCase := range cases {
+		testCase.prepare()
+		request := httptest.NewRequest(http.MethodDelete, "/admin/user/1/roles/admin", nil)
+		request = mux.SetURLVars(request, map[string]string{"USER_ID": "1", "ROLE": "admin"})
+		ctx := context.WithValue(request.Context(), middleware.MyLoggerKey, logger)
+		ctx = context.WithValue(ctx, middleware.MyTokenKey, "token")
+		respWriter := httptest.NewRecorder()
+		testHandler.RevokeRole(respWriter, request.WithContext(ctx))
+		resp := respWriter.Result()
+		_, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("unable to read response body")
+			return
+		}
+		err = resp.Body.Close()
+		if err != nil {
+			t.Fatalf("failed to close response body")
+		}
+		if resp.StatusCode != testCase.expectedCode {
+			t.Errorf("expected status %d, got status %d", testCase.expectedCode, resp.StatusCode)
+		}
+	}
+}
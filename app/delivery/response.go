@@ -15,3 +15,14 @@ func WriteResponse(logger *zap.SugaredLogger, w http.ResponseWriter, dataJSON []
 		logger.Errorf("error in writing response body: %s", err)
 	}
 }
+
+func WriteCalendarResponse(logger *zap.SugaredLogger, w http.ResponseWriter, calendar []byte, fileName string) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="`+fileName+`"`)
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	_, err := w.Write(calendar)
+	if err != nil {
+		logger.Errorf("error in writing response body: %s", err)
+	}
+}
@@ -6,13 +6,98 @@ type (
 	AuthRequestDTO struct {
 		Password string `json:"password" valid:"required,length(8|255)"`
 		Username string `json:"username" valid:"required,matches(^[a-zA-Z0-9_]+$)"`
+		// Email is required in register and ignored in login, it gets the verification and the password reset letters
+		Email string `json:"email" valid:"optional,email,length(3|255)"`
 	}
 	AuthResponseDTO struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	RefreshTokenDTO struct {
+		RefreshToken string `json:"refresh_token" valid:"required"`
+	}
+	EmailDTO struct {
+		Email string `json:"email" valid:"required,email,length(3|255)"`
+	}
+	EmailVerificationDTO struct {
+		Token string `json:"token" valid:"required"`
+	}
+	PasswordChangeDTO struct {
+		OldPassword string `json:"old_password" valid:"required"`
+		NewPassword string `json:"new_password" valid:"required,length(8|255)"`
+	}
+	PasswordForgotDTO struct {
+		Email string `json:"email" valid:"required,email"`
+	}
+	PasswordResetDTO struct {
+		Token       string `json:"token" valid:"required"`
+		NewPassword string `json:"new_password" valid:"required,length(8|255)"`
+	}
+	CalendarTokenDTO struct {
 		Token string `json:"token"`
+		URL   string `json:"url"`
 	}
 	ReviewDTO struct {
-		Mark    uint32 `valid:"int,range(1|10),required"`
-		Comment string `valid:"optional,length(10|10000)"`
+		Mark      uint32 `valid:"int,range(1|10),required"`
+		Comment   string `valid:"optional,length(10|10000)"`
+		IsSpoiler bool   `valid:"optional"`
+		// sub-scores by the rating criteria, 0 leaves the criterion not rated
+		Story   uint32 `valid:"optional,range(1|10)"`
+		Acting  uint32 `valid:"optional,range(1|10)"`
+		Visuals uint32 `valid:"optional,range(1|10)"`
+		Music   uint32 `valid:"optional,range(1|10)"`
+	}
+	VoteDTO struct {
+		Helpful *bool `json:"helpful"`
+	}
+	CommentDTO struct {
+		Text     string `json:"text" valid:"required,length(1|5000)"`
+		ParentID uint64 `json:"parent_id" valid:"optional"`
+	}
+	ReportDTO struct {
+		Reason string `json:"reason" valid:"required,length(3|1000)"`
+	}
+	SpoilerFlagDTO struct {
+		Spoiler *bool `json:"spoiler"`
+	}
+	PrivacyDTO struct {
+		Private *bool `json:"private"`
+	}
+	ModerationDTO struct {
+		Action string `json:"action" valid:"required,in(approve|reject|hide)"`
+		Reason string `json:"reason" valid:"optional,length(1|1000)"`
+	}
+	PageQueryDTO struct {
+		PageSize  uint32 `valid:"optional,range(1|100)"`
+		PageToken string `valid:"optional"`
+	}
+	ReviewsQueryDTO struct {
+		Sort      string `valid:"optional,in(newest|oldest|highest_mark|lowest_mark|most_helpful)"`
+		PageSize  uint32 `valid:"optional,range(1|100)"`
+		PageToken string `valid:"optional"`
+
+		RevealSpoilers bool `valid:"optional"`
+		CriticsOnly    bool `valid:"optional"`
+	}
+	FilmsQueryDTO struct {
+		Genre    string `valid:"optional"`
+		Country  string `valid:"optional"`
+		Producer string `valid:"optional"`
+		Sort     string `valid:"optional,in(story|acting|visuals|music)"`
+		// MinRatings are the lowest average marks of the films by the rating criteria
+		MinRatings map[string]float64 `valid:"-"`
+	}
+	ChartQueryDTO struct {
+		Genre   string `valid:"optional,length(1|255)"`
+		Country string `valid:"optional,length(1|255)"`
+		Decade  string `valid:"optional,matches(^[0-9]{3}0$)"`
+	}
+	UserReviewsQueryDTO struct {
+		PageSize  uint32 `valid:"optional,range(1|100)"`
+		PageToken string `valid:"optional"`
+
+		RevealSpoilers bool `valid:"optional"`
 	}
 )
 
@@ -21,11 +106,131 @@ func (authReqDTO *AuthRequestDTO) Validate() []string {
 	return collectErrors(err)
 }
 
+// ValidateRegister checks the register form, which unlike the login form must have the email.
+func (authReqDTO *AuthRequestDTO) ValidateRegister() []string {
+	validationErrors := authReqDTO.Validate()
+	if authReqDTO.Email == "" {
+		validationErrors = append(validationErrors, "email: non zero value required")
+	}
+	return validationErrors
+}
+
+func (emailDTO *EmailDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(emailDTO)
+	return collectErrors(err)
+}
+
+func (emailVerificationDTO *EmailVerificationDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(emailVerificationDTO)
+	return collectErrors(err)
+}
+
+func (refreshTokenDTO *RefreshTokenDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(refreshTokenDTO)
+	return collectErrors(err)
+}
+
+func (passwordChangeDTO *PasswordChangeDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(passwordChangeDTO)
+	return collectErrors(err)
+}
+
+func (passwordForgotDTO *PasswordForgotDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(passwordForgotDTO)
+	return collectErrors(err)
+}
+
+func (passwordResetDTO *PasswordResetDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(passwordResetDTO)
+	return collectErrors(err)
+}
+
 func (reviewDTO *ReviewDTO) Validate() []string {
 	_, err := govalidator.ValidateStruct(reviewDTO)
 	return collectErrors(err)
 }
 
+// Validate checks the vote by hand: govalidator treats false as a missing required value.
+func (voteDTO *VoteDTO) Validate() []string {
+	if voteDTO.Helpful == nil {
+		return []string{"helpful: value required"}
+	}
+	return []string{}
+}
+
+func (commentDTO *CommentDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(commentDTO)
+	return collectErrors(err)
+}
+
+func (reportDTO *ReportDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(reportDTO)
+	return collectErrors(err)
+}
+
+// Validate checks the flag by hand: govalidator treats false as a missing required value.
+func (spoilerFlagDTO *SpoilerFlagDTO) Validate() []string {
+	if spoilerFlagDTO.Spoiler == nil {
+		return []string{"spoiler: value required"}
+	}
+	return []string{}
+}
+
+func (privacyDTO *PrivacyDTO) Validate() []string {
+	if privacyDTO.Private == nil {
+		return []string{"private: value required"}
+	}
+	return []string{}
+}
+
+func (moderationDTO *ModerationDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(moderationDTO)
+	return collectErrors(err)
+}
+
+func (pageQueryDTO *PageQueryDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(pageQueryDTO)
+	return collectErrors(err)
+}
+
+func (reviewsQueryDTO *ReviewsQueryDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(reviewsQueryDTO)
+	return collectErrors(err)
+}
+
+func (userReviewsQueryDTO *UserReviewsQueryDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(userReviewsQueryDTO)
+	return collectErrors(err)
+}
+
+// Validate checks the min ratings by hand: govalidator does not validate map values.
+func (filmsQueryDTO *FilmsQueryDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(filmsQueryDTO)
+	validationErrors := collectErrors(err)
+	for criterion, minRating := range filmsQueryDTO.MinRatings {
+		if minRating < 0 || minRating > 10 {
+			validationErrors = append(validationErrors, "min_"+criterion+": must be from 0 to 10")
+		}
+	}
+	return validationErrors
+}
+
+// Validate also allows at most one of the chart variants.
+func (chartQueryDTO *ChartQueryDTO) Validate() []string {
+	_, err := govalidator.ValidateStruct(chartQueryDTO)
+	validationErrors := collectErrors(err)
+	filtersNumber := 0
+	for _, filter := range []string{chartQueryDTO.Genre, chartQueryDTO.Country, chartQueryDTO.Decade} {
+		if filter != "" {
+			filtersNumber++
+		}
+	}
+	if filtersNumber > 1 {
+		validationErrors = append(validationErrors, "only one of genre, country and decade can be set")
+	}
+	return validationErrors
+}
+
 func collectErrors(err error) []string {
 	validationErrors := make([]string, 0)
 	if err == nil {
@@ -12,4 +12,48 @@ type Film struct {
 	SumMark       uint64
 	NumOfMarks    uint64
 	Rating        float64
+	// CriteriaRatings are the average marks of the film by the rated criteria
+	CriteriaRatings map[string]float64
+	CriticScore     *CriticScore
+}
+
+// CriticScore is the score of the film by the reviews of critics, shown next to the audience Rating.
+type CriticScore struct {
+	NumOfMarks uint64
+	Rating     float64
+	// FreshPercent is the percent of the critic marks which are not lower than 6
+	FreshPercent float64
+}
+
+type FilmStats struct {
+	FilmID         uint64
+	NumOfMarks     uint64
+	Rating         float64
+	WeightedRating float64
+	Distribution   map[uint32]uint64
+	Median         float64
+	StdDev         float64
+	WithText       uint64
+	MarkOnly       uint64
+	WithTextShare  float64
+	ReviewsByMonth []*ReviewsMonth
+}
+
+type ReviewsMonth struct {
+	Month      string
+	NumReviews uint64
+}
+
+type ChartEntry struct {
+	Place          uint32
+	Film           *Film
+	WeightedRating float64
+}
+
+type Chart struct {
+	Chart          string
+	Scope          string
+	ScopeValue     string
+	MaterializedAt string
+	Films          []*ChartEntry
 }
@@ -1,8 +1,101 @@
 package entity
 
 type Review struct {
-	ID      uint64
-	Mark    uint32
-	Comment string
-	Author  *User
+	ID        uint64
+	FilmID    uint64
+	Mark      uint32
+	Comment   string
+	Author    *User
+	CreatedAt string
+	UpdatedAt string
+	IsEdited  bool
+	Status    string
+
+	IsSpoiler      bool
+	SpoilersMasked bool
+
+	HelpfulVotes   uint32
+	UnhelpfulVotes uint32
+
+	SubScores *SubScores
+	ByCritic  bool
+}
+
+// RatingCriteria are the criteria a review can rate the film by besides the overall mark.
+var RatingCriteria = []string{"story", "acting", "visuals", "music"}
+
+// SubScores are the marks of the review by the rating criteria, 0 means the criterion is not rated.
+type SubScores struct {
+	Story   uint32
+	Acting  uint32
+	Visuals uint32
+	Music   uint32
+}
+
+type UserProfile struct {
+	User          *User
+	JoinedAt      string
+	IsPrivate     bool
+	ReviewsCount  uint32
+	AverageMark   float64
+	RecentReviews []*Review
+}
+
+type ReviewRevision struct {
+	Mark       uint32
+	Comment    string
+	CreatedAt  string
+	ReplacedAt string
+}
+
+type ReviewHistory struct {
+	Review    *Review
+	Revisions []*ReviewRevision
+}
+
+type ReviewsPage struct {
+	Reviews       []*Review
+	NextPageToken string
+}
+
+type ReviewReport struct {
+	Author    *User
+	Reason    string
+	CreatedAt string
+}
+
+type ModerationQueueItem struct {
+	Review  *Review
+	Reports []*ReviewReport
+}
+
+type ModerationQueue struct {
+	Items         []*ModerationQueueItem
+	NextPageToken string
+}
+
+type ModerationLogEntry struct {
+	Moderator *User
+	Action    string
+	OldStatus string
+	NewStatus string
+	Reason    string
+	CreatedAt string
+}
+
+type Comment struct {
+	ID        uint64
+	ParentID  uint64
+	Author    *User
+	Text      string
+	Depth     uint32
+	IsDeleted bool
+	CreatedAt string
+	UpdatedAt string
+	Replies   []*Comment
+}
+
+type CommentsPage struct {
+	Comments      []*Comment
+	NextPageToken string
 }
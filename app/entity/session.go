@@ -4,3 +4,27 @@ type Session struct {
 	ID   string
 	User *User
 }
+
+// ClientInfo is where the user logs in from, Device is guessed from the user agent.
+type ClientInfo struct {
+	Device    string
+	IP        string
+	UserAgent string
+}
+
+// UserSession is one login of the user as it is shown to the user, ID is not a token.
+type UserSession struct {
+	ID         string
+	Device     string
+	IP         string
+	UserAgent  string
+	CreatedAt  string
+	LastSeenAt string
+	Current    bool
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64
+}
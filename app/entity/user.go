@@ -1,6 +1,23 @@
 package entity
 
+// User has the roles and the permissions only as the user of the session, they are not shown with the reviews.
 type User struct {
-	ID       uint64
-	Username string
+	ID          uint64
+	Username    string
+	Roles       []string `json:",omitempty"`
+	Permissions []string `json:",omitempty"`
+}
+
+// UserRoles are the roles of the user and the permissions they give.
+type UserRoles struct {
+	UserID      uint64
+	Roles       []string
+	Permissions []string
+}
+
+// EmailStatus is the verified email of the user and the new email waiting for the verification.
+type EmailStatus struct {
+	Email        string
+	Verified     bool
+	PendingEmail string
 }
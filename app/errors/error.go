@@ -8,4 +8,39 @@ var (
 	ErrorNoSession   = errors.New("no session with such id")
 	ErrorNoLogger    = errors.New("no logger in context")
 	ErrorNoRequestID = errors.New("no request id in logger")
+
+	ErrorNoUser         = errors.New("user with such id does not exist")
+	ErrorPrivateProfile = errors.New("profile of this user is private")
+
+	ErrorPermissionDenied = errors.New("user has no permission for this action")
+	ErrorUnknownRole      = errors.New("no such role")
+	ErrorLastAdmin        = errors.New("last admin can not lose the admin role")
+	ErrorEmailNotVerified = errors.New("user has no verified email")
+
+	ErrorRefreshTokenReused = errors.New("refresh token has already been used")
+	ErrorWrongPassword      = errors.New("wrong password")
+	ErrorBadResetToken      = errors.New("password reset token is invalid, expired or used")
+
+	ErrorEmailTaken           = errors.New("email is used by another user")
+	ErrorBadVerificationToken = errors.New("email verification token is invalid, expired or used")
+
+	ErrorNoIdentityProvider = errors.New("no identity provider with such name")
+	ErrorBadOIDCState       = errors.New("login with identity provider is unknown or expired")
+	ErrorOIDCLoginFailed    = errors.New("identity provider did not confirm the login")
+	ErrorIdentityLinked     = errors.New("identity is linked to another user")
+
+	ErrorNoCalendarToken = errors.New("no calendar feed with such token")
+	ErrorBadPageToken    = errors.New("bad page token")
+
+	ErrorNoReview  = errors.New("review with such id does not exist")
+	ErrorOwnReview = errors.New("user can not vote for own review")
+	ErrorNoVote    = errors.New("user has not voted for this review")
+
+	ErrorReportOwnReview = errors.New("user can not report own review")
+	ErrorAlreadyReported = errors.New("user has already reported this review")
+
+	ErrorNoComment       = errors.New("comment with such id does not exist")
+	ErrorNoParentComment = errors.New("there is no such comment to reply in this review")
+	ErrorCommentTooDeep  = errors.New("comment is nested too deep to reply")
+	ErrorCommentDeleted  = errors.New("comment has been deleted")
 )
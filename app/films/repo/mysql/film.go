@@ -6,19 +6,48 @@ import (
 	"go.uber.org/zap"
 	"kinopoisk/app/entity"
 	errorapp "kinopoisk/app/errors"
+	"sort"
 )
 
 type FilmRepo interface {
-	GetFilmsRepo(genre, country, producer string) ([]*entity.Film, error)
+	GetFilmsRepo(filter *FilmsFilter) ([]*entity.Film, error)
 	GetFilmByIDRepo(filmID uint64) (*entity.Film, error)
+	GetFilmCriteriaRatingsRepo(filmID uint64) (map[string]float64, error)
+	GetFilmCriticScoreRepo(filmID uint64) (*entity.CriticScore, error)
 	GetFilmsByActorRepo(ID uint64) ([]*entity.Film, error)
 	GetSoonFilmsRepo(date string) ([]*entity.Film, error)
 	GetFavouriteFilmsRepo(userID uint64) ([]*entity.Film, error)
+	GetFavouriteSoonFilmsRepo(userID uint64, date string) ([]*entity.Film, error)
 	AddFavouriteFilmRepo(userID, filmID uint64) (bool, error)
 	DeleteFavouriteFilmRepo(ID uint64) (bool, error)
 	GetFilmActorsRepo(filmID uint64) ([]*entity.Actor, error)
 	GetFilmGenresRepo(filmID uint64) ([]*entity.Genre, error)
 	GetFilmInFavourites(filmID, userID uint64) (uint64, error)
+	GetFilmStatsRepo(filmID uint64) (*FilmStatsData, error)
+	GetChartRepo(chart, scope, scopeValue string) ([]*entity.ChartEntry, error)
+	GetChartMaterializedAtRepo() (string, error)
+}
+
+// FilmsFilter selects the films, the empty fields are not used. SortCriterion orders the films
+// by the average mark of the criterion, the films without the marks go last.
+type FilmsFilter struct {
+	Genre              string
+	Country            string
+	Producer           string
+	SortCriterion      string
+	MinCriteriaRatings map[string]float64
+}
+
+// FilmStatsData holds the aggregates kept up to date by the rating service.
+type FilmStatsData struct {
+	NumOfMarks     uint64
+	SumMark        uint64
+	SumMarkSquares uint64
+	Rating         float64
+	WeightedRating float64
+	NumWithText    uint64
+	Distribution   map[uint32]uint64
+	ReviewsByMonth []*entity.ReviewsMonth
 }
 
 type FilmRepoMySQL struct {
@@ -33,23 +62,36 @@ func NewFilmRepoMySQL(db *sql.DB, logger *zap.SugaredLogger) *FilmRepoMySQL {
 	}
 }
 
-func (r *FilmRepoMySQL) GetFilmsRepo(genre, country, producer string) ([]*entity.Film, error) {
+func (r *FilmRepoMySQL) GetFilmsRepo(filter *FilmsFilter) ([]*entity.Film, error) {
 	var args []interface{}
 	query := "SELECT f.id, f.name, f.description, f.duration, f.min_age, f.country, f.producer_name, f.date_of_release, f.num_of_marks, f.rating from films f"
-	if genre != "" {
+	if filter.Genre != "" {
 		query += " INNER JOIN film_genres fg ON f.id = fg.film_id INNER JOIN genres g ON g.id = fg.genre_id WHERE g.name = ?"
-		args = append(args, genre)
+		args = append(args, filter.Genre)
 	} else {
 		query += " WHERE 1 = 1"
 	}
-	if country != "" {
-		args = append(args, country)
+	if filter.Country != "" {
+		args = append(args, filter.Country)
 		query += " AND f.country = ?"
 	}
-	if producer != "" {
-		args = append(args, producer)
+	if filter.Producer != "" {
+		args = append(args, filter.Producer)
 		query += " AND f.producer_name = ?"
 	}
+	criteria := make([]string, 0, len(filter.MinCriteriaRatings))
+	for criterion := range filter.MinCriteriaRatings {
+		criteria = append(criteria, criterion)
+	}
+	sort.Strings(criteria)
+	for _, criterion := range criteria {
+		args = append(args, criterion, filter.MinCriteriaRatings[criterion])
+		query += " AND f.id IN (SELECT film_id FROM film_criteria_ratings WHERE criterion = ? AND num_of_marks > 0 AND rating >= ?)"
+	}
+	if filter.SortCriterion != "" {
+		args = append(args, filter.SortCriterion)
+		query += " ORDER BY (SELECT rating FROM film_criteria_ratings WHERE film_id = f.id AND criterion = ? AND num_of_marks > 0) DESC, f.id"
+	}
 	rows, err := r.db.Query(query, args...)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -90,6 +132,37 @@ func (r *FilmRepoMySQL) GetFilmByIDRepo(filmID uint64) (*entity.Film, error) {
 	return film, nil
 }
 
+// GetFilmCriteriaRatingsRepo returns the average marks of the film by the criteria rated at least once.
+func (r *FilmRepoMySQL) GetFilmCriteriaRatingsRepo(filmID uint64) (map[string]float64, error) {
+	rows, err := r.db.Query("SELECT criterion, rating FROM film_criteria_ratings WHERE film_id = ? AND num_of_marks > 0", filmID)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+	criteriaRatings := make(map[string]float64)
+	for rows.Next() {
+		var criterion string
+		var rating float64
+		err = rows.Scan(&criterion, &rating)
+		if err != nil {
+			return nil, err
+		}
+		criteriaRatings[criterion] = rating
+	}
+	return criteriaRatings, rows.Err()
+}
+
+func (r *FilmRepoMySQL) GetFilmCriticScoreRepo(filmID uint64) (*entity.CriticScore, error) {
+	criticScore := &entity.CriticScore{}
+	err := r.db.
+		QueryRow("SELECT critic_num_of_marks, critic_rating, critic_fresh_percent FROM films WHERE id = ?", filmID).
+		Scan(&criticScore.NumOfMarks, &criticScore.Rating, &criticScore.FreshPercent)
+	if err != nil {
+		return nil, err
+	}
+	return criticScore, nil
+}
+
 func (r *FilmRepoMySQL) GetFilmsByActorRepo(id uint64) ([]*entity.Film, error) {
 	films := []*entity.Film{}
 	rows, err := r.db.Query(`SELECT f.id, f.name, f.description, f.duration, f.min_age, f.country, f.producer_name, f.date_of_release, f.num_of_marks, f.rating
@@ -169,6 +242,32 @@ func (r *FilmRepoMySQL) GetFavouriteFilmsRepo(userID uint64) ([]*entity.Film, er
 	return films, nil
 }
 
+func (r *FilmRepoMySQL) GetFavouriteSoonFilmsRepo(userID uint64, date string) ([]*entity.Film, error) {
+	films := []*entity.Film{}
+	rows, err := r.db.Query("SELECT f.id, f.name, f.description, f.duration, f.min_age, f.country, f.producer_name, f.date_of_release, f.num_of_marks, f.rating FROM films f JOIN favourite_films ff on f.id = ff.film_id WHERE ff.user_id = ? AND f.date_of_release > ?", userID, date)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	for rows.Next() {
+		film := &entity.Film{}
+		err = rows.Scan(&film.ID, &film.Name, &film.Description, &film.Duration, &film.MinAge, &film.Country, &film.ProducerName, &film.DateOfRelease, &film.NumOfMarks, &film.Rating)
+		if err != nil {
+			return nil, err
+		}
+		films = append(films, film)
+	}
+	return films, nil
+}
+
 func (r *FilmRepoMySQL) AddFavouriteFilmRepo(userID, filmID uint64) (bool, error) {
 	_, err := r.db.Exec(
 		"INSERT INTO favourite_films (`user_id`, `film_id`) VALUES (?, ?)",
@@ -251,3 +350,96 @@ func (r *FilmRepoMySQL) GetFilmInFavourites(filmID, userID uint64) (uint64, erro
 	}
 	return id, nil
 }
+
+// GetFilmStatsRepo returns nil if there is no film with such id.
+func (r *FilmRepoMySQL) GetFilmStatsRepo(filmID uint64) (*FilmStatsData, error) {
+	stats := &FilmStatsData{
+		Distribution:   make(map[uint32]uint64),
+		ReviewsByMonth: []*entity.ReviewsMonth{},
+	}
+	err := r.db.
+		QueryRow("SELECT f.num_of_marks, f.sum_mark, f.rating, f.weighted_rating, COALESCE(s.sum_mark_squares, 0), COALESCE(s.num_with_text, 0) FROM films f LEFT JOIN film_stats s ON f.id = s.film_id WHERE f.id = ?", filmID).
+		Scan(&stats.NumOfMarks, &stats.SumMark, &stats.Rating, &stats.WeightedRating, &stats.SumMarkSquares, &stats.NumWithText)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.db.Query("SELECT mark, num_of_marks FROM film_mark_distribution WHERE film_id = ? AND num_of_marks > 0", filmID)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var mark uint32
+		var numOfMarks uint64
+		err = rows.Scan(&mark, &numOfMarks)
+		if err != nil {
+			r.closeRows(rows)
+			return nil, err
+		}
+		stats.Distribution[mark] = numOfMarks
+	}
+	r.closeRows(rows)
+	rows, err = r.db.Query("SELECT DATE_FORMAT(month, '%Y-%m'), num_of_reviews FROM film_review_months WHERE film_id = ? AND num_of_reviews > 0 ORDER BY month", filmID)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+	for rows.Next() {
+		month := &entity.ReviewsMonth{}
+		err = rows.Scan(&month.Month, &month.NumReviews)
+		if err != nil {
+			return nil, err
+		}
+		stats.ReviewsByMonth = append(stats.ReviewsByMonth, month)
+	}
+	return stats, nil
+}
+
+// GetChartRepo reads the chart materialized by the rating service, films are ordered by place.
+func (r *FilmRepoMySQL) GetChartRepo(chart, scope, scopeValue string) ([]*entity.ChartEntry, error) {
+	rows, err := r.db.Query(
+		"SELECT c.place, c.weighted_rating, f.id, f.name, f.description, f.duration, f.min_age, f.country, f.producer_name, f.date_of_release, "+
+			"f.num_of_marks, f.rating FROM film_charts c JOIN films f ON f.id = c.film_id "+
+			"WHERE c.chart = ? AND c.scope = ? AND c.scope_value = ? ORDER BY c.place",
+		chart,
+		scope,
+		scopeValue,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer r.closeRows(rows)
+	entries := make([]*entity.ChartEntry, 0)
+	for rows.Next() {
+		entry := &entity.ChartEntry{
+			Film: &entity.Film{},
+		}
+		film := entry.Film
+		err = rows.Scan(&entry.Place, &entry.WeightedRating, &film.ID, &film.Name, &film.Description, &film.Duration, &film.MinAge,
+			&film.Country, &film.ProducerName, &film.DateOfRelease, &film.NumOfMarks, &film.Rating)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetChartMaterializedAtRepo returns an empty string if the charts have not been materialized yet.
+func (r *FilmRepoMySQL) GetChartMaterializedAtRepo() (string, error) {
+	var materializedAt sql.NullString
+	err := r.db.QueryRow("SELECT MAX(materialized_at) FROM film_charts").Scan(&materializedAt)
+	if err != nil {
+		return "", err
+	}
+	return materializedAt.String, nil
+}
+
+func (r *FilmRepoMySQL) closeRows(rows *sql.Rows) {
+	err := rows.Close()
+	if err != nil {
+		r.logger.Errorf("error in closing db rows")
+	}
+}
@@ -2,15 +2,19 @@ package filmusecase
 
 import (
 	"errors"
+	"kinopoisk/app/dto"
 	"kinopoisk/app/entity"
 	errorapp "kinopoisk/app/errors"
 	filmrepo "kinopoisk/app/films/repo/mysql"
+	"math"
 	"sync"
 	"time"
 )
 
+const maxMark = 10
+
 type FilmUseCase interface {
-	GetFilms(genre, country, producer string) ([]*entity.Film, error)
+	GetFilms(query *dto.FilmsQueryDTO) ([]*entity.Film, error)
 	GetFilmByID(filmID uint64) (*entity.Film, error)
 	GetFilmsByActor(ID uint64) ([]*entity.Film, error)
 	GetSoonFilms() ([]*entity.Film, error)
@@ -19,6 +23,8 @@ type FilmUseCase interface {
 	DeleteFavouriteFilm(userID, filmID uint64) (bool, error)
 	GetFilmActors(filmID uint64) ([]*entity.Actor, error)
 	GetFilmGenres(filmID uint64) ([]*entity.Genre, error)
+	GetFilmStats(filmID uint64) (*entity.FilmStats, error)
+	GetChart(chart string, query *dto.ChartQueryDTO) (*entity.Chart, error)
 }
 
 type FilmUseCaseStruct struct {
@@ -33,9 +39,16 @@ func NewFilmUseCaseStruct(filmRepo filmrepo.FilmRepo) *FilmUseCaseStruct {
 	}
 }
 
-func (f *FilmUseCaseStruct) GetFilms(genre, country, producer string) ([]*entity.Film, error) {
+func (f *FilmUseCaseStruct) GetFilms(query *dto.FilmsQueryDTO) ([]*entity.Film, error) {
+	filter := &filmrepo.FilmsFilter{
+		Genre:              query.Genre,
+		Country:            query.Country,
+		Producer:           query.Producer,
+		SortCriterion:      query.Sort,
+		MinCriteriaRatings: query.MinRatings,
+	}
 	f.mu.RLock()
-	films, err := f.FilmRepo.GetFilmsRepo(genre, country, producer)
+	films, err := f.FilmRepo.GetFilmsRepo(filter)
 	f.mu.RUnlock()
 	if err != nil {
 		return nil, err
@@ -53,6 +66,15 @@ func (f *FilmUseCaseStruct) GetFilmByID(filmID uint64) (*entity.Film, error) {
 	if film == nil {
 		return nil, nil
 	}
+	f.mu.RLock()
+	film.CriteriaRatings, err = f.FilmRepo.GetFilmCriteriaRatingsRepo(filmID)
+	if err == nil {
+		film.CriticScore, err = f.FilmRepo.GetFilmCriticScoreRepo(filmID)
+	}
+	f.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
 	return film, nil
 }
 
@@ -138,7 +160,7 @@ func (f *FilmUseCaseStruct) DeleteFavouriteFilm(userID, filmID uint64) (bool, er
 
 func (f *FilmUseCaseStruct) GetFilmActors(filmID uint64) ([]*entity.Actor, error) {
 	f.mu.RLock()
-	film, err := f.GetFilmByID(filmID)
+	film, err := f.FilmRepo.GetFilmByIDRepo(filmID)
 	f.mu.RUnlock()
 	if err != nil {
 		return nil, err
@@ -157,7 +179,7 @@ func (f *FilmUseCaseStruct) GetFilmActors(filmID uint64) ([]*entity.Actor, error
 
 func (f *FilmUseCaseStruct) GetFilmGenres(filmID uint64) ([]*entity.Genre, error) {
 	f.mu.RLock()
-	film, err := f.GetFilmByID(filmID)
+	film, err := f.FilmRepo.GetFilmByIDRepo(filmID)
 	f.mu.RUnlock()
 	if err != nil {
 		return nil, err
@@ -173,3 +195,96 @@ func (f *FilmUseCaseStruct) GetFilmGenres(filmID uint64) ([]*entity.Genre, error
 	}
 	return genres, nil
 }
+
+func (f *FilmUseCaseStruct) GetFilmStats(filmID uint64) (*entity.FilmStats, error) {
+	f.mu.RLock()
+	statsData, err := f.FilmRepo.GetFilmStatsRepo(filmID)
+	f.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if statsData == nil {
+		return nil, errorapp.ErrorNoFilm
+	}
+	stats := &entity.FilmStats{
+		FilmID:         filmID,
+		NumOfMarks:     statsData.NumOfMarks,
+		Rating:         statsData.Rating,
+		WeightedRating: statsData.WeightedRating,
+		Distribution:   make(map[uint32]uint64, maxMark),
+		Median:         getMarksMedian(statsData.Distribution),
+		WithText:       statsData.NumWithText,
+		ReviewsByMonth: statsData.ReviewsByMonth,
+	}
+	for mark := uint32(1); mark <= maxMark; mark++ {
+		stats.Distribution[mark] = statsData.Distribution[mark]
+	}
+	if statsData.NumOfMarks == 0 {
+		return stats, nil
+	}
+	numOfMarks := float64(statsData.NumOfMarks)
+	mean := float64(statsData.SumMark) / numOfMarks
+	variance := float64(statsData.SumMarkSquares)/numOfMarks - mean*mean
+	if variance > 0 {
+		stats.StdDev = math.Sqrt(variance)
+	}
+	if statsData.NumOfMarks > statsData.NumWithText {
+		stats.MarkOnly = statsData.NumOfMarks - statsData.NumWithText
+	}
+	stats.WithTextShare = float64(statsData.NumWithText) / numOfMarks
+	return stats, nil
+}
+
+func (f *FilmUseCaseStruct) GetChart(chart string, query *dto.ChartQueryDTO) (*entity.Chart, error) {
+	scope, scopeValue := "all", ""
+	switch {
+	case query.Genre != "":
+		scope, scopeValue = "genre", query.Genre
+	case query.Country != "":
+		scope, scopeValue = "country", query.Country
+	case query.Decade != "":
+		scope, scopeValue = "decade", query.Decade
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	entries, err := f.FilmRepo.GetChartRepo(chart, scope, scopeValue)
+	if err != nil {
+		return nil, err
+	}
+	materializedAt, err := f.FilmRepo.GetChartMaterializedAtRepo()
+	if err != nil {
+		return nil, err
+	}
+	return &entity.Chart{
+		Chart:          chart,
+		Scope:          scope,
+		ScopeValue:     scopeValue,
+		MaterializedAt: materializedAt,
+		Films:          entries,
+	}, nil
+}
+
+// getMarksMedian finds the median by walking the mark distribution instead of sorting all marks.
+func getMarksMedian(distribution map[uint32]uint64) float64 {
+	var numOfMarks uint64
+	for _, marksCount := range distribution {
+		numOfMarks += marksCount
+	}
+	if numOfMarks == 0 {
+		return 0
+	}
+	lowerPosition, upperPosition := (numOfMarks-1)/2, numOfMarks/2
+	var lower, upper uint32
+	var passed uint64
+	for mark := uint32(1); mark <= maxMark; mark++ {
+		passed += distribution[mark]
+		if lower == 0 && passed > lowerPosition {
+			lower = mark
+		}
+		if passed > upperPosition {
+			upper = mark
+			break
+		}
+	}
+	return float64(lower+upper) / 2
+}
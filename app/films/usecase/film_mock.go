@@ -5,6 +5,7 @@
 package filmusecase
 
 import (
+	dto "kinopoisk/app/dto"
 	entity "kinopoisk/app/entity"
 	reflect "reflect"
 
@@ -64,6 +65,21 @@ func (mr *MockFilmUseCaseMockRecorder) DeleteFavouriteFilm(userID, filmID interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFavouriteFilm", reflect.TypeOf((*MockFilmUseCase)(nil).DeleteFavouriteFilm), userID, filmID)
 }
 
+// GetChart mocks base method.
+func (m *MockFilmUseCase) GetChart(chart string, query *dto.ChartQueryDTO) (*entity.Chart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChart", chart, query)
+	ret0, _ := ret[0].(*entity.Chart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChart indicates an expected call of GetChart.
+func (mr *MockFilmUseCaseMockRecorder) GetChart(chart, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChart", reflect.TypeOf((*MockFilmUseCase)(nil).GetChart), chart, query)
+}
+
 // GetFavouriteFilms mocks base method.
 func (m *MockFilmUseCase) GetFavouriteFilms(userID uint64) ([]*entity.Film, error) {
 	m.ctrl.T.Helper()
@@ -124,19 +140,34 @@ func (mr *MockFilmUseCaseMockRecorder) GetFilmGenres(filmID interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmGenres", reflect.TypeOf((*MockFilmUseCase)(nil).GetFilmGenres), filmID)
 }
 
+// GetFilmStats mocks base method.
+func (m *MockFilmUseCase) GetFilmStats(filmID uint64) (*entity.FilmStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFilmStats", filmID)
+	ret0, _ := ret[0].(*entity.FilmStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFilmStats indicates an expected call of GetFilmStats.
+func (mr *MockFilmUseCaseMockRecorder) GetFilmStats(filmID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmStats", reflect.TypeOf((*MockFilmUseCase)(nil).GetFilmStats), filmID)
+}
+
 // GetFilms mocks base method.
-func (m *MockFilmUseCase) GetFilms(genre, country, producer string) ([]*entity.Film, error) {
+func (m *MockFilmUseCase) GetFilms(query *dto.FilmsQueryDTO) ([]*entity.Film, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilms", genre, country, producer)
+	ret := m.ctrl.Call(m, "GetFilms", query)
 	ret0, _ := ret[0].([]*entity.Film)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilms indicates an expected call of GetFilms.
-func (mr *MockFilmUseCaseMockRecorder) GetFilms(genre, country, producer interface{}) *gomock.Call {
+func (mr *MockFilmUseCaseMockRecorder) GetFilms(query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilms", reflect.TypeOf((*MockFilmUseCase)(nil).GetFilms), genre, country, producer)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilms", reflect.TypeOf((*MockFilmUseCase)(nil).GetFilms), query)
 }
 
 // GetFilmsByActor mocks base method.
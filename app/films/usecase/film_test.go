@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"go.uber.org/zap"
 	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+	"kinopoisk/app/dto"
 	"kinopoisk/app/entity"
 	errorapp "kinopoisk/app/errors"
 	filmrepo "kinopoisk/app/films/repo/mysql"
 	filmusecase "kinopoisk/app/films/usecase"
+	"math"
 	"reflect"
 	"testing"
 )
@@ -30,7 +32,7 @@ func TestGetFilms(t *testing.T) {
 		WithArgs(genre).
 		WillReturnError(fmt.Errorf("db_error"))
 
-	_, err = testUsecase.GetFilms("drama", "", "")
+	_, err = testUsecase.GetFilms(&dto.FilmsQueryDTO{Genre: "drama"})
 	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -63,7 +65,7 @@ func TestGetFilms(t *testing.T) {
 		WithArgs(genre).
 		WillReturnRows(rows)
 
-	films, err := testUsecase.GetFilms("drama", "", "")
+	films, err := testUsecase.GetFilms(&dto.FilmsQueryDTO{Genre: "drama"})
 	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
 		t.Errorf("there were unfulfilled expectations: %s", err)
 		return
@@ -77,6 +79,30 @@ func TestGetFilms(t *testing.T) {
 		return
 	}
 
+	// filter and sort by criteria
+	rows = sqlmock.NewRows([]string{"id", "name", "description", "duration", "min_age", "country", "producer_name", "date_of_release", "num_of_marks", "rating"})
+	for _, currentFilm := range expectedFilms {
+		rows = rows.AddRow(currentFilm.ID, currentFilm.Name, currentFilm.Description, currentFilm.Duration, currentFilm.MinAge,
+			currentFilm.Country, currentFilm.ProducerName, currentFilm.DateOfRelease, currentFilm.NumOfMarks, currentFilm.Rating)
+	}
+	mock.
+		ExpectQuery("SELECT (.+) from films f WHERE 1 = 1 AND f.id IN \\(SELECT film_id FROM film_criteria_ratings WHERE criterion = \\? AND num_of_marks > 0 AND rating >= \\?\\) ORDER BY").
+		WithArgs("story", 7.5, "music").
+		WillReturnRows(rows)
+
+	films, err = testUsecase.GetFilms(&dto.FilmsQueryDTO{Sort: "music", MinRatings: map[string]float64{"story": 7.5}})
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if !reflect.DeepEqual(expectedFilms, films) {
+		t.Errorf("wrong result: expected %v, got %v", expectedFilms, films)
+		return
+	}
 }
 
 func TestGetFilmActors(t *testing.T) {
@@ -337,3 +363,82 @@ func TestAddFavouriteFilm(t *testing.T) {
 		return
 	}
 }
+
+func TestGetFilmStats(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := filmrepo.NewFilmRepoMySQL(db, zap.NewNop().Sugar())
+	testUsecase := filmusecase.NewFilmUseCaseStruct(dbRepo)
+
+	// нет такого фильма
+	var filmID uint64 = 1
+	mock.
+		ExpectQuery("SELECT f.num_of_marks, f.sum_mark, f.rating").
+		WithArgs(filmID).
+		WillReturnError(sql.ErrNoRows)
+
+	_, err = testUsecase.GetFilmStats(filmID)
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+	if !errors.Is(err, errorapp.ErrorNoFilm) {
+		t.Errorf("wrong error: expected %s, got %s", errorapp.ErrorNoFilm, err)
+		return
+	}
+
+	// оценки 4, 6, 8, 8 и два отзыва с текстом
+	mock.
+		ExpectQuery("SELECT f.num_of_marks, f.sum_mark, f.rating").
+		WithArgs(filmID).
+		WillReturnRows(sqlmock.NewRows([]string{"num_of_marks", "sum_mark", "rating", "weighted_rating", "sum_mark_squares", "num_with_text"}).
+			AddRow(4, 26, 6.5, 6.5, 180, 2))
+	mock.
+		ExpectQuery("SELECT mark, num_of_marks FROM film_mark_distribution WHERE").
+		WithArgs(filmID).
+		WillReturnRows(sqlmock.NewRows([]string{"mark", "num_of_marks"}).
+			AddRow(4, 1).
+			AddRow(6, 1).
+			AddRow(8, 2))
+	mock.
+		ExpectQuery("FROM film_review_months WHERE").
+		WithArgs(filmID).
+		WillReturnRows(sqlmock.NewRows([]string{"month", "num_of_reviews"}).
+			AddRow("2023-11", 3).
+			AddRow("2023-12", 1))
+
+	stats, err := testUsecase.GetFilmStats(filmID)
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	expectedStats := &entity.FilmStats{
+		FilmID:         filmID,
+		NumOfMarks:     4,
+		Rating:         6.5,
+		WeightedRating: 6.5,
+		Distribution: map[uint32]uint64{
+			1: 0, 2: 0, 3: 0, 4: 1, 5: 0, 6: 1, 7: 0, 8: 2, 9: 0, 10: 0,
+		},
+		Median:        7,
+		StdDev:        math.Sqrt(2.75),
+		WithText:      2,
+		MarkOnly:      2,
+		WithTextShare: 0.5,
+		ReviewsByMonth: []*entity.ReviewsMonth{
+			{Month: "2023-11", NumReviews: 3},
+			{Month: "2023-12", NumReviews: 1},
+		},
+	}
+	if !reflect.DeepEqual(expectedStats, stats) {
+		t.Errorf("wrong result: expected %+v, got %+v", expectedStats, stats)
+		return
+	}
+}
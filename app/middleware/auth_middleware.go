@@ -48,3 +48,30 @@ func AuthMiddleware(uc userusecase.UserUseCase, next http.Handler) http.Handler
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// OptionalAuthMiddleware lets anonymous requests through and puts the user into context only for a valid token.
+func OptionalAuthMiddleware(uc userusecase.UserUseCase, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger, err := GetLoggerFromContext(r.Context())
+		if err != nil {
+			log.Printf("can not get logger from context: %s", err)
+			WriteNoLoggerResponse(w)
+		}
+		authHeader := r.Header.Get("Authorization")
+		if authHeader == "" || !strings.HasPrefix(authHeader, "Bearer ") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		tokenValue := strings.TrimPrefix(authHeader, "Bearer ")
+		mySession, err := uc.GetSession(tokenValue, logger)
+		if err != nil || mySession.ID == "" {
+			logger.Infof("no session for token, request is served as anonymous")
+			next.ServeHTTP(w, r)
+			return
+		}
+		ctx := r.Context()
+		ctx = context.WithValue(ctx, MyUserKey, mySession.User)
+		ctx = context.WithValue(ctx, MyTokenKey, tokenValue)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"kinopoisk/app/delivery"
+	"kinopoisk/app/entity"
+	authpermission "kinopoisk/service_auth/permission"
+	"log"
+	"net/http"
+)
+
+// RequirePermission passes only the users whose roles give the permission, so it must be put after AuthMiddleware.
+// The permissions come with the session, a granted role works after the next login or token refresh.
+func RequirePermission(permission string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger, err := GetLoggerFromContext(r.Context())
+		if err != nil {
+			log.Printf("can not get logger from context: %s", err)
+			WriteNoLoggerResponse(w)
+		}
+		user, ok := r.Context().Value(MyUserKey).(*entity.User)
+		if !ok {
+			delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+			return
+		}
+		if !authpermission.HasPermission(user.Permissions, permission) {
+			logger.Infof("user %d has no permission %s", user.ID, permission)
+			delivery.WriteResponse(logger, w, []byte(`{"message": "no permission for this page"}`), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"fmt"
+	"kinopoisk/app/delivery"
+	"kinopoisk/app/entity"
+	userusecase "kinopoisk/app/users/usecase"
+	"log"
+	"net/http"
+)
+
+// VerifiedEmailMiddleware passes only users with a verified email, so it must be put after AuthMiddleware.
+// The email is checked on each request, so the user does not have to log in again after the verification.
+func VerifiedEmailMiddleware(uc userusecase.UserUseCase, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger, err := GetLoggerFromContext(r.Context())
+		if err != nil {
+			log.Printf("can not get logger from context: %s", err)
+			WriteNoLoggerResponse(w)
+		}
+		user, ok := r.Context().Value(MyUserKey).(*entity.User)
+		if !ok {
+			delivery.WriteResponse(logger, w, []byte(`{"message": "can not cast context value to user"}`), http.StatusInternalServerError)
+			return
+		}
+		emailStatus, err := uc.GetEmailStatus(user.ID, logger)
+		if err != nil {
+			errText := fmt.Sprintf(`{"message": "error in getting email: %s"}`, err)
+			delivery.WriteResponse(logger, w, []byte(errText), http.StatusInternalServerError)
+			return
+		}
+		if !emailStatus.Verified {
+			delivery.WriteResponse(logger, w, []byte(`{"message": "email must be verified to do this"}`), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
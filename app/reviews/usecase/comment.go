@@ -0,0 +1,127 @@
+package reviewusecase
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	review "kinopoisk/service_review/proto"
+)
+
+func (r *ReviewGRPCClient) GetReviewComments(token string, reviewID uint64, query *dto.PageQueryDTO, logger *zap.SugaredLogger) (*entity.CommentsPage, error) {
+	comments, err := r.grpcClient.GetReviewComments(viewerContext(token), &review.ReviewCommentsRequest{
+		ReviewID:  &review.ReviewID{ID: reviewID},
+		PageSize:  query.PageSize,
+		PageToken: query.PageToken,
+	})
+	if status.Code(err) == codes.InvalidArgument {
+		logger.Errorf("bad page token: %s", err)
+		return nil, errorapp.ErrorBadPageToken
+	}
+	if err != nil {
+		logger.Errorf("error in getting review comments: %s", err)
+		return nil, err
+	}
+	if comments.Status == review.CommentStatus_COMMENT_NO_REVIEW {
+		return nil, errorapp.ErrorNoReview
+	}
+	return &entity.CommentsPage{
+		Comments:      buildCommentsTree(comments.GetComments()),
+		NextPageToken: comments.GetNextPageToken(),
+	}, nil
+}
+
+func (r *ReviewGRPCClient) AddComment(reviewID uint64, newComment *dto.CommentDTO, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error) {
+	commentResult, err := r.grpcClient.AddComment(context.Background(), &review.NewCommentData{
+		ReviewID: &review.ReviewID{ID: reviewID},
+		ParentID: &review.CommentID{ID: newComment.ParentID},
+		UserID:   &review.UserID{ID: user.ID},
+		Text:     newComment.Text,
+	})
+	if err != nil {
+		logger.Errorf("error in adding comment: %s", err)
+		return nil, err
+	}
+	return getCommentFromResult(commentResult)
+}
+
+func (r *ReviewGRPCClient) UpdateComment(commentID uint64, commentToUpdate *dto.CommentDTO, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error) {
+	commentResult, err := r.grpcClient.UpdateComment(context.Background(), &review.UpdateCommentData{
+		CommentID: &review.CommentID{ID: commentID},
+		UserID:    &review.UserID{ID: user.ID},
+		Text:      commentToUpdate.Text,
+	})
+	if err != nil {
+		logger.Errorf("error in updating comment: %s", err)
+		return nil, err
+	}
+	return getCommentFromResult(commentResult)
+}
+
+func (r *ReviewGRPCClient) DeleteComment(commentID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error) {
+	commentResult, err := r.grpcClient.DeleteComment(context.Background(), &review.DeleteCommentData{
+		CommentID: &review.CommentID{ID: commentID},
+		UserID:    &review.UserID{ID: user.ID},
+	})
+	if err != nil {
+		logger.Errorf("error in deleting comment: %s", err)
+		return nil, err
+	}
+	return getCommentFromResult(commentResult)
+}
+
+func getCommentFromResult(commentResult *review.CommentResult) (*entity.Comment, error) {
+	switch commentResult.Status {
+	case review.CommentStatus_COMMENT_NO_REVIEW:
+		return nil, errorapp.ErrorNoReview
+	case review.CommentStatus_COMMENT_NO_PARENT:
+		return nil, errorapp.ErrorNoParentComment
+	case review.CommentStatus_COMMENT_TOO_DEEP:
+		return nil, errorapp.ErrorCommentTooDeep
+	case review.CommentStatus_COMMENT_NOT_FOUND:
+		return nil, errorapp.ErrorNoComment
+	case review.CommentStatus_COMMENT_DELETED:
+		return nil, errorapp.ErrorCommentDeleted
+	}
+	return getCommentFromGRPCStruct(commentResult.Comment), nil
+}
+
+// buildCommentsTree nests replies into their parents, comments have to go after their parents.
+func buildCommentsTree(commentsGRPC []*review.Comment) []*entity.Comment {
+	roots := make([]*entity.Comment, 0)
+	commentsByID := make(map[uint64]*entity.Comment, len(commentsGRPC))
+	for _, commentGRPC := range commentsGRPC {
+		comment := getCommentFromGRPCStruct(commentGRPC)
+		commentsByID[comment.ID] = comment
+		parent, ok := commentsByID[comment.ParentID]
+		if comment.ParentID == 0 || !ok {
+			roots = append(roots, comment)
+			continue
+		}
+		parent.Replies = append(parent.Replies, comment)
+	}
+	return roots
+}
+
+func getCommentFromGRPCStruct(commentGRPC *review.Comment) *entity.Comment {
+	comment := &entity.Comment{
+		ID:        commentGRPC.GetID().GetID(),
+		ParentID:  commentGRPC.GetParentID().GetID(),
+		Text:      commentGRPC.GetText(),
+		Depth:     commentGRPC.GetDepth(),
+		IsDeleted: commentGRPC.GetIsDeleted(),
+		CreatedAt: commentGRPC.GetCreatedAt(),
+		UpdatedAt: commentGRPC.GetUpdatedAt(),
+		Replies:   []*entity.Comment{},
+	}
+	if commentGRPC.Author != nil {
+		comment.Author = &entity.User{
+			ID:       commentGRPC.Author.GetID().GetID(),
+			Username: commentGRPC.Author.GetUsername(),
+		}
+	}
+	return comment
+}
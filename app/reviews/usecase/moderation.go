@@ -0,0 +1,168 @@
+package reviewusecase
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	authpermission "kinopoisk/service_auth/permission"
+	review "kinopoisk/service_review/proto"
+)
+
+var reviewStatusNames = map[review.ReviewStatus]string{
+	review.ReviewStatus_REVIEW_PUBLISHED: "published",
+	review.ReviewStatus_REVIEW_PENDING:   "pending",
+	review.ReviewStatus_REVIEW_REJECTED:  "rejected",
+	review.ReviewStatus_REVIEW_HIDDEN:    "hidden",
+}
+
+var moderationActions = map[string]review.ModerationAction{
+	"approve": review.ModerationAction_ACTION_APPROVE,
+	"reject":  review.ModerationAction_ACTION_REJECT,
+	"hide":    review.ModerationAction_ACTION_HIDE,
+}
+
+var moderationActionNames = map[review.ModerationAction]string{
+	review.ModerationAction_ACTION_APPROVE: "approve",
+	review.ModerationAction_ACTION_REJECT:  "reject",
+	review.ModerationAction_ACTION_HIDE:    "hide",
+
+	review.ModerationAction_ACTION_MARK_SPOILER:   "mark_spoiler",
+	review.ModerationAction_ACTION_UNMARK_SPOILER: "unmark_spoiler",
+}
+
+func (r *ReviewGRPCClient) ReportReview(reviewID uint64, report *dto.ReportDTO, user *entity.User, logger *zap.SugaredLogger) error {
+	reportResult, err := r.grpcClient.ReportReview(context.Background(), &review.ReportData{
+		ReviewID: &review.ReviewID{ID: reviewID},
+		UserID:   &review.UserID{ID: user.ID},
+		Reason:   report.Reason,
+	})
+	if err != nil {
+		logger.Errorf("error in reporting review: %s", err)
+		return err
+	}
+	switch reportResult.Status {
+	case review.ModerationStatus_MODERATION_NO_REVIEW:
+		return errorapp.ErrorNoReview
+	case review.ModerationStatus_MODERATION_OWN_REVIEW:
+		return errorapp.ErrorReportOwnReview
+	case review.ModerationStatus_MODERATION_ALREADY_REPORTED:
+		return errorapp.ErrorAlreadyReported
+	}
+	return nil
+}
+
+// GetModerationQueue and the other moderation methods pass the access token of the moderator,
+// service_review checks the permission by it.
+func (r *ReviewGRPCClient) GetModerationQueue(token string, query *dto.PageQueryDTO, logger *zap.SugaredLogger) (*entity.ModerationQueue, error) {
+	queue, err := r.grpcClient.GetModerationQueue(authpermission.WithAccessToken(context.Background(), token), &review.ModerationQueueRequest{
+		PageSize:  query.PageSize,
+		PageToken: query.PageToken,
+	})
+	if authpermission.IsDenied(err) {
+		return nil, errorapp.ErrorPermissionDenied
+	}
+	if status.Code(err) == codes.InvalidArgument {
+		logger.Errorf("bad page token: %s", err)
+		return nil, errorapp.ErrorBadPageToken
+	}
+	if err != nil {
+		logger.Errorf("error in getting moderation queue: %s", err)
+		return nil, err
+	}
+	items := make([]*entity.ModerationQueueItem, len(queue.Items))
+	for i, item := range queue.Items {
+		reports := make([]*entity.ReviewReport, len(item.Reports))
+		for j, report := range item.Reports {
+			reports[j] = &entity.ReviewReport{
+				Author: &entity.User{
+					ID:       report.Author.ID.ID,
+					Username: report.Author.Username,
+				},
+				Reason:    report.Reason,
+				CreatedAt: report.CreatedAt,
+			}
+		}
+		items[i] = &entity.ModerationQueueItem{
+			Review:  getReviewFromGRPCStruct(item.Review),
+			Reports: reports,
+		}
+	}
+	return &entity.ModerationQueue{
+		Items:         items,
+		NextPageToken: queue.GetNextPageToken(),
+	}, nil
+}
+
+func (r *ReviewGRPCClient) ModerateReview(token string, reviewID uint64, decision *dto.ModerationDTO, moderator *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
+	moderationResult, err := r.grpcClient.ModerateReview(authpermission.WithAccessToken(context.Background(), token), &review.ModerationData{
+		ReviewID:    &review.ReviewID{ID: reviewID},
+		ModeratorID: &review.UserID{ID: moderator.ID},
+		Action:      moderationActions[decision.Action],
+		Reason:      decision.Reason,
+	})
+	if authpermission.IsDenied(err) {
+		return nil, errorapp.ErrorPermissionDenied
+	}
+	if err != nil {
+		logger.Errorf("error in moderating review: %s", err)
+		return nil, err
+	}
+	if moderationResult.Status == review.ModerationStatus_MODERATION_NO_REVIEW {
+		return nil, errorapp.ErrorNoReview
+	}
+	return getReviewFromGRPCStruct(moderationResult.Review), nil
+}
+
+func (r *ReviewGRPCClient) SetSpoilerFlag(token string, reviewID uint64, isSpoiler bool, moderator *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
+	moderationResult, err := r.grpcClient.SetSpoilerFlag(authpermission.WithAccessToken(context.Background(), token), &review.SpoilerFlagData{
+		ReviewID:    &review.ReviewID{ID: reviewID},
+		ModeratorID: &review.UserID{ID: moderator.ID},
+		IsSpoiler:   isSpoiler,
+	})
+	if authpermission.IsDenied(err) {
+		return nil, errorapp.ErrorPermissionDenied
+	}
+	if err != nil {
+		logger.Errorf("error in setting spoiler flag: %s", err)
+		return nil, err
+	}
+	if moderationResult.Status == review.ModerationStatus_MODERATION_NO_REVIEW {
+		return nil, errorapp.ErrorNoReview
+	}
+	return getReviewFromGRPCStruct(moderationResult.Review), nil
+}
+
+func (r *ReviewGRPCClient) GetModerationLog(token string, reviewID uint64, logger *zap.SugaredLogger) ([]*entity.ModerationLogEntry, error) {
+	moderationLog, err := r.grpcClient.GetModerationLog(authpermission.WithAccessToken(context.Background(), token), &review.ReviewID{
+		ID: reviewID,
+	})
+	if authpermission.IsDenied(err) {
+		return nil, errorapp.ErrorPermissionDenied
+	}
+	if err != nil {
+		logger.Errorf("error in getting moderation log: %s", err)
+		return nil, err
+	}
+	if moderationLog.Status == review.ModerationStatus_MODERATION_NO_REVIEW {
+		return nil, errorapp.ErrorNoReview
+	}
+	entries := make([]*entity.ModerationLogEntry, len(moderationLog.Entries))
+	for i, entry := range moderationLog.Entries {
+		entries[i] = &entity.ModerationLogEntry{
+			Moderator: &entity.User{
+				ID:       entry.Moderator.ID.ID,
+				Username: entry.Moderator.Username,
+			},
+			Action:    moderationActionNames[entry.Action],
+			OldStatus: reviewStatusNames[entry.OldStatus],
+			NewStatus: reviewStatusNames[entry.NewStatus],
+			Reason:    entry.Reason,
+			CreatedAt: entry.CreatedAt,
+		}
+	}
+	return entries, nil
+}
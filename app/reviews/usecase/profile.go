@@ -0,0 +1,90 @@
+package reviewusecase
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kinopoisk/app/dto"
+	"kinopoisk/app/entity"
+	errorapp "kinopoisk/app/errors"
+	review "kinopoisk/service_review/proto"
+)
+
+func (r *ReviewGRPCClient) GetUserProfile(userID uint64, requester *entity.User, revealSpoilers bool, logger *zap.SugaredLogger) (*entity.UserProfile, error) {
+	profile, err := r.grpcClient.GetUserProfile(context.Background(), &review.UserProfileRequest{
+		UserID:         &review.UserID{ID: userID},
+		RequesterID:    &review.UserID{ID: getRequesterID(requester)},
+		RevealSpoilers: revealSpoilers,
+	})
+	if err != nil {
+		logger.Errorf("error in getting user profile: %s", err)
+		return nil, err
+	}
+	if err = getProfileStatusError(profile.Status); err != nil {
+		return nil, err
+	}
+	recentReviews := make([]*entity.Review, len(profile.RecentReviews))
+	for i, recentReview := range profile.RecentReviews {
+		recentReviews[i] = getReviewFromGRPCStruct(recentReview)
+	}
+	return &entity.UserProfile{
+		User: &entity.User{
+			ID:       profile.GetUser().GetID().GetID(),
+			Username: profile.GetUser().GetUsername(),
+		},
+		JoinedAt:      profile.JoinedAt,
+		IsPrivate:     profile.IsPrivate,
+		ReviewsCount:  profile.ReviewsCount,
+		AverageMark:   profile.AverageMark,
+		RecentReviews: recentReviews,
+	}, nil
+}
+
+func (r *ReviewGRPCClient) GetUserReviews(userID uint64, requester *entity.User, query *dto.UserReviewsQueryDTO, logger *zap.SugaredLogger) (*entity.ReviewsPage, error) {
+	reviews, err := r.grpcClient.GetUserReviews(context.Background(), &review.UserReviewsRequest{
+		UserID:      &review.UserID{ID: userID},
+		RequesterID: &review.UserID{ID: getRequesterID(requester)},
+		PageSize:    query.PageSize,
+		PageToken:   query.PageToken,
+
+		RevealSpoilers: query.RevealSpoilers,
+	})
+	if status.Code(err) == codes.InvalidArgument {
+		logger.Errorf("bad page token: %s", err)
+		return nil, errorapp.ErrorBadPageToken
+	}
+	if err != nil {
+		logger.Errorf("error in getting user reviews: %s", err)
+		return nil, err
+	}
+	if err = getProfileStatusError(reviews.Status); err != nil {
+		return nil, err
+	}
+	reviewsApp := make([]*entity.Review, len(reviews.Reviews))
+	for i, userReview := range reviews.Reviews {
+		reviewsApp[i] = getReviewFromGRPCStruct(userReview)
+	}
+	return &entity.ReviewsPage{
+		Reviews:       reviewsApp,
+		NextPageToken: reviews.NextPageToken,
+	}, nil
+}
+
+// getRequesterID returns 0 for anonymous requests, no user has such id.
+func getRequesterID(requester *entity.User) uint64 {
+	if requester == nil {
+		return 0
+	}
+	return requester.ID
+}
+
+func getProfileStatusError(profileStatus review.ProfileStatus) error {
+	switch profileStatus {
+	case review.ProfileStatus_PROFILE_NOT_FOUND:
+		return errorapp.ErrorNoUser
+	case review.ProfileStatus_PROFILE_PRIVATE:
+		return errorapp.ErrorPrivateProfile
+	}
+	return nil
+}
@@ -3,18 +3,35 @@ package reviewusecase
 import (
 	"context"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"kinopoisk/app/dto"
 	"kinopoisk/app/entity"
 	errorapp "kinopoisk/app/errors"
 	filmrepo "kinopoisk/app/films/repo/mysql"
+	authpermission "kinopoisk/service_auth/permission"
 	review "kinopoisk/service_review/proto"
 )
 
 type ReviewUseCase interface {
-	GetFilmReviews(filmID uint64, logger *zap.SugaredLogger) ([]*entity.Review, error)
+	GetFilmReviews(filmID uint64, query *dto.ReviewsQueryDTO, logger *zap.SugaredLogger) (*entity.ReviewsPage, error)
 	NewReview(newReview *dto.ReviewDTO, filmID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error)
 	DeleteReview(reviewID, userID uint64, logger *zap.SugaredLogger) (bool, error)
 	UpdateReview(reviewToUpdate *dto.ReviewDTO, reviewID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error)
+	GetReviewHistory(token string, reviewID uint64, revealSpoilers bool, logger *zap.SugaredLogger) (*entity.ReviewHistory, error)
+	VoteReview(reviewID uint64, isHelpful bool, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error)
+	DeleteVote(reviewID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error)
+	GetReviewComments(token string, reviewID uint64, query *dto.PageQueryDTO, logger *zap.SugaredLogger) (*entity.CommentsPage, error)
+	AddComment(reviewID uint64, newComment *dto.CommentDTO, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error)
+	UpdateComment(commentID uint64, commentToUpdate *dto.CommentDTO, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error)
+	DeleteComment(commentID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error)
+	ReportReview(reviewID uint64, report *dto.ReportDTO, user *entity.User, logger *zap.SugaredLogger) error
+	GetModerationQueue(token string, query *dto.PageQueryDTO, logger *zap.SugaredLogger) (*entity.ModerationQueue, error)
+	ModerateReview(token string, reviewID uint64, decision *dto.ModerationDTO, moderator *entity.User, logger *zap.SugaredLogger) (*entity.Review, error)
+	GetModerationLog(token string, reviewID uint64, logger *zap.SugaredLogger) ([]*entity.ModerationLogEntry, error)
+	SetSpoilerFlag(token string, reviewID uint64, isSpoiler bool, moderator *entity.User, logger *zap.SugaredLogger) (*entity.Review, error)
+	GetUserProfile(userID uint64, requester *entity.User, revealSpoilers bool, logger *zap.SugaredLogger) (*entity.UserProfile, error)
+	GetUserReviews(userID uint64, requester *entity.User, query *dto.UserReviewsQueryDTO, logger *zap.SugaredLogger) (*entity.ReviewsPage, error)
 }
 
 type ReviewGRPCClient struct {
@@ -26,6 +43,15 @@ type loggerKey int
 
 const MyLoggerKey loggerKey = 3
 
+var reviewSorts = map[string]review.ReviewSort{
+	"":             review.ReviewSort_SORT_NEWEST,
+	"newest":       review.ReviewSort_SORT_NEWEST,
+	"oldest":       review.ReviewSort_SORT_OLDEST,
+	"highest_mark": review.ReviewSort_SORT_HIGHEST_MARK,
+	"lowest_mark":  review.ReviewSort_SORT_LOWEST_MARK,
+	"most_helpful": review.ReviewSort_SORT_MOST_HELPFUL,
+}
+
 func NewReviewGRPCClient(grpcClient review.ReviewMakerClient, filmRepo filmrepo.FilmRepo) *ReviewGRPCClient {
 	return &ReviewGRPCClient{
 		grpcClient: grpcClient,
@@ -33,10 +59,20 @@ func NewReviewGRPCClient(grpcClient review.ReviewMakerClient, filmRepo filmrepo.
 	}
 }
 
-func (r *ReviewGRPCClient) GetFilmReviews(filmID uint64, logger *zap.SugaredLogger) ([]*entity.Review, error) {
-	reviews, err := r.grpcClient.GetFilmReviews(context.Background(), &review.FilmID{
-		ID: filmID,
+func (r *ReviewGRPCClient) GetFilmReviews(filmID uint64, query *dto.ReviewsQueryDTO, logger *zap.SugaredLogger) (*entity.ReviewsPage, error) {
+	reviews, err := r.grpcClient.GetFilmReviews(context.Background(), &review.FilmReviewsRequest{
+		FilmID:    &review.FilmID{ID: filmID},
+		Sort:      reviewSorts[query.Sort],
+		PageSize:  query.PageSize,
+		PageToken: query.PageToken,
+
+		RevealSpoilers: query.RevealSpoilers,
+		CriticsOnly:    query.CriticsOnly,
 	})
+	if status.Code(err) == codes.InvalidArgument {
+		logger.Errorf("bad page token: %s", err)
+		return nil, errorapp.ErrorBadPageToken
+	}
 	if err != nil {
 		logger.Errorf("error in getting film reviews: %s", err)
 		return nil, err
@@ -45,9 +81,13 @@ func (r *ReviewGRPCClient) GetFilmReviews(filmID uint64, logger *zap.SugaredLogg
 	reviewsApp := make([]*entity.Review, len(reviewsArr))
 	for i, currentReview := range reviewsArr {
 		newReviewApp := getReviewFromGRPCStruct(currentReview)
+		newReviewApp.FilmID = filmID
 		reviewsApp[i] = newReviewApp
 	}
-	return reviewsApp, nil
+	return &entity.ReviewsPage{
+		Reviews:       reviewsApp,
+		NextPageToken: reviews.GetNextPageToken(),
+	}, nil
 }
 
 func (r *ReviewGRPCClient) NewReview(newReview *dto.ReviewDTO, filmID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
@@ -111,21 +151,130 @@ func (r *ReviewGRPCClient) UpdateReview(reviewToUpdate *dto.ReviewDTO, reviewID
 	return updatedReviewApp, nil
 }
 
+// GetReviewHistory returns nil if there is no review or it is not published and the user of the token
+// is neither its author nor a moderator; the token is empty for anonymous users.
+func (r *ReviewGRPCClient) GetReviewHistory(token string, reviewID uint64, revealSpoilers bool, logger *zap.SugaredLogger) (*entity.ReviewHistory, error) {
+	history, err := r.grpcClient.GetReviewHistory(viewerContext(token), &review.ReviewHistoryRequest{
+		ReviewID:       &review.ReviewID{ID: reviewID},
+		RevealSpoilers: revealSpoilers,
+	})
+	if err != nil {
+		logger.Errorf("error in getting review history: %s", err)
+		return nil, err
+	}
+	if history.Review == nil {
+		return nil, nil
+	}
+	revisions := make([]*entity.ReviewRevision, len(history.Revisions))
+	for i, revision := range history.Revisions {
+		revisions[i] = &entity.ReviewRevision{
+			Mark:       revision.Mark,
+			Comment:    revision.Comment,
+			CreatedAt:  revision.CreatedAt,
+			ReplacedAt: revision.ReplacedAt,
+		}
+	}
+	return &entity.ReviewHistory{
+		Review:    getReviewFromGRPCStruct(history.Review),
+		Revisions: revisions,
+	}, nil
+}
+
+func (r *ReviewGRPCClient) VoteReview(reviewID uint64, isHelpful bool, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
+	voteResult, err := r.grpcClient.VoteReview(context.Background(), &review.VoteData{
+		ReviewID:  &review.ReviewID{ID: reviewID},
+		UserID:    &review.UserID{ID: user.ID},
+		IsHelpful: isHelpful,
+	})
+	if err != nil {
+		logger.Errorf("error in voting for review: %s", err)
+		return nil, err
+	}
+	return getReviewFromVoteResult(voteResult)
+}
+
+func (r *ReviewGRPCClient) DeleteVote(reviewID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
+	voteResult, err := r.grpcClient.DeleteVote(context.Background(), &review.DeleteVoteData{
+		ReviewID: &review.ReviewID{ID: reviewID},
+		UserID:   &review.UserID{ID: user.ID},
+	})
+	if err != nil {
+		logger.Errorf("error in deleting review vote: %s", err)
+		return nil, err
+	}
+	return getReviewFromVoteResult(voteResult)
+}
+
+// viewerContext passes the token of the user to service_review, it decides by it which reviews are shown.
+func viewerContext(token string) context.Context {
+	if token == "" {
+		return context.Background()
+	}
+	return authpermission.WithAccessToken(context.Background(), token)
+}
+
+func getReviewFromVoteResult(voteResult *review.VoteResult) (*entity.Review, error) {
+	switch voteResult.Status {
+	case review.VoteStatus_VOTE_NO_REVIEW:
+		return nil, errorapp.ErrorNoReview
+	case review.VoteStatus_VOTE_OWN_REVIEW:
+		return nil, errorapp.ErrorOwnReview
+	case review.VoteStatus_VOTE_NOT_FOUND:
+		return nil, errorapp.ErrorNoVote
+	}
+	return getReviewFromGRPCStruct(voteResult.Review), nil
+}
+
 func getReviewFromGRPCStruct(reviewGRPC *review.Review) *entity.Review {
 	return &entity.Review{
 		ID:      reviewGRPC.ID.ID,
+		FilmID:  reviewGRPC.GetFilmID().GetID(),
 		Mark:    reviewGRPC.Mark,
 		Comment: reviewGRPC.Comment,
 		Author: &entity.User{
 			ID:       reviewGRPC.Author.ID.ID,
 			Username: reviewGRPC.Author.Username,
 		},
+		CreatedAt: reviewGRPC.CreatedAt,
+		UpdatedAt: reviewGRPC.UpdatedAt,
+		IsEdited:  reviewGRPC.IsEdited,
+		Status:    reviewStatusNames[reviewGRPC.Status],
+
+		IsSpoiler:      reviewGRPC.IsSpoiler,
+		SpoilersMasked: reviewGRPC.SpoilersMasked,
+
+		HelpfulVotes:   reviewGRPC.HelpfulVotes,
+		UnhelpfulVotes: reviewGRPC.UnhelpfulVotes,
+
+		SubScores: getSubScoresFromGRPCStruct(reviewGRPC.GetSubScores()),
+		ByCritic:  reviewGRPC.ByCritic,
 	}
 }
 
+// getSubScoresFromGRPCStruct returns nil if the review has no rated criteria.
+func getSubScoresFromGRPCStruct(subScoresGRPC *review.SubScores) *entity.SubScores {
+	subScores := &entity.SubScores{
+		Story:   subScoresGRPC.GetStory(),
+		Acting:  subScoresGRPC.GetActing(),
+		Visuals: subScoresGRPC.GetVisuals(),
+		Music:   subScoresGRPC.GetMusic(),
+	}
+	if *subScores == (entity.SubScores{}) {
+		return nil
+	}
+	return subScores
+}
+
 func getGRPCReviewFromDTO(reviewDTO *dto.ReviewDTO) *review.Review {
 	return &review.Review{
-		Mark:    reviewDTO.Mark,
-		Comment: reviewDTO.Comment,
+		Mark:      reviewDTO.Mark,
+		Comment:   reviewDTO.Comment,
+		IsSpoiler: reviewDTO.IsSpoiler,
+		SubScores: &review.SubScores{
+			Story:   reviewDTO.Story,
+			Acting:  reviewDTO.Acting,
+			Visuals: reviewDTO.Visuals,
+			Music:   reviewDTO.Music,
+		},
 	}
 }
@@ -36,6 +36,36 @@ func (m *MockReviewUseCase) EXPECT() *MockReviewUseCaseMockRecorder {
 	return m.recorder
 }
 
+// AddComment mocks base method.
+func (m *MockReviewUseCase) AddComment(reviewID uint64, newComment *dto.CommentDTO, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddComment", reviewID, newComment, user, logger)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddComment indicates an expected call of AddComment.
+func (mr *MockReviewUseCaseMockRecorder) AddComment(reviewID, newComment, user, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddComment", reflect.TypeOf((*MockReviewUseCase)(nil).AddComment), reviewID, newComment, user, logger)
+}
+
+// DeleteComment mocks base method.
+func (m *MockReviewUseCase) DeleteComment(commentID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteComment", commentID, user, logger)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteComment indicates an expected call of DeleteComment.
+func (mr *MockReviewUseCaseMockRecorder) DeleteComment(commentID, user, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteComment", reflect.TypeOf((*MockReviewUseCase)(nil).DeleteComment), commentID, user, logger)
+}
+
 // DeleteReview mocks base method.
 func (m *MockReviewUseCase) DeleteReview(reviewID, userID uint64, logger *zap.SugaredLogger) (bool, error) {
 	m.ctrl.T.Helper()
@@ -51,19 +81,139 @@ func (mr *MockReviewUseCaseMockRecorder) DeleteReview(reviewID, userID, logger i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReview", reflect.TypeOf((*MockReviewUseCase)(nil).DeleteReview), reviewID, userID, logger)
 }
 
+// DeleteVote mocks base method.
+func (m *MockReviewUseCase) DeleteVote(reviewID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVote", reviewID, user, logger)
+	ret0, _ := ret[0].(*entity.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteVote indicates an expected call of DeleteVote.
+func (mr *MockReviewUseCaseMockRecorder) DeleteVote(reviewID, user, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVote", reflect.TypeOf((*MockReviewUseCase)(nil).DeleteVote), reviewID, user, logger)
+}
+
 // GetFilmReviews mocks base method.
-func (m *MockReviewUseCase) GetFilmReviews(filmID uint64, logger *zap.SugaredLogger) ([]*entity.Review, error) {
+func (m *MockReviewUseCase) GetFilmReviews(filmID uint64, query *dto.ReviewsQueryDTO, logger *zap.SugaredLogger) (*entity.ReviewsPage, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetFilmReviews", filmID, logger)
-	ret0, _ := ret[0].([]*entity.Review)
+	ret := m.ctrl.Call(m, "GetFilmReviews", filmID, query, logger)
+	ret0, _ := ret[0].(*entity.ReviewsPage)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetFilmReviews indicates an expected call of GetFilmReviews.
-func (mr *MockReviewUseCaseMockRecorder) GetFilmReviews(filmID, logger interface{}) *gomock.Call {
+func (mr *MockReviewUseCaseMockRecorder) GetFilmReviews(filmID, query, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmReviews", reflect.TypeOf((*MockReviewUseCase)(nil).GetFilmReviews), filmID, query, logger)
+}
+
+// GetModerationLog mocks base method.
+func (m *MockReviewUseCase) GetModerationLog(token string, reviewID uint64, logger *zap.SugaredLogger) ([]*entity.ModerationLogEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModerationLog", token, reviewID, logger)
+	ret0, _ := ret[0].([]*entity.ModerationLogEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModerationLog indicates an expected call of GetModerationLog.
+func (mr *MockReviewUseCaseMockRecorder) GetModerationLog(token, reviewID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationLog", reflect.TypeOf((*MockReviewUseCase)(nil).GetModerationLog), token, reviewID, logger)
+}
+
+// GetModerationQueue mocks base method.
+func (m *MockReviewUseCase) GetModerationQueue(token string, query *dto.PageQueryDTO, logger *zap.SugaredLogger) (*entity.ModerationQueue, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetModerationQueue", token, query, logger)
+	ret0, _ := ret[0].(*entity.ModerationQueue)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetModerationQueue indicates an expected call of GetModerationQueue.
+func (mr *MockReviewUseCaseMockRecorder) GetModerationQueue(token, query, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetModerationQueue", reflect.TypeOf((*MockReviewUseCase)(nil).GetModerationQueue), token, query, logger)
+}
+
+// GetReviewComments mocks base method.
+func (m *MockReviewUseCase) GetReviewComments(token string, reviewID uint64, query *dto.PageQueryDTO, logger *zap.SugaredLogger) (*entity.CommentsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewComments", token, reviewID, query, logger)
+	ret0, _ := ret[0].(*entity.CommentsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewComments indicates an expected call of GetReviewComments.
+func (mr *MockReviewUseCaseMockRecorder) GetReviewComments(token, reviewID, query, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewComments", reflect.TypeOf((*MockReviewUseCase)(nil).GetReviewComments), token, reviewID, query, logger)
+}
+
+// GetReviewHistory mocks base method.
+func (m *MockReviewUseCase) GetReviewHistory(token string, reviewID uint64, revealSpoilers bool, logger *zap.SugaredLogger) (*entity.ReviewHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReviewHistory", token, reviewID, revealSpoilers, logger)
+	ret0, _ := ret[0].(*entity.ReviewHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReviewHistory indicates an expected call of GetReviewHistory.
+func (mr *MockReviewUseCaseMockRecorder) GetReviewHistory(token, reviewID, revealSpoilers, logger interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFilmReviews", reflect.TypeOf((*MockReviewUseCase)(nil).GetFilmReviews), filmID, logger)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReviewHistory", reflect.TypeOf((*MockReviewUseCase)(nil).GetReviewHistory), token, reviewID, revealSpoilers, logger)
+}
+
+// GetUserProfile mocks base method.
+func (m *MockReviewUseCase) GetUserProfile(userID uint64, requester *entity.User, revealSpoilers bool, logger *zap.SugaredLogger) (*entity.UserProfile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserProfile", userID, requester, revealSpoilers, logger)
+	ret0, _ := ret[0].(*entity.UserProfile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserProfile indicates an expected call of GetUserProfile.
+func (mr *MockReviewUseCaseMockRecorder) GetUserProfile(userID, requester, revealSpoilers, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserProfile", reflect.TypeOf((*MockReviewUseCase)(nil).GetUserProfile), userID, requester, revealSpoilers, logger)
+}
+
+// GetUserReviews mocks base method.
+func (m *MockReviewUseCase) GetUserReviews(userID uint64, requester *entity.User, query *dto.UserReviewsQueryDTO, logger *zap.SugaredLogger) (*entity.ReviewsPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserReviews", userID, requester, query, logger)
+	ret0, _ := ret[0].(*entity.ReviewsPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserReviews indicates an expected call of GetUserReviews.
+func (mr *MockReviewUseCaseMockRecorder) GetUserReviews(userID, requester, query, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReviews", reflect.TypeOf((*MockReviewUseCase)(nil).GetUserReviews), userID, requester, query, logger)
+}
+
+// ModerateReview mocks base method.
+func (m *MockReviewUseCase) ModerateReview(token string, reviewID uint64, decision *dto.ModerationDTO, moderator *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ModerateReview", token, reviewID, decision, moderator, logger)
+	ret0, _ := ret[0].(*entity.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ModerateReview indicates an expected call of ModerateReview.
+func (mr *MockReviewUseCaseMockRecorder) ModerateReview(token, reviewID, decision, moderator, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ModerateReview", reflect.TypeOf((*MockReviewUseCase)(nil).ModerateReview), token, reviewID, decision, moderator, logger)
 }
 
 // NewReview mocks base method.
@@ -81,6 +231,50 @@ func (mr *MockReviewUseCaseMockRecorder) NewReview(newReview, filmID, user, logg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewReview", reflect.TypeOf((*MockReviewUseCase)(nil).NewReview), newReview, filmID, user, logger)
 }
 
+// ReportReview mocks base method.
+func (m *MockReviewUseCase) ReportReview(reviewID uint64, report *dto.ReportDTO, user *entity.User, logger *zap.SugaredLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReportReview", reviewID, report, user, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReportReview indicates an expected call of ReportReview.
+func (mr *MockReviewUseCaseMockRecorder) ReportReview(reviewID, report, user, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReportReview", reflect.TypeOf((*MockReviewUseCase)(nil).ReportReview), reviewID, report, user, logger)
+}
+
+// SetSpoilerFlag mocks base method.
+func (m *MockReviewUseCase) SetSpoilerFlag(token string, reviewID uint64, isSpoiler bool, moderator *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSpoilerFlag", token, reviewID, isSpoiler, moderator, logger)
+	ret0, _ := ret[0].(*entity.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSpoilerFlag indicates an expected call of SetSpoilerFlag.
+func (mr *MockReviewUseCaseMockRecorder) SetSpoilerFlag(token, reviewID, isSpoiler, moderator, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSpoilerFlag", reflect.TypeOf((*MockReviewUseCase)(nil).SetSpoilerFlag), token, reviewID, isSpoiler, moderator, logger)
+}
+
+// UpdateComment mocks base method.
+func (m *MockReviewUseCase) UpdateComment(commentID uint64, commentToUpdate *dto.CommentDTO, user *entity.User, logger *zap.SugaredLogger) (*entity.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateComment", commentID, commentToUpdate, user, logger)
+	ret0, _ := ret[0].(*entity.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateComment indicates an expected call of UpdateComment.
+func (mr *MockReviewUseCaseMockRecorder) UpdateComment(commentID, commentToUpdate, user, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateComment", reflect.TypeOf((*MockReviewUseCase)(nil).UpdateComment), commentID, commentToUpdate, user, logger)
+}
+
 // UpdateReview mocks base method.
 func (m *MockReviewUseCase) UpdateReview(reviewToUpdate *dto.ReviewDTO, reviewID uint64, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
 	m.ctrl.T.Helper()
@@ -95,3 +289,18 @@ func (mr *MockReviewUseCaseMockRecorder) UpdateReview(reviewToUpdate, reviewID,
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateReview", reflect.TypeOf((*MockReviewUseCase)(nil).UpdateReview), reviewToUpdate, reviewID, user, logger)
 }
+
+// VoteReview mocks base method.
+func (m *MockReviewUseCase) VoteReview(reviewID uint64, isHelpful bool, user *entity.User, logger *zap.SugaredLogger) (*entity.Review, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VoteReview", reviewID, isHelpful, user, logger)
+	ret0, _ := ret[0].(*entity.Review)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VoteReview indicates an expected call of VoteReview.
+func (mr *MockReviewUseCaseMockRecorder) VoteReview(reviewID, isHelpful, user, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VoteReview", reflect.TypeOf((*MockReviewUseCase)(nil).VoteReview), reviewID, isHelpful, user, logger)
+}
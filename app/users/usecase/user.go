@@ -3,17 +3,36 @@ package userusecase
 import (
 	"context"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"kinopoisk/app/entity"
 	errorapp "kinopoisk/app/errors"
+	authpermission "kinopoisk/service_auth/permission"
 	auth "kinopoisk/service_auth/proto"
 )
 
 type UserUseCase interface {
 	Login(username, password string, logger *zap.SugaredLogger) (*entity.User, error)
-	Register(username, password string, logger *zap.SugaredLogger) (*entity.User, error)
-	CreateSession(user *entity.User, logger *zap.SugaredLogger) (string, error)
+	Register(username, password, email string, logger *zap.SugaredLogger) (*entity.User, error)
+	CreateSession(user *entity.User, client *entity.ClientInfo, logger *zap.SugaredLogger) (*entity.TokenPair, error)
+	RefreshTokens(refreshToken string, client *entity.ClientInfo, logger *zap.SugaredLogger) (*entity.TokenPair, error)
 	GetSession(token string, logger *zap.SugaredLogger) (*entity.Session, error)
 	DeleteSession(token string, logger *zap.SugaredLogger) (bool, error)
+	GetUserSessions(token string, logger *zap.SugaredLogger) ([]*entity.UserSession, error)
+	DeleteUserSession(userID uint64, sessionID string, logger *zap.SugaredLogger) error
+	DeleteAllSessions(userID uint64, logger *zap.SugaredLogger) (uint64, error)
+	ChangePassword(token, oldPassword, newPassword string, logger *zap.SugaredLogger) error
+	RequestPasswordReset(email string, logger *zap.SugaredLogger) error
+	ResetPassword(resetToken, newPassword string, logger *zap.SugaredLogger) error
+	GetEmailStatus(userID uint64, logger *zap.SugaredLogger) (*entity.EmailStatus, error)
+	ChangeEmail(userID uint64, email string, logger *zap.SugaredLogger) (*entity.EmailStatus, error)
+	VerifyEmail(verificationToken string, logger *zap.SugaredLogger) error
+	StartOIDCLogin(provider string, linkUserID uint64, logger *zap.SugaredLogger) (string, string, error)
+	FinishOIDCLogin(provider, code, state, binding string, callerUserID uint64, logger *zap.SugaredLogger) (*entity.User, error)
+	SetProfilePrivacy(userID uint64, isPrivate bool, logger *zap.SugaredLogger) error
+	GetUserRoles(token string, userID uint64, logger *zap.SugaredLogger) (*entity.UserRoles, error)
+	GrantRole(token string, userID uint64, role string, logger *zap.SugaredLogger) (*entity.UserRoles, error)
+	RevokeRole(token string, userID uint64, role string, logger *zap.SugaredLogger) (*entity.UserRoles, error)
 }
 
 type AuthGRPCClient struct {
@@ -46,10 +65,11 @@ func (a *AuthGRPCClient) Login(username, password string, logger *zap.SugaredLog
 	return newUserApp, nil
 }
 
-func (a *AuthGRPCClient) Register(username, password string, logger *zap.SugaredLogger) (*entity.User, error) {
+func (a *AuthGRPCClient) Register(username, password, email string, logger *zap.SugaredLogger) (*entity.User, error) {
 	newUser, err := a.grpcClient.Register(context.Background(), &auth.AuthData{
 		Username: username,
 		Password: password,
+		Email:    email,
 	})
 	if err != nil {
 		logger.Errorf("error in register: %s", err)
@@ -62,14 +82,35 @@ func (a *AuthGRPCClient) Register(username, password string, logger *zap.Sugared
 	return newUserApp, nil
 }
 
-func (a *AuthGRPCClient) CreateSession(user *entity.User, logger *zap.SugaredLogger) (string, error) {
-	userGRPC := getGRPCUserFromEntityUser(user)
-	token, err := a.grpcClient.CreateSession(context.Background(), userGRPC)
+func (a *AuthGRPCClient) CreateSession(user *entity.User, client *entity.ClientInfo, logger *zap.SugaredLogger) (*entity.TokenPair, error) {
+	tokenPair, err := a.grpcClient.CreateTokenPair(context.Background(), &auth.NewSession{
+		User:   getGRPCUserFromEntityUser(user),
+		Client: getGRPCClientInfoFromEntity(client),
+	})
 	if err != nil {
 		logger.Errorf("error in creating session: %s", err)
-		return "", err
+		return nil, err
 	}
-	return token.Token, nil
+	return getTokenPairFromGRPCStruct(tokenPair), nil
+}
+
+func (a *AuthGRPCClient) RefreshTokens(refreshToken string, client *entity.ClientInfo, logger *zap.SugaredLogger) (*entity.TokenPair, error) {
+	tokenPair, err := a.grpcClient.RefreshTokens(context.Background(), &auth.RefreshToken{
+		RefreshToken: refreshToken,
+		Client:       getGRPCClientInfoFromEntity(client),
+	})
+	if status.Code(err) == codes.Unauthenticated {
+		logger.Infof("refresh token reuse, all sessions of the login are revoked")
+		return nil, errorapp.ErrorRefreshTokenReused
+	}
+	if err != nil {
+		logger.Errorf("error in refreshing tokens: %s", err)
+		return nil, err
+	}
+	if tokenPair.AccessToken == "" {
+		return nil, errorapp.ErrorNoSession
+	}
+	return getTokenPairFromGRPCStruct(tokenPair), nil
 }
 
 func (a *AuthGRPCClient) GetSession(token string, logger *zap.SugaredLogger) (*entity.Session, error) {
@@ -98,10 +139,258 @@ func (a *AuthGRPCClient) DeleteSession(token string, logger *zap.SugaredLogger)
 	return true, nil
 }
 
+func (a *AuthGRPCClient) GetUserSessions(token string, logger *zap.SugaredLogger) ([]*entity.UserSession, error) {
+	userSessions, err := a.grpcClient.GetUserSessions(context.Background(), &auth.Token{
+		Token: token,
+	})
+	if err != nil {
+		logger.Errorf("error in getting user sessions: %s", err)
+		return nil, err
+	}
+	sessions := make([]*entity.UserSession, 0, len(userSessions.Sessions))
+	for _, userSession := range userSessions.Sessions {
+		sessions = append(sessions, &entity.UserSession{
+			ID:         userSession.ID,
+			Device:     userSession.Device,
+			IP:         userSession.IP,
+			UserAgent:  userSession.UserAgent,
+			CreatedAt:  userSession.CreatedAt,
+			LastSeenAt: userSession.LastSeenAt,
+			Current:    userSession.Current,
+		})
+	}
+	return sessions, nil
+}
+
+func (a *AuthGRPCClient) DeleteUserSession(userID uint64, sessionID string, logger *zap.SugaredLogger) error {
+	isDeleted, err := a.grpcClient.DeleteUserSession(context.Background(), &auth.SessionOfUser{
+		UserID:    userID,
+		SessionID: sessionID,
+	})
+	if err != nil {
+		logger.Errorf("error in deleting user session: %s", err)
+		return err
+	}
+	if !isDeleted.IsDeleted {
+		return errorapp.ErrorNoSession
+	}
+	return nil
+}
+
+func (a *AuthGRPCClient) DeleteAllSessions(userID uint64, logger *zap.SugaredLogger) (uint64, error) {
+	deletedSessions, err := a.grpcClient.DeleteAllSessions(context.Background(), &auth.User{
+		ID: userID,
+	})
+	if err != nil {
+		logger.Errorf("error in deleting all sessions: %s", err)
+		return 0, err
+	}
+	return deletedSessions.Count, nil
+}
+
+func (a *AuthGRPCClient) ChangePassword(token, oldPassword, newPassword string, logger *zap.SugaredLogger) error {
+	passwordChanged, err := a.grpcClient.ChangePassword(context.Background(), &auth.PasswordChange{
+		Token:       token,
+		OldPassword: oldPassword,
+		NewPassword: newPassword,
+	})
+	if err != nil {
+		logger.Errorf("error in changing password: %s", err)
+		return err
+	}
+	if !passwordChanged.IsChanged {
+		return errorapp.ErrorWrongPassword
+	}
+	return nil
+}
+
+func (a *AuthGRPCClient) RequestPasswordReset(email string, logger *zap.SugaredLogger) error {
+	_, err := a.grpcClient.RequestPasswordReset(context.Background(), &auth.PasswordResetRequest{
+		Email: email,
+	})
+	if err != nil {
+		logger.Errorf("error in requesting password reset: %s", err)
+		return err
+	}
+	return nil
+}
+
+func (a *AuthGRPCClient) ResetPassword(resetToken, newPassword string, logger *zap.SugaredLogger) error {
+	user, err := a.grpcClient.ResetPassword(context.Background(), &auth.PasswordReset{
+		Token:       resetToken,
+		NewPassword: newPassword,
+	})
+	if err != nil {
+		logger.Errorf("error in resetting password: %s", err)
+		return err
+	}
+	if user.ID == 0 {
+		return errorapp.ErrorBadResetToken
+	}
+	return nil
+}
+
+func (a *AuthGRPCClient) GetEmailStatus(userID uint64, logger *zap.SugaredLogger) (*entity.EmailStatus, error) {
+	emailStatus, err := a.grpcClient.GetEmailStatus(context.Background(), &auth.User{
+		ID: userID,
+	})
+	if err != nil {
+		logger.Errorf("error in getting email status: %s", err)
+		return nil, err
+	}
+	return getEmailStatusFromGRPCStruct(emailStatus), nil
+}
+
+func (a *AuthGRPCClient) ChangeEmail(userID uint64, email string, logger *zap.SugaredLogger) (*entity.EmailStatus, error) {
+	emailStatus, err := a.grpcClient.ChangeEmail(context.Background(), &auth.EmailChange{
+		UserID: userID,
+		Email:  email,
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return nil, errorapp.ErrorEmailTaken
+	}
+	if err != nil {
+		logger.Errorf("error in changing email: %s", err)
+		return nil, err
+	}
+	return getEmailStatusFromGRPCStruct(emailStatus), nil
+}
+
+func (a *AuthGRPCClient) VerifyEmail(verificationToken string, logger *zap.SugaredLogger) error {
+	user, err := a.grpcClient.VerifyEmail(context.Background(), &auth.EmailVerification{
+		Token: verificationToken,
+	})
+	if status.Code(err) == codes.AlreadyExists {
+		return errorapp.ErrorEmailTaken
+	}
+	if err != nil {
+		logger.Errorf("error in verifying email: %s", err)
+		return err
+	}
+	if user.ID == 0 {
+		return errorapp.ErrorBadVerificationToken
+	}
+	return nil
+}
+
+// StartOIDCLogin returns the page of the identity provider to send the user to and the binding the browser keeps
+// until the callback. With linkUserID the identity is linked to this user after the login.
+func (a *AuthGRPCClient) StartOIDCLogin(provider string, linkUserID uint64, logger *zap.SugaredLogger) (string, string, error) {
+	loginURL, err := a.grpcClient.StartOIDCLogin(context.Background(), &auth.OIDCLoginRequest{
+		Provider:   provider,
+		LinkUserID: linkUserID,
+	})
+	if err != nil {
+		logger.Errorf("error in starting oidc login: %s", err)
+		return "", "", err
+	}
+	if loginURL.URL == "" {
+		return "", "", errorapp.ErrorNoIdentityProvider
+	}
+	return loginURL.URL, loginURL.Binding, nil
+}
+
+// FinishOIDCLogin takes the binding from the browser of the callback, callerUserID is the user of the session
+// the callback is made in, 0 for an anonymous one.
+func (a *AuthGRPCClient) FinishOIDCLogin(provider, code, state, binding string, callerUserID uint64,
+	logger *zap.SugaredLogger) (*entity.User, error) {
+	loggedInUser, err := a.grpcClient.FinishOIDCLogin(context.Background(), &auth.OIDCCallback{
+		Provider:     provider,
+		Code:         code,
+		State:        state,
+		Binding:      binding,
+		CallerUserID: callerUserID,
+	})
+	if status.Code(err) == codes.Unauthenticated {
+		return nil, errorapp.ErrorOIDCLoginFailed
+	}
+	if status.Code(err) == codes.AlreadyExists {
+		return nil, errorapp.ErrorIdentityLinked
+	}
+	if status.Code(err) == codes.PermissionDenied {
+		return nil, errorapp.ErrorPermissionDenied
+	}
+	if err != nil {
+		logger.Errorf("error in finishing oidc login: %s", err)
+		return nil, err
+	}
+	if loggedInUser.ID == 0 {
+		return nil, errorapp.ErrorBadOIDCState
+	}
+	return getUserFromGRPCStruct(loggedInUser), nil
+}
+
+func (a *AuthGRPCClient) SetProfilePrivacy(userID uint64, isPrivate bool, logger *zap.SugaredLogger) error {
+	_, err := a.grpcClient.SetProfilePrivacy(context.Background(), &auth.PrivacySettings{
+		UserID:    userID,
+		IsPrivate: isPrivate,
+	})
+	if err != nil {
+		logger.Errorf("error in setting profile privacy: %s", err)
+		return err
+	}
+	return nil
+}
+
+// The methods of the roles pass the access token of the admin, service_auth checks the permission by it.
+func (a *AuthGRPCClient) GetUserRoles(token string, userID uint64, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+	userRoles, err := a.grpcClient.GetUserRoles(authpermission.WithAccessToken(context.Background(), token), &auth.User{
+		ID: userID,
+	})
+	return getUserRolesResult(userRoles, err, logger)
+}
+
+// GrantRole returns the roles of the user after the grant, granting the role the user has does nothing.
+func (a *AuthGRPCClient) GrantRole(token string, userID uint64, role string, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+	userRoles, err := a.grpcClient.GrantRole(authpermission.WithAccessToken(context.Background(), token), &auth.RoleChange{
+		UserID: userID,
+		Role:   role,
+	})
+	if status.Code(err) == codes.FailedPrecondition {
+		return nil, errorapp.ErrorEmailNotVerified
+	}
+	return getUserRolesResult(userRoles, err, logger)
+}
+
+// RevokeRole returns the roles of the user after the revoke, all the sessions of the user are ended.
+func (a *AuthGRPCClient) RevokeRole(token string, userID uint64, role string, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+	userRoles, err := a.grpcClient.RevokeRole(authpermission.WithAccessToken(context.Background(), token), &auth.RoleChange{
+		UserID: userID,
+		Role:   role,
+	})
+	if status.Code(err) == codes.FailedPrecondition {
+		return nil, errorapp.ErrorLastAdmin
+	}
+	return getUserRolesResult(userRoles, err, logger)
+}
+
+func getUserRolesResult(userRoles *auth.UserRoles, err error, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+	if authpermission.IsDenied(err) {
+		return nil, errorapp.ErrorPermissionDenied
+	}
+	if status.Code(err) == codes.InvalidArgument {
+		return nil, errorapp.ErrorUnknownRole
+	}
+	if err != nil {
+		logger.Errorf("error in changing roles: %s", err)
+		return nil, err
+	}
+	if userRoles.UserID == 0 {
+		return nil, errorapp.ErrorNoUser
+	}
+	return &entity.UserRoles{
+		UserID:      userRoles.UserID,
+		Roles:       userRoles.Roles,
+		Permissions: userRoles.Permissions,
+	}, nil
+}
+
 func getUserFromGRPCStruct(user *auth.User) *entity.User {
 	return &entity.User{
-		ID:       user.ID,
-		Username: user.Username,
+		ID:          user.ID,
+		Username:    user.Username,
+		Roles:       user.Roles,
+		Permissions: user.Permissions,
 	}
 }
 
@@ -112,9 +401,33 @@ func getGRPCUserFromEntityUser(user *entity.User) *auth.User {
 	}
 }
 
+func getGRPCClientInfoFromEntity(client *entity.ClientInfo) *auth.ClientInfo {
+	return &auth.ClientInfo{
+		Device:    client.Device,
+		IP:        client.IP,
+		UserAgent: client.UserAgent,
+	}
+}
+
+func getEmailStatusFromGRPCStruct(emailStatus *auth.EmailStatus) *entity.EmailStatus {
+	return &entity.EmailStatus{
+		Email:        emailStatus.Email,
+		Verified:     emailStatus.Email != "",
+		PendingEmail: emailStatus.PendingEmail,
+	}
+}
+
 func getSessionFromGRPCStruct(sess *auth.Session) *entity.Session {
 	return &entity.Session{
 		ID:   sess.ID,
 		User: getUserFromGRPCStruct(sess.User),
 	}
 }
+
+func getTokenPairFromGRPCStruct(tokenPair *auth.TokenPair) *entity.TokenPair {
+	return &entity.TokenPair{
+		AccessToken:  tokenPair.AccessToken,
+		RefreshToken: tokenPair.RefreshToken,
+		ExpiresIn:    tokenPair.ExpiresIn,
+	}
+}
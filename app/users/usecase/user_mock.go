@@ -35,19 +35,63 @@ func (m *MockUserUseCase) EXPECT() *MockUserUseCaseMockRecorder {
 	return m.recorder
 }
 
+// ChangeEmail mocks base method.
+func (m *MockUserUseCase) ChangeEmail(userID uint64, email string, logger *zap.SugaredLogger) (*entity.EmailStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeEmail", userID, email, logger)
+	ret0, _ := ret[0].(*entity.EmailStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeEmail indicates an expected call of ChangeEmail.
+func (mr *MockUserUseCaseMockRecorder) ChangeEmail(userID, email, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeEmail", reflect.TypeOf((*MockUserUseCase)(nil).ChangeEmail), userID, email, logger)
+}
+
+// ChangePassword mocks base method.
+func (m *MockUserUseCase) ChangePassword(token, oldPassword, newPassword string, logger *zap.SugaredLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", token, oldPassword, newPassword, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserUseCaseMockRecorder) ChangePassword(token, oldPassword, newPassword, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserUseCase)(nil).ChangePassword), token, oldPassword, newPassword, logger)
+}
+
 // CreateSession mocks base method.
-func (m *MockUserUseCase) CreateSession(user *entity.User, logger *zap.SugaredLogger) (string, error) {
+func (m *MockUserUseCase) CreateSession(user *entity.User, client *entity.ClientInfo, logger *zap.SugaredLogger) (*entity.TokenPair, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", user, logger)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "CreateSession", user, client, logger)
+	ret0, _ := ret[0].(*entity.TokenPair)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateSession indicates an expected call of CreateSession.
-func (mr *MockUserUseCaseMockRecorder) CreateSession(user, logger interface{}) *gomock.Call {
+func (mr *MockUserUseCaseMockRecorder) CreateSession(user, client, logger interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockUserUseCase)(nil).CreateSession), user, logger)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockUserUseCase)(nil).CreateSession), user, client, logger)
+}
+
+// DeleteAllSessions mocks base method.
+func (m *MockUserUseCase) DeleteAllSessions(userID uint64, logger *zap.SugaredLogger) (uint64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAllSessions", userID, logger)
+	ret0, _ := ret[0].(uint64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteAllSessions indicates an expected call of DeleteAllSessions.
+func (mr *MockUserUseCaseMockRecorder) DeleteAllSessions(userID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAllSessions", reflect.TypeOf((*MockUserUseCase)(nil).DeleteAllSessions), userID, logger)
 }
 
 // DeleteSession mocks base method.
@@ -65,6 +109,50 @@ func (mr *MockUserUseCaseMockRecorder) DeleteSession(token, logger interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSession", reflect.TypeOf((*MockUserUseCase)(nil).DeleteSession), token, logger)
 }
 
+// DeleteUserSession mocks base method.
+func (m *MockUserUseCase) DeleteUserSession(userID uint64, sessionID string, logger *zap.SugaredLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserSession", userID, sessionID, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserSession indicates an expected call of DeleteUserSession.
+func (mr *MockUserUseCaseMockRecorder) DeleteUserSession(userID, sessionID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserSession", reflect.TypeOf((*MockUserUseCase)(nil).DeleteUserSession), userID, sessionID, logger)
+}
+
+// FinishOIDCLogin mocks base method.
+func (m *MockUserUseCase) FinishOIDCLogin(provider, code, state, binding string, callerUserID uint64, logger *zap.SugaredLogger) (*entity.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishOIDCLogin", provider, code, state, binding, callerUserID, logger)
+	ret0, _ := ret[0].(*entity.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishOIDCLogin indicates an expected call of FinishOIDCLogin.
+func (mr *MockUserUseCaseMockRecorder) FinishOIDCLogin(provider, code, state, binding, callerUserID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishOIDCLogin", reflect.TypeOf((*MockUserUseCase)(nil).FinishOIDCLogin), provider, code, state, binding, callerUserID, logger)
+}
+
+// GetEmailStatus mocks base method.
+func (m *MockUserUseCase) GetEmailStatus(userID uint64, logger *zap.SugaredLogger) (*entity.EmailStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEmailStatus", userID, logger)
+	ret0, _ := ret[0].(*entity.EmailStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEmailStatus indicates an expected call of GetEmailStatus.
+func (mr *MockUserUseCaseMockRecorder) GetEmailStatus(userID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEmailStatus", reflect.TypeOf((*MockUserUseCase)(nil).GetEmailStatus), userID, logger)
+}
+
 // GetSession mocks base method.
 func (m *MockUserUseCase) GetSession(token string, logger *zap.SugaredLogger) (*entity.Session, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +168,51 @@ func (mr *MockUserUseCaseMockRecorder) GetSession(token, logger interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockUserUseCase)(nil).GetSession), token, logger)
 }
 
+// GetUserRoles mocks base method.
+func (m *MockUserUseCase) GetUserRoles(token string, userID uint64, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRoles", token, userID, logger)
+	ret0, _ := ret[0].(*entity.UserRoles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRoles indicates an expected call of GetUserRoles.
+func (mr *MockUserUseCaseMockRecorder) GetUserRoles(token, userID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRoles", reflect.TypeOf((*MockUserUseCase)(nil).GetUserRoles), token, userID, logger)
+}
+
+// GetUserSessions mocks base method.
+func (m *MockUserUseCase) GetUserSessions(token string, logger *zap.SugaredLogger) ([]*entity.UserSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserSessions", token, logger)
+	ret0, _ := ret[0].([]*entity.UserSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserSessions indicates an expected call of GetUserSessions.
+func (mr *MockUserUseCaseMockRecorder) GetUserSessions(token, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserSessions", reflect.TypeOf((*MockUserUseCase)(nil).GetUserSessions), token, logger)
+}
+
+// GrantRole mocks base method.
+func (m *MockUserUseCase) GrantRole(token string, userID uint64, role string, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GrantRole", token, userID, role, logger)
+	ret0, _ := ret[0].(*entity.UserRoles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GrantRole indicates an expected call of GrantRole.
+func (mr *MockUserUseCaseMockRecorder) GrantRole(token, userID, role, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GrantRole", reflect.TypeOf((*MockUserUseCase)(nil).GrantRole), token, userID, role, logger)
+}
+
 // Login mocks base method.
 func (m *MockUserUseCase) Login(username, password string, logger *zap.SugaredLogger) (*entity.User, error) {
 	m.ctrl.T.Helper()
@@ -95,17 +228,119 @@ func (mr *MockUserUseCaseMockRecorder) Login(username, password, logger interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserUseCase)(nil).Login), username, password, logger)
 }
 
+// RefreshTokens mocks base method.
+func (m *MockUserUseCase) RefreshTokens(refreshToken string, client *entity.ClientInfo, logger *zap.SugaredLogger) (*entity.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTokens", refreshToken, client, logger)
+	ret0, _ := ret[0].(*entity.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefreshTokens indicates an expected call of RefreshTokens.
+func (mr *MockUserUseCaseMockRecorder) RefreshTokens(refreshToken, client, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTokens", reflect.TypeOf((*MockUserUseCase)(nil).RefreshTokens), refreshToken, client, logger)
+}
+
 // Register mocks base method.
-func (m *MockUserUseCase) Register(username, password string, logger *zap.SugaredLogger) (*entity.User, error) {
+func (m *MockUserUseCase) Register(username, password, email string, logger *zap.SugaredLogger) (*entity.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", username, password, logger)
+	ret := m.ctrl.Call(m, "Register", username, password, email, logger)
 	ret0, _ := ret[0].(*entity.User)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockUserUseCaseMockRecorder) Register(username, password, logger interface{}) *gomock.Call {
+func (mr *MockUserUseCaseMockRecorder) Register(username, password, email, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUseCase)(nil).Register), username, password, email, logger)
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockUserUseCase) RequestPasswordReset(email string, logger *zap.SugaredLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", email, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockUserUseCaseMockRecorder) RequestPasswordReset(email, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockUserUseCase)(nil).RequestPasswordReset), email, logger)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserUseCase) ResetPassword(resetToken, newPassword string, logger *zap.SugaredLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", resetToken, newPassword, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserUseCaseMockRecorder) ResetPassword(resetToken, newPassword, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserUseCase)(nil).ResetPassword), resetToken, newPassword, logger)
+}
+
+// RevokeRole mocks base method.
+func (m *MockUserUseCase) RevokeRole(token string, userID uint64, role string, logger *zap.SugaredLogger) (*entity.UserRoles, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeRole", token, userID, role, logger)
+	ret0, _ := ret[0].(*entity.UserRoles)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeRole indicates an expected call of RevokeRole.
+func (mr *MockUserUseCaseMockRecorder) RevokeRole(token, userID, role, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeRole", reflect.TypeOf((*MockUserUseCase)(nil).RevokeRole), token, userID, role, logger)
+}
+
+// SetProfilePrivacy mocks base method.
+func (m *MockUserUseCase) SetProfilePrivacy(userID uint64, isPrivate bool, logger *zap.SugaredLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProfilePrivacy", userID, isPrivate, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProfilePrivacy indicates an expected call of SetProfilePrivacy.
+func (mr *MockUserUseCaseMockRecorder) SetProfilePrivacy(userID, isPrivate, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProfilePrivacy", reflect.TypeOf((*MockUserUseCase)(nil).SetProfilePrivacy), userID, isPrivate, logger)
+}
+
+// StartOIDCLogin mocks base method.
+func (m *MockUserUseCase) StartOIDCLogin(provider string, linkUserID uint64, logger *zap.SugaredLogger) (string, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartOIDCLogin", provider, linkUserID, logger)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// StartOIDCLogin indicates an expected call of StartOIDCLogin.
+func (mr *MockUserUseCaseMockRecorder) StartOIDCLogin(provider, linkUserID, logger interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartOIDCLogin", reflect.TypeOf((*MockUserUseCase)(nil).StartOIDCLogin), provider, linkUserID, logger)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockUserUseCase) VerifyEmail(verificationToken string, logger *zap.SugaredLogger) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", verificationToken, logger)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockUserUseCaseMockRecorder) VerifyEmail(verificationToken, logger interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserUseCase)(nil).Register), username, password, logger)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockUserUseCase)(nil).VerifyEmail), verificationToken, logger)
 }
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"kinopoisk/service_auth/oidcmock"
+	"log"
+	"net/http"
+)
+
+// oidc_mock is an identity provider for the local runs, it signs in everyone without a password.
+// Open /authorize?login_hint=alice to sign in as alice, without the hint the user is mock-user.
+func main() {
+	addr := flag.String("addr", ":8090", "listen address")
+	issuer := flag.String("issuer", "http://localhost:8090", "issuer, the address the services reach the provider by")
+	clientID := flag.String("client-id", "kinopoisk", "client id")
+	clientSecret := flag.String("client-secret", "mock-secret", "client secret")
+	flag.Parse()
+
+	provider, err := oidcmock.NewProvider(*issuer, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatalf("can not start the provider: %s", err)
+	}
+	log.Printf("mock oidc provider %s is listening at %s", *issuer, *addr)
+	err = http.ListenAndServe(*addr, provider)
+	if err != nil {
+		log.Fatalf("error in serving mock oidc provider: %s", err)
+	}
+}
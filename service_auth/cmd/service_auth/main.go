@@ -7,13 +7,19 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"kinopoisk/service_auth/interceptor"
+	authmail "kinopoisk/service_auth/mail"
+	authoidc "kinopoisk/service_auth/oidc"
+	authpermission "kinopoisk/service_auth/permission"
 	auth "kinopoisk/service_auth/proto"
 	userrepo "kinopoisk/service_auth/repo/mysql"
 	sessionrepo "kinopoisk/service_auth/repo/redis"
 	authserviceusecase "kinopoisk/service_auth/usecase"
 	"log"
 	"net"
+	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
@@ -22,8 +28,26 @@ import (
 const (
 	maxDBConnections  = 10
 	maxPingDBAttempts = 60
+
+	legacyPasswordsCheckInterval = time.Hour
+
+	defaultAccessTokenTTL  = 15 * time.Minute
+	defaultRefreshTokenTTL = 30 * 24 * time.Hour
+
+	defaultResetTokenTTL        = time.Hour
+	defaultVerificationTokenTTL = 24 * time.Hour
+	defaultMailFrom             = "noreply@kinopoisk.local"
+
+	oidcRequestTimeout = 10 * time.Second
 )
 
+// protectedMethods are the methods which need a permission of the caller.
+var protectedMethods = map[string]string{
+	"/auth.AuthMaker/GetUserRoles": authpermission.ManageRoles,
+	"/auth.AuthMaker/GrantRole":    authpermission.ManageRoles,
+	"/auth.AuthMaker/RevokeRole":   authpermission.ManageRoles,
+}
+
 func openMySQLConnection() (*sql.DB, error) {
 	dsn := "root:"
 	mysqlPassword := os.Getenv("pass")
@@ -107,14 +131,122 @@ func main() {
 		logger.Fatalf("can not listen port 8082: %s", err)
 	}
 	server := grpc.NewServer(
-		grpc.UnaryInterceptor(interceptor.AccessLogInterceptor),
+		grpc.ChainUnaryInterceptor(
+			interceptor.AccessLogInterceptor,
+			authpermission.UnaryInterceptor([]byte(os.Getenv("SECRET")), protectedMethods),
+		),
 	)
+	accessTokenTTL := defaultAccessTokenTTL
+	if accessTokenTTLEnv := os.Getenv("ACCESS_TOKEN_TTL"); accessTokenTTLEnv != "" {
+		accessTokenTTL, err = time.ParseDuration(accessTokenTTLEnv)
+		if err != nil || accessTokenTTL < time.Second {
+			logger.Fatalf("bad ACCESS_TOKEN_TTL: %s", accessTokenTTLEnv)
+		}
+	}
+	refreshTokenTTL := defaultRefreshTokenTTL
+	if refreshTokenTTLEnv := os.Getenv("REFRESH_TOKEN_TTL"); refreshTokenTTLEnv != "" {
+		refreshTokenTTL, err = time.ParseDuration(refreshTokenTTLEnv)
+		if err != nil || refreshTokenTTL < accessTokenTTL {
+			logger.Fatalf("bad REFRESH_TOKEN_TTL: %s", refreshTokenTTLEnv)
+		}
+	}
+	passwordReset := &authserviceusecase.LetterSettings{
+		TokenTTL: defaultResetTokenTTL,
+		URL:      os.Getenv("PASSWORD_RESET_URL"),
+	}
+	if resetTokenTTLEnv := os.Getenv("PASSWORD_RESET_TOKEN_TTL"); resetTokenTTLEnv != "" {
+		passwordReset.TokenTTL, err = time.ParseDuration(resetTokenTTLEnv)
+		if err != nil || passwordReset.TokenTTL < time.Second {
+			logger.Fatalf("bad PASSWORD_RESET_TOKEN_TTL: %s", resetTokenTTLEnv)
+		}
+	}
+	emailVerification := &authserviceusecase.LetterSettings{
+		TokenTTL: defaultVerificationTokenTTL,
+		URL:      os.Getenv("EMAIL_VERIFICATION_URL"),
+	}
+	if verificationTokenTTLEnv := os.Getenv("EMAIL_VERIFICATION_TOKEN_TTL"); verificationTokenTTLEnv != "" {
+		emailVerification.TokenTTL, err = time.ParseDuration(verificationTokenTTLEnv)
+		if err != nil || emailVerification.TokenTTL < time.Second {
+			logger.Fatalf("bad EMAIL_VERIFICATION_TOKEN_TTL: %s", verificationTokenTTLEnv)
+		}
+	}
+	mailSender, err := newMailSender(logger)
+	if err != nil {
+		logger.Fatalf("can not init mail sender: %s", err)
+	}
+	oidcProviders := make(map[string]*authoidc.Provider)
+	if oidcProvidersFile := os.Getenv("OIDC_PROVIDERS_FILE"); oidcProvidersFile != "" {
+		oidcProviders, err = authoidc.LoadProviders(oidcProvidersFile, &http.Client{Timeout: oidcRequestTimeout})
+		if err != nil {
+			logger.Fatalf("bad OIDC_PROVIDERS_FILE: %s", err)
+		}
+		logger.Infof("%d identity providers are configured", len(oidcProviders))
+	}
 	userRepo := userrepo.NewUserRepoMySQL(mySQLDb)
-	sessionRepo := sessionrepo.NewSessionRepoRedis(redisConn)
-	auth.RegisterAuthMakerServer(server, authserviceusecase.NewAuthGRPCServer(userRepo, sessionRepo))
+	sessionRepo := sessionrepo.NewSessionRepoRedis(redisConn, accessTokenTTL, refreshTokenTTL)
+	authServer := authserviceusecase.NewAuthGRPCServer(userRepo, sessionRepo, mailSender, accessTokenTTL, passwordReset,
+		emailVerification, oidcProviders)
+	if adminIDsEnv := os.Getenv("ADMIN_IDS"); adminIDsEnv != "" {
+		adminIDs, err := parseUserIDs(adminIDsEnv)
+		if err != nil {
+			logger.Fatalf("bad ADMIN_IDS: %s", err)
+		}
+		authServer.GrantInitialAdmins(logger, adminIDs)
+	}
+	if deadline := os.Getenv("LEGACY_PASSWORD_DEADLINE"); deadline != "" {
+		legacyPasswordDeadline, err := time.Parse("2006-01-02", deadline)
+		if err != nil {
+			logger.Fatalf("bad LEGACY_PASSWORD_DEADLINE: %s", err)
+		}
+		go expireLegacyPasswords(logger, authServer, legacyPasswordDeadline)
+	}
+	auth.RegisterAuthMakerServer(server, authServer)
 	logger.Info("starting server at :8082")
 	err = server.Serve(lis)
 	if err != nil {
 		logger.Fatalf("error in serving server on port 8082 %s", err)
 	}
 }
+
+// parseUserIDs reads comma separated ids of the users, for example "1,5,12".
+func parseUserIDs(userIDs string) ([]uint64, error) {
+	ids := make([]uint64, 0)
+	for _, userID := range strings.Split(userIDs, ",") {
+		userID = strings.TrimSpace(userID)
+		if userID == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(userID, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// newMailSender sends the letters through SMTP_ADDR, without it the letters are only written to the log.
+func newMailSender(logger *zap.SugaredLogger) (authmail.Sender, error) {
+	smtpAddr := os.Getenv("SMTP_ADDR")
+	if smtpAddr == "" {
+		logger.Infof("SMTP_ADDR is not set, letters are written to the log")
+		return authmail.NewLogSender(logger), nil
+	}
+	mailFrom := os.Getenv("MAIL_FROM")
+	if mailFrom == "" {
+		mailFrom = defaultMailFrom
+	}
+	return authmail.NewSMTPSender(smtpAddr, mailFrom, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+}
+
+// expireLegacyPasswords forces the users who have not logged in since the deadline, so their password hashes
+// have not been upgraded, to reset their passwords.
+func expireLegacyPasswords(logger *zap.SugaredLogger, authServer *authserviceusecase.AuthGRPCServer, deadline time.Time) {
+	ticker := time.NewTicker(legacyPasswordsCheckInterval)
+	defer ticker.Stop()
+	for ; ; <-ticker.C {
+		if time.Now().After(deadline) {
+			authServer.ExpireLegacyPasswords(logger)
+		}
+	}
+}
@@ -1,10 +1,23 @@
 package errorauth
 
-import "errors"
+import (
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 var (
 	ErrorUserNotExist      = errors.New("no user with such username")
 	ErrorBadPassword       = errors.New("wrong password for this user")
 	ErrorUserAlreadyExists = errors.New("user with such username already exist")
 	ErrorNoLogger          = errors.New("there is no logger in context")
+
+	ErrorRefreshTokenReused = status.Error(codes.Unauthenticated, "refresh token reused, token family revoked")
+	ErrorEmailTaken         = status.Error(codes.AlreadyExists, "email is used by another user")
+	ErrorIdentityLinked     = status.Error(codes.AlreadyExists, "identity is linked to another user")
+	ErrorOIDCLoginFailed    = status.Error(codes.Unauthenticated, "identity provider did not confirm the login")
+	ErrorNotLinkingUser     = status.Error(codes.PermissionDenied, "identity is linked only by the user who started the linking")
+	ErrorUnknownRole        = status.Error(codes.InvalidArgument, "no such role")
+	ErrorLastAdmin          = status.Error(codes.FailedPrecondition, "last admin can not lose the admin role")
+	ErrorEmailNotVerified   = status.Error(codes.FailedPrecondition, "user has no verified email")
 )
@@ -0,0 +1,71 @@
+package authmail
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Sender sends plain text letters to the users.
+type Sender interface {
+	Send(to, subject, body string) error
+}
+
+// SMTPSender sends letters through an SMTP server. Without the username it does not authenticate,
+// that is enough for a local fake server such as MailHog.
+type SMTPSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPSender(addr, from, username, password string) (*SMTPSender, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	sender := &SMTPSender{
+		addr: addr,
+		from: from,
+	}
+	if username != "" {
+		sender.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return sender, nil
+}
+
+func (s *SMTPSender) Send(to, subject, body string) error {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return fmt.Errorf("line break in mail header")
+	}
+	message := strings.Join([]string{
+		"From: " + s.from,
+		"To: " + to,
+		"Subject: " + subject,
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		strings.ReplaceAll(body, "\n", "\r\n"),
+	}, "\r\n")
+	return smtp.SendMail(s.addr, s.auth, s.from, []string{to}, []byte(message))
+}
+
+// LogSender writes the letters to the log instead of sending them, it is used when SMTP is not configured.
+type LogSender struct {
+	logger *zap.SugaredLogger
+}
+
+func NewLogSender(logger *zap.SugaredLogger) *LogSender {
+	return &LogSender{
+		logger: logger,
+	}
+}
+
+func (s *LogSender) Send(to, subject, body string) error {
+	s.logger.Infof("mail to %s, subject %q: %s", to, subject, body)
+	return nil
+}
@@ -0,0 +1,142 @@
+package authmail_test
+
+import (
+	"bufio"
+	authmail "kinopoisk/service_auth/mail"
+	"net"
+	"strings"
+	"testing"
+)
+
+// smtpLetter is what the fake SMTP server has got in one session.
+type smtpLetter struct {
+	from string
+	to   []string
+	data string
+}
+
+// serveSMTP accepts one session on the listener and answers the commands net/smtp sends without auth and TLS.
+func serveSMTP(listener net.Listener, letters chan<- *smtpLetter) {
+	conn, err := listener.Accept()
+	if err != nil {
+		close(letters)
+		return
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	reply := func(line string) {
+		_, _ = conn.Write([]byte(line + "\r\n"))
+	}
+	letter := &smtpLetter{}
+	reply("220 fake ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(command, "EHLO"):
+			reply("250 fake")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			letter.from = strings.TrimPrefix(command, "MAIL FROM:")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			letter.to = append(letter.to, strings.TrimPrefix(command, "RCPT TO:"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 end data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			letter.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 bye")
+			letters <- letter
+			return
+		default:
+			reply("502 unknown command")
+		}
+	}
+}
+
+func TestSMTPSenderSend(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("can not listen: %s", err)
+	}
+	defer listener.Close()
+	letters := make(chan *smtpLetter, 1)
+	go serveSMTP(listener, letters)
+
+	sender, err := authmail.NewSMTPSender(listener.Addr().String(), "noreply@kinopoisk.local", "", "")
+	if err != nil {
+		t.Fatalf("can not create sender: %s", err)
+	}
+	err = sender.Send("user@example.com", "Password reset", "Your token:\nabc\n.dot line")
+	if err != nil {
+		t.Fatalf("can not send: %s", err)
+	}
+	letter := <-letters
+	if letter == nil {
+		t.Fatalf("no letter is sent")
+	}
+	if letter.from != "<noreply@kinopoisk.local>" {
+		t.Errorf("unexpected envelope sender %s", letter.from)
+	}
+	if len(letter.to) != 1 || letter.to[0] != "<user@example.com>" {
+		t.Errorf("unexpected envelope recipients %v", letter.to)
+	}
+
+	header, body, found := strings.Cut(letter.data, "\r\n\r\n")
+	if !found {
+		t.Fatalf("no header in %q", letter.data)
+	}
+	for _, expectedHeader := range []string{
+		"From: noreply@kinopoisk.local",
+		"To: user@example.com",
+		"Subject: Password reset",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+	} {
+		if !strings.Contains(header+"\r\n", expectedHeader+"\r\n") {
+			t.Errorf("no header %q in %q", expectedHeader, header)
+		}
+	}
+	if !strings.Contains(header, "\r\nDate: ") {
+		t.Errorf("no date in %q", header)
+	}
+	if expectedBody := "Your token:\r\nabc\r\n.dot line\r\n"; body != expectedBody {
+		t.Errorf("expected body %q, got %q", expectedBody, body)
+	}
+}
+
+func TestSMTPSenderHeaderInjection(t *testing.T) {
+	// nothing listens on the port: the letter must be refused before connecting
+	sender, err := authmail.NewSMTPSender("127.0.0.1:1", "noreply@kinopoisk.local", "", "")
+	if err != nil {
+		t.Fatalf("can not create sender: %s", err)
+	}
+	cases := []struct {
+		to      string
+		subject string
+	}{
+		{to: "user@example.com\r\nBcc: victim@example.com", subject: "Password reset"},
+		{to: "user@example.com", subject: "Password reset\nBcc: victim@example.com"},
+	}
+	for _, testCase := range cases {
+		err = sender.Send(testCase.to, testCase.subject, "body")
+		if err == nil || !strings.Contains(err.Error(), "line break") {
+			t.Errorf("expected line break error for %q, %q, got %v", testCase.to, testCase.subject, err)
+		}
+	}
+}
@@ -0,0 +1,353 @@
+package authoidc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+
+	// keysRefreshInterval limits fetching of the keys when an id token is signed with an unknown key
+	keysRefreshInterval = time.Minute
+	maxResponseSize     = 1 << 20
+	verifierLength      = 32
+)
+
+var (
+	ErrBadIDToken = errors.New("id token is not valid")
+
+	providerNameRegexp = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+)
+
+// ProviderConfig is one identity provider in the providers file.
+type ProviderConfig struct {
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	RedirectURL  string   `json:"redirect_url"`
+	Scopes       []string `json:"scopes"`
+	// AuthorizationURL replaces the discovered one, it is needed when the browser reaches the provider
+	// by another address than the service, as in docker compose
+	AuthorizationURL string `json:"authorization_url"`
+	// LinkByEmail logs the user in to the account with the same verified email. It must be set only
+	// for the providers which do not let the users claim the emails they do not own.
+	LinkByEmail bool `json:"link_by_email"`
+}
+
+// Identity is the user confirmed by the provider.
+type Identity struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	Name              string
+}
+
+type discoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type tokenResponse struct {
+	IDToken string `json:"id_token"`
+}
+
+type jsonWebKeySet struct {
+	Keys []struct {
+		KeyType string `json:"kty"`
+		KeyID   string `json:"kid"`
+		Use     string `json:"use"`
+		N       string `json:"n"`
+		E       string `json:"e"`
+	} `json:"keys"`
+}
+
+// Provider makes the authorization code flow with PKCE with one identity provider. The discovery document
+// and the signing keys are fetched on the first use and cached.
+type Provider struct {
+	Config     ProviderConfig
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	discovery     *discoveryDocument
+	keys          map[string]*rsa.PublicKey
+	keysFetchedAt time.Time
+}
+
+func NewProvider(config ProviderConfig, httpClient *http.Client) *Provider {
+	return &Provider{
+		Config:     config,
+		httpClient: httpClient,
+	}
+}
+
+// LoadProviders reads the JSON array of the provider configs from the file.
+func LoadProviders(path string, httpClient *http.Client) (map[string]*Provider, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	configs := make([]ProviderConfig, 0)
+	err = json.Unmarshal(data, &configs)
+	if err != nil {
+		return nil, err
+	}
+	providers := make(map[string]*Provider, len(configs))
+	for _, config := range configs {
+		if !providerNameRegexp.MatchString(config.Name) {
+			return nil, fmt.Errorf("bad provider name %q", config.Name)
+		}
+		if _, ok := providers[config.Name]; ok {
+			return nil, fmt.Errorf("provider %s is configured twice", config.Name)
+		}
+		if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+			return nil, fmt.Errorf("provider %s needs issuer, client_id and redirect_url", config.Name)
+		}
+		if len(config.Scopes) == 0 {
+			config.Scopes = []string{"openid", "email", "profile"}
+		}
+		providers[config.Name] = NewProvider(config, httpClient)
+	}
+	return providers, nil
+}
+
+// NewPKCE returns the code verifier and its S256 code challenge.
+func NewPKCE() (string, string, error) {
+	verifier, err := newRandomString(verifierLength)
+	if err != nil {
+		return "", "", err
+	}
+	return verifier, codeChallenge(verifier), nil
+}
+
+func newRandomString(length int) (string, error) {
+	randomBytes := make([]byte, length)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+func codeChallenge(verifier string) string {
+	challenge := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(challenge[:])
+}
+
+// AuthCodeURL returns the page of the provider the user signs in at.
+func (p *Provider) AuthCodeURL(state, challenge, nonce string) (string, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return "", err
+	}
+	authorizationURL := discovery.AuthorizationEndpoint
+	if p.Config.AuthorizationURL != "" {
+		authorizationURL = p.Config.AuthorizationURL
+	}
+	authURL, err := url.Parse(authorizationURL)
+	if err != nil {
+		return "", err
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.Config.ClientID)
+	query.Set("redirect_uri", p.Config.RedirectURL)
+	query.Set("scope", strings.Join(p.Config.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", challenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+	return authURL.String(), nil
+}
+
+// Exchange redeems the authorization code and returns the identity from the verified id token.
+func (p *Provider) Exchange(code, verifier, nonce string) (*Identity, error) {
+	discovery, err := p.getDiscovery()
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.Config.RedirectURL)
+	form.Set("code_verifier", verifier)
+	req, err := http.NewRequest(http.MethodPost, discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(p.Config.ClientID), url.QueryEscape(p.Config.ClientSecret))
+	tokens := &tokenResponse{}
+	err = p.doJSON(req, tokens)
+	if err != nil {
+		return nil, fmt.Errorf("token request: %w", err)
+	}
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: no id token in the token response", ErrBadIDToken)
+	}
+	return p.verifyIDToken(tokens.IDToken, discovery.Issuer, nonce)
+}
+
+func (p *Provider) verifyIDToken(rawIDToken, issuer, nonce string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (interface{}, error) {
+		if token.Method != jwt.SigningMethodRS256 {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		keyID, _ := token.Header["kid"].(string)
+		return p.getKey(keyID)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrBadIDToken, err)
+	}
+	if _, ok := claims["exp"]; !ok {
+		return nil, fmt.Errorf("%w: no exp", ErrBadIDToken)
+	}
+	if !claims.VerifyIssuer(issuer, true) {
+		return nil, fmt.Errorf("%w: issuer is not %s", ErrBadIDToken, issuer)
+	}
+	if !hasAudience(claims["aud"], p.Config.ClientID) {
+		return nil, fmt.Errorf("%w: token is not for %s", ErrBadIDToken, p.Config.ClientID)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce does not match", ErrBadIDToken)
+	}
+	identity := &Identity{}
+	identity.Subject, _ = claims["sub"].(string)
+	if identity.Subject == "" {
+		return nil, fmt.Errorf("%w: no sub", ErrBadIDToken)
+	}
+	identity.Email, _ = claims["email"].(string)
+	identity.PreferredUsername, _ = claims["preferred_username"].(string)
+	identity.Name, _ = claims["name"].(string)
+	// some providers send the flag as a string
+	switch emailVerified := claims["email_verified"].(type) {
+	case bool:
+		identity.EmailVerified = emailVerified
+	case string:
+		identity.EmailVerified = emailVerified == "true"
+	}
+	return identity, nil
+}
+
+func hasAudience(aud interface{}, clientID string) bool {
+	switch aud := aud.(type) {
+	case string:
+		return aud == clientID
+	case []interface{}:
+		for _, audience := range aud {
+			if audience == clientID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *Provider) getDiscovery() (*discoveryDocument, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(p.Config.Issuer, "/")+discoveryPath, nil)
+	if err != nil {
+		return nil, err
+	}
+	discovery := &discoveryDocument{}
+	err = p.doJSON(req, discovery)
+	if err != nil {
+		return nil, fmt.Errorf("discovery of %s: %w", p.Config.Issuer, err)
+	}
+	if discovery.Issuer != p.Config.Issuer {
+		return nil, fmt.Errorf("discovery of %s returned issuer %s", p.Config.Issuer, discovery.Issuer)
+	}
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return nil, fmt.Errorf("discovery of %s has no endpoints", p.Config.Issuer)
+	}
+	p.discovery = discovery
+	return discovery, nil
+}
+
+// getKey returns the signing key by its id, the keys are fetched again if the provider has rotated them.
+func (p *Provider) getKey(keyID string) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if key, ok := p.keys[keyID]; ok {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+	p.keysFetchedAt = time.Now()
+	req, err := http.NewRequest(http.MethodGet, p.discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	keySet := &jsonWebKeySet{}
+	err = p.doJSON(req, keySet)
+	if err != nil {
+		return nil, fmt.Errorf("keys request: %w", err)
+	}
+	keys := make(map[string]*rsa.PublicKey, len(keySet.Keys))
+	for _, jsonKey := range keySet.Keys {
+		if jsonKey.KeyType != "RSA" || (jsonKey.Use != "" && jsonKey.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(jsonKey.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(jsonKey.E)
+		if err != nil || len(e) > 4 {
+			continue
+		}
+		keys[jsonKey.KeyID] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	p.keys = keys
+	key, ok := keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %q", keyID)
+	}
+	return key, nil
+}
+
+func (p *Provider) doJSON(req *http.Request, result interface{}) error {
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %d: %s", resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, result)
+}
@@ -0,0 +1,258 @@
+package oidcmock
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/dgrijalva/jwt-go"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	keyBits        = 2048
+	codeLength     = 32
+	codeTTL        = time.Minute
+	idTokenTTL     = time.Hour
+	defaultSubject = "mock-user"
+	emailDomain    = "@oidc-mock.local"
+)
+
+// authorization is the code given to the client and what it must be redeemed with.
+type authorization struct {
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	subject       string
+	expiresAt     time.Time
+}
+
+// Provider is an OpenID Connect provider for the local runs and tests. It approves every login without asking,
+// the login_hint parameter of the authorization request is the subject, so different users can be simulated.
+// The signing key is generated on start, the provider keeps nothing on restart.
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	key          *rsa.PrivateKey
+	keyID        string
+	mux          *http.ServeMux
+
+	mu    sync.Mutex
+	codes map[string]*authorization
+	// claims replace the claims of the id tokens, the tests make the tokens a client must refuse with them
+	claims map[string]interface{}
+}
+
+func NewProvider(issuer, clientID, clientSecret string) (*Provider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, err
+	}
+	keyID, err := newRandomString(8)
+	if err != nil {
+		return nil, err
+	}
+	p := &Provider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		key:          key,
+		keyID:        keyID,
+		mux:          http.NewServeMux(),
+		codes:        make(map[string]*authorization),
+	}
+	p.mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	p.mux.HandleFunc("/authorize", p.authorize)
+	p.mux.HandleFunc("/token", p.token)
+	p.mux.HandleFunc("/jwks", p.jwks)
+	return p, nil
+}
+
+// OverrideClaims sets the claims of the next id tokens, nil brings back the right ones.
+func (p *Provider) OverrideClaims(claims map[string]interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.claims = claims
+}
+
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mux.ServeHTTP(w, r)
+}
+
+func (p *Provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	})
+}
+
+// authorize redirects back with the code at once, the errors before the redirect uri is known are shown as is.
+func (p *Provider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != p.clientID {
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "bad redirect_uri", http.StatusBadRequest)
+		return
+	}
+	redirectQuery := redirectURI.Query()
+	redirectQuery.Set("state", query.Get("state"))
+	switch {
+	case query.Get("response_type") != "code":
+		redirectQuery.Set("error", "unsupported_response_type")
+	case !strings.Contains(" "+query.Get("scope")+" ", " openid "):
+		redirectQuery.Set("error", "invalid_scope")
+	case query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256":
+		redirectQuery.Set("error", "invalid_request")
+		redirectQuery.Set("error_description", "S256 code challenge is required")
+	default:
+		code, err := newRandomString(codeLength)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		subject := query.Get("login_hint")
+		if subject == "" {
+			subject = defaultSubject
+		}
+		p.mu.Lock()
+		p.codes[code] = &authorization{
+			redirectURI:   redirectURI.String(),
+			codeChallenge: query.Get("code_challenge"),
+			nonce:         query.Get("nonce"),
+			subject:       subject,
+			expiresAt:     time.Now().Add(codeTTL),
+		}
+		p.mu.Unlock()
+		redirectQuery.Set("code", code)
+	}
+	redirectURI.RawQuery = redirectQuery.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *Provider) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeTokenError(w, http.StatusMethodNotAllowed, "invalid_request")
+		return
+	}
+	err := r.ParseForm()
+	if err != nil {
+		writeTokenError(w, http.StatusBadRequest, "invalid_request")
+		return
+	}
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientID, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != p.clientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(p.clientSecret)) != 1 {
+		writeTokenError(w, http.StatusUnauthorized, "invalid_client")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type")
+		return
+	}
+	p.mu.Lock()
+	code := r.PostForm.Get("code")
+	auth, ok := p.codes[code]
+	delete(p.codes, code)
+	overriddenClaims := p.claims
+	p.mu.Unlock()
+	if !ok || time.Now().After(auth.expiresAt) || auth.redirectURI != r.PostForm.Get("redirect_uri") {
+		writeTokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != auth.codeChallenge {
+		writeTokenError(w, http.StatusBadRequest, "invalid_grant")
+		return
+	}
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                p.issuer,
+		"sub":                auth.subject,
+		"aud":                p.clientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(idTokenTTL).Unix(),
+		"nonce":              auth.nonce,
+		"email":              auth.subject + emailDomain,
+		"email_verified":     true,
+		"preferred_username": auth.subject,
+		"name":               auth.subject,
+	}
+	for name, value := range overriddenClaims {
+		claims[name] = value
+	}
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	idToken.Header["kid"] = p.keyID
+	signedIDToken, err := idToken.SignedString(p.key)
+	if err != nil {
+		writeTokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	accessToken, err := newRandomString(codeLength)
+	if err != nil {
+		writeTokenError(w, http.StatusInternalServerError, "server_error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(idTokenTTL.Seconds()),
+		"id_token":     signedIDToken,
+	})
+}
+
+func (p *Provider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": p.keyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(p.key.PublicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(p.key.PublicKey.E)).Bytes()),
+		}},
+	})
+}
+
+func writeTokenError(w http.ResponseWriter, code int, errorCode string) {
+	writeJSON(w, code, map[string]string{"error": errorCode})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func newRandomString(length int) (string, error) {
+	randomBytes := make([]byte, length)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
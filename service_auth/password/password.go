@@ -0,0 +1,115 @@
+package authpassword
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"strings"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+var ErrBadHash = errors.New("password hash has unknown format")
+
+// Params are the Argon2id parameters of the new hashes, Memory is in KiB.
+type Params struct {
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// DefaultParams follow the second recommended option of RFC 9106 with a lower memory cost.
+var DefaultParams = &Params{
+	Memory:      64 * 1024,
+	Iterations:  3,
+	Parallelism: 2,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+// Hash returns the Argon2id hash of the password with a random salt in the PHC string format,
+// for example $argon2id$v=19$m=65536,t=3,p=2$<salt>$<hash>, so the parameters can be changed later.
+func Hash(password string, params *Params) (string, error) {
+	salt := make([]byte, params.SaltLength)
+	_, err := rand.Read(salt)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, params.Memory, params.Iterations,
+		params.Parallelism, base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Verify checks the password against the Argon2id hash or the legacy unsalted SHA-256 hash.
+// needsRehash is set for a matching password whose hash is legacy or made with other parameters.
+func Verify(password, encodedHash string, params *Params) (ok bool, needsRehash bool, err error) {
+	if IsLegacy(encodedHash) {
+		legacyHash := sha256.Sum256([]byte(password))
+		ok = subtle.ConstantTimeCompare([]byte(hex.EncodeToString(legacyHash[:])), []byte(encodedHash)) == 1
+		return ok, ok, nil
+	}
+	hashParams, salt, key, err := decodeHash(encodedHash)
+	if err != nil {
+		return false, false, err
+	}
+	passwordKey := argon2.IDKey([]byte(password), salt, hashParams.Iterations, hashParams.Memory, hashParams.Parallelism, hashParams.KeyLength)
+	if subtle.ConstantTimeCompare(passwordKey, key) != 1 {
+		return false, false, nil
+	}
+	return true, *hashParams != *params, nil
+}
+
+// VerifyDummy takes as long as Verify of a wrong password against a hash made with the params, so a login
+// of an unknown user or of a user without a password can not be told apart by its time.
+func VerifyDummy(password string, params *Params) {
+	salt := make([]byte, params.SaltLength)
+	argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+}
+
+// IsLegacy reports if the hash is the unsalted SHA-256 hex digest the passwords were stored with before Argon2id.
+func IsLegacy(encodedHash string) bool {
+	if len(encodedHash) != hex.EncodedLen(sha256.Size) {
+		return false
+	}
+	_, err := hex.DecodeString(encodedHash)
+	return err == nil
+}
+
+func decodeHash(encodedHash string) (*Params, []byte, []byte, error) {
+	if !strings.HasPrefix(encodedHash, argon2idPrefix) {
+		return nil, nil, nil, ErrBadHash
+	}
+	parts := strings.Split(strings.TrimPrefix(encodedHash, argon2idPrefix), "$")
+	if len(parts) != 4 {
+		return nil, nil, nil, ErrBadHash
+	}
+	var version int
+	_, err := fmt.Sscanf(parts[0], "v=%d", &version)
+	if err != nil || version != argon2.Version {
+		return nil, nil, nil, ErrBadHash
+	}
+	params := &Params{}
+	_, err = fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism)
+	// argon2 panics on zero iterations or parallelism
+	if err != nil || params.Iterations == 0 || params.Parallelism == 0 {
+		return nil, nil, nil, ErrBadHash
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, nil, nil, ErrBadHash
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil || len(salt) == 0 || len(key) == 0 {
+		return nil, nil, nil, ErrBadHash
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+	return params, salt, key, nil
+}
@@ -0,0 +1,117 @@
+package authpassword_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	authpassword "kinopoisk/service_auth/password"
+	"strings"
+	"testing"
+)
+
+// testParams keep the tests fast, the format of the hash does not depend on the costs.
+var testParams = &authpassword.Params{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestHashVerify(t *testing.T) {
+	hash, err := authpassword.Hash("secret password", testParams)
+	if err != nil {
+		t.Fatalf("can not hash password: %s", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=1024,t=1,p=1$") {
+		t.Errorf("unexpected hash format: %s", hash)
+	}
+	otherHash, err := authpassword.Hash("secret password", testParams)
+	if err != nil {
+		t.Fatalf("can not hash password: %s", err)
+	}
+	if otherHash == hash {
+		t.Errorf("hashes of the same password have the same salt")
+	}
+
+	cases := []struct {
+		name                string
+		password            string
+		params              *authpassword.Params
+		expectedOK          bool
+		expectedNeedsRehash bool
+	}{
+		{name: "right password", password: "secret password", params: testParams, expectedOK: true},
+		{name: "wrong password", password: "secret passworD", params: testParams},
+		{name: "empty password", password: "", params: testParams},
+		{name: "params have changed", password: "secret password", params: authpassword.DefaultParams,
+			expectedOK: true, expectedNeedsRehash: true},
+		{name: "wrong password, params have changed", password: "wrong", params: authpassword.DefaultParams},
+	}
+	for _, testCase := range cases {
+		ok, needsRehash, err := authpassword.Verify(testCase.password, hash, testCase.params)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		if ok != testCase.expectedOK || needsRehash != testCase.expectedNeedsRehash {
+			t.Errorf("%s: expected ok %t and needs rehash %t, got %t and %t", testCase.name,
+				testCase.expectedOK, testCase.expectedNeedsRehash, ok, needsRehash)
+		}
+	}
+}
+
+func TestVerifyLegacy(t *testing.T) {
+	legacyHash := sha256.Sum256([]byte("old password"))
+	encodedHash := hex.EncodeToString(legacyHash[:])
+	if !authpassword.IsLegacy(encodedHash) {
+		t.Fatalf("legacy hash is not recognized")
+	}
+
+	ok, needsRehash, err := authpassword.Verify("old password", encodedHash, testParams)
+	if err != nil || !ok || !needsRehash {
+		t.Errorf("expected right legacy password to need rehash, got ok %t, needs rehash %t, error %v", ok, needsRehash, err)
+	}
+	ok, needsRehash, err = authpassword.Verify("wrong password", encodedHash, testParams)
+	if err != nil || ok || needsRehash {
+		t.Errorf("expected wrong legacy password to fail, got ok %t, needs rehash %t, error %v", ok, needsRehash, err)
+	}
+}
+
+func TestVerifyBadHash(t *testing.T) {
+	hash, err := authpassword.Hash("secret password", testParams)
+	if err != nil {
+		t.Fatalf("can not hash password: %s", err)
+	}
+	parts := strings.Split(hash, "$")
+
+	cases := []struct {
+		name string
+		hash string
+	}{
+		{name: "empty", hash: ""},
+		{name: "unknown algorithm", hash: strings.Replace(hash, "argon2id", "argon2i", 1)},
+		{name: "truncated after prefix", hash: "$argon2id$"},
+		{name: "truncated after version", hash: strings.Join(parts[:3], "$")},
+		{name: "truncated after params", hash: strings.Join(parts[:4], "$")},
+		{name: "no key", hash: strings.Join(parts[:5], "$") + "$"},
+		{name: "extra part", hash: hash + "$extra"},
+		{name: "other version", hash: strings.Replace(hash, "v=19", "v=16", 1)},
+		{name: "bad params", hash: strings.Replace(hash, "m=1024,t=1,p=1", "m=1024,t=1", 1)},
+		{name: "zero iterations", hash: strings.Replace(hash, "t=1", "t=0", 1)},
+		{name: "zero parallelism", hash: strings.Replace(hash, "p=1", "p=0", 1)},
+		{name: "empty salt", hash: strings.Replace(hash, "$"+parts[4]+"$", "$$", 1)},
+		{name: "bad salt", hash: strings.Replace(hash, parts[4], "!!!", 1)},
+		{name: "bad key", hash: strings.Replace(hash, parts[5], "!!!", 1)},
+		{name: "hex of other length", hash: strings.Repeat("a", 63)},
+		{name: "not hex", hash: strings.Repeat("z", 64)},
+	}
+	for _, testCase := range cases {
+		ok, needsRehash, err := authpassword.Verify("secret password", testCase.hash, testParams)
+		if err != authpassword.ErrBadHash {
+			t.Errorf("%s: expected error %s, got %v", testCase.name, authpassword.ErrBadHash, err)
+		}
+		if ok || needsRehash {
+			t.Errorf("%s: bad hash is accepted", testCase.name)
+		}
+	}
+}
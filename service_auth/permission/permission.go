@@ -0,0 +1,125 @@
+package authpermission
+
+import (
+	"context"
+	"fmt"
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	auth "kinopoisk/service_auth/proto"
+	"strings"
+)
+
+// The permissions are granted through the roles, the roles are stored in the role_permissions table of service_auth.
+const (
+	ModerateReviews = "reviews:moderate"
+	ManageRoles     = "roles:manage"
+	// CriticReviews is what the critic role gives: the reviews of the user count toward the critic score of the film.
+	CriticReviews = "reviews:critic"
+)
+
+// AdminRole has all the permissions, the last admin can not lose it.
+const AdminRole = "admin"
+
+// CriticRole is granted only to the users with a verified email, the reviews are marked as written by a critic
+// by it.
+const CriticRole = "critic"
+
+const authorizationKey = "authorization"
+
+var (
+	ErrorNoAccessToken    = status.Error(codes.Unauthenticated, "no access token")
+	ErrorBadAccessToken   = status.Error(codes.Unauthenticated, "access token is invalid or expired")
+	ErrorPermissionDenied = status.Error(codes.PermissionDenied, "permission denied")
+)
+
+type userKey int
+
+const myUserKey userKey = 1
+
+type tokenClaims struct {
+	User *auth.User `json:"user"`
+	jwt.StandardClaims
+}
+
+// HasPermission reports if the user has the permission, the user has the permissions of all its roles.
+func HasPermission(permissions []string, permission string) bool {
+	for _, userPermission := range permissions {
+		if userPermission == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsDenied reports if the called service has refused the call for the lack of the token or the permission.
+func IsDenied(err error) bool {
+	code := status.Code(err)
+	return code == codes.PermissionDenied || code == codes.Unauthenticated
+}
+
+// WithAccessToken passes the access token of the user to the called service, it is checked by UnaryInterceptor there.
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, authorizationKey, "Bearer "+token)
+}
+
+// UserFromContext returns the user of the access token checked by UnaryInterceptor, or nil if the call
+// has come without a valid token.
+func UserFromContext(ctx context.Context) *auth.User {
+	user, _ := ctx.Value(myUserKey).(*auth.User)
+	return user
+}
+
+// UnaryInterceptor lets the methods from required through only with an access token of service_auth
+// which has the permission. The token is checked by the signature, so it works until it expires
+// even if the session is ended; the gateway checks the session itself. The other methods are open,
+// a valid token only puts its user into the context for them.
+func UnaryInterceptor(secret []byte, required map[string]string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		permission, ok := required[info.FullMethod]
+		user, err := userFromToken(ctx, secret)
+		if !ok {
+			if err == nil {
+				ctx = context.WithValue(ctx, myUserKey, user)
+			}
+			return handler(ctx, req)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !HasPermission(user.Permissions, permission) {
+			return nil, ErrorPermissionDenied
+		}
+		return handler(context.WithValue(ctx, myUserKey, user), req)
+	}
+}
+
+func userFromToken(ctx context.Context, secret []byte) (*auth.User, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	authHeaders := md.Get(authorizationKey)
+	if len(authHeaders) == 0 || !strings.HasPrefix(authHeaders[0], "Bearer ") {
+		return nil, ErrorNoAccessToken
+	}
+	claims := &tokenClaims{}
+	token, err := jwt.ParseWithClaims(strings.TrimPrefix(authHeaders[0], "Bearer "), claims,
+		func(token *jwt.Token) (interface{}, error) {
+			if token.Method != jwt.SigningMethodHS256 {
+				return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+			}
+			return secret, nil
+		})
+	if err != nil || !token.Valid {
+		return nil, ErrorBadAccessToken
+	}
+	if claims.User == nil || claims.User.ID == 0 {
+		return nil, ErrorBadAccessToken
+	}
+	return claims.User, nil
+}
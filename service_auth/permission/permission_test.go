@@ -0,0 +1,92 @@
+package authpermission_test
+
+import (
+	"context"
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	authpermission "kinopoisk/service_auth/permission"
+	auth "kinopoisk/service_auth/proto"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test secret")
+
+const (
+	protectedMethod = "/auth.AuthMaker/GrantRole"
+	openMethod      = "/auth.AuthMaker/GetSession"
+)
+
+func newToken(t *testing.T, method jwt.SigningMethod, key interface{}, permissions []string, expiresAt time.Time) string {
+	token := jwt.NewWithClaims(method, jwt.MapClaims{
+		"user": &auth.User{ID: 1, Username: "admin", Permissions: permissions},
+		"exp":  expiresAt.Unix(),
+	})
+	signedToken, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("can not sign token: %s", err)
+	}
+	return signedToken
+}
+
+// callWithToken makes the call through the interceptor and returns the user the handler has got.
+func callWithToken(token, method string) (*auth.User, bool, error) {
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+	}
+	interceptor := authpermission.UnaryInterceptor(testSecret, map[string]string{protectedMethod: authpermission.ManageRoles})
+	var handlerUser *auth.User
+	handlerCalled := false
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			handlerCalled = true
+			handlerUser = authpermission.UserFromContext(ctx)
+			return nil, nil
+		})
+	return handlerUser, handlerCalled, err
+}
+
+func TestUnaryInterceptor(t *testing.T) {
+	inHour := time.Now().Add(time.Hour)
+	validToken := newToken(t, jwt.SigningMethodHS256, testSecret, []string{authpermission.ManageRoles}, inHour)
+
+	cases := []struct {
+		name         string
+		token        string
+		method       string
+		expectedErr  error
+		expectedCall bool
+		expectedUser bool
+	}{
+		{name: "no token", method: protectedMethod, expectedErr: authpermission.ErrorNoAccessToken},
+		{name: "wrong alg", method: protectedMethod, expectedErr: authpermission.ErrorBadAccessToken,
+			token: newToken(t, jwt.SigningMethodHS512, testSecret, []string{authpermission.ManageRoles}, inHour)},
+		{name: "no signature", method: protectedMethod, expectedErr: authpermission.ErrorBadAccessToken,
+			token: newToken(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, []string{authpermission.ManageRoles}, inHour)},
+		{name: "other secret", method: protectedMethod, expectedErr: authpermission.ErrorBadAccessToken,
+			token: newToken(t, jwt.SigningMethodHS256, []byte("other secret"), []string{authpermission.ManageRoles}, inHour)},
+		{name: "expired token", method: protectedMethod, expectedErr: authpermission.ErrorBadAccessToken,
+			token: newToken(t, jwt.SigningMethodHS256, testSecret, []string{authpermission.ManageRoles}, time.Now().Add(-time.Minute))},
+		{name: "missing permission", method: protectedMethod, expectedErr: authpermission.ErrorPermissionDenied,
+			token: newToken(t, jwt.SigningMethodHS256, testSecret, []string{authpermission.ModerateReviews}, inHour)},
+		{name: "permission", method: protectedMethod, token: validToken, expectedCall: true, expectedUser: true},
+		{name: "open method without token", method: openMethod, expectedCall: true},
+		{name: "open method with expired token", method: openMethod, expectedCall: true,
+			token: newToken(t, jwt.SigningMethodHS256, testSecret, nil, time.Now().Add(-time.Minute))},
+		{name: "open method with token", method: openMethod, token: validToken, expectedCall: true, expectedUser: true},
+	}
+	for _, testCase := range cases {
+		user, called, err := callWithToken(testCase.token, testCase.method)
+		if err != testCase.expectedErr {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+		if called != testCase.expectedCall {
+			t.Errorf("%s: expected handler call %t, got %t", testCase.name, testCase.expectedCall, called)
+		}
+		if (user != nil) != testCase.expectedUser || (user != nil && user.ID != 1) {
+			t.Errorf("%s: unexpected user in the context of the handler: %+v", testCase.name, user)
+		}
+	}
+}
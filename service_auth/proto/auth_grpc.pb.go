@@ -20,9 +20,25 @@ const _ = grpc.SupportPackageIsVersion7
 type AuthMakerClient interface {
 	Login(ctx context.Context, in *AuthData, opts ...grpc.CallOption) (*User, error)
 	Register(ctx context.Context, in *AuthData, opts ...grpc.CallOption) (*User, error)
-	CreateSession(ctx context.Context, in *User, opts ...grpc.CallOption) (*Token, error)
+	CreateTokenPair(ctx context.Context, in *NewSession, opts ...grpc.CallOption) (*TokenPair, error)
+	RefreshTokens(ctx context.Context, in *RefreshToken, opts ...grpc.CallOption) (*TokenPair, error)
 	GetSession(ctx context.Context, in *Token, opts ...grpc.CallOption) (*Session, error)
 	DeleteSession(ctx context.Context, in *Token, opts ...grpc.CallOption) (*IsDeleted, error)
+	GetUserSessions(ctx context.Context, in *Token, opts ...grpc.CallOption) (*UserSessions, error)
+	DeleteUserSession(ctx context.Context, in *SessionOfUser, opts ...grpc.CallOption) (*IsDeleted, error)
+	DeleteAllSessions(ctx context.Context, in *User, opts ...grpc.CallOption) (*DeletedSessions, error)
+	ChangePassword(ctx context.Context, in *PasswordChange, opts ...grpc.CallOption) (*PasswordChanged, error)
+	RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...grpc.CallOption) (*PasswordResetRequested, error)
+	ResetPassword(ctx context.Context, in *PasswordReset, opts ...grpc.CallOption) (*User, error)
+	GetEmailStatus(ctx context.Context, in *User, opts ...grpc.CallOption) (*EmailStatus, error)
+	ChangeEmail(ctx context.Context, in *EmailChange, opts ...grpc.CallOption) (*EmailStatus, error)
+	VerifyEmail(ctx context.Context, in *EmailVerification, opts ...grpc.CallOption) (*User, error)
+	StartOIDCLogin(ctx context.Context, in *OIDCLoginRequest, opts ...grpc.CallOption) (*OIDCLoginURL, error)
+	FinishOIDCLogin(ctx context.Context, in *OIDCCallback, opts ...grpc.CallOption) (*User, error)
+	SetProfilePrivacy(ctx context.Context, in *PrivacySettings, opts ...grpc.CallOption) (*PrivacySettings, error)
+	GetUserRoles(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserRoles, error)
+	GrantRole(ctx context.Context, in *RoleChange, opts ...grpc.CallOption) (*UserRoles, error)
+	RevokeRole(ctx context.Context, in *RoleChange, opts ...grpc.CallOption) (*UserRoles, error)
 }
 
 type authMakerClient struct {
@@ -51,9 +67,18 @@ func (c *authMakerClient) Register(ctx context.Context, in *AuthData, opts ...gr
 	return out, nil
 }
 
-func (c *authMakerClient) CreateSession(ctx context.Context, in *User, opts ...grpc.CallOption) (*Token, error) {
-	out := new(Token)
-	err := c.cc.Invoke(ctx, "/auth.AuthMaker/CreateSession", in, out, opts...)
+func (c *authMakerClient) CreateTokenPair(ctx context.Context, in *NewSession, opts ...grpc.CallOption) (*TokenPair, error) {
+	out := new(TokenPair)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/CreateTokenPair", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) RefreshTokens(ctx context.Context, in *RefreshToken, opts ...grpc.CallOption) (*TokenPair, error) {
+	out := new(TokenPair)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/RefreshTokens", in, out, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -78,15 +103,166 @@ func (c *authMakerClient) DeleteSession(ctx context.Context, in *Token, opts ...
 	return out, nil
 }
 
+func (c *authMakerClient) GetUserSessions(ctx context.Context, in *Token, opts ...grpc.CallOption) (*UserSessions, error) {
+	out := new(UserSessions)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/GetUserSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) DeleteUserSession(ctx context.Context, in *SessionOfUser, opts ...grpc.CallOption) (*IsDeleted, error) {
+	out := new(IsDeleted)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/DeleteUserSession", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) DeleteAllSessions(ctx context.Context, in *User, opts ...grpc.CallOption) (*DeletedSessions, error) {
+	out := new(DeletedSessions)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/DeleteAllSessions", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) ChangePassword(ctx context.Context, in *PasswordChange, opts ...grpc.CallOption) (*PasswordChanged, error) {
+	out := new(PasswordChanged)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/ChangePassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) RequestPasswordReset(ctx context.Context, in *PasswordResetRequest, opts ...grpc.CallOption) (*PasswordResetRequested, error) {
+	out := new(PasswordResetRequested)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/RequestPasswordReset", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) ResetPassword(ctx context.Context, in *PasswordReset, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/ResetPassword", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) GetEmailStatus(ctx context.Context, in *User, opts ...grpc.CallOption) (*EmailStatus, error) {
+	out := new(EmailStatus)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/GetEmailStatus", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) ChangeEmail(ctx context.Context, in *EmailChange, opts ...grpc.CallOption) (*EmailStatus, error) {
+	out := new(EmailStatus)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/ChangeEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) VerifyEmail(ctx context.Context, in *EmailVerification, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/VerifyEmail", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) StartOIDCLogin(ctx context.Context, in *OIDCLoginRequest, opts ...grpc.CallOption) (*OIDCLoginURL, error) {
+	out := new(OIDCLoginURL)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/StartOIDCLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) FinishOIDCLogin(ctx context.Context, in *OIDCCallback, opts ...grpc.CallOption) (*User, error) {
+	out := new(User)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/FinishOIDCLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) SetProfilePrivacy(ctx context.Context, in *PrivacySettings, opts ...grpc.CallOption) (*PrivacySettings, error) {
+	out := new(PrivacySettings)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/SetProfilePrivacy", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) GetUserRoles(ctx context.Context, in *User, opts ...grpc.CallOption) (*UserRoles, error) {
+	out := new(UserRoles)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/GetUserRoles", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) GrantRole(ctx context.Context, in *RoleChange, opts ...grpc.CallOption) (*UserRoles, error) {
+	out := new(UserRoles)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/GrantRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authMakerClient) RevokeRole(ctx context.Context, in *RoleChange, opts ...grpc.CallOption) (*UserRoles, error) {
+	out := new(UserRoles)
+	err := c.cc.Invoke(ctx, "/auth.AuthMaker/RevokeRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AuthMakerServer is the server API for AuthMaker service.
 // All implementations must embed UnimplementedAuthMakerServer
 // for forward compatibility
 type AuthMakerServer interface {
 	Login(context.Context, *AuthData) (*User, error)
 	Register(context.Context, *AuthData) (*User, error)
-	CreateSession(context.Context, *User) (*Token, error)
+	CreateTokenPair(context.Context, *NewSession) (*TokenPair, error)
+	RefreshTokens(context.Context, *RefreshToken) (*TokenPair, error)
 	GetSession(context.Context, *Token) (*Session, error)
 	DeleteSession(context.Context, *Token) (*IsDeleted, error)
+	GetUserSessions(context.Context, *Token) (*UserSessions, error)
+	DeleteUserSession(context.Context, *SessionOfUser) (*IsDeleted, error)
+	DeleteAllSessions(context.Context, *User) (*DeletedSessions, error)
+	ChangePassword(context.Context, *PasswordChange) (*PasswordChanged, error)
+	RequestPasswordReset(context.Context, *PasswordResetRequest) (*PasswordResetRequested, error)
+	ResetPassword(context.Context, *PasswordReset) (*User, error)
+	GetEmailStatus(context.Context, *User) (*EmailStatus, error)
+	ChangeEmail(context.Context, *EmailChange) (*EmailStatus, error)
+	VerifyEmail(context.Context, *EmailVerification) (*User, error)
+	StartOIDCLogin(context.Context, *OIDCLoginRequest) (*OIDCLoginURL, error)
+	FinishOIDCLogin(context.Context, *OIDCCallback) (*User, error)
+	SetProfilePrivacy(context.Context, *PrivacySettings) (*PrivacySettings, error)
+	GetUserRoles(context.Context, *User) (*UserRoles, error)
+	GrantRole(context.Context, *RoleChange) (*UserRoles, error)
+	RevokeRole(context.Context, *RoleChange) (*UserRoles, error)
 	mustEmbedUnimplementedAuthMakerServer()
 }
 
@@ -100,8 +276,11 @@ func (UnimplementedAuthMakerServer) Login(context.Context, *AuthData) (*User, er
 func (UnimplementedAuthMakerServer) Register(context.Context, *AuthData) (*User, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
 }
-func (UnimplementedAuthMakerServer) CreateSession(context.Context, *User) (*Token, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateSession not implemented")
+func (UnimplementedAuthMakerServer) CreateTokenPair(context.Context, *NewSession) (*TokenPair, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateTokenPair not implemented")
+}
+func (UnimplementedAuthMakerServer) RefreshTokens(context.Context, *RefreshToken) (*TokenPair, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshTokens not implemented")
 }
 func (UnimplementedAuthMakerServer) GetSession(context.Context, *Token) (*Session, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetSession not implemented")
@@ -109,6 +288,51 @@ func (UnimplementedAuthMakerServer) GetSession(context.Context, *Token) (*Sessio
 func (UnimplementedAuthMakerServer) DeleteSession(context.Context, *Token) (*IsDeleted, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteSession not implemented")
 }
+func (UnimplementedAuthMakerServer) GetUserSessions(context.Context, *Token) (*UserSessions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserSessions not implemented")
+}
+func (UnimplementedAuthMakerServer) DeleteUserSession(context.Context, *SessionOfUser) (*IsDeleted, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUserSession not implemented")
+}
+func (UnimplementedAuthMakerServer) DeleteAllSessions(context.Context, *User) (*DeletedSessions, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAllSessions not implemented")
+}
+func (UnimplementedAuthMakerServer) ChangePassword(context.Context, *PasswordChange) (*PasswordChanged, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePassword not implemented")
+}
+func (UnimplementedAuthMakerServer) RequestPasswordReset(context.Context, *PasswordResetRequest) (*PasswordResetRequested, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestPasswordReset not implemented")
+}
+func (UnimplementedAuthMakerServer) ResetPassword(context.Context, *PasswordReset) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetPassword not implemented")
+}
+func (UnimplementedAuthMakerServer) GetEmailStatus(context.Context, *User) (*EmailStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEmailStatus not implemented")
+}
+func (UnimplementedAuthMakerServer) ChangeEmail(context.Context, *EmailChange) (*EmailStatus, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangeEmail not implemented")
+}
+func (UnimplementedAuthMakerServer) VerifyEmail(context.Context, *EmailVerification) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEmail not implemented")
+}
+func (UnimplementedAuthMakerServer) StartOIDCLogin(context.Context, *OIDCLoginRequest) (*OIDCLoginURL, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartOIDCLogin not implemented")
+}
+func (UnimplementedAuthMakerServer) FinishOIDCLogin(context.Context, *OIDCCallback) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FinishOIDCLogin not implemented")
+}
+func (UnimplementedAuthMakerServer) SetProfilePrivacy(context.Context, *PrivacySettings) (*PrivacySettings, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetProfilePrivacy not implemented")
+}
+func (UnimplementedAuthMakerServer) GetUserRoles(context.Context, *User) (*UserRoles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserRoles not implemented")
+}
+func (UnimplementedAuthMakerServer) GrantRole(context.Context, *RoleChange) (*UserRoles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GrantRole not implemented")
+}
+func (UnimplementedAuthMakerServer) RevokeRole(context.Context, *RoleChange) (*UserRoles, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeRole not implemented")
+}
 func (UnimplementedAuthMakerServer) mustEmbedUnimplementedAuthMakerServer() {}
 
 // UnsafeAuthMakerServer may be embedded to opt out of forward compatibility for this service.
@@ -158,20 +382,38 @@ func _AuthMaker_Register_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
-func _AuthMaker_CreateSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(User)
+func _AuthMaker_CreateTokenPair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewSession)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).CreateTokenPair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/CreateTokenPair",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).CreateTokenPair(ctx, req.(*NewSession))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_RefreshTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshToken)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(AuthMakerServer).CreateSession(ctx, in)
+		return srv.(AuthMakerServer).RefreshTokens(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/auth.AuthMaker/CreateSession",
+		FullMethod: "/auth.AuthMaker/RefreshTokens",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthMakerServer).CreateSession(ctx, req.(*User))
+		return srv.(AuthMakerServer).RefreshTokens(ctx, req.(*RefreshToken))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -212,6 +454,276 @@ func _AuthMaker_DeleteSession_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AuthMaker_GetUserSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Token)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).GetUserSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/GetUserSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).GetUserSessions(ctx, req.(*Token))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_DeleteUserSession_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SessionOfUser)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).DeleteUserSession(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/DeleteUserSession",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).DeleteUserSession(ctx, req.(*SessionOfUser))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_DeleteAllSessions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).DeleteAllSessions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/DeleteAllSessions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).DeleteAllSessions(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_ChangePassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasswordChange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).ChangePassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/ChangePassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).ChangePassword(ctx, req.(*PasswordChange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_RequestPasswordReset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasswordResetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).RequestPasswordReset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/RequestPasswordReset",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).RequestPasswordReset(ctx, req.(*PasswordResetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_ResetPassword_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PasswordReset)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).ResetPassword(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/ResetPassword",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).ResetPassword(ctx, req.(*PasswordReset))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_GetEmailStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).GetEmailStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/GetEmailStatus",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).GetEmailStatus(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_ChangeEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailChange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).ChangeEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/ChangeEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).ChangeEmail(ctx, req.(*EmailChange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_VerifyEmail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EmailVerification)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).VerifyEmail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/VerifyEmail",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).VerifyEmail(ctx, req.(*EmailVerification))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_StartOIDCLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OIDCLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).StartOIDCLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/StartOIDCLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).StartOIDCLogin(ctx, req.(*OIDCLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_FinishOIDCLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OIDCCallback)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).FinishOIDCLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/FinishOIDCLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).FinishOIDCLogin(ctx, req.(*OIDCCallback))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_SetProfilePrivacy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PrivacySettings)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).SetProfilePrivacy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/SetProfilePrivacy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).SetProfilePrivacy(ctx, req.(*PrivacySettings))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_GetUserRoles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).GetUserRoles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/GetUserRoles",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).GetUserRoles(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_GrantRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleChange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).GrantRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/GrantRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).GrantRole(ctx, req.(*RoleChange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthMaker_RevokeRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoleChange)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthMakerServer).RevokeRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.AuthMaker/RevokeRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthMakerServer).RevokeRole(ctx, req.(*RoleChange))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AuthMaker_ServiceDesc is the grpc.ServiceDesc for AuthMaker service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -228,8 +740,12 @@ var AuthMaker_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _AuthMaker_Register_Handler,
 		},
 		{
-			MethodName: "CreateSession",
-			Handler:    _AuthMaker_CreateSession_Handler,
+			MethodName: "CreateTokenPair",
+			Handler:    _AuthMaker_CreateTokenPair_Handler,
+		},
+		{
+			MethodName: "RefreshTokens",
+			Handler:    _AuthMaker_RefreshTokens_Handler,
 		},
 		{
 			MethodName: "GetSession",
@@ -239,6 +755,66 @@ var AuthMaker_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteSession",
 			Handler:    _AuthMaker_DeleteSession_Handler,
 		},
+		{
+			MethodName: "GetUserSessions",
+			Handler:    _AuthMaker_GetUserSessions_Handler,
+		},
+		{
+			MethodName: "DeleteUserSession",
+			Handler:    _AuthMaker_DeleteUserSession_Handler,
+		},
+		{
+			MethodName: "DeleteAllSessions",
+			Handler:    _AuthMaker_DeleteAllSessions_Handler,
+		},
+		{
+			MethodName: "ChangePassword",
+			Handler:    _AuthMaker_ChangePassword_Handler,
+		},
+		{
+			MethodName: "RequestPasswordReset",
+			Handler:    _AuthMaker_RequestPasswordReset_Handler,
+		},
+		{
+			MethodName: "ResetPassword",
+			Handler:    _AuthMaker_ResetPassword_Handler,
+		},
+		{
+			MethodName: "GetEmailStatus",
+			Handler:    _AuthMaker_GetEmailStatus_Handler,
+		},
+		{
+			MethodName: "ChangeEmail",
+			Handler:    _AuthMaker_ChangeEmail_Handler,
+		},
+		{
+			MethodName: "VerifyEmail",
+			Handler:    _AuthMaker_VerifyEmail_Handler,
+		},
+		{
+			MethodName: "StartOIDCLogin",
+			Handler:    _AuthMaker_StartOIDCLogin_Handler,
+		},
+		{
+			MethodName: "FinishOIDCLogin",
+			Handler:    _AuthMaker_FinishOIDCLogin_Handler,
+		},
+		{
+			MethodName: "SetProfilePrivacy",
+			Handler:    _AuthMaker_SetProfilePrivacy_Handler,
+		},
+		{
+			MethodName: "GetUserRoles",
+			Handler:    _AuthMaker_GetUserRoles_Handler,
+		},
+		{
+			MethodName: "GrantRole",
+			Handler:    _AuthMaker_GrantRole_Handler,
+		},
+		{
+			MethodName: "RevokeRole",
+			Handler:    _AuthMaker_RevokeRole_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "auth.proto",
@@ -3,13 +3,45 @@ package userrepo
 import (
 	"database/sql"
 	"errors"
+	"fmt"
 	auth "kinopoisk/service_auth/proto"
+	"time"
 )
 
 type UserRepo interface {
-	LoginRepo(username, password string) (*auth.User, error)
+	GetUserCredentialsRepo(username string) (*auth.User, *UserCredentials, error)
+	GetUserCredentialsByIDRepo(userID uint64) (*UserCredentials, error)
+	UpdatePasswordHashRepo(userID uint64, oldHash, newHash string) (bool, error)
+	ExpireLegacyPasswordsRepo() (int64, error)
 	RegisterRepo(username, password string) (*auth.User, error)
 	FindUserByUsername(username string) (*auth.User, error)
+	FindUserByEmailRepo(email string) (*auth.User, error)
+	CreatePasswordResetTokenRepo(userID uint64, tokenHash string, ttl time.Duration) error
+	ResetPasswordRepo(tokenHash, newHash string) (uint64, error)
+	CreateEmailVerificationTokenRepo(userID uint64, email, tokenHash string, ttl time.Duration) error
+	VerifyEmailRepo(tokenHash string) (uint64, bool, error)
+	GetEmailStatusRepo(userID uint64) (*EmailStatus, error)
+	FindUserByIdentityRepo(provider, subject string) (*auth.User, error)
+	LinkIdentityRepo(userID uint64, provider, subject, email string) (bool, error)
+	RegisterExternalUserRepo(username, email, provider, subject string) (*auth.User, error)
+	SetProfilePrivacyRepo(userID uint64, isPrivate bool) error
+	GetUserRolesRepo(userID uint64) ([]string, []string, error)
+	RoleExistsRepo(role string) (bool, error)
+	GrantRoleRepo(userID uint64, role string, grantedBy uint64) (bool, error)
+	RevokeRoleRepo(userID uint64, role string, keepLast bool) (bool, bool, error)
+}
+
+// UserCredentials is the stored password of the user. The hash is empty if the password has been expired
+// and the user must reset it.
+type UserCredentials struct {
+	PasswordHash  string
+	ResetRequired bool
+}
+
+// EmailStatus is the verified email of the user and the one waiting for verification, both can be empty.
+type EmailStatus struct {
+	Email        string
+	PendingEmail string
 }
 
 type UserRepoMySQL struct {
@@ -22,18 +54,67 @@ func NewUserRepoMySQL(db *sql.DB) *UserRepoMySQL {
 	}
 }
 
-func (u *UserRepoMySQL) LoginRepo(username, password string) (*auth.User, error) {
+// GetUserCredentialsRepo returns nil user if there is no user with such username.
+func (u *UserRepoMySQL) GetUserCredentialsRepo(username string) (*auth.User, *UserCredentials, error) {
 	foundUser := &auth.User{}
+	credentials := &UserCredentials{}
 	err := u.db.
-		QueryRow("SELECT id, username FROM users WHERE username = ? AND password = ?", username, password).
-		Scan(&foundUser.ID, &foundUser.Username)
+		QueryRow("SELECT id, username, password, password_reset_required FROM users WHERE username = ?", username).
+		Scan(&foundUser.ID, &foundUser.Username, &credentials.PasswordHash, &credentials.ResetRequired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return foundUser, credentials, nil
+}
+
+// GetUserCredentialsByIDRepo returns nil if there is no user with such id.
+func (u *UserRepoMySQL) GetUserCredentialsByIDRepo(userID uint64) (*UserCredentials, error) {
+	credentials := &UserCredentials{}
+	err := u.db.
+		QueryRow("SELECT password, password_reset_required FROM users WHERE id = ?", userID).
+		Scan(&credentials.PasswordHash, &credentials.ResetRequired)
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, err
 	}
-	return foundUser, nil
+	return credentials, nil
+}
+
+// UpdatePasswordHashRepo replaces the hash only if it is still oldHash, so a password changed meanwhile is kept.
+// It returns false if the hash has been changed meanwhile. The new hash has a new salt, so it always differs
+// from oldHash and the matched row is always counted as affected.
+func (u *UserRepoMySQL) UpdatePasswordHashRepo(userID uint64, oldHash, newHash string) (bool, error) {
+	res, err := u.db.Exec(
+		"UPDATE users SET password = ? WHERE id = ? AND password = ?",
+		newHash,
+		userID,
+		oldHash,
+	)
+	if err != nil {
+		return false, err
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return updated > 0, nil
+}
+
+// ExpireLegacyPasswordsRepo removes the password hashes which are not Argon2id and requires these users
+// to reset the password. It returns the number of the expired passwords.
+func (u *UserRepoMySQL) ExpireLegacyPasswordsRepo() (int64, error) {
+	res, err := u.db.Exec(
+		"UPDATE users SET password = '', password_reset_required = TRUE WHERE password NOT LIKE '$argon2id$%' AND password_reset_required = FALSE",
+	)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 func (u *UserRepoMySQL) RegisterRepo(username, password string) (*auth.User, error) {
@@ -68,3 +149,366 @@ func (u *UserRepoMySQL) FindUserByUsername(username string) (*auth.User, error)
 	}
 	return foundUser, nil
 }
+
+// FindUserByEmailRepo looks only through the verified emails, the pending ones are not emails of the users yet.
+func (u *UserRepoMySQL) FindUserByEmailRepo(email string) (*auth.User, error) {
+	foundUser := &auth.User{}
+	err := u.db.
+		QueryRow("SELECT id, username FROM users WHERE email = ?", email).
+		Scan(&foundUser.ID, &foundUser.Username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return foundUser, nil
+}
+
+// CreatePasswordResetTokenRepo stores the new reset token of the user, the tokens sent to the user before stop working.
+func (u *UserRepoMySQL) CreatePasswordResetTokenRepo(userID uint64, tokenHash string, ttl time.Duration) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		"UPDATE password_reset_tokens SET used_at = NOW() WHERE user_id = ? AND used_at IS NULL",
+		userID,
+	)
+	if err != nil {
+		return rollback(tx, err)
+	}
+	_, err = tx.Exec(
+		"INSERT INTO password_reset_tokens (`token_hash`, `user_id`, `expires_at`) VALUES (?, ?, DATE_ADD(NOW(), INTERVAL ? SECOND))",
+		tokenHash,
+		userID,
+		int64(ttl.Seconds()),
+	)
+	if err != nil {
+		return rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+// ResetPasswordRepo uses the reset token and sets the new password in one transaction, so the token works only once.
+// It returns the id of the user or 0 if the token is unknown, expired or used.
+func (u *UserRepoMySQL) ResetPasswordRepo(tokenHash, newHash string) (uint64, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	var userID uint64
+	err = tx.QueryRow(
+		"SELECT user_id FROM password_reset_tokens WHERE token_hash = ? AND used_at IS NULL AND expires_at > NOW() FOR UPDATE",
+		tokenHash,
+	).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, rollback(tx, nil)
+	}
+	if err != nil {
+		return 0, rollback(tx, err)
+	}
+	_, err = tx.Exec("UPDATE password_reset_tokens SET used_at = NOW() WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return 0, rollback(tx, err)
+	}
+	_, err = tx.Exec(
+		"UPDATE users SET password = ?, password_reset_required = FALSE WHERE id = ?",
+		newHash,
+		userID,
+	)
+	if err != nil {
+		return 0, rollback(tx, err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
+
+// CreateEmailVerificationTokenRepo stores the token which verifies the email for the user, the tokens sent before
+// stop working. The email becomes the email of the user only after the verification.
+func (u *UserRepoMySQL) CreateEmailVerificationTokenRepo(userID uint64, email, tokenHash string, ttl time.Duration) error {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		"UPDATE email_verification_tokens SET used_at = NOW() WHERE user_id = ? AND used_at IS NULL",
+		userID,
+	)
+	if err != nil {
+		return rollback(tx, err)
+	}
+	_, err = tx.Exec(
+		"INSERT INTO email_verification_tokens (`token_hash`, `user_id`, `email`, `expires_at`) VALUES (?, ?, ?, DATE_ADD(NOW(), INTERVAL ? SECOND))",
+		tokenHash,
+		userID,
+		email,
+		int64(ttl.Seconds()),
+	)
+	if err != nil {
+		return rollback(tx, err)
+	}
+	return tx.Commit()
+}
+
+// VerifyEmailRepo uses the verification token and sets its email to the user. It returns the id of the user
+// or 0 if the token is unknown, expired or used. If the email has been verified by another user meanwhile,
+// the second result is true and the token stays unused.
+func (u *UserRepoMySQL) VerifyEmailRepo(tokenHash string) (uint64, bool, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return 0, false, err
+	}
+	var userID uint64
+	var email string
+	err = tx.QueryRow(
+		"SELECT user_id, email FROM email_verification_tokens WHERE token_hash = ? AND used_at IS NULL AND expires_at > NOW() FOR UPDATE",
+		tokenHash,
+	).Scan(&userID, &email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, rollback(tx, nil)
+	}
+	if err != nil {
+		return 0, false, rollback(tx, err)
+	}
+	var ownerID uint64
+	err = tx.QueryRow("SELECT id FROM users WHERE email = ? FOR UPDATE", email).Scan(&ownerID)
+	if err == nil && ownerID != userID {
+		return 0, true, rollback(tx, nil)
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, rollback(tx, err)
+	}
+	_, err = tx.Exec("UPDATE email_verification_tokens SET used_at = NOW() WHERE token_hash = ?", tokenHash)
+	if err != nil {
+		return 0, false, rollback(tx, err)
+	}
+	_, err = tx.Exec("UPDATE users SET email = ? WHERE id = ?", email, userID)
+	if err != nil {
+		return 0, false, rollback(tx, err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, false, err
+	}
+	return userID, false, nil
+}
+
+// GetEmailStatusRepo returns nil if there is no user with such id.
+func (u *UserRepoMySQL) GetEmailStatusRepo(userID uint64) (*EmailStatus, error) {
+	emailStatus := &EmailStatus{}
+	var email sql.NullString
+	err := u.db.QueryRow("SELECT email FROM users WHERE id = ?", userID).Scan(&email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	emailStatus.Email = email.String
+	err = u.db.QueryRow(
+		"SELECT email FROM email_verification_tokens WHERE user_id = ? AND used_at IS NULL AND expires_at > NOW() LIMIT 1",
+		userID,
+	).Scan(&emailStatus.PendingEmail)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	return emailStatus, nil
+}
+
+// FindUserByIdentityRepo returns nil if the identity of the provider is not linked to any user.
+func (u *UserRepoMySQL) FindUserByIdentityRepo(provider, subject string) (*auth.User, error) {
+	foundUser := &auth.User{}
+	err := u.db.
+		QueryRow(
+			"SELECT users.id, users.username FROM user_identities JOIN users ON users.id = user_identities.user_id WHERE provider = ? AND subject = ?",
+			provider,
+			subject,
+		).
+		Scan(&foundUser.ID, &foundUser.Username)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return foundUser, nil
+}
+
+// LinkIdentityRepo returns false if the identity is already linked, to this or to another user.
+func (u *UserRepoMySQL) LinkIdentityRepo(userID uint64, provider, subject, email string) (bool, error) {
+	res, err := u.db.Exec(
+		"INSERT IGNORE INTO user_identities (`provider`, `subject`, `user_id`, `email`) VALUES (?, ?, ?, NULLIF(?, ''))",
+		provider,
+		subject,
+		userID,
+		email,
+	)
+	if err != nil {
+		return false, err
+	}
+	linked, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return linked == 1, nil
+}
+
+// RegisterExternalUserRepo creates the user who signs in with the identity provider and links the identity to it.
+// The user has no password and can set it through the password reset. The email is stored as verified,
+// it must be verified by the provider and not used by another user.
+func (u *UserRepoMySQL) RegisterExternalUserRepo(username, email, provider, subject string) (*auth.User, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.Exec(
+		"INSERT INTO users (`username`, `password`, `email`) VALUES (?, '', NULLIF(?, ''))",
+		username,
+		email,
+	)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+	userID, err := res.LastInsertId()
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+	_, err = tx.Exec(
+		"INSERT INTO user_identities (`provider`, `subject`, `user_id`, `email`) VALUES (?, ?, ?, NULLIF(?, ''))",
+		provider,
+		subject,
+		userID,
+		email,
+	)
+	if err != nil {
+		return nil, rollback(tx, err)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	return &auth.User{
+		ID:       uint64(userID),
+		Username: username,
+	}, nil
+}
+
+func (u *UserRepoMySQL) SetProfilePrivacyRepo(userID uint64, isPrivate bool) error {
+	_, err := u.db.Exec(
+		"UPDATE users SET is_private = ? WHERE id = ?",
+		isPrivate,
+		userID,
+	)
+	return err
+}
+
+// GetUserRolesRepo returns the roles of the user and the permissions the roles give, both sorted.
+func (u *UserRepoMySQL) GetUserRolesRepo(userID uint64) ([]string, []string, error) {
+	rows, err := u.db.Query("SELECT role FROM user_roles WHERE user_id = ? ORDER BY role", userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	roles, err := scanStrings(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	rows, err = u.db.Query(
+		"SELECT DISTINCT role_permissions.permission FROM user_roles JOIN role_permissions ON role_permissions.role = user_roles.role WHERE user_roles.user_id = ? ORDER BY role_permissions.permission",
+		userID,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	permissions, err := scanStrings(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return roles, permissions, nil
+}
+
+// RoleExistsRepo reports if the role gives any permissions.
+func (u *UserRepoMySQL) RoleExistsRepo(role string) (bool, error) {
+	var exists bool
+	err := u.db.QueryRow("SELECT EXISTS(SELECT 1 FROM role_permissions WHERE role = ?)", role).Scan(&exists)
+	return exists, err
+}
+
+// GrantRoleRepo returns false if the user already has the role, grantedBy is 0 for the roles granted on start.
+func (u *UserRepoMySQL) GrantRoleRepo(userID uint64, role string, grantedBy uint64) (bool, error) {
+	res, err := u.db.Exec(
+		"INSERT IGNORE INTO user_roles (`user_id`, `role`, `granted_by`) VALUES (?, ?, NULLIF(?, 0))",
+		userID,
+		role,
+		grantedBy,
+	)
+	if err != nil {
+		return false, err
+	}
+	granted, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return granted == 1, nil
+}
+
+// RevokeRoleRepo returns false if the user does not have the role. With keepLast the role is not taken from
+// its last user, then it returns true as the second value. The users of the role are locked while they are
+// counted, so the concurrent revocations can not take the role from all of them.
+func (u *UserRepoMySQL) RevokeRoleRepo(userID uint64, role string, keepLast bool) (bool, bool, error) {
+	tx, err := u.db.Begin()
+	if err != nil {
+		return false, false, err
+	}
+	var roleUsers uint64
+	err = tx.QueryRow("SELECT COUNT(*) FROM user_roles WHERE role = ? FOR UPDATE", role).Scan(&roleUsers)
+	if err != nil {
+		return false, false, rollback(tx, err)
+	}
+	res, err := tx.Exec("DELETE FROM user_roles WHERE user_id = ? AND role = ?", userID, role)
+	if err != nil {
+		return false, false, rollback(tx, err)
+	}
+	revoked, err := res.RowsAffected()
+	if err != nil {
+		return false, false, rollback(tx, err)
+	}
+	if revoked == 0 {
+		return false, false, rollback(tx, nil)
+	}
+	if keepLast && roleUsers <= 1 {
+		return false, true, rollback(tx, nil)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return false, false, err
+	}
+	return true, false, nil
+}
+
+// scanStrings reads the rows of one string column and closes them.
+func scanStrings(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+	values := make([]string, 0)
+	for rows.Next() {
+		var value string
+		err := rows.Scan(&value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, rows.Err()
+}
+
+// rollback returns the error which caused the rollback, the error of the rollback itself is added to it.
+func rollback(tx *sql.Tx, err error) error {
+	rollbackErr := tx.Rollback()
+	if rollbackErr != nil {
+		return fmt.Errorf("%v, rollback: %w", err, rollbackErr)
+	}
+	return err
+}
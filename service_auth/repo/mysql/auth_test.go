@@ -0,0 +1,127 @@
+package userrepo_test
+
+import (
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+	userrepo "kinopoisk/service_auth/repo/mysql"
+	"regexp"
+	"testing"
+)
+
+func TestVerifyEmailRepoEmailTaken(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := userrepo.NewUserRepoMySQL(db)
+
+	// the email has been verified by the user 2 after the user 1 has asked to verify it
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery("SELECT user_id, email FROM email_verification_tokens").
+		WithArgs("token hash").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email"}).AddRow(1, "user@example.com"))
+	mock.
+		ExpectQuery("SELECT id FROM users WHERE email = ").
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	mock.ExpectRollback()
+
+	userID, isTaken, err := dbRepo.VerifyEmailRepo("token hash")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if !isTaken || userID != 0 {
+		t.Errorf("expected taken email, got user %d and taken %t", userID, isTaken)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+
+	// the user verifies the email they already have, the token is used
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery("SELECT user_id, email FROM email_verification_tokens").
+		WithArgs("token hash").
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "email"}).AddRow(1, "user@example.com"))
+	mock.
+		ExpectQuery("SELECT id FROM users WHERE email = ").
+		WithArgs("user@example.com").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.
+		ExpectExec("UPDATE email_verification_tokens SET used_at").
+		WithArgs("token hash").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("UPDATE users SET email").
+		WithArgs("user@example.com", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	userID, isTaken, err = dbRepo.VerifyEmailRepo("token hash")
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if isTaken || userID != 1 {
+		t.Errorf("expected email of user 1 to be verified, got user %d and taken %t", userID, isTaken)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRevokeRoleRepoKeepLast(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := userrepo.NewUserRepoMySQL(db)
+
+	cases := []struct {
+		name            string
+		roleUsers       int
+		deleted         int64
+		isCommitted     bool
+		expectedRevoked bool
+		expectedIsLast  bool
+	}{
+		{name: "one of two admins", roleUsers: 2, deleted: 1, isCommitted: true, expectedRevoked: true},
+		// the delete is rolled back, the counted users stay locked until then
+		{name: "last admin", roleUsers: 1, deleted: 1, expectedIsLast: true},
+		{name: "user is not admin", roleUsers: 1, deleted: 0},
+	}
+	for _, testCase := range cases {
+		mock.ExpectBegin()
+		mock.
+			ExpectQuery(regexp.QuoteMeta("SELECT COUNT(*) FROM user_roles WHERE role = ? FOR UPDATE")).
+			WithArgs("admin").
+			WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(testCase.roleUsers))
+		mock.
+			ExpectExec("DELETE FROM user_roles").
+			WithArgs(1, "admin").
+			WillReturnResult(sqlmock.NewResult(0, testCase.deleted))
+		if testCase.isCommitted {
+			mock.ExpectCommit()
+		} else {
+			mock.ExpectRollback()
+		}
+
+		revoked, isLast, err := dbRepo.RevokeRoleRepo(1, "admin", true)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			return
+		}
+		if revoked != testCase.expectedRevoked || isLast != testCase.expectedIsLast {
+			t.Errorf("%s: expected revoked %t and last %t, got %t and %t", testCase.name,
+				testCase.expectedRevoked, testCase.expectedIsLast, revoked, isLast)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+			t.Errorf("%s: there were unfulfilled expectations: %s", testCase.name, err)
+			return
+		}
+	}
+}
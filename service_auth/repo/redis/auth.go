@@ -1,26 +1,88 @@
 package sessionrepo
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"github.com/gomodule/redigo/redis"
 	auth "kinopoisk/service_auth/proto"
+	"strconv"
+	"time"
+)
+
+const (
+	refreshTokenKeyPrefix     = "refresh:"
+	usedRefreshTokenKeyPrefix = "refresh_used:"
+	refreshFamilyKeyPrefix    = "refresh_family:"
+	sessionInfoKeyPrefix      = "session_info:"
+	userSessionsKeyPrefix     = "user_sessions:"
+	oidcStateKeyPrefix        = "oidc_state:"
+
+	// oidcStateExpireTime is how long the user has to sign in at the identity provider, in seconds
+	oidcStateExpireTime = 10 * 60
+
+	// lastSeenUpdateInterval keeps GetSession from writing to redis on every request
+	lastSeenUpdateInterval = time.Minute
 )
 
 type SessionRepo interface {
 	CreateSessionRepo(session *auth.Session) error
 	GetSessionRepo(token string) (*auth.Session, error)
 	DeleteSessionRepo(token string) (bool, error)
+	CreateRefreshTokenRepo(token string, refreshSession *RefreshSession) error
+	UseRefreshTokenRepo(token string) (*RefreshSession, bool, error)
+	RevokeFamilyRepo(familyID string) error
+	CreateSessionInfoRepo(sessionInfo *SessionInfo) error
+	TouchSessionInfoRepo(familyID string, lastSeenAt time.Time) error
+	UpdateSessionClientRepo(familyID string, client *auth.ClientInfo, lastSeenAt time.Time) error
+	GetUserSessionsRepo(userID uint64) ([]*SessionInfo, error)
+	HasUserSessionRepo(userID uint64, familyID string) (bool, error)
+	CreateOIDCStateRepo(state string, oidcState *OIDCState) error
+	PopOIDCStateRepo(state string) (*OIDCState, error)
+}
+
+// RefreshSession is what a refresh token is exchanged for. All the tokens issued from one login share the family,
+// so the whole chain can be revoked when a used refresh token is replayed.
+type RefreshSession struct {
+	FamilyID string
+	User     *auth.User
+}
+
+// SessionInfo is what the user sees about one of the logins, it lives as long as the token family.
+// The times are unix seconds.
+type SessionInfo struct {
+	ID         string `redis:"-"`
+	UserID     uint64 `redis:"userID"`
+	Device     string `redis:"device"`
+	IP         string `redis:"ip"`
+	UserAgent  string `redis:"userAgent"`
+	CreatedAt  int64  `redis:"createdAt"`
+	LastSeenAt int64  `redis:"lastSeenAt"`
+}
+
+// OIDCState is what is kept between sending the user to the identity provider and the callback.
+// LinkUserID is set if the identity is linked to the logged in user instead of logging in. BindingHash is
+// the hash of the binding kept in the cookie of the browser that has started the login.
+type OIDCState struct {
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	LinkUserID   uint64
+	BindingHash  string
 }
 
 type SessionRepoRedis struct {
-	redisConn  redis.Conn
-	expireTime int
+	redisConn         redis.Conn
+	expireTime        int
+	refreshExpireTime int
 }
 
-func NewSessionRepoRedis(redisConn redis.Conn) *SessionRepoRedis {
+func NewSessionRepoRedis(redisConn redis.Conn, accessTokenTTL, refreshTokenTTL time.Duration) *SessionRepoRedis {
 	return &SessionRepoRedis{
-		redisConn:  redisConn,
-		expireTime: 24 * 60 * 60,
+		redisConn:         redisConn,
+		expireTime:        int(accessTokenTTL.Seconds()),
+		refreshExpireTime: int(refreshTokenTTL.Seconds()),
 	}
 }
 
@@ -33,6 +95,9 @@ func (s *SessionRepoRedis) CreateSessionRepo(session *auth.Session) error {
 	if err != nil || result != "OK" {
 		return err
 	}
+	if session.FamilyID != "" {
+		return s.addToFamily(session.FamilyID, session.ID)
+	}
 	return nil
 }
 
@@ -50,17 +115,234 @@ func (s *SessionRepoRedis) GetSessionRepo(token string) (*auth.Session, error) {
 
 }
 
+// DeleteSessionRepo deletes the session and revokes its refresh token family, so the user can not refresh after logout.
 func (s *SessionRepoRedis) DeleteSessionRepo(token string) (bool, error) {
-	exists, err := redis.Bool(s.redisConn.Do("EXISTS", token))
+	sess, err := s.GetSessionRepo(token)
+	if errors.Is(err, redis.ErrNil) {
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
-	if !exists {
-		return false, nil
-	}
 	_, err = s.redisConn.Do("DEL", token)
 	if err != nil {
 		return false, err
 	}
+	if sess.FamilyID != "" {
+		err = s.RevokeFamilyRepo(sess.FamilyID)
+		if err != nil {
+			return false, err
+		}
+	}
 	return true, nil
 }
+
+// CreateRefreshTokenRepo stores the refresh token by its SHA-256, so the tokens can not be taken from a redis dump.
+func (s *SessionRepoRedis) CreateRefreshTokenRepo(token string, refreshSession *RefreshSession) error {
+	refreshSessionJSON, err := json.Marshal(refreshSession)
+	if err != nil {
+		return err
+	}
+	tokenHash := hashToken(token)
+	_, err = s.redisConn.Do("SET", refreshTokenKeyPrefix+tokenHash, refreshSessionJSON, "EX", s.refreshExpireTime)
+	if err != nil {
+		return err
+	}
+	return s.addToFamily(refreshSession.FamilyID, refreshTokenKeyPrefix+tokenHash, usedRefreshTokenKeyPrefix+tokenHash)
+}
+
+// UseRefreshTokenRepo marks the refresh token as used and returns its session, or nil if the token is unknown or expired.
+// The second result is true if the token has already been used before. The used mark is set with NX,
+// so of two concurrent requests with the same token only one gets it as unused.
+func (s *SessionRepoRedis) UseRefreshTokenRepo(token string) (*RefreshSession, bool, error) {
+	tokenHash := hashToken(token)
+	refreshSessionFromRedis, err := redis.Bytes(s.redisConn.Do("GET", refreshTokenKeyPrefix+tokenHash))
+	if errors.Is(err, redis.ErrNil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	refreshSession := &RefreshSession{}
+	err = json.Unmarshal(refreshSessionFromRedis, refreshSession)
+	if err != nil {
+		return nil, false, err
+	}
+	_, err = redis.String(s.redisConn.Do("SET", usedRefreshTokenKeyPrefix+tokenHash, 1, "NX", "EX", s.refreshExpireTime))
+	if errors.Is(err, redis.ErrNil) {
+		return refreshSession, true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return refreshSession, false, nil
+}
+
+// RevokeFamilyRepo deletes all the access and refresh tokens of the family and removes it from the sessions of the user.
+func (s *SessionRepoRedis) RevokeFamilyRepo(familyID string) error {
+	userID, err := redis.Uint64(s.redisConn.Do("HGET", sessionInfoKeyPrefix+familyID, "userID"))
+	if err != nil && !errors.Is(err, redis.ErrNil) {
+		return err
+	}
+	if err == nil {
+		_, err = s.redisConn.Do("SREM", userSessionsKey(userID), familyID)
+		if err != nil {
+			return err
+		}
+	}
+	familyKey := refreshFamilyKeyPrefix + familyID
+	keys, err := redis.Strings(s.redisConn.Do("SMEMBERS", familyKey))
+	if err != nil {
+		return err
+	}
+	_, err = s.redisConn.Do("DEL", redis.Args{}.Add(familyKey, sessionInfoKeyPrefix+familyID).AddFlat(keys)...)
+	return err
+}
+
+func (s *SessionRepoRedis) CreateSessionInfoRepo(sessionInfo *SessionInfo) error {
+	sessionInfoKey := sessionInfoKeyPrefix + sessionInfo.ID
+	_, err := s.redisConn.Do("HSET", redis.Args{}.Add(sessionInfoKey).AddFlat(sessionInfo)...)
+	if err != nil {
+		return err
+	}
+	_, err = s.redisConn.Do("EXPIRE", sessionInfoKey, s.refreshExpireTime)
+	if err != nil {
+		return err
+	}
+	sessionsKey := userSessionsKey(sessionInfo.UserID)
+	_, err = s.redisConn.Do("SADD", sessionsKey, sessionInfo.ID)
+	if err != nil {
+		return err
+	}
+	_, err = s.redisConn.Do("EXPIRE", sessionsKey, s.refreshExpireTime)
+	return err
+}
+
+// TouchSessionInfoRepo updates the last seen time of the session at most once in lastSeenUpdateInterval.
+func (s *SessionRepoRedis) TouchSessionInfoRepo(familyID string, lastSeenAt time.Time) error {
+	sessionInfoKey := sessionInfoKeyPrefix + familyID
+	previousLastSeenAt, err := redis.Int64(s.redisConn.Do("HGET", sessionInfoKey, "lastSeenAt"))
+	if errors.Is(err, redis.ErrNil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if lastSeenAt.Sub(time.Unix(previousLastSeenAt, 0)) < lastSeenUpdateInterval {
+		return nil
+	}
+	_, err = s.redisConn.Do("HSET", sessionInfoKey, "lastSeenAt", lastSeenAt.Unix())
+	return err
+}
+
+// UpdateSessionClientRepo records the client of the refresh and prolongs the session as the new refresh token does.
+func (s *SessionRepoRedis) UpdateSessionClientRepo(familyID string, client *auth.ClientInfo, lastSeenAt time.Time) error {
+	sessionInfoKey := sessionInfoKeyPrefix + familyID
+	userID, err := redis.Uint64(s.redisConn.Do("HGET", sessionInfoKey, "userID"))
+	if errors.Is(err, redis.ErrNil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = s.redisConn.Do("HSET", sessionInfoKey, "device", client.GetDevice(), "ip", client.GetIP(),
+		"userAgent", client.GetUserAgent(), "lastSeenAt", lastSeenAt.Unix())
+	if err != nil {
+		return err
+	}
+	_, err = s.redisConn.Do("EXPIRE", sessionInfoKey, s.refreshExpireTime)
+	if err != nil {
+		return err
+	}
+	_, err = s.redisConn.Do("EXPIRE", userSessionsKey(userID), s.refreshExpireTime)
+	return err
+}
+
+// GetUserSessionsRepo returns the sessions of the user and forgets the ones that have expired.
+func (s *SessionRepoRedis) GetUserSessionsRepo(userID uint64) ([]*SessionInfo, error) {
+	sessionsKey := userSessionsKey(userID)
+	familyIDs, err := redis.Strings(s.redisConn.Do("SMEMBERS", sessionsKey))
+	if err != nil {
+		return nil, err
+	}
+	sessions := make([]*SessionInfo, 0, len(familyIDs))
+	for _, familyID := range familyIDs {
+		values, err := redis.Values(s.redisConn.Do("HGETALL", sessionInfoKeyPrefix+familyID))
+		if err != nil {
+			return nil, err
+		}
+		if len(values) == 0 {
+			_, err = s.redisConn.Do("SREM", sessionsKey, familyID)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		sessionInfo := &SessionInfo{ID: familyID}
+		err = redis.ScanStruct(values, sessionInfo)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sessionInfo)
+	}
+	return sessions, nil
+}
+
+func (s *SessionRepoRedis) HasUserSessionRepo(userID uint64, familyID string) (bool, error) {
+	return redis.Bool(s.redisConn.Do("SISMEMBER", userSessionsKey(userID), familyID))
+}
+
+func (s *SessionRepoRedis) CreateOIDCStateRepo(state string, oidcState *OIDCState) error {
+	oidcStateJSON, err := json.Marshal(oidcState)
+	if err != nil {
+		return err
+	}
+	_, err = s.redisConn.Do("SET", oidcStateKeyPrefix+hashToken(state), oidcStateJSON, "EX", oidcStateExpireTime)
+	return err
+}
+
+// PopOIDCStateRepo returns the state and deletes it, so each state is used once. It returns nil if the state
+// is unknown, expired or has been used by a concurrent request.
+func (s *SessionRepoRedis) PopOIDCStateRepo(state string) (*OIDCState, error) {
+	oidcStateKey := oidcStateKeyPrefix + hashToken(state)
+	oidcStateFromRedis, err := redis.Bytes(s.redisConn.Do("GET", oidcStateKey))
+	if errors.Is(err, redis.ErrNil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := redis.Int(s.redisConn.Do("DEL", oidcStateKey))
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, nil
+	}
+	oidcState := &OIDCState{}
+	err = json.Unmarshal(oidcStateFromRedis, oidcState)
+	if err != nil {
+		return nil, err
+	}
+	return oidcState, nil
+}
+
+// addToFamily remembers the keys of the family, the family lives as long as its last refresh token.
+func (s *SessionRepoRedis) addToFamily(familyID string, keys ...string) error {
+	familyKey := refreshFamilyKeyPrefix + familyID
+	_, err := s.redisConn.Do("SADD", redis.Args{}.Add(familyKey).AddFlat(keys)...)
+	if err != nil {
+		return err
+	}
+	_, err = s.redisConn.Do("EXPIRE", familyKey, s.refreshExpireTime)
+	return err
+}
+
+func userSessionsKey(userID uint64) string {
+	return userSessionsKeyPrefix + strconv.FormatUint(userID, 10)
+}
+
+func hashToken(token string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(tokenHash[:])
+}
@@ -2,35 +2,73 @@ package authserviceusecase
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"fmt"
 	"github.com/dgrijalva/jwt-go"
+	"go.uber.org/zap"
 	errorauth "kinopoisk/service_auth/error"
 	"kinopoisk/service_auth/interceptor"
+	authmail "kinopoisk/service_auth/mail"
+	authoidc "kinopoisk/service_auth/oidc"
+	authpassword "kinopoisk/service_auth/password"
 	auth "kinopoisk/service_auth/proto"
 	userrepo "kinopoisk/service_auth/repo/mysql"
 	sessionrepo "kinopoisk/service_auth/repo/redis"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
 
+const (
+	refreshTokenLength = 32
+	familyIDLength     = 16
+	tokenIDLength      = 16
+	resetTokenLength   = 32
+
+	sessionTimeFormat = "2006-01-02 15:04:05"
+)
+
+// LetterSettings configure the letters with the password reset or the email verification token. URL is the page
+// of the frontend the token is appended to, without it the letter has only the token.
+type LetterSettings struct {
+	TokenTTL time.Duration
+	URL      string
+}
+
 type AuthGRPCServer struct {
 	auth.UnimplementedAuthMakerServer
 
-	mu          *sync.RWMutex
-	UserRepo    userrepo.UserRepo
-	SessionRepo sessionrepo.SessionRepo
-	secret      []byte
+	mu             *sync.RWMutex
+	UserRepo       userrepo.UserRepo
+	SessionRepo    sessionrepo.SessionRepo
+	MailSender     authmail.Sender
+	secret         []byte
+	accessTokenTTL time.Duration
+	passwordReset  *LetterSettings
+	// emailVerification is used for the email given in register and for the changed email
+	emailVerification *LetterSettings
+	// oidcProviders are the identity providers by name, it is empty if the login with them is off
+	oidcProviders map[string]*authoidc.Provider
 }
 
-func NewAuthGRPCServer(userRepo userrepo.UserRepo, sessionRepo sessionrepo.SessionRepo) *AuthGRPCServer {
+func NewAuthGRPCServer(userRepo userrepo.UserRepo, sessionRepo sessionrepo.SessionRepo, mailSender authmail.Sender,
+	accessTokenTTL time.Duration, passwordReset, emailVerification *LetterSettings,
+	oidcProviders map[string]*authoidc.Provider) *AuthGRPCServer {
 	return &AuthGRPCServer{
 		UnimplementedAuthMakerServer: auth.UnimplementedAuthMakerServer{},
 		UserRepo:                     userRepo,
 		SessionRepo:                  sessionRepo,
+		MailSender:                   mailSender,
 		secret:                       []byte(os.Getenv("SECRET")),
+		accessTokenTTL:               accessTokenTTL,
+		passwordReset:                passwordReset,
+		emailVerification:            emailVerification,
+		oidcProviders:                oidcProviders,
 		mu:                           &sync.RWMutex{},
 	}
 }
@@ -40,24 +78,79 @@ func (a *AuthGRPCServer) Login(ctx context.Context, in *auth.AuthData) (*auth.Us
 	if err != nil {
 		return &auth.User{}, errorauth.ErrorNoLogger
 	}
-	hashPassword, err := getHashPassword(in.Password)
-	if err != nil {
-		logger.Errorf("error in getting hash password: %s", err)
-		return &auth.User{}, err
-	}
 	a.mu.RLock()
-	loggedInUser, err := a.UserRepo.LoginRepo(in.Username, hashPassword)
+	loggedInUser, credentials, err := a.UserRepo.GetUserCredentialsRepo(in.Username)
 	a.mu.RUnlock()
 	if err != nil {
 		logger.Errorf("error in login user in db: %s", err)
 		return &auth.User{}, err
 	}
+	// the password is checked against some hash in every case, so the time of the answer does not show
+	// if the user exists or what state the account is in
 	if loggedInUser == nil {
-		loggedInUser = &auth.User{}
+		authpassword.VerifyDummy(in.Password, authpassword.DefaultParams)
+		return &auth.User{}, nil
+	}
+	if credentials.PasswordHash == "" {
+		// the user has signed up with an identity provider and has not set a password, or the expired password
+		// has been removed. The answer is the one for the wrong password, the reset is told about only
+		// in the letter of the forgot-password flow
+		authpassword.VerifyDummy(in.Password, authpassword.DefaultParams)
+		if credentials.ResetRequired {
+			logger.Infof("password of user %d must be reset", loggedInUser.ID)
+		}
+		return &auth.User{}, nil
+	}
+	ok, needsRehash, err := authpassword.Verify(in.Password, credentials.PasswordHash, authpassword.DefaultParams)
+	if err != nil {
+		logger.Errorf("error in checking password of user %d: %s", loggedInUser.ID, err)
+		return &auth.User{}, err
+	}
+	if !ok {
+		return &auth.User{}, nil
+	}
+	if needsRehash {
+		a.rehashPassword(logger, loggedInUser.ID, in.Password, credentials.PasswordHash)
 	}
 	return loggedInUser, nil
 }
 
+// rehashPassword upgrades the hash of the user to the current parameters, the user is logged in even if it fails.
+func (a *AuthGRPCServer) rehashPassword(logger *zap.SugaredLogger, userID uint64, password, oldHash string) {
+	newHash, err := authpassword.Hash(password, authpassword.DefaultParams)
+	if err != nil {
+		logger.Errorf("error in hashing password of user %d: %s", userID, err)
+		return
+	}
+	a.mu.Lock()
+	updated, err := a.UserRepo.UpdatePasswordHashRepo(userID, oldHash, newHash)
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in upgrading password hash of user %d: %s", userID, err)
+		return
+	}
+	if !updated {
+		logger.Infof("password of user %d has been changed meanwhile, the hash is not upgraded", userID)
+		return
+	}
+	logger.Infof("password hash of user %d is upgraded", userID)
+}
+
+// ExpireLegacyPasswords makes the users who still have the legacy password hashes reset their passwords
+// and removes the hashes.
+func (a *AuthGRPCServer) ExpireLegacyPasswords(logger *zap.SugaredLogger) {
+	a.mu.Lock()
+	expired, err := a.UserRepo.ExpireLegacyPasswordsRepo()
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in expiring legacy passwords: %s", err)
+		return
+	}
+	if expired > 0 {
+		logger.Infof("%d legacy passwords are expired, the users must reset them", expired)
+	}
+}
+
 func (a *AuthGRPCServer) Register(ctx context.Context, in *auth.AuthData) (*auth.User, error) {
 	logger, err := interceptor.GetLoggerFromContext(ctx)
 	if err != nil {
@@ -74,7 +167,21 @@ func (a *AuthGRPCServer) Register(ctx context.Context, in *auth.AuthData) (*auth
 		logger.Errorf("user with login %s already exists", in.Username)
 		return &auth.User{}, nil
 	}
-	hashPassword, err := getHashPassword(in.Password)
+	email := normalizeEmail(in.Email)
+	if email != "" {
+		a.mu.RLock()
+		userWithEmail, err := a.UserRepo.FindUserByEmailRepo(email)
+		a.mu.RUnlock()
+		if err != nil {
+			logger.Errorf("error in getting user by email: %s", err)
+			return &auth.User{}, err
+		}
+		if userWithEmail != nil {
+			logger.Errorf("user with email of %s already exists", in.Username)
+			return &auth.User{}, nil
+		}
+	}
+	hashPassword, err := authpassword.Hash(in.Password, authpassword.DefaultParams)
 	if err != nil {
 		logger.Errorf("error in getting hash password: %s", err)
 		return &auth.User{}, err
@@ -87,34 +194,133 @@ func (a *AuthGRPCServer) Register(ctx context.Context, in *auth.AuthData) (*auth
 		return &auth.User{}, err
 	}
 	if newUser == nil {
-		newUser = &auth.User{}
+		return &auth.User{}, nil
+	}
+	if email != "" {
+		err = a.startEmailVerification(logger, newUser, email)
+		if err != nil {
+			// the user is registered anyway and can ask for the letter again by changing the email
+			logger.Errorf("error in starting verification of email of user %d: %s", newUser.ID, err)
+		}
 	}
 	return newUser, nil
 }
 
-func (a *AuthGRPCServer) CreateSession(ctx context.Context, in *auth.User) (*auth.Token, error) {
+// CreateTokenPair starts a new token family with an access token and a refresh token, the family is a session
+// the user can see in the list of the sessions.
+func (a *AuthGRPCServer) CreateTokenPair(ctx context.Context, in *auth.NewSession) (*auth.TokenPair, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.TokenPair{}, errorauth.ErrorNoLogger
+	}
+	familyID, err := newRandomString(familyIDLength)
+	if err != nil {
+		logger.Errorf("error in generating token family id: %s", err)
+		return &auth.TokenPair{}, err
+	}
+	now := time.Now().Unix()
+	a.mu.Lock()
+	err = a.SessionRepo.CreateSessionInfoRepo(&sessionrepo.SessionInfo{
+		ID:         familyID,
+		UserID:     in.User.GetID(),
+		Device:     in.Client.GetDevice(),
+		IP:         in.Client.GetIP(),
+		UserAgent:  in.Client.GetUserAgent(),
+		CreatedAt:  now,
+		LastSeenAt: now,
+	})
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in saving session info: %s", err)
+		return &auth.TokenPair{}, err
+	}
+	tokenPair, err := a.issueTokenPair(in.User, familyID)
+	if err != nil {
+		logger.Errorf("error in creating token pair: %s", err)
+		return &auth.TokenPair{}, err
+	}
+	return tokenPair, nil
+}
+
+// RefreshTokens exchanges the refresh token for a new pair, each refresh token can be used once.
+// A replayed refresh token means that it has leaked, so all the tokens of its family are revoked.
+func (a *AuthGRPCServer) RefreshTokens(ctx context.Context, in *auth.RefreshToken) (*auth.TokenPair, error) {
 	logger, err := interceptor.GetLoggerFromContext(ctx)
 	if err != nil {
-		return &auth.Token{}, errorauth.ErrorNoLogger
+		return &auth.TokenPair{}, errorauth.ErrorNoLogger
 	}
-	token, err := a.newToken(in)
+	a.mu.Lock()
+	refreshSession, isReused, err := a.SessionRepo.UseRefreshTokenRepo(in.RefreshToken)
+	a.mu.Unlock()
 	if err != nil {
-		logger.Errorf("error in getting session token: %s", err)
-		return &auth.Token{}, err
+		logger.Errorf("error in using refresh token: %s", err)
+		return &auth.TokenPair{}, err
+	}
+	if refreshSession == nil {
+		return &auth.TokenPair{}, nil
+	}
+	if isReused {
+		logger.Warnf("refresh token of user %d is reused, revoking token family %s", refreshSession.User.GetID(),
+			refreshSession.FamilyID)
+		a.mu.Lock()
+		err = a.SessionRepo.RevokeFamilyRepo(refreshSession.FamilyID)
+		a.mu.Unlock()
+		if err != nil {
+			logger.Errorf("error in revoking token family %s: %s", refreshSession.FamilyID, err)
+			return &auth.TokenPair{}, err
+		}
+		return &auth.TokenPair{}, errorauth.ErrorRefreshTokenReused
 	}
-	newSession := &auth.Session{
-		ID:   token,
-		User: in,
+	tokenPair, err := a.issueTokenPair(refreshSession.User, refreshSession.FamilyID)
+	if err != nil {
+		logger.Errorf("error in rotating refresh token: %s", err)
+		return &auth.TokenPair{}, err
 	}
 	a.mu.Lock()
-	err = a.SessionRepo.CreateSessionRepo(newSession)
+	err = a.SessionRepo.UpdateSessionClientRepo(refreshSession.FamilyID, in.Client, time.Now())
 	a.mu.Unlock()
 	if err != nil {
-		logger.Errorf("error in creating session: %s", err)
-		return &auth.Token{}, err
+		logger.Errorf("error in updating session info of family %s: %s", refreshSession.FamilyID, err)
 	}
-	return &auth.Token{Token: token}, nil
+	return tokenPair, nil
+}
 
+// issueTokenPair loads the roles of the user again, so the tokens carry the roles granted since the login.
+func (a *AuthGRPCServer) issueTokenPair(user *auth.User, familyID string) (*auth.TokenPair, error) {
+	user, err := a.userWithRoles(user)
+	if err != nil {
+		return nil, err
+	}
+	accessToken, err := a.newToken(user)
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := newRandomString(refreshTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	err = a.SessionRepo.CreateSessionRepo(&auth.Session{
+		ID:       accessToken,
+		User:     user,
+		FamilyID: familyID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = a.SessionRepo.CreateRefreshTokenRepo(refreshToken, &sessionrepo.RefreshSession{
+		FamilyID: familyID,
+		User:     user,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &auth.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(a.accessTokenTTL.Seconds()),
+	}, nil
 }
 
 func (a *AuthGRPCServer) GetSession(ctx context.Context, in *auth.Token) (*auth.Session, error) {
@@ -142,6 +348,14 @@ func (a *AuthGRPCServer) GetSession(ctx context.Context, in *auth.Token) (*auth.
 		logger.Errorf("error in getting session from db: %s", err)
 		return &auth.Session{}, err
 	}
+	if sess.FamilyID != "" {
+		a.mu.Lock()
+		err = a.SessionRepo.TouchSessionInfoRepo(sess.FamilyID, time.Now())
+		a.mu.Unlock()
+		if err != nil {
+			logger.Errorf("error in updating last seen time of session: %s", err)
+		}
+	}
 	return sess, nil
 
 }
@@ -161,22 +375,379 @@ func (a *AuthGRPCServer) DeleteSession(ctx context.Context, in *auth.Token) (*au
 	return &auth.IsDeleted{IsDeleted: idDeleted}, nil
 }
 
-func getHashPassword(password string) (string, error) {
-	hash := sha256.New()
-	_, err := hash.Write([]byte(password))
+// GetUserSessions lists the sessions of the owner of the token, the most recently used first.
+func (a *AuthGRPCServer) GetUserSessions(ctx context.Context, in *auth.Token) (*auth.UserSessions, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
 	if err != nil {
-		return "", err
+		return &auth.UserSessions{}, errorauth.ErrorNoLogger
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	sess, err := a.SessionRepo.GetSessionRepo(in.Token)
+	if err != nil {
+		logger.Errorf("error in getting session from db: %s", err)
+		return &auth.UserSessions{}, err
+	}
+	sessionInfos, err := a.SessionRepo.GetUserSessionsRepo(sess.User.GetID())
+	if err != nil {
+		logger.Errorf("error in getting sessions of user %d: %s", sess.User.GetID(), err)
+		return &auth.UserSessions{}, err
+	}
+	sort.Slice(sessionInfos, func(i, j int) bool {
+		return sessionInfos[i].LastSeenAt > sessionInfos[j].LastSeenAt
+	})
+	userSessions := &auth.UserSessions{Sessions: make([]*auth.UserSession, 0, len(sessionInfos))}
+	for _, sessionInfo := range sessionInfos {
+		userSessions.Sessions = append(userSessions.Sessions, &auth.UserSession{
+			ID:         sessionInfo.ID,
+			Device:     sessionInfo.Device,
+			IP:         sessionInfo.IP,
+			UserAgent:  sessionInfo.UserAgent,
+			CreatedAt:  time.Unix(sessionInfo.CreatedAt, 0).UTC().Format(sessionTimeFormat),
+			LastSeenAt: time.Unix(sessionInfo.LastSeenAt, 0).UTC().Format(sessionTimeFormat),
+			Current:    sessionInfo.ID == sess.FamilyID,
+		})
+	}
+	return userSessions, nil
+}
+
+// DeleteUserSession revokes one session of the user, the session of another user is treated as missing.
+func (a *AuthGRPCServer) DeleteUserSession(ctx context.Context, in *auth.SessionOfUser) (*auth.IsDeleted, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.IsDeleted{IsDeleted: false}, errorauth.ErrorNoLogger
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	isUserSession, err := a.SessionRepo.HasUserSessionRepo(in.UserID, in.SessionID)
+	if err != nil {
+		logger.Errorf("error in checking session of user %d: %s", in.UserID, err)
+		return &auth.IsDeleted{IsDeleted: false}, err
+	}
+	if !isUserSession {
+		return &auth.IsDeleted{IsDeleted: false}, nil
+	}
+	err = a.SessionRepo.RevokeFamilyRepo(in.SessionID)
+	if err != nil {
+		logger.Errorf("error in revoking session %s: %s", in.SessionID, err)
+		return &auth.IsDeleted{IsDeleted: false}, err
+	}
+	return &auth.IsDeleted{IsDeleted: true}, nil
+}
+
+// DeleteAllSessions revokes all the sessions of the user including the one of the request.
+func (a *AuthGRPCServer) DeleteAllSessions(ctx context.Context, in *auth.User) (*auth.DeletedSessions, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.DeletedSessions{}, errorauth.ErrorNoLogger
+	}
+	a.mu.Lock()
+	revoked, err := a.revokeUserSessions(in.ID, "")
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in revoking sessions of user %d: %s", in.ID, err)
+		return &auth.DeletedSessions{}, err
+	}
+	return &auth.DeletedSessions{Count: revoked}, nil
+}
+
+// revokeUserSessions revokes the sessions of the user except keptFamilyID and returns how many were revoked,
+// the caller holds the lock.
+func (a *AuthGRPCServer) revokeUserSessions(userID uint64, keptFamilyID string) (uint64, error) {
+	sessionInfos, err := a.SessionRepo.GetUserSessionsRepo(userID)
+	if err != nil {
+		return 0, err
+	}
+	var revoked uint64
+	for _, sessionInfo := range sessionInfos {
+		if sessionInfo.ID == keptFamilyID {
+			continue
+		}
+		err = a.SessionRepo.RevokeFamilyRepo(sessionInfo.ID)
+		if err != nil {
+			return revoked, err
+		}
+		revoked++
 	}
-	hashBytes := hash.Sum(nil)
-	hashPass := hex.EncodeToString(hashBytes)
-	return hashPass, nil
+	return revoked, nil
+}
+
+// ChangePassword sets the new password if the old one is right and ends all the other sessions of the user.
+func (a *AuthGRPCServer) ChangePassword(ctx context.Context, in *auth.PasswordChange) (*auth.PasswordChanged, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.PasswordChanged{}, errorauth.ErrorNoLogger
+	}
+	a.mu.RLock()
+	sess, err := a.SessionRepo.GetSessionRepo(in.Token)
+	a.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting session from db: %s", err)
+		return &auth.PasswordChanged{}, err
+	}
+	userID := sess.User.GetID()
+	a.mu.RLock()
+	credentials, err := a.UserRepo.GetUserCredentialsByIDRepo(userID)
+	a.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting credentials of user %d: %s", userID, err)
+		return &auth.PasswordChanged{}, err
+	}
+	// the user without a password sets it through the password reset
+	if credentials == nil || credentials.PasswordHash == "" {
+		return &auth.PasswordChanged{IsChanged: false}, nil
+	}
+	ok, _, err := authpassword.Verify(in.OldPassword, credentials.PasswordHash, authpassword.DefaultParams)
+	if err != nil {
+		logger.Errorf("error in checking password of user %d: %s", userID, err)
+		return &auth.PasswordChanged{}, err
+	}
+	if !ok {
+		return &auth.PasswordChanged{IsChanged: false}, nil
+	}
+	newHash, err := authpassword.Hash(in.NewPassword, authpassword.DefaultParams)
+	if err != nil {
+		logger.Errorf("error in hashing password of user %d: %s", userID, err)
+		return &auth.PasswordChanged{}, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	updated, err := a.UserRepo.UpdatePasswordHashRepo(userID, credentials.PasswordHash, newHash)
+	if err != nil {
+		logger.Errorf("error in updating password of user %d: %s", userID, err)
+		return &auth.PasswordChanged{}, err
+	}
+	// the password has been changed by a concurrent request since the old one was checked
+	if !updated {
+		logger.Infof("password of user %d has been changed meanwhile", userID)
+		return &auth.PasswordChanged{IsChanged: false}, nil
+	}
+	revoked, err := a.revokeUserSessions(userID, sess.FamilyID)
+	if err != nil {
+		logger.Errorf("error in revoking sessions of user %d: %s", userID, err)
+		return &auth.PasswordChanged{}, err
+	}
+	logger.Infof("password of user %d is changed, %d other sessions are ended", userID, revoked)
+	return &auth.PasswordChanged{IsChanged: true}, nil
+}
+
+// RequestPasswordReset mails a reset token if there is a user with the email. The answer does not depend on it
+// and the letter is sent in background, so the endpoint does not tell which emails are registered.
+func (a *AuthGRPCServer) RequestPasswordReset(ctx context.Context, in *auth.PasswordResetRequest) (*auth.PasswordResetRequested, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.PasswordResetRequested{}, errorauth.ErrorNoLogger
+	}
+	email := normalizeEmail(in.Email)
+	a.mu.RLock()
+	user, err := a.UserRepo.FindUserByEmailRepo(email)
+	a.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting user by email: %s", err)
+		return &auth.PasswordResetRequested{}, err
+	}
+	if user == nil {
+		logger.Infof("password reset is requested for unknown email")
+		return &auth.PasswordResetRequested{}, nil
+	}
+	// the letter tells the owner of the email if the password has been expired, the login does not
+	a.mu.RLock()
+	credentials, err := a.UserRepo.GetUserCredentialsByIDRepo(user.ID)
+	a.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting credentials of user %d: %s", user.ID, err)
+		return &auth.PasswordResetRequested{}, err
+	}
+	resetToken, err := newRandomString(resetTokenLength)
+	if err != nil {
+		logger.Errorf("error in generating reset token: %s", err)
+		return &auth.PasswordResetRequested{}, err
+	}
+	a.mu.Lock()
+	err = a.UserRepo.CreatePasswordResetTokenRepo(user.ID, hashMailToken(resetToken), a.passwordReset.TokenTTL)
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in saving reset token of user %d: %s", user.ID, err)
+		return &auth.PasswordResetRequested{}, err
+	}
+	purpose := "reset your password"
+	if credentials != nil && credentials.ResetRequired {
+		purpose += ". Your old password has expired, set a new one to log in again"
+	}
+	go a.sendTokenLetter(logger, email, user, "Password reset", purpose, resetToken, a.passwordReset)
+	return &auth.PasswordResetRequested{}, nil
+}
+
+// sendTokenLetter mails the reset or the verification token, it runs in background and only logs the errors.
+func (a *AuthGRPCServer) sendTokenLetter(logger *zap.SugaredLogger, email string, user *auth.User, subject, purpose, token string,
+	settings *LetterSettings) {
+	greeting := "Hello!"
+	if user.Username != "" {
+		greeting = fmt.Sprintf("Hello, %s!", user.Username)
+	}
+	body := fmt.Sprintf("%s\n\nSomeone, hopefully you, asked to %s. The token is\n\n%s\n\n", greeting, purpose, token)
+	if settings.URL != "" {
+		body += fmt.Sprintf("To do it open %s%s\n\n", settings.URL, token)
+	}
+	body += fmt.Sprintf("The token works once and expires in %s. If you have not asked for it, ignore this letter.\n",
+		settings.TokenTTL)
+	err := a.MailSender.Send(email, subject, body)
+	if err != nil {
+		logger.Errorf("error in sending %q letter to user %d: %s", subject, user.ID, err)
+		return
+	}
+	logger.Infof("%q letter is sent to user %d", subject, user.ID)
+}
+
+// ResetPassword sets the new password by the reset token and ends all the sessions of the user.
+// It returns the empty user if the token is unknown, expired or used.
+func (a *AuthGRPCServer) ResetPassword(ctx context.Context, in *auth.PasswordReset) (*auth.User, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.User{}, errorauth.ErrorNoLogger
+	}
+	newHash, err := authpassword.Hash(in.NewPassword, authpassword.DefaultParams)
+	if err != nil {
+		logger.Errorf("error in hashing password: %s", err)
+		return &auth.User{}, err
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	userID, err := a.UserRepo.ResetPasswordRepo(hashMailToken(in.Token), newHash)
+	if err != nil {
+		logger.Errorf("error in resetting password: %s", err)
+		return &auth.User{}, err
+	}
+	if userID == 0 {
+		return &auth.User{}, nil
+	}
+	revoked, err := a.revokeUserSessions(userID, "")
+	if err != nil {
+		logger.Errorf("error in revoking sessions of user %d: %s", userID, err)
+		return &auth.User{}, err
+	}
+	logger.Infof("password of user %d is reset, %d sessions are ended", userID, revoked)
+	return &auth.User{ID: userID}, nil
+}
+
+// startEmailVerification mails the token which makes the email the email of the user.
+func (a *AuthGRPCServer) startEmailVerification(logger *zap.SugaredLogger, user *auth.User, email string) error {
+	verificationToken, err := newRandomString(resetTokenLength)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	err = a.UserRepo.CreateEmailVerificationTokenRepo(user.ID, email, hashMailToken(verificationToken), a.emailVerification.TokenTTL)
+	a.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	go a.sendTokenLetter(logger, email, user, "Email verification", "verify this email for your account", verificationToken,
+		a.emailVerification)
+	return nil
+}
+
+func (a *AuthGRPCServer) GetEmailStatus(ctx context.Context, in *auth.User) (*auth.EmailStatus, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.EmailStatus{}, errorauth.ErrorNoLogger
+	}
+	a.mu.RLock()
+	emailStatus, err := a.UserRepo.GetEmailStatusRepo(in.ID)
+	a.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting email of user %d: %s", in.ID, err)
+		return &auth.EmailStatus{}, err
+	}
+	if emailStatus == nil {
+		return &auth.EmailStatus{}, nil
+	}
+	return &auth.EmailStatus{
+		Email:        emailStatus.Email,
+		PendingEmail: emailStatus.PendingEmail,
+	}, nil
+}
+
+// ChangeEmail starts the verification of the new email, the old one stays the email of the user until then.
+func (a *AuthGRPCServer) ChangeEmail(ctx context.Context, in *auth.EmailChange) (*auth.EmailStatus, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.EmailStatus{}, errorauth.ErrorNoLogger
+	}
+	email := normalizeEmail(in.Email)
+	a.mu.RLock()
+	userWithEmail, err := a.UserRepo.FindUserByEmailRepo(email)
+	a.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting user by email: %s", err)
+		return &auth.EmailStatus{}, err
+	}
+	if userWithEmail != nil && userWithEmail.ID != in.UserID {
+		return &auth.EmailStatus{}, errorauth.ErrorEmailTaken
+	}
+	if userWithEmail == nil {
+		err = a.startEmailVerification(logger, &auth.User{ID: in.UserID}, email)
+		if err != nil {
+			logger.Errorf("error in starting verification of email of user %d: %s", in.UserID, err)
+			return &auth.EmailStatus{}, err
+		}
+	}
+	return a.GetEmailStatus(ctx, &auth.User{ID: in.UserID})
+}
+
+// VerifyEmail sets the email of the token to its user, it returns the empty user if the token is unknown,
+// expired or used.
+func (a *AuthGRPCServer) VerifyEmail(ctx context.Context, in *auth.EmailVerification) (*auth.User, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.User{}, errorauth.ErrorNoLogger
+	}
+	a.mu.Lock()
+	userID, isTaken, err := a.UserRepo.VerifyEmailRepo(hashMailToken(in.Token))
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in verifying email: %s", err)
+		return &auth.User{}, err
+	}
+	if isTaken {
+		return &auth.User{}, errorauth.ErrorEmailTaken
+	}
+	if userID == 0 {
+		return &auth.User{}, nil
+	}
+	logger.Infof("email of user %d is verified", userID)
+	return &auth.User{ID: userID}, nil
+}
+
+func (a *AuthGRPCServer) SetProfilePrivacy(ctx context.Context, in *auth.PrivacySettings) (*auth.PrivacySettings, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.PrivacySettings{}, errorauth.ErrorNoLogger
+	}
+	a.mu.Lock()
+	err = a.UserRepo.SetProfilePrivacyRepo(in.UserID, in.IsPrivate)
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in setting profile privacy in db: %s", err)
+		return &auth.PrivacySettings{}, err
+	}
+	return &auth.PrivacySettings{
+		UserID:    in.UserID,
+		IsPrivate: in.IsPrivate,
+	}, nil
 }
 
 func (a *AuthGRPCServer) newToken(user *auth.User) (string, error) {
+	// jti keeps the tokens issued to one user in the same second different, they are the session keys
+	tokenID, err := newRandomString(tokenIDLength)
+	if err != nil {
+		return "", err
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"user": user,
+		"jti":  tokenID,
 		"iat":  time.Now().Unix(),
-		"exp":  time.Now().Add(time.Hour * 24 * 7).Unix(),
+		"exp":  time.Now().Add(a.accessTokenTTL).Unix(),
 	})
 	tokenString, err := token.SignedString(a.secret)
 	if err != nil {
@@ -184,3 +755,23 @@ func (a *AuthGRPCServer) newToken(user *auth.User) (string, error) {
 	}
 	return tokenString, nil
 }
+
+func newRandomString(length int) (string, error) {
+	randomBytes := make([]byte, length)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(randomBytes), nil
+}
+
+// hashMailToken is what the mailed tokens are stored by, a leaked table does not let anyone use them.
+func hashMailToken(token string) string {
+	tokenHash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(tokenHash[:])
+}
+
+// normalizeEmail keeps one account per mailbox however the user types the address.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
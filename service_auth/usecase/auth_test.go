@@ -0,0 +1,492 @@
+package authserviceusecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"go.uber.org/zap"
+	errorauth "kinopoisk/service_auth/error"
+	"kinopoisk/service_auth/interceptor"
+	authpassword "kinopoisk/service_auth/password"
+	authpermission "kinopoisk/service_auth/permission"
+	auth "kinopoisk/service_auth/proto"
+	userrepo "kinopoisk/service_auth/repo/mysql"
+	authserviceusecase "kinopoisk/service_auth/usecase"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeUserRepo keeps the users in memory, the methods the tests do not need panic on the nil interface.
+type fakeUserRepo struct {
+	userrepo.UserRepo
+	users       map[string]*auth.User
+	credentials map[uint64]*userrepo.UserCredentials
+	emails      map[uint64]string
+	// pendingEmails are the emails waiting for verification
+	pendingEmails map[uint64]string
+	// identities are the users by the provider and the subject of the identity
+	identities map[string]uint64
+	roles      map[uint64][]string
+	// beforeUpdate runs before the password hash is replaced, as a concurrent request would
+	beforeUpdate func()
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{
+		users:         make(map[string]*auth.User),
+		credentials:   make(map[uint64]*userrepo.UserCredentials),
+		emails:        make(map[uint64]string),
+		pendingEmails: make(map[uint64]string),
+		identities:    make(map[string]uint64),
+		roles:         make(map[uint64][]string),
+	}
+}
+
+func (f *fakeUserRepo) addUser(id uint64, username, passwordHash string) {
+	f.users[username] = &auth.User{ID: id, Username: username}
+	f.credentials[id] = &userrepo.UserCredentials{PasswordHash: passwordHash}
+}
+
+func (f *fakeUserRepo) GetUserCredentialsRepo(username string) (*auth.User, *userrepo.UserCredentials, error) {
+	foundUser, ok := f.users[username]
+	if !ok {
+		return nil, nil, nil
+	}
+	credentials := *f.credentials[foundUser.ID]
+	return &auth.User{ID: foundUser.ID, Username: foundUser.Username}, &credentials, nil
+}
+
+func (f *fakeUserRepo) GetUserCredentialsByIDRepo(userID uint64) (*userrepo.UserCredentials, error) {
+	credentials, ok := f.credentials[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *credentials
+	return &copied, nil
+}
+
+func (f *fakeUserRepo) UpdatePasswordHashRepo(userID uint64, oldHash, newHash string) (bool, error) {
+	if f.beforeUpdate != nil {
+		f.beforeUpdate()
+	}
+	if f.credentials[userID].PasswordHash != oldHash {
+		return false, nil
+	}
+	f.credentials[userID].PasswordHash = newHash
+	return true, nil
+}
+
+func (f *fakeUserRepo) GetUserRolesRepo(userID uint64) ([]string, []string, error) {
+	return append([]string{}, f.roles[userID]...), []string{}, nil
+}
+
+func (f *fakeUserRepo) RoleExistsRepo(role string) (bool, error) {
+	return role == authpermission.AdminRole || role == authpermission.CriticRole, nil
+}
+
+func (f *fakeUserRepo) GrantRoleRepo(userID uint64, role string, grantedBy uint64) (bool, error) {
+	for _, userRole := range f.roles[userID] {
+		if userRole == role {
+			return false, nil
+		}
+	}
+	f.roles[userID] = append(f.roles[userID], role)
+	return true, nil
+}
+
+func (f *fakeUserRepo) RevokeRoleRepo(userID uint64, role string, keepLast bool) (bool, bool, error) {
+	var roleUsers int
+	for _, userRoles := range f.roles {
+		for _, userRole := range userRoles {
+			if userRole == role {
+				roleUsers++
+			}
+		}
+	}
+	keptRoles := make([]string, 0)
+	for _, userRole := range f.roles[userID] {
+		if userRole != role {
+			keptRoles = append(keptRoles, userRole)
+		}
+	}
+	if len(keptRoles) == len(f.roles[userID]) {
+		return false, false, nil
+	}
+	if keepLast && roleUsers <= 1 {
+		return false, true, nil
+	}
+	f.roles[userID] = keptRoles
+	return true, false, nil
+}
+
+func (f *fakeUserRepo) GetEmailStatusRepo(userID uint64) (*userrepo.EmailStatus, error) {
+	email, ok := f.emails[userID]
+	if !ok {
+		return nil, nil
+	}
+	return &userrepo.EmailStatus{Email: email}, nil
+}
+
+func (f *fakeUserRepo) FindUserByEmailRepo(email string) (*auth.User, error) {
+	for userID, verifiedEmail := range f.emails {
+		if verifiedEmail != "" && verifiedEmail == email {
+			return &auth.User{ID: userID}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeUserRepo) FindUserByUsername(username string) (*auth.User, error) {
+	return f.users[username], nil
+}
+
+func (f *fakeUserRepo) FindUserByIdentityRepo(provider, subject string) (*auth.User, error) {
+	userID, ok := f.identities[provider+"/"+subject]
+	if !ok {
+		return nil, nil
+	}
+	return &auth.User{ID: userID}, nil
+}
+
+func (f *fakeUserRepo) LinkIdentityRepo(userID uint64, provider, subject, email string) (bool, error) {
+	if _, ok := f.identities[provider+"/"+subject]; ok {
+		return false, nil
+	}
+	f.identities[provider+"/"+subject] = userID
+	return true, nil
+}
+
+func (f *fakeUserRepo) RegisterExternalUserRepo(username, email, provider, subject string) (*auth.User, error) {
+	userID := uint64(len(f.credentials) + 1)
+	f.addUser(userID, username, "")
+	if email != "" {
+		f.emails[userID] = email
+	}
+	f.identities[provider+"/"+subject] = userID
+	return &auth.User{ID: userID, Username: username}, nil
+}
+
+func (f *fakeUserRepo) RegisterRepo(username, password string) (*auth.User, error) {
+	newUser := &auth.User{ID: uint64(len(f.users) + 1), Username: username}
+	f.users[username] = newUser
+	f.credentials[newUser.ID] = &userrepo.UserCredentials{PasswordHash: password}
+	return newUser, nil
+}
+
+func (f *fakeUserRepo) CreateEmailVerificationTokenRepo(userID uint64, email, tokenHash string, ttl time.Duration) error {
+	f.pendingEmails[userID] = email
+	return nil
+}
+
+func (f *fakeUserRepo) CreatePasswordResetTokenRepo(userID uint64, tokenHash string, ttl time.Duration) error {
+	return nil
+}
+
+// fakeMailSender sends the letters to the channel.
+type fakeMailSender struct {
+	letters chan string
+}
+
+func (f *fakeMailSender) Send(to, subject, body string) error {
+	f.letters <- body
+	return nil
+}
+
+func newTestContext() context.Context {
+	return context.WithValue(context.Background(), interceptor.MyLoggerKey, zap.NewNop().Sugar())
+}
+
+func newTestServer(userRepo userrepo.UserRepo) *authserviceusecase.AuthGRPCServer {
+	return authserviceusecase.NewAuthGRPCServer(userRepo, nil, nil, time.Minute, nil, nil, nil)
+}
+
+func TestCriticRole(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	// the user 1 has a verified email, the email of the user 2 is not verified yet
+	userRepo.addUser(1, "critic", "")
+	userRepo.emails[1] = "critic@example.com"
+	userRepo.addUser(2, "former critic", "")
+	userRepo.emails[2] = ""
+	userRepo.roles[2] = []string{authpermission.CriticRole}
+	a := authserviceusecase.NewAuthGRPCServer(userRepo, newFakeSessionRepo(), nil, time.Minute, nil, nil, nil)
+
+	cases := []struct {
+		name           string
+		userID         uint64
+		grant          bool
+		expectedErr    error
+		expectedUserID uint64
+		expectedRoles  []string
+	}{
+		{name: "verified email", userID: 1, grant: true, expectedUserID: 1, expectedRoles: []string{authpermission.CriticRole}},
+		{name: "no verified email", userID: 2, grant: true, expectedErr: errorauth.ErrorEmailNotVerified,
+			expectedRoles: []string{authpermission.CriticRole}},
+		{name: "revoke without verified email", userID: 2, grant: false, expectedUserID: 2, expectedRoles: []string{}},
+		{name: "no such user", userID: 3, grant: true, expectedUserID: 0},
+	}
+	for _, testCase := range cases {
+		roleChange := &auth.RoleChange{UserID: testCase.userID, Role: authpermission.CriticRole}
+		var userRoles *auth.UserRoles
+		var err error
+		if testCase.grant {
+			userRoles, err = a.GrantRole(newTestContext(), roleChange)
+		} else {
+			userRoles, err = a.RevokeRole(newTestContext(), roleChange)
+		}
+		if err != testCase.expectedErr {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			continue
+		}
+		if userRoles.UserID != testCase.expectedUserID {
+			t.Errorf("%s: expected user id %d, got %d", testCase.name, testCase.expectedUserID, userRoles.UserID)
+		}
+		if strings.Join(userRepo.roles[testCase.userID], ",") != strings.Join(testCase.expectedRoles, ",") {
+			t.Errorf("%s: expected roles %v, got %v", testCase.name, testCase.expectedRoles, userRepo.roles[testCase.userID])
+		}
+	}
+}
+
+func TestLogin(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	passwordHash, err := authpassword.Hash("password", authpassword.DefaultParams)
+	if err != nil {
+		t.Fatalf("can not hash password: %s", err)
+	}
+	legacyHash := sha256.Sum256([]byte("old password"))
+	userRepo.addUser(1, "user", passwordHash)
+	userRepo.addUser(2, "legacy", hex.EncodeToString(legacyHash[:]))
+	userRepo.addUser(3, "expired", "")
+	userRepo.credentials[3].ResetRequired = true
+	userRepo.addUser(4, "external", "")
+	a := newTestServer(userRepo)
+
+	cases := []struct {
+		name           string
+		username       string
+		password       string
+		expectedErr    error
+		expectedUserID uint64
+	}{
+		{name: "right password", username: "user", password: "password", expectedUserID: 1},
+		{name: "wrong password", username: "user", password: "Password"},
+		{name: "unknown user", username: "nobody", password: "password"},
+		{name: "legacy password", username: "legacy", password: "old password", expectedUserID: 2},
+		// the login does not tell the expired password from the wrong one
+		{name: "expired password", username: "expired", password: "password"},
+		{name: "user without password", username: "external", password: ""},
+	}
+	for _, testCase := range cases {
+		loggedInUser, err := a.Login(newTestContext(), &auth.AuthData{Username: testCase.username, Password: testCase.password})
+		if err != testCase.expectedErr {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			continue
+		}
+		if loggedInUser.ID != testCase.expectedUserID {
+			t.Errorf("%s: expected user %d, got %d", testCase.name, testCase.expectedUserID, loggedInUser.ID)
+		}
+	}
+
+	// the legacy hash is upgraded on the login
+	if !strings.HasPrefix(userRepo.credentials[2].PasswordHash, "$argon2id$") {
+		t.Errorf("legacy hash is not upgraded: %s", userRepo.credentials[2].PasswordHash)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	passwordHash, err := authpassword.Hash("old password", authpassword.DefaultParams)
+	if err != nil {
+		t.Fatalf("can not hash password: %s", err)
+	}
+	userRepo.addUser(1, "user", passwordHash)
+	sessionRepo := newFakeSessionRepo()
+	t.Setenv("SECRET", "test secret")
+	a := authserviceusecase.NewAuthGRPCServer(userRepo, sessionRepo, nil, time.Minute, nil, nil, nil)
+	tokenPair := login(t, a)
+	otherTokenPair := login(t, a)
+
+	// the password is changed by another request after the old one has been checked
+	concurrentHash, err := authpassword.Hash("concurrent password", authpassword.DefaultParams)
+	if err != nil {
+		t.Fatalf("can not hash password: %s", err)
+	}
+	userRepo.beforeUpdate = func() {
+		userRepo.credentials[1].PasswordHash = concurrentHash
+	}
+	changed, err := a.ChangePassword(newTestContext(), &auth.PasswordChange{
+		Token:       tokenPair.AccessToken,
+		OldPassword: "old password",
+		NewPassword: "new password",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if changed.IsChanged {
+		t.Errorf("password is reported changed when the hash has been changed meanwhile")
+	}
+	if userRepo.credentials[1].PasswordHash != concurrentHash {
+		t.Errorf("concurrently changed password is overwritten")
+	}
+	if _, ok := sessionRepo.sessions[otherTokenPair.AccessToken]; !ok {
+		t.Errorf("other sessions are ended though the password is not changed")
+	}
+
+	userRepo.beforeUpdate = nil
+	changed, err = a.ChangePassword(newTestContext(), &auth.PasswordChange{
+		Token:       tokenPair.AccessToken,
+		OldPassword: "concurrent password",
+		NewPassword: "new password",
+	})
+	if err != nil || !changed.IsChanged {
+		t.Fatalf("expected password to be changed, got %t and error %v", changed.IsChanged, err)
+	}
+	if ok, _, _ := authpassword.Verify("new password", userRepo.credentials[1].PasswordHash, authpassword.DefaultParams); !ok {
+		t.Errorf("new password is not stored")
+	}
+	if _, ok := sessionRepo.sessions[otherTokenPair.AccessToken]; ok {
+		t.Errorf("other sessions are not ended")
+	}
+	if _, ok := sessionRepo.sessions[tokenPair.AccessToken]; !ok {
+		t.Errorf("session of the change is ended")
+	}
+}
+
+func TestRegisterEmailPending(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	mailSender := &fakeMailSender{letters: make(chan string)}
+	a := authserviceusecase.NewAuthGRPCServer(userRepo, nil, mailSender, time.Minute, nil,
+		&authserviceusecase.LetterSettings{TokenTTL: time.Hour}, nil)
+
+	newUser, err := a.Register(newTestContext(), &auth.AuthData{Username: "user", Password: "password", Email: " User@Example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if newUser.ID == 0 {
+		t.Fatalf("user is not registered")
+	}
+	// the email becomes the email of the user only after the verification
+	if email, ok := userRepo.emails[newUser.ID]; ok {
+		t.Errorf("unverified email %q is stored as the email of the user", email)
+	}
+	if userRepo.pendingEmails[newUser.ID] != "user@example.com" {
+		t.Errorf("expected pending email %q, got %q", "user@example.com", userRepo.pendingEmails[newUser.ID])
+	}
+	select {
+	case <-mailSender.letters:
+	case <-time.After(time.Second):
+		t.Errorf("verification letter is not sent")
+	}
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	userRepo.addUser(1, "user", "hash")
+	userRepo.emails[1] = "user@example.com"
+	userRepo.addUser(2, "expired", "")
+	userRepo.credentials[2].ResetRequired = true
+	userRepo.emails[2] = "expired@example.com"
+	mailSender := &fakeMailSender{letters: make(chan string)}
+	a := authserviceusecase.NewAuthGRPCServer(userRepo, nil, mailSender, time.Minute,
+		&authserviceusecase.LetterSettings{TokenTTL: time.Hour}, nil, nil)
+
+	cases := []struct {
+		name          string
+		email         string
+		expiredNotice bool
+	}{
+		{name: "password set", email: "user@example.com", expiredNotice: false},
+		// the expired password is told about only to the owner of the email
+		{name: "expired password", email: "expired@example.com", expiredNotice: true},
+	}
+	for _, testCase := range cases {
+		_, err := a.RequestPasswordReset(newTestContext(), &auth.PasswordResetRequest{Email: testCase.email})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		select {
+		case body := <-mailSender.letters:
+			if strings.Contains(body, "has expired") != testCase.expiredNotice {
+				t.Errorf("%s: expected expired password notice %t, got letter %q", testCase.name, testCase.expiredNotice, body)
+			}
+		case <-time.After(time.Second):
+			t.Errorf("%s: letter is not sent", testCase.name)
+		}
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	userRepo.emails[1] = "first@example.com"
+	userRepo.emails[2] = "second@example.com"
+	a := newTestServer(userRepo)
+
+	cases := []struct {
+		name          string
+		userID        uint64
+		email         string
+		expectedErr   error
+		expectedEmail string
+	}{
+		{name: "email of other user", userID: 1, email: "second@example.com", expectedErr: errorauth.ErrorEmailTaken},
+		{name: "email of other user in other case", userID: 1, email: " Second@Example.com", expectedErr: errorauth.ErrorEmailTaken},
+		{name: "own email", userID: 1, email: "FIRST@example.com", expectedEmail: "first@example.com"},
+	}
+	for _, testCase := range cases {
+		emailStatus, err := a.ChangeEmail(newTestContext(), &auth.EmailChange{UserID: testCase.userID, Email: testCase.email})
+		if err != testCase.expectedErr {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+			continue
+		}
+		if emailStatus.Email != testCase.expectedEmail || emailStatus.PendingEmail != "" {
+			t.Errorf("%s: expected email %q without pending one, got %+v", testCase.name, testCase.expectedEmail, emailStatus)
+		}
+	}
+	if userRepo.emails[1] != "first@example.com" || userRepo.emails[2] != "second@example.com" {
+		t.Errorf("emails are changed: %v", userRepo.emails)
+	}
+}
+
+func TestRevokeRoleLastAdmin(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	userRepo.addUser(1, "admin", "")
+	userRepo.addUser(2, "other admin", "")
+	userRepo.roles[1] = []string{authpermission.AdminRole}
+	userRepo.roles[2] = []string{authpermission.AdminRole}
+	sessionRepo := newFakeSessionRepo()
+	t.Setenv("SECRET", "test secret")
+	a := authserviceusecase.NewAuthGRPCServer(userRepo, sessionRepo, nil, time.Minute, nil, nil, nil)
+	tokenPair := login(t, a)
+
+	cases := []struct {
+		name          string
+		userID        uint64
+		role          string
+		expectedErr   error
+		expectedRoles []string
+	}{
+		{name: "unknown role", userID: 1, role: "owner", expectedErr: errorauth.ErrorUnknownRole,
+			expectedRoles: []string{authpermission.AdminRole}},
+		{name: "one of two admins", userID: 1, role: authpermission.AdminRole, expectedRoles: []string{}},
+		{name: "last admin", userID: 2, role: authpermission.AdminRole, expectedErr: errorauth.ErrorLastAdmin,
+			expectedRoles: []string{authpermission.AdminRole}},
+	}
+	for _, testCase := range cases {
+		userRoles, err := a.RevokeRole(newTestContext(), &auth.RoleChange{UserID: testCase.userID, Role: testCase.role})
+		if err != testCase.expectedErr {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+		if err == nil && userRoles.UserID != testCase.userID {
+			t.Errorf("%s: expected roles of user %d, got %+v", testCase.name, testCase.userID, userRoles)
+		}
+		if strings.Join(userRepo.roles[testCase.userID], ",") != strings.Join(testCase.expectedRoles, ",") {
+			t.Errorf("%s: expected roles %v, got %v", testCase.name, testCase.expectedRoles, userRepo.roles[testCase.userID])
+		}
+	}
+
+	// the sessions of the user who has lost the role are ended
+	if _, ok := sessionRepo.sessions[tokenPair.AccessToken]; ok {
+		t.Errorf("session of user 1 is left after the role is revoked")
+	}
+}
@@ -0,0 +1,231 @@
+package authserviceusecase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go.uber.org/zap"
+	errorauth "kinopoisk/service_auth/error"
+	"kinopoisk/service_auth/interceptor"
+	authoidc "kinopoisk/service_auth/oidc"
+	auth "kinopoisk/service_auth/proto"
+	sessionrepo "kinopoisk/service_auth/repo/redis"
+	"regexp"
+	"strings"
+)
+
+const (
+	oidcStateLength      = 32
+	oidcBindingLength    = 32
+	oidcNonceLength      = 16
+	maxUsernameLength    = 32
+	maxUsernameAttempts  = 20
+	defaultOIDCUsername  = "user"
+	usernameSuffixLength = 4
+)
+
+var notUsernameSymbols = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// StartOIDCLogin returns the page of the identity provider the user is sent to and the binding the browser
+// keeps until the callback. The state, the PKCE verifier, the nonce and the hash of the binding stay in redis.
+func (a *AuthGRPCServer) StartOIDCLogin(ctx context.Context, in *auth.OIDCLoginRequest) (*auth.OIDCLoginURL, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.OIDCLoginURL{}, errorauth.ErrorNoLogger
+	}
+	provider, ok := a.oidcProviders[in.Provider]
+	if !ok {
+		return &auth.OIDCLoginURL{}, nil
+	}
+	state, err := newRandomString(oidcStateLength)
+	if err != nil {
+		logger.Errorf("error in generating oidc state: %s", err)
+		return &auth.OIDCLoginURL{}, err
+	}
+	nonce, err := newRandomString(oidcNonceLength)
+	if err != nil {
+		logger.Errorf("error in generating oidc nonce: %s", err)
+		return &auth.OIDCLoginURL{}, err
+	}
+	binding, err := newRandomString(oidcBindingLength)
+	if err != nil {
+		logger.Errorf("error in generating oidc binding: %s", err)
+		return &auth.OIDCLoginURL{}, err
+	}
+	verifier, challenge, err := authoidc.NewPKCE()
+	if err != nil {
+		logger.Errorf("error in generating pkce verifier: %s", err)
+		return &auth.OIDCLoginURL{}, err
+	}
+	authURL, err := provider.AuthCodeURL(state, challenge, nonce)
+	if err != nil {
+		logger.Errorf("error in getting authorization url of provider %s: %s", in.Provider, err)
+		return &auth.OIDCLoginURL{}, err
+	}
+	a.mu.Lock()
+	err = a.SessionRepo.CreateOIDCStateRepo(state, &sessionrepo.OIDCState{
+		Provider:     in.Provider,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		LinkUserID:   in.LinkUserID,
+		BindingHash:  hashOIDCBinding(binding),
+	})
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in saving oidc state: %s", err)
+		return &auth.OIDCLoginURL{}, err
+	}
+	return &auth.OIDCLoginURL{URL: authURL, Binding: binding}, nil
+}
+
+// FinishOIDCLogin redeems the code of the callback and returns the user of the identity. An unknown identity
+// is linked to the user who started the login to link it, else to the user with the same email if the provider
+// is trusted with the emails, else a new user is registered. The user is empty if the state is unknown or expired
+// or the callback comes from another browser than the one the login is started in. The identity is linked only
+// if the callback is made in a session of the user who started the linking.
+func (a *AuthGRPCServer) FinishOIDCLogin(ctx context.Context, in *auth.OIDCCallback) (*auth.User, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.User{}, errorauth.ErrorNoLogger
+	}
+	a.mu.Lock()
+	oidcState, err := a.SessionRepo.PopOIDCStateRepo(in.State)
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in getting oidc state: %s", err)
+		return &auth.User{}, err
+	}
+	if oidcState == nil || oidcState.Provider != in.Provider {
+		return &auth.User{}, nil
+	}
+	if oidcState.BindingHash != hashOIDCBinding(in.Binding) {
+		logger.Infof("oidc login with provider %s is finished in another browser", in.Provider)
+		return &auth.User{}, nil
+	}
+	if oidcState.LinkUserID != 0 && oidcState.LinkUserID != in.CallerUserID {
+		logger.Infof("linking of %s identity to user %d is finished by user %d", in.Provider, oidcState.LinkUserID, in.CallerUserID)
+		return &auth.User{}, errorauth.ErrorNotLinkingUser
+	}
+	provider, ok := a.oidcProviders[in.Provider]
+	if !ok {
+		return &auth.User{}, nil
+	}
+	identity, err := provider.Exchange(in.Code, oidcState.CodeVerifier, oidcState.Nonce)
+	if err != nil {
+		logger.Errorf("error in oidc login with provider %s: %s", in.Provider, err)
+		return &auth.User{}, errorauth.ErrorOIDCLoginFailed
+	}
+	if oidcState.LinkUserID != 0 {
+		return a.linkIdentity(logger, oidcState.LinkUserID, in.Provider, identity)
+	}
+	a.mu.RLock()
+	identityUser, err := a.UserRepo.FindUserByIdentityRepo(in.Provider, identity.Subject)
+	a.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting user by identity: %s", err)
+		return &auth.User{}, err
+	}
+	if identityUser != nil {
+		return identityUser, nil
+	}
+	email := ""
+	if identity.EmailVerified {
+		email = normalizeEmail(identity.Email)
+	}
+	if email != "" {
+		a.mu.RLock()
+		userWithEmail, err := a.UserRepo.FindUserByEmailRepo(email)
+		a.mu.RUnlock()
+		if err != nil {
+			logger.Errorf("error in getting user by email: %s", err)
+			return &auth.User{}, err
+		}
+		if userWithEmail != nil && provider.Config.LinkByEmail {
+			return a.linkIdentity(logger, userWithEmail.ID, in.Provider, identity)
+		}
+		if userWithEmail != nil {
+			// the new user does not get the email, the owner of it can link the identity from the account
+			logger.Infof("email of %s identity %s is used by user %d", in.Provider, identity.Subject, userWithEmail.ID)
+			email = ""
+		}
+	}
+	username, err := a.freeUsername(identity)
+	if err != nil {
+		logger.Errorf("error in choosing username for %s identity %s: %s", in.Provider, identity.Subject, err)
+		return &auth.User{}, err
+	}
+	a.mu.Lock()
+	newUser, err := a.UserRepo.RegisterExternalUserRepo(username, email, in.Provider, identity.Subject)
+	a.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in registering user of %s identity %s: %s", in.Provider, identity.Subject, err)
+		return &auth.User{}, err
+	}
+	logger.Infof("user %d is registered with %s identity", newUser.ID, in.Provider)
+	return newUser, nil
+}
+
+// linkIdentity links the identity to the user, it fails if the identity is linked to another user.
+func (a *AuthGRPCServer) linkIdentity(logger *zap.SugaredLogger, userID uint64, provider string,
+	identity *authoidc.Identity) (*auth.User, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	_, err := a.UserRepo.LinkIdentityRepo(userID, provider, identity.Subject, normalizeEmail(identity.Email))
+	if err != nil {
+		logger.Errorf("error in linking %s identity to user %d: %s", provider, userID, err)
+		return &auth.User{}, err
+	}
+	identityUser, err := a.UserRepo.FindUserByIdentityRepo(provider, identity.Subject)
+	if err != nil {
+		logger.Errorf("error in getting user by identity: %s", err)
+		return &auth.User{}, err
+	}
+	if identityUser == nil || identityUser.ID != userID {
+		logger.Infof("%s identity %s is linked to another user than %d", provider, identity.Subject, userID)
+		return &auth.User{}, errorauth.ErrorIdentityLinked
+	}
+	return identityUser, nil
+}
+
+// hashOIDCBinding keeps the binding itself only in the browser.
+func hashOIDCBinding(binding string) string {
+	bindingHash := sha256.Sum256([]byte(binding))
+	return hex.EncodeToString(bindingHash[:])
+}
+
+// freeUsername makes the username from the name the provider knows the user by. If it is taken, a number
+// is added, then a random suffix.
+func (a *AuthGRPCServer) freeUsername(identity *authoidc.Identity) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base = strings.Trim(notUsernameSymbols.ReplaceAllString(base, "_"), "_")
+	if len(base) > maxUsernameLength {
+		base = base[:maxUsernameLength]
+	}
+	if base == "" {
+		base = defaultOIDCUsername
+	}
+	for attempt := 1; attempt <= maxUsernameAttempts; attempt++ {
+		username := base
+		if attempt > 1 {
+			username = fmt.Sprintf("%s_%d", base, attempt)
+		}
+		a.mu.RLock()
+		foundUser, err := a.UserRepo.FindUserByUsername(username)
+		a.mu.RUnlock()
+		if err != nil {
+			return "", err
+		}
+		if foundUser == nil {
+			return username, nil
+		}
+	}
+	suffix, err := newRandomString(usernameSuffixLength)
+	if err != nil {
+		return "", err
+	}
+	return base + "_" + notUsernameSymbols.ReplaceAllString(suffix, "_"), nil
+}
@@ -0,0 +1,252 @@
+package authserviceusecase_test
+
+import (
+	errorauth "kinopoisk/service_auth/error"
+	authoidc "kinopoisk/service_auth/oidc"
+	"kinopoisk/service_auth/oidcmock"
+	auth "kinopoisk/service_auth/proto"
+	authserviceusecase "kinopoisk/service_auth/usecase"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// newOIDCTestServer serves the mock provider on a local port, the server logs in through it as "mock".
+func newOIDCTestServer(t *testing.T, userRepo *fakeUserRepo, sessionRepo *fakeSessionRepo,
+	linkByEmail bool) (*authserviceusecase.AuthGRPCServer, *oidcmock.Provider) {
+	server := httptest.NewUnstartedServer(nil)
+	mockProvider, err := oidcmock.NewProvider("http://"+server.Listener.Addr().String(), "kinopoisk", "mock-secret")
+	if err != nil {
+		t.Fatalf("can not create mock provider: %s", err)
+	}
+	server.Config.Handler = mockProvider
+	server.Start()
+	t.Cleanup(server.Close)
+
+	provider := authoidc.NewProvider(authoidc.ProviderConfig{
+		Name:         "mock",
+		Issuer:       server.URL,
+		ClientID:     "kinopoisk",
+		ClientSecret: "mock-secret",
+		RedirectURL:  "http://localhost/oidc/mock/callback",
+		Scopes:       []string{"openid", "email", "profile"},
+		LinkByEmail:  linkByEmail,
+	}, server.Client())
+	return authserviceusecase.NewAuthGRPCServer(userRepo, sessionRepo, nil, time.Minute, nil, nil,
+		map[string]*authoidc.Provider{"mock": provider}), mockProvider
+}
+
+// startOIDCLogin signs in at the mock provider as the subject and returns the callback the browser
+// of the user who started the login makes.
+func startOIDCLogin(t *testing.T, a *authserviceusecase.AuthGRPCServer, linkUserID uint64, subject string) *auth.OIDCCallback {
+	loginURL, err := a.StartOIDCLogin(newTestContext(), &auth.OIDCLoginRequest{Provider: "mock", LinkUserID: linkUserID})
+	if err != nil {
+		t.Fatalf("can not start login: %s", err)
+	}
+	if loginURL.URL == "" || loginURL.Binding == "" {
+		t.Fatalf("expected login url and binding, got %+v", loginURL)
+	}
+	authURL, err := url.Parse(loginURL.URL)
+	if err != nil {
+		t.Fatalf("bad login url %s: %s", loginURL.URL, err)
+	}
+	query := authURL.Query()
+	query.Set("login_hint", subject)
+	authURL.RawQuery = query.Encode()
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Get(authURL.String())
+	if err != nil {
+		t.Fatalf("can not sign in at mock provider: %s", err)
+	}
+	resp.Body.Close()
+	location, err := resp.Location()
+	if err != nil {
+		t.Fatalf("mock provider does not redirect back: %s", err)
+	}
+	if location.Query().Get("code") == "" {
+		t.Fatalf("no code in the redirect %s", location)
+	}
+	return &auth.OIDCCallback{
+		Provider:     "mock",
+		Code:         location.Query().Get("code"),
+		State:        location.Query().Get("state"),
+		Binding:      loginURL.Binding,
+		CallerUserID: linkUserID,
+	}
+}
+
+func TestFinishOIDCLoginRegistersUser(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	userRepo.addUser(1, "alice", "")
+	a, _ := newOIDCTestServer(t, userRepo, newFakeSessionRepo(), false)
+
+	newUser, err := a.FinishOIDCLogin(newTestContext(), startOIDCLogin(t, a, 0, "alice"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the name from the provider is taken, the number is added
+	if newUser.ID != 2 || newUser.Username != "alice_2" {
+		t.Errorf("expected new user 2 alice_2, got %+v", newUser)
+	}
+	if userRepo.emails[2] != "alice@oidc-mock.local" {
+		t.Errorf("verified email of the provider is not given to the new user: %q", userRepo.emails[2])
+	}
+
+	// the next login with the identity finds the user
+	loggedInUser, err := a.FinishOIDCLogin(newTestContext(), startOIDCLogin(t, a, 0, "alice"))
+	if err != nil || loggedInUser.ID != 2 {
+		t.Errorf("expected user 2, got %+v and error %v", loggedInUser, err)
+	}
+	if len(userRepo.credentials) != 2 {
+		t.Errorf("user is registered again")
+	}
+}
+
+func TestFinishOIDCLoginBadState(t *testing.T) {
+	sessionRepo := newFakeSessionRepo()
+	a, _ := newOIDCTestServer(t, newFakeUserRepo(), sessionRepo, false)
+
+	callback := startOIDCLogin(t, a, 0, "alice")
+	loggedInUser, err := a.FinishOIDCLogin(newTestContext(), callback)
+	if err != nil || loggedInUser.ID == 0 {
+		t.Fatalf("expected user, got %+v and error %v", loggedInUser, err)
+	}
+	// the state is used once
+	replayed, err := a.FinishOIDCLogin(newTestContext(), callback)
+	if err != nil || replayed.ID != 0 {
+		t.Errorf("expected empty user for the used state, got %+v and error %v", replayed, err)
+	}
+
+	cases := []struct {
+		name   string
+		change func(callback *auth.OIDCCallback)
+	}{
+		{name: "another browser", change: func(callback *auth.OIDCCallback) { callback.Binding = "other binding" }},
+		{name: "no binding", change: func(callback *auth.OIDCCallback) { callback.Binding = "" }},
+		{name: "another provider", change: func(callback *auth.OIDCCallback) { callback.Provider = "other" }},
+		{name: "unknown state", change: func(callback *auth.OIDCCallback) { callback.State = "unknown" }},
+	}
+	for _, testCase := range cases {
+		callback := startOIDCLogin(t, a, 0, "bob")
+		testCase.change(callback)
+		loggedInUser, err := a.FinishOIDCLogin(newTestContext(), callback)
+		if err != nil || loggedInUser.ID != 0 {
+			t.Errorf("%s: expected empty user, got %+v and error %v", testCase.name, loggedInUser, err)
+		}
+	}
+	if _, err := a.FinishOIDCLogin(newTestContext(), startOIDCLogin(t, a, 0, "bob")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestFinishOIDCLoginRefusedTokens(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	sessionRepo := newFakeSessionRepo()
+	a, mockProvider := newOIDCTestServer(t, userRepo, sessionRepo, false)
+
+	// the code is redeemed with a verifier of another login
+	callback := startOIDCLogin(t, a, 0, "alice")
+	otherVerifier, _, err := authoidc.NewPKCE()
+	if err != nil {
+		t.Fatalf("can not create verifier: %s", err)
+	}
+	sessionRepo.oidcStates[callback.State].CodeVerifier = otherVerifier
+	loggedInUser, err := a.FinishOIDCLogin(newTestContext(), callback)
+	if err != errorauth.ErrorOIDCLoginFailed || loggedInUser.ID != 0 {
+		t.Errorf("pkce verifier mismatch: expected error %s, got %+v and error %v", errorauth.ErrorOIDCLoginFailed, loggedInUser, err)
+	}
+
+	cases := []struct {
+		name   string
+		claims map[string]interface{}
+	}{
+		{name: "nonce of another login", claims: map[string]interface{}{"nonce": "other nonce"}},
+		{name: "token for another client", claims: map[string]interface{}{"aud": "other-client"}},
+		{name: "token of another issuer", claims: map[string]interface{}{"iss": "http://other-issuer"}},
+		{name: "expired token", claims: map[string]interface{}{"exp": time.Now().Add(-time.Minute).Unix()}},
+	}
+	for _, testCase := range cases {
+		mockProvider.OverrideClaims(testCase.claims)
+		loggedInUser, err := a.FinishOIDCLogin(newTestContext(), startOIDCLogin(t, a, 0, "alice"))
+		if err != errorauth.ErrorOIDCLoginFailed || loggedInUser.ID != 0 {
+			t.Errorf("%s: expected error %s, got %+v and error %v", testCase.name, errorauth.ErrorOIDCLoginFailed, loggedInUser, err)
+		}
+	}
+	if len(userRepo.credentials) != 0 || len(userRepo.identities) != 0 {
+		t.Errorf("user is registered with the refused token")
+	}
+}
+
+func TestFinishOIDCLoginLinkByEmail(t *testing.T) {
+	cases := []struct {
+		name           string
+		linkByEmail    bool
+		expectedUserID uint64
+		expectedEmail  string
+	}{
+		{name: "link by email", linkByEmail: true, expectedUserID: 1},
+		// the email stays with its owner, the new user gets none
+		{name: "no link by email", linkByEmail: false, expectedUserID: 2, expectedEmail: ""},
+	}
+	for _, testCase := range cases {
+		userRepo := newFakeUserRepo()
+		userRepo.addUser(1, "bob", "")
+		userRepo.emails[1] = "alice@oidc-mock.local"
+		a, _ := newOIDCTestServer(t, userRepo, newFakeSessionRepo(), testCase.linkByEmail)
+
+		loggedInUser, err := a.FinishOIDCLogin(newTestContext(), startOIDCLogin(t, a, 0, "alice"))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		if loggedInUser.ID != testCase.expectedUserID || userRepo.identities["mock/alice"] != testCase.expectedUserID {
+			t.Errorf("%s: expected identity of user %d, got user %+v and identities %v", testCase.name,
+				testCase.expectedUserID, loggedInUser, userRepo.identities)
+		}
+		if testCase.expectedUserID != 1 && userRepo.emails[testCase.expectedUserID] != testCase.expectedEmail {
+			t.Errorf("%s: expected email %q of the new user, got %q", testCase.name, testCase.expectedEmail,
+				userRepo.emails[testCase.expectedUserID])
+		}
+	}
+}
+
+func TestFinishOIDCLoginLinkIdentity(t *testing.T) {
+	userRepo := newFakeUserRepo()
+	userRepo.addUser(1, "bob", "")
+	userRepo.addUser(2, "carol", "")
+	userRepo.identities["mock/alice"] = 1
+	a, _ := newOIDCTestServer(t, userRepo, newFakeSessionRepo(), false)
+
+	// the identity of another user is not moved
+	linkedUser, err := a.FinishOIDCLogin(newTestContext(), startOIDCLogin(t, a, 2, "alice"))
+	if err != errorauth.ErrorIdentityLinked || linkedUser.ID != 0 {
+		t.Errorf("expected error %s, got %+v and error %v", errorauth.ErrorIdentityLinked, linkedUser, err)
+	}
+	if userRepo.identities["mock/alice"] != 1 {
+		t.Errorf("identity of user 1 is linked to user %d", userRepo.identities["mock/alice"])
+	}
+
+	// the callback of the linking started by the user 2 is not made in the session of the user 2
+	for _, callerUserID := range []uint64{0, 1} {
+		callback := startOIDCLogin(t, a, 2, "dave")
+		callback.CallerUserID = callerUserID
+		linkedUser, err = a.FinishOIDCLogin(newTestContext(), callback)
+		if err != errorauth.ErrorNotLinkingUser || linkedUser.ID != 0 {
+			t.Errorf("caller %d: expected error %s, got %+v and error %v", callerUserID, errorauth.ErrorNotLinkingUser, linkedUser, err)
+		}
+	}
+	if _, ok := userRepo.identities["mock/dave"]; ok {
+		t.Errorf("identity is linked by another caller")
+	}
+
+	linkedUser, err = a.FinishOIDCLogin(newTestContext(), startOIDCLogin(t, a, 2, "dave"))
+	if err != nil || linkedUser.ID != 2 || userRepo.identities["mock/dave"] != 2 {
+		t.Errorf("expected identity to be linked to user 2, got %+v and error %v", linkedUser, err)
+	}
+}
@@ -0,0 +1,167 @@
+package authserviceusecase
+
+import (
+	"context"
+	"go.uber.org/zap"
+	errorauth "kinopoisk/service_auth/error"
+	"kinopoisk/service_auth/interceptor"
+	authpermission "kinopoisk/service_auth/permission"
+	auth "kinopoisk/service_auth/proto"
+)
+
+// userWithRoles returns the user with the current roles and permissions, it is what the tokens are issued for.
+func (a *AuthGRPCServer) userWithRoles(user *auth.User) (*auth.User, error) {
+	a.mu.RLock()
+	roles, permissions, err := a.UserRepo.GetUserRolesRepo(user.GetID())
+	a.mu.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	return &auth.User{
+		ID:          user.GetID(),
+		Username:    user.GetUsername(),
+		Roles:       roles,
+		Permissions: permissions,
+	}, nil
+}
+
+func (a *AuthGRPCServer) GetUserRoles(ctx context.Context, in *auth.User) (*auth.UserRoles, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.UserRoles{}, errorauth.ErrorNoLogger
+	}
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.getUserRoles(logger, in.ID)
+}
+
+// GrantRole gives the role to the user, the user gets its permissions with the next tokens, on login or refresh.
+// The reviews the user writes with the critic role are critic reviews at once, the reviews written before stay as they are.
+func (a *AuthGRPCServer) GrantRole(ctx context.Context, in *auth.RoleChange) (*auth.UserRoles, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.UserRoles{}, errorauth.ErrorNoLogger
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	userRoles, err := a.checkRoleChange(logger, in)
+	if err != nil || userRoles.UserID == 0 {
+		return userRoles, err
+	}
+	// only a user with a verified email can become a critic, revoking the role needs no email
+	if in.Role == authpermission.CriticRole {
+		emailStatus, err := a.UserRepo.GetEmailStatusRepo(in.UserID)
+		if err != nil {
+			logger.Errorf("error in getting email of user %d: %s", in.UserID, err)
+			return &auth.UserRoles{}, err
+		}
+		if emailStatus == nil || emailStatus.Email == "" {
+			return &auth.UserRoles{}, errorauth.ErrorEmailNotVerified
+		}
+	}
+	grantedBy := authpermission.UserFromContext(ctx).GetID()
+	granted, err := a.UserRepo.GrantRoleRepo(in.UserID, in.Role, grantedBy)
+	if err != nil {
+		logger.Errorf("error in granting role %s to user %d: %s", in.Role, in.UserID, err)
+		return &auth.UserRoles{}, err
+	}
+	if granted {
+		logger.Infof("role %s is granted to user %d by user %d", in.Role, in.UserID, grantedBy)
+	}
+	return a.getUserRoles(logger, in.UserID)
+}
+
+// RevokeRole takes the role from the user and ends all the sessions of the user, so the permissions
+// of the role stop working at once.
+func (a *AuthGRPCServer) RevokeRole(ctx context.Context, in *auth.RoleChange) (*auth.UserRoles, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &auth.UserRoles{}, errorauth.ErrorNoLogger
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	userRoles, err := a.checkRoleChange(logger, in)
+	if err != nil || userRoles.UserID == 0 {
+		return userRoles, err
+	}
+	// the last admin is kept by the repo, the other replicas revoke the roles too
+	revoked, isLastAdmin, err := a.UserRepo.RevokeRoleRepo(in.UserID, in.Role, in.Role == authpermission.AdminRole)
+	if err != nil {
+		logger.Errorf("error in revoking role %s of user %d: %s", in.Role, in.UserID, err)
+		return &auth.UserRoles{}, err
+	}
+	if isLastAdmin {
+		return &auth.UserRoles{}, errorauth.ErrorLastAdmin
+	}
+	if revoked {
+		endedSessions, err := a.revokeUserSessions(in.UserID, "")
+		if err != nil {
+			logger.Errorf("error in revoking sessions of user %d: %s", in.UserID, err)
+			return &auth.UserRoles{}, err
+		}
+		logger.Infof("role %s of user %d is revoked by user %d, %d sessions are ended", in.Role, in.UserID,
+			authpermission.UserFromContext(ctx).GetID(), endedSessions)
+	}
+	return a.getUserRoles(logger, in.UserID)
+}
+
+// GrantInitialAdmins makes the users admins on start, so the first admin can grant the roles to the others.
+func (a *AuthGRPCServer) GrantInitialAdmins(logger *zap.SugaredLogger, userIDs []uint64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for _, userID := range userIDs {
+		credentials, err := a.UserRepo.GetUserCredentialsByIDRepo(userID)
+		if err != nil {
+			logger.Errorf("error in getting user %d: %s", userID, err)
+			continue
+		}
+		if credentials == nil {
+			logger.Warnf("user %d from ADMIN_IDS does not exist", userID)
+			continue
+		}
+		granted, err := a.UserRepo.GrantRoleRepo(userID, authpermission.AdminRole, 0)
+		if err != nil {
+			logger.Errorf("error in granting admin role to user %d: %s", userID, err)
+			continue
+		}
+		if granted {
+			logger.Infof("user %d from ADMIN_IDS is made admin", userID)
+		}
+	}
+}
+
+// checkRoleChange returns the current roles of the user, they are empty if there is no such user.
+// It must be called with the lock held.
+func (a *AuthGRPCServer) checkRoleChange(logger *zap.SugaredLogger, in *auth.RoleChange) (*auth.UserRoles, error) {
+	roleExists, err := a.UserRepo.RoleExistsRepo(in.Role)
+	if err != nil {
+		logger.Errorf("error in checking role %s: %s", in.Role, err)
+		return &auth.UserRoles{}, err
+	}
+	if !roleExists {
+		return &auth.UserRoles{}, errorauth.ErrorUnknownRole
+	}
+	return a.getUserRoles(logger, in.UserID)
+}
+
+// getUserRoles must be called with the lock held.
+func (a *AuthGRPCServer) getUserRoles(logger *zap.SugaredLogger, userID uint64) (*auth.UserRoles, error) {
+	credentials, err := a.UserRepo.GetUserCredentialsByIDRepo(userID)
+	if err != nil {
+		logger.Errorf("error in getting user %d: %s", userID, err)
+		return &auth.UserRoles{}, err
+	}
+	if credentials == nil {
+		return &auth.UserRoles{}, nil
+	}
+	roles, permissions, err := a.UserRepo.GetUserRolesRepo(userID)
+	if err != nil {
+		logger.Errorf("error in getting roles of user %d: %s", userID, err)
+		return &auth.UserRoles{}, err
+	}
+	return &auth.UserRoles{
+		UserID:      userID,
+		Roles:       roles,
+		Permissions: permissions,
+	}, nil
+}
@@ -0,0 +1,403 @@
+package authserviceusecase_test
+
+import (
+	"github.com/gomodule/redigo/redis"
+	errorauth "kinopoisk/service_auth/error"
+	auth "kinopoisk/service_auth/proto"
+	sessionrepo "kinopoisk/service_auth/repo/redis"
+	authserviceusecase "kinopoisk/service_auth/usecase"
+	"testing"
+	"time"
+)
+
+const testRefreshTokenTTL = time.Hour
+
+type fakeRefreshToken struct {
+	session   *sessionrepo.RefreshSession
+	expiresAt time.Time
+	used      bool
+}
+
+// fakeSessionRepo keeps the sessions in memory, the refresh tokens expire by the clock of the test.
+type fakeSessionRepo struct {
+	sessionrepo.SessionRepo
+	now           time.Time
+	sessions      map[string]*auth.Session
+	refreshTokens map[string]*fakeRefreshToken
+	sessionInfos  map[string]*sessionrepo.SessionInfo
+	oidcStates    map[string]*sessionrepo.OIDCState
+}
+
+func newFakeSessionRepo() *fakeSessionRepo {
+	return &fakeSessionRepo{
+		now:           time.Now(),
+		sessions:      make(map[string]*auth.Session),
+		refreshTokens: make(map[string]*fakeRefreshToken),
+		sessionInfos:  make(map[string]*sessionrepo.SessionInfo),
+		oidcStates:    make(map[string]*sessionrepo.OIDCState),
+	}
+}
+
+func (f *fakeSessionRepo) CreateSessionRepo(session *auth.Session) error {
+	f.sessions[session.ID] = session
+	return nil
+}
+
+func (f *fakeSessionRepo) GetSessionRepo(token string) (*auth.Session, error) {
+	session, ok := f.sessions[token]
+	if !ok {
+		return nil, redis.ErrNil
+	}
+	return session, nil
+}
+
+func (f *fakeSessionRepo) CreateRefreshTokenRepo(token string, refreshSession *sessionrepo.RefreshSession) error {
+	f.refreshTokens[token] = &fakeRefreshToken{
+		session:   refreshSession,
+		expiresAt: f.now.Add(testRefreshTokenTTL),
+	}
+	return nil
+}
+
+func (f *fakeSessionRepo) UseRefreshTokenRepo(token string) (*sessionrepo.RefreshSession, bool, error) {
+	refreshToken, ok := f.refreshTokens[token]
+	if !ok || !f.now.Before(refreshToken.expiresAt) {
+		return nil, false, nil
+	}
+	isReused := refreshToken.used
+	refreshToken.used = true
+	return refreshToken.session, isReused, nil
+}
+
+func (f *fakeSessionRepo) RevokeFamilyRepo(familyID string) error {
+	for token, session := range f.sessions {
+		if session.FamilyID == familyID {
+			delete(f.sessions, token)
+		}
+	}
+	for token, refreshToken := range f.refreshTokens {
+		if refreshToken.session.FamilyID == familyID {
+			delete(f.refreshTokens, token)
+		}
+	}
+	delete(f.sessionInfos, familyID)
+	return nil
+}
+
+func (f *fakeSessionRepo) CreateSessionInfoRepo(sessionInfo *sessionrepo.SessionInfo) error {
+	f.sessionInfos[sessionInfo.ID] = sessionInfo
+	return nil
+}
+
+func (f *fakeSessionRepo) UpdateSessionClientRepo(familyID string, client *auth.ClientInfo, lastSeenAt time.Time) error {
+	sessionInfo, ok := f.sessionInfos[familyID]
+	if !ok {
+		return nil
+	}
+	sessionInfo.Device = client.GetDevice()
+	sessionInfo.LastSeenAt = lastSeenAt.Unix()
+	return nil
+}
+
+func (f *fakeSessionRepo) GetUserSessionsRepo(userID uint64) ([]*sessionrepo.SessionInfo, error) {
+	sessionInfos := make([]*sessionrepo.SessionInfo, 0)
+	for _, sessionInfo := range f.sessionInfos {
+		if sessionInfo.UserID == userID {
+			sessionInfos = append(sessionInfos, sessionInfo)
+		}
+	}
+	return sessionInfos, nil
+}
+
+func (f *fakeSessionRepo) HasUserSessionRepo(userID uint64, familyID string) (bool, error) {
+	sessionInfo, ok := f.sessionInfos[familyID]
+	return ok && sessionInfo.UserID == userID, nil
+}
+
+func (f *fakeSessionRepo) TouchSessionInfoRepo(familyID string, lastSeenAt time.Time) error {
+	return nil
+}
+
+func (f *fakeSessionRepo) CreateOIDCStateRepo(state string, oidcState *sessionrepo.OIDCState) error {
+	f.oidcStates[state] = oidcState
+	return nil
+}
+
+func (f *fakeSessionRepo) PopOIDCStateRepo(state string) (*sessionrepo.OIDCState, error) {
+	oidcState, ok := f.oidcStates[state]
+	if !ok {
+		return nil, nil
+	}
+	delete(f.oidcStates, state)
+	return oidcState, nil
+}
+
+func newSessionTestServer(t *testing.T, sessionRepo sessionrepo.SessionRepo, accessTokenTTL time.Duration) *authserviceusecase.AuthGRPCServer {
+	t.Setenv("SECRET", "test secret")
+	return authserviceusecase.NewAuthGRPCServer(newFakeUserRepo(), sessionRepo, nil, accessTokenTTL, nil, nil, nil)
+}
+
+func login(t *testing.T, a *authserviceusecase.AuthGRPCServer) *auth.TokenPair {
+	return loginAs(t, a, &auth.User{ID: 1, Username: "user"})
+}
+
+func loginAs(t *testing.T, a *authserviceusecase.AuthGRPCServer, user *auth.User) *auth.TokenPair {
+	tokenPair, err := a.CreateTokenPair(newTestContext(), &auth.NewSession{
+		User:   user,
+		Client: &auth.ClientInfo{Device: "phone"},
+	})
+	if err != nil {
+		t.Fatalf("can not create token pair: %s", err)
+	}
+	return tokenPair
+}
+
+func TestRefreshTokensRotation(t *testing.T) {
+	sessionRepo := newFakeSessionRepo()
+	a := newSessionTestServer(t, sessionRepo, time.Minute)
+	tokenPair := login(t, a)
+
+	refreshed, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{
+		RefreshToken: tokenPair.RefreshToken,
+		Client:       &auth.ClientInfo{Device: "laptop"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if refreshed.AccessToken == "" || refreshed.RefreshToken == "" || refreshed.ExpiresIn != 60 {
+		t.Fatalf("expected new token pair, got %+v", refreshed)
+	}
+	if refreshed.RefreshToken == tokenPair.RefreshToken || refreshed.AccessToken == tokenPair.AccessToken {
+		t.Errorf("tokens are not rotated")
+	}
+	newSession := sessionRepo.sessions[refreshed.AccessToken]
+	oldSession := sessionRepo.sessions[tokenPair.AccessToken]
+	if newSession == nil || oldSession == nil || newSession.FamilyID != oldSession.FamilyID {
+		t.Errorf("new tokens are not in the family of the login")
+	}
+	if sessionInfo := sessionRepo.sessionInfos[newSession.GetFamilyID()]; sessionInfo == nil || sessionInfo.Device != "laptop" {
+		t.Errorf("client of the refresh is not recorded in the session")
+	}
+
+	// the new refresh token is used once too
+	refreshedAgain, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: refreshed.RefreshToken})
+	if err != nil || refreshedAgain.RefreshToken == "" {
+		t.Errorf("expected rotated refresh token to work, got %+v and error %v", refreshedAgain, err)
+	}
+}
+
+func TestRefreshTokensReplay(t *testing.T) {
+	sessionRepo := newFakeSessionRepo()
+	a := newSessionTestServer(t, sessionRepo, time.Minute)
+	tokenPair := login(t, a)
+	otherTokenPair := login(t, a)
+
+	refreshed, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: tokenPair.RefreshToken})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// the stolen refresh token is replayed after the user has rotated it
+	replayed, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: tokenPair.RefreshToken})
+	if err != errorauth.ErrorRefreshTokenReused {
+		t.Fatalf("expected error %s, got %v", errorauth.ErrorRefreshTokenReused, err)
+	}
+	if replayed.AccessToken != "" || replayed.RefreshToken != "" {
+		t.Errorf("tokens are issued for the replayed refresh token")
+	}
+
+	// the whole family is revoked, the tokens of the legitimate user stop working too
+	for _, accessToken := range []string{tokenPair.AccessToken, refreshed.AccessToken} {
+		if _, ok := sessionRepo.sessions[accessToken]; ok {
+			t.Errorf("access token of the revoked family is left")
+		}
+	}
+	afterRevoke, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: refreshed.RefreshToken})
+	if err != nil || afterRevoke.RefreshToken != "" {
+		t.Errorf("expected refresh token of the revoked family to be unknown, got %+v and error %v", afterRevoke, err)
+	}
+
+	// the other login of the user is not touched
+	if _, ok := sessionRepo.sessions[otherTokenPair.AccessToken]; !ok {
+		t.Errorf("access token of the other family is revoked")
+	}
+	otherRefreshed, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: otherTokenPair.RefreshToken})
+	if err != nil || otherRefreshed.RefreshToken == "" {
+		t.Errorf("expected refresh token of the other family to work, got %+v and error %v", otherRefreshed, err)
+	}
+}
+
+func TestRefreshTokensExpiry(t *testing.T) {
+	sessionRepo := newFakeSessionRepo()
+	a := newSessionTestServer(t, sessionRepo, time.Minute)
+	tokenPair := login(t, a)
+
+	sessionRepo.now = sessionRepo.now.Add(testRefreshTokenTTL)
+	expired, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: tokenPair.RefreshToken})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if expired.AccessToken != "" || expired.RefreshToken != "" {
+		t.Errorf("tokens are issued for the expired refresh token")
+	}
+
+	unknown, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: "unknown"})
+	if err != nil || unknown.RefreshToken != "" {
+		t.Errorf("expected unknown refresh token to give no tokens, got %+v and error %v", unknown, err)
+	}
+}
+
+func TestGetSessionExpiredAccessToken(t *testing.T) {
+	sessionRepo := newFakeSessionRepo()
+	// the access token is issued already expired
+	a := newSessionTestServer(t, sessionRepo, -time.Minute)
+	tokenPair := login(t, a)
+
+	session, err := a.GetSession(newTestContext(), &auth.Token{Token: tokenPair.AccessToken})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if session.GetUser() != nil {
+		t.Errorf("session is returned for the expired access token")
+	}
+
+	a = newSessionTestServer(t, sessionRepo, time.Minute)
+	tokenPair = login(t, a)
+	session, err = a.GetSession(newTestContext(), &auth.Token{Token: tokenPair.AccessToken})
+	if err != nil || session.GetUser().GetID() != 1 {
+		t.Errorf("expected session of user 1, got %+v and error %v", session, err)
+	}
+}
+
+// familyOf returns the session the access token belongs to.
+func familyOf(t *testing.T, sessionRepo *fakeSessionRepo, tokenPair *auth.TokenPair) string {
+	session, ok := sessionRepo.sessions[tokenPair.AccessToken]
+	if !ok {
+		t.Fatalf("no session of access token")
+	}
+	return session.FamilyID
+}
+
+func TestGetUserSessions(t *testing.T) {
+	sessionRepo := newFakeSessionRepo()
+	a := newSessionTestServer(t, sessionRepo, time.Minute)
+	oldTokenPair := login(t, a)
+	currentTokenPair := login(t, a)
+	recentTokenPair := login(t, a)
+	loginAs(t, a, &auth.User{ID: 2, Username: "other"})
+	// the current session is neither the most nor the least recently used one
+	lastSeenAt := map[string]int64{
+		familyOf(t, sessionRepo, oldTokenPair):     1000,
+		familyOf(t, sessionRepo, currentTokenPair): 2000,
+		familyOf(t, sessionRepo, recentTokenPair):  3000,
+	}
+	for familyID, seenAt := range lastSeenAt {
+		sessionRepo.sessionInfos[familyID].LastSeenAt = seenAt
+	}
+
+	userSessions, err := a.GetUserSessions(newTestContext(), &auth.Token{Token: currentTokenPair.AccessToken})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expectedIDs := []string{
+		familyOf(t, sessionRepo, recentTokenPair),
+		familyOf(t, sessionRepo, currentTokenPair),
+		familyOf(t, sessionRepo, oldTokenPair),
+	}
+	if len(userSessions.Sessions) != len(expectedIDs) {
+		t.Fatalf("expected %d sessions of the user, got %d", len(expectedIDs), len(userSessions.Sessions))
+	}
+	for i, userSession := range userSessions.Sessions {
+		if userSession.ID != expectedIDs[i] {
+			t.Errorf("expected session %s at %d, got %s", expectedIDs[i], i, userSession.ID)
+		}
+		if userSession.Current != (userSession.ID == familyOf(t, sessionRepo, currentTokenPair)) {
+			t.Errorf("session %s is marked current %t", userSession.ID, userSession.Current)
+		}
+		if userSession.Device != "phone" {
+			t.Errorf("expected device phone, got %q", userSession.Device)
+		}
+	}
+	if userSessions.Sessions[1].LastSeenAt != "1970-01-01 00:33:20" {
+		t.Errorf("expected last seen time 1970-01-01 00:33:20, got %s", userSessions.Sessions[1].LastSeenAt)
+	}
+}
+
+func TestDeleteUserSession(t *testing.T) {
+	sessionRepo := newFakeSessionRepo()
+	a := newSessionTestServer(t, sessionRepo, time.Minute)
+	tokenPair := login(t, a)
+	otherUserTokenPair := loginAs(t, a, &auth.User{ID: 2, Username: "other"})
+
+	cases := []struct {
+		name            string
+		userID          uint64
+		sessionID       string
+		expectedDeleted bool
+	}{
+		// the session of another user is treated as missing and is not ended
+		{name: "session of another user", userID: 1, sessionID: familyOf(t, sessionRepo, otherUserTokenPair),
+			expectedDeleted: false},
+		{name: "unknown session", userID: 1, sessionID: "unknown", expectedDeleted: false},
+		{name: "own session", userID: 1, sessionID: familyOf(t, sessionRepo, tokenPair), expectedDeleted: true},
+		{name: "deleted session", userID: 1, sessionID: familyOf(t, sessionRepo, tokenPair), expectedDeleted: false},
+	}
+	for _, testCase := range cases {
+		isDeleted, err := a.DeleteUserSession(newTestContext(), &auth.SessionOfUser{
+			UserID:    testCase.userID,
+			SessionID: testCase.sessionID,
+		})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		if isDeleted.IsDeleted != testCase.expectedDeleted {
+			t.Errorf("%s: expected deleted %t, got %t", testCase.name, testCase.expectedDeleted, isDeleted.IsDeleted)
+		}
+	}
+
+	if _, ok := sessionRepo.sessions[otherUserTokenPair.AccessToken]; !ok {
+		t.Errorf("session of another user is ended")
+	}
+	if _, ok := sessionRepo.sessions[tokenPair.AccessToken]; ok {
+		t.Errorf("access token of the deleted session is left")
+	}
+	refreshed, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: tokenPair.RefreshToken})
+	if err != nil || refreshed.RefreshToken != "" {
+		t.Errorf("expected refresh token of the deleted session to be unknown, got %+v and error %v", refreshed, err)
+	}
+}
+
+func TestDeleteAllSessions(t *testing.T) {
+	sessionRepo := newFakeSessionRepo()
+	a := newSessionTestServer(t, sessionRepo, time.Minute)
+	currentTokenPair := login(t, a)
+	otherTokenPair := login(t, a)
+	otherUserTokenPair := loginAs(t, a, &auth.User{ID: 2, Username: "other"})
+
+	// the logout everywhere ends the session of the request too, unlike the password change
+	deletedSessions, err := a.DeleteAllSessions(newTestContext(), &auth.User{ID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if deletedSessions.Count != 2 {
+		t.Errorf("expected 2 deleted sessions, got %d", deletedSessions.Count)
+	}
+	for _, tokenPair := range []*auth.TokenPair{currentTokenPair, otherTokenPair} {
+		if _, ok := sessionRepo.sessions[tokenPair.AccessToken]; ok {
+			t.Errorf("access token of the user is left")
+		}
+		refreshed, err := a.RefreshTokens(newTestContext(), &auth.RefreshToken{RefreshToken: tokenPair.RefreshToken})
+		if err != nil || refreshed.RefreshToken != "" {
+			t.Errorf("expected refresh token of the user to be unknown, got %+v and error %v", refreshed, err)
+		}
+	}
+	if _, ok := sessionRepo.sessions[otherUserTokenPair.AccessToken]; !ok {
+		t.Errorf("session of another user is ended")
+	}
+
+	// nothing is left to end
+	deletedSessions, err = a.DeleteAllSessions(newTestContext(), &auth.User{ID: 1})
+	if err != nil || deletedSessions.Count != 0 {
+		t.Errorf("expected no deleted sessions, got %d and error %v", deletedSessions.Count, err)
+	}
+}
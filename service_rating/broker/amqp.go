@@ -0,0 +1,337 @@
+package ratingbroker
+
+import (
+	"fmt"
+	"github.com/streadway/amqp"
+	"sync"
+	"time"
+)
+
+const (
+	RetryCountHeader = "x-retry-count"
+	LastErrorHeader  = "x-last-error"
+
+	amqpConfirmTimeout = 5 * time.Second
+	// confirms of the timed out messages wait in the buffer so that they do not block the connection
+	amqpConfirmsBuffer = 64
+)
+
+// AMQPBroker publishes in confirm mode and retries failed messages through delayed retry queues.
+// Messages expired in a retry queue are routed back to their queue by the dead letter exchange.
+type AMQPBroker struct {
+	rabbitConn  *amqp.Connection
+	rabbitChan  *amqp.Channel
+	confirms    <-chan amqp.Confirmation
+	closed      <-chan *amqp.Error
+	retryPolicy *RetryPolicy
+	prefetch    int
+
+	mu       sync.Mutex
+	declared map[string]bool
+	// publishedNumber is the delivery tag of the last published message, the broker confirms messages by it
+	publishedNumber uint64
+}
+
+func NewAMQPBroker(addr string, retryPolicy *RetryPolicy, prefetch int) (*AMQPBroker, error) {
+	rabbitConn, err := amqp.Dial(addr)
+	if err != nil {
+		return nil, err
+	}
+	rabbitChan, err := rabbitConn.Channel()
+	if err != nil {
+		rabbitConn.Close()
+		return nil, err
+	}
+	err = rabbitChan.Confirm(false)
+	if err != nil {
+		rabbitConn.Close()
+		return nil, err
+	}
+	return &AMQPBroker{
+		rabbitConn:  rabbitConn,
+		rabbitChan:  rabbitChan,
+		confirms:    rabbitChan.NotifyPublish(make(chan amqp.Confirmation, amqpConfirmsBuffer)),
+		closed:      rabbitChan.NotifyClose(make(chan *amqp.Error, 1)),
+		retryPolicy: retryPolicy,
+		prefetch:    prefetch,
+		declared:    make(map[string]bool),
+	}, nil
+}
+
+func (b *AMQPBroker) Publish(queue string, body []byte) error {
+	err := b.declareQueue(queue)
+	if err != nil {
+		return err
+	}
+	return b.publish(queue, amqp.Publishing{
+		DeliveryMode: amqp.Persistent,
+		ContentType:  "text/plain",
+		Body:         body,
+	})
+}
+
+func (b *AMQPBroker) publish(queue string, msg amqp.Publishing) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	err := b.rabbitChan.Publish(
+		"",
+		queue,
+		false,
+		false,
+		msg)
+	if err != nil {
+		return err
+	}
+	b.publishedNumber++
+	timeout := time.NewTimer(amqpConfirmTimeout)
+	defer timeout.Stop()
+	for {
+		select {
+		case confirmation, ok := <-b.confirms:
+			if !ok {
+				return ErrPublishNotConfirmed
+			}
+			// late confirmation of a message that has already timed out
+			if confirmation.DeliveryTag < b.publishedNumber {
+				continue
+			}
+			if !confirmation.Ack {
+				return ErrPublishNotConfirmed
+			}
+			return nil
+		case <-timeout.C:
+			return ErrPublishNotConfirmed
+		}
+	}
+}
+
+// Consume declares the retry queues and the dead letter queue of the queue. The messages prefetched before done
+// is closed are still given, so they are processed instead of waiting for the redelivery.
+func (b *AMQPBroker) Consume(queue string, done <-chan struct{}) (<-chan Delivery, error) {
+	err := b.declareQueue(queue)
+	if err != nil {
+		return nil, err
+	}
+	err = b.declareRetryQueues(queue)
+	if err != nil {
+		return nil, err
+	}
+	err = b.rabbitChan.Qos(
+		b.prefetch, // prefetch count
+		0,          // prefetch size
+		false,      // global
+	)
+	if err != nil {
+		return nil, err
+	}
+	consumerTag := queue + ".consumer"
+	tasks, err := b.rabbitChan.Consume(
+		queue,       // queue
+		consumerTag, // consumer
+		false,       // auto-ack
+		false,       // exclusive
+		false,       // no-local
+		false,       // no-wait
+		nil,         // args
+	)
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		<-done
+		// the tasks channel is closed by the library once the prefetched messages are given
+		_ = b.rabbitChan.Cancel(consumerTag, false)
+	}()
+	deliveries := make(chan Delivery)
+	go func() {
+		defer close(deliveries)
+		for task := range tasks {
+			deliveries <- &amqpDelivery{
+				broker:   b,
+				queue:    queue,
+				delivery: task,
+			}
+		}
+	}()
+	return deliveries, nil
+}
+
+func (b *AMQPBroker) Ping() error {
+	if b.rabbitConn.IsClosed() {
+		return ErrBrokerClosed
+	}
+	select {
+	case <-b.closed:
+		return ErrBrokerClosed
+	default:
+		return nil
+	}
+}
+
+func (b *AMQPBroker) QueueDepth(queue string) (int, error) {
+	state, err := b.rabbitChan.QueueInspect(queue)
+	if err != nil {
+		return 0, err
+	}
+	return state.Messages, nil
+}
+
+func (b *AMQPBroker) Close() error {
+	err := b.rabbitChan.Close()
+	if err != nil {
+		b.rabbitConn.Close()
+		return err
+	}
+	return b.rabbitConn.Close()
+}
+
+func (b *AMQPBroker) declareQueue(queue string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.declared[queue] {
+		return nil
+	}
+	_, err := b.rabbitChan.QueueDeclare(
+		queue, // name
+		true,  // durable
+		false, // delete when unused
+		false, // exclusive
+		false, // no-wait
+		nil,   // arguments
+	)
+	if err != nil {
+		return err
+	}
+	b.declared[queue] = true
+	return nil
+}
+
+func (b *AMQPBroker) declareRetryQueues(queue string) error {
+	err := b.declareQueue(DeadLetterQueueName(queue))
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for attempt := uint32(1); b.retryPolicy.hasAttemptsAfter(attempt); attempt++ {
+		_, err = b.rabbitChan.QueueDeclare(
+			b.retryQueueName(queue, attempt),
+			true,
+			false,
+			false,
+			false,
+			amqp.Table{
+				"x-message-ttl":             b.retryPolicy.RetryDelay(attempt).Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queue,
+			},
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retryQueueName contains the delay since the delay of the queue can not be changed after it is declared.
+func (b *AMQPBroker) retryQueueName(queue string, attempt uint32) string {
+	return fmt.Sprintf("%s.retry.%dms", queue, b.retryPolicy.RetryDelay(attempt).Milliseconds())
+}
+
+// RetryCount returns the number of failed attempts to process the message.
+func RetryCount(headers amqp.Table) uint32 {
+	switch count := headers[RetryCountHeader].(type) {
+	case int8:
+		return uint32(count)
+	case int16:
+		return uint32(count)
+	case int32:
+		return uint32(count)
+	case int64:
+		return uint32(count)
+	case uint8:
+		return uint32(count)
+	}
+	return 0
+}
+
+type amqpDelivery struct {
+	broker   *AMQPBroker
+	queue    string
+	delivery amqp.Delivery
+}
+
+func (d *amqpDelivery) Body() []byte {
+	return d.delivery.Body
+}
+
+func (d *amqpDelivery) Attempts() uint32 {
+	return RetryCount(d.delivery.Headers)
+}
+
+func (d *amqpDelivery) Ack() error {
+	return d.delivery.Ack(false)
+}
+
+func (d *amqpDelivery) Retry(cause error) (bool, error) {
+	attempt := d.Attempts() + 1
+	if !d.broker.retryPolicy.hasAttemptsAfter(attempt) {
+		return true, d.move(DeadLetterQueueName(d.queue), attempt, cause)
+	}
+	return false, d.move(d.broker.retryQueueName(d.queue, attempt), attempt, cause)
+}
+
+func (d *amqpDelivery) DeadLetter(cause error) error {
+	return d.move(DeadLetterQueueName(d.queue), d.Attempts()+1, cause)
+}
+
+// move republishes the message to the queue with the attempt and the error in its headers.
+// If it can not be moved, the message is requeued after a delay, so a broker failure does not make
+// the consumer take it again and again at once.
+func (d *amqpDelivery) move(queue string, attempt uint32, cause error) error {
+	err := d.broker.publish(queue, d.withAttempt(attempt, cause))
+	if err != nil {
+		return d.requeueLater(attempt, fmt.Errorf("can not move message to %s: %s", queue, err))
+	}
+	return d.delivery.Ack(false)
+}
+
+// requeueLater puts the message which could not be moved to the retry queue of its attempt, or to the last
+// retry queue if it has no attempts left. If that fails too, the message is held and returned to its queue
+// when the retry delay has passed.
+func (d *amqpDelivery) requeueLater(attempt uint32, moveErr error) error {
+	retryPolicy := d.broker.retryPolicy
+	retryAttempt := attempt
+	if !retryPolicy.hasAttemptsAfter(retryAttempt) && retryPolicy.MaxAttempts > 1 {
+		retryAttempt = retryPolicy.MaxAttempts - 1
+	}
+	if retryPolicy.hasAttemptsAfter(retryAttempt) {
+		err := d.broker.publish(d.broker.retryQueueName(d.queue, retryAttempt), d.withAttempt(retryAttempt, moveErr))
+		if err == nil {
+			ackErr := d.delivery.Ack(false)
+			if ackErr != nil {
+				return fmt.Errorf("%s, error with ack: %s", moveErr, ackErr)
+			}
+			return moveErr
+		}
+	}
+	time.AfterFunc(retryPolicy.RetryDelay(retryAttempt), func() {
+		_ = d.delivery.Nack(false, true)
+	})
+	return moveErr
+}
+
+func (d *amqpDelivery) withAttempt(attempt uint32, cause error) amqp.Publishing {
+	headers := amqp.Table{}
+	for key, value := range d.delivery.Headers {
+		headers[key] = value
+	}
+	headers[RetryCountHeader] = int32(attempt)
+	headers[LastErrorHeader] = cause.Error()
+	return amqp.Publishing{
+		Headers:      headers,
+		DeliveryMode: amqp.Persistent,
+		ContentType:  d.delivery.ContentType,
+		Body:         d.delivery.Body,
+	}
+}
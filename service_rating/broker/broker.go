@@ -0,0 +1,114 @@
+package ratingbroker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	ChangeRatingQueueName = "change_rating"
+
+	KindAMQP   = "amqp"
+	KindRedis  = "redis"
+	KindMemory = "memory"
+
+	DefaultMaxAttempts    = 5
+	DefaultRetryBaseDelay = time.Second
+	DefaultPrefetch       = 1
+)
+
+var (
+	ErrPublishNotConfirmed = errors.New("broker did not confirm the message")
+	ErrBrokerClosed        = errors.New("broker is closed")
+	ErrQueueFull           = errors.New("queue is full")
+)
+
+// Publisher puts messages to a queue. A nil error means the broker has taken responsibility for the message.
+type Publisher interface {
+	Publish(queue string, body []byte) error
+}
+
+// Delivery is a message taken from a queue. Exactly one of Ack, Retry and DeadLetter must be called on it.
+type Delivery interface {
+	Body() []byte
+	// Attempts is the number of the previous failed attempts to process the message
+	Attempts() uint32
+	Ack() error
+	// Retry returns the message to the queue after the delay of its attempt or dead-letters it
+	// if there are no attempts left, deadLettered reports which one happened
+	Retry(cause error) (deadLettered bool, err error)
+	// DeadLetter moves the message which can never be processed straight to the dead letter queue
+	DeadLetter(cause error) error
+}
+
+// Consumer gives the messages of a queue. When done is closed the consumer stops taking new messages
+// and closes the channel, the messages already given can still be acked until the broker is closed.
+type Consumer interface {
+	Consume(queue string, done <-chan struct{}) (<-chan Delivery, error)
+}
+
+type Broker interface {
+	Publisher
+	Consumer
+	// Ping fails if the connection to the broker is lost
+	Ping() error
+	// QueueDepth is the number of messages waiting in the queue
+	QueueDepth(queue string) (int, error)
+	Close() error
+}
+
+// RetryPolicy makes a failed message wait before it goes back to its queue, every next delay is twice longer.
+// The message is dead-lettered after MaxAttempts failed attempts.
+type RetryPolicy struct {
+	MaxAttempts uint32
+	BaseDelay   time.Duration
+}
+
+func (p *RetryPolicy) RetryDelay(attempt uint32) time.Duration {
+	return p.BaseDelay << (attempt - 1)
+}
+
+func (p *RetryPolicy) hasAttemptsAfter(attempt uint32) bool {
+	return attempt < p.MaxAttempts
+}
+
+type Config struct {
+	Kind        string
+	AMQPAddr    string
+	RedisAddr   string
+	RetryPolicy *RetryPolicy
+	// Prefetch is the number of messages the consumer may hold unacked
+	Prefetch int
+}
+
+// Open connects to the broker of the configured kind, AMQP is used if the kind is not set.
+// The memory broker works only inside one process, so it is meant for tests: the services must not use it
+// to talk to each other.
+func Open(config *Config) (Broker, error) {
+	retryPolicy := config.RetryPolicy
+	if retryPolicy == nil {
+		retryPolicy = &RetryPolicy{
+			MaxAttempts: DefaultMaxAttempts,
+			BaseDelay:   DefaultRetryBaseDelay,
+		}
+	}
+	prefetch := config.Prefetch
+	if prefetch <= 0 {
+		prefetch = DefaultPrefetch
+	}
+	switch config.Kind {
+	case KindAMQP, "":
+		return NewAMQPBroker(config.AMQPAddr, retryPolicy, prefetch)
+	case KindRedis:
+		return NewRedisBroker(config.RedisAddr, retryPolicy, prefetch)
+	case KindMemory:
+		return NewMemoryBroker(retryPolicy), nil
+	}
+	return nil, fmt.Errorf("unknown broker kind %q", config.Kind)
+}
+
+// DeadLetterQueueName is the queue where the messages of the queue go when they can not be processed.
+func DeadLetterQueueName(queue string) string {
+	return queue + ".dead"
+}
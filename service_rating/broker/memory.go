@@ -0,0 +1,175 @@
+package ratingbroker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const memoryQueueSize = 1024
+
+// MemoryBroker passes messages through channels inside one process. Messages are lost when the process stops.
+type MemoryBroker struct {
+	retryPolicy *RetryPolicy
+
+	mu          sync.Mutex
+	queues      map[string]chan *memoryDelivery
+	deadLetters map[string][]*DeadLetter
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+type DeadLetter struct {
+	Body      []byte
+	Attempts  uint32
+	LastError string
+}
+
+func NewMemoryBroker(retryPolicy *RetryPolicy) *MemoryBroker {
+	return &MemoryBroker{
+		retryPolicy: retryPolicy,
+		queues:      make(map[string]chan *memoryDelivery),
+		deadLetters: make(map[string][]*DeadLetter),
+		done:        make(chan struct{}),
+	}
+}
+
+// Publish does not wait for a consumer, it fails when the queue is full.
+func (b *MemoryBroker) Publish(queue string, body []byte) error {
+	return b.push(&memoryDelivery{
+		broker: b,
+		queue:  queue,
+		body:   body,
+	})
+}
+
+// Messages left in the queue when done is closed are taken by the next consumer of the queue.
+func (b *MemoryBroker) Consume(queue string, done <-chan struct{}) (<-chan Delivery, error) {
+	messages := b.queue(queue)
+	deliveries := make(chan Delivery)
+	go func() {
+		defer close(deliveries)
+		for {
+			select {
+			case <-b.done:
+				return
+			case <-done:
+				return
+			case message := <-messages:
+				select {
+				case deliveries <- message:
+				case <-b.done:
+					return
+				}
+			}
+		}
+	}()
+	return deliveries, nil
+}
+
+func (b *MemoryBroker) Ping() error {
+	select {
+	case <-b.done:
+		return ErrBrokerClosed
+	default:
+		return nil
+	}
+}
+
+func (b *MemoryBroker) QueueDepth(queue string) (int, error) {
+	return len(b.queue(queue)), nil
+}
+
+func (b *MemoryBroker) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+	return nil
+}
+
+// DeadLetters returns the messages dead-lettered from the queue.
+func (b *MemoryBroker) DeadLetters(queue string) []*DeadLetter {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*DeadLetter(nil), b.deadLetters[queue]...)
+}
+
+func (b *MemoryBroker) queue(name string) chan *memoryDelivery {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	messages, ok := b.queues[name]
+	if !ok {
+		messages = make(chan *memoryDelivery, memoryQueueSize)
+		b.queues[name] = messages
+	}
+	return messages
+}
+
+func (b *MemoryBroker) push(message *memoryDelivery) error {
+	select {
+	case <-b.done:
+		return ErrBrokerClosed
+	default:
+	}
+	select {
+	case b.queue(message.queue) <- message:
+		return nil
+	default:
+		return ErrQueueFull
+	}
+}
+
+type memoryDelivery struct {
+	broker   *MemoryBroker
+	queue    string
+	body     []byte
+	attempts uint32
+}
+
+func (d *memoryDelivery) Body() []byte {
+	return d.body
+}
+
+func (d *memoryDelivery) Attempts() uint32 {
+	return d.attempts
+}
+
+func (d *memoryDelivery) Ack() error {
+	return nil
+}
+
+func (d *memoryDelivery) Retry(cause error) (bool, error) {
+	attempt := d.attempts + 1
+	if !d.broker.retryPolicy.hasAttemptsAfter(attempt) {
+		return true, d.deadLetter(attempt, cause)
+	}
+	retry := &memoryDelivery{
+		broker:   d.broker,
+		queue:    d.queue,
+		body:     d.body,
+		attempts: attempt,
+	}
+	time.AfterFunc(d.broker.retryPolicy.RetryDelay(attempt), func() {
+		// a retry that does not fit into the queue is dead-lettered instead of being lost silently
+		err := d.broker.push(retry)
+		if errors.Is(err, ErrQueueFull) {
+			_ = retry.deadLetter(attempt, err)
+		}
+	})
+	return false, nil
+}
+
+func (d *memoryDelivery) DeadLetter(cause error) error {
+	return d.deadLetter(d.attempts+1, cause)
+}
+
+func (d *memoryDelivery) deadLetter(attempt uint32, cause error) error {
+	d.broker.mu.Lock()
+	defer d.broker.mu.Unlock()
+	d.broker.deadLetters[d.queue] = append(d.broker.deadLetters[d.queue], &DeadLetter{
+		Body:      d.body,
+		Attempts:  attempt,
+		LastError: cause.Error(),
+	})
+	return nil
+}
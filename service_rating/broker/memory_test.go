@@ -0,0 +1,187 @@
+package ratingbroker_test
+
+import (
+	"errors"
+	ratingbroker "kinopoisk/service_rating/broker"
+	"testing"
+	"time"
+)
+
+const (
+	testQueue   = "test"
+	waitTimeout = 5 * time.Second
+)
+
+func receive(t *testing.T, deliveries <-chan ratingbroker.Delivery) ratingbroker.Delivery {
+	select {
+	case delivery, ok := <-deliveries:
+		if !ok {
+			t.Fatalf("deliveries are closed")
+		}
+		return delivery
+	case <-time.After(waitTimeout):
+		t.Fatalf("timed out waiting for a delivery")
+	}
+	return nil
+}
+
+func TestMemoryBrokerPublishConsume(t *testing.T) {
+	broker := ratingbroker.NewMemoryBroker(&ratingbroker.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	defer broker.Close()
+
+	for _, body := range []string{"first", "second"} {
+		err := broker.Publish(testQueue, []byte(body))
+		if err != nil {
+			t.Fatalf("can not publish: %s", err)
+		}
+	}
+	if depth, _ := broker.QueueDepth(testQueue); depth != 2 {
+		t.Errorf("expected queue depth 2, got %d", depth)
+	}
+	done := make(chan struct{})
+	defer close(done)
+	deliveries, err := broker.Consume(testQueue, done)
+	if err != nil {
+		t.Fatalf("can not consume: %s", err)
+	}
+	for _, expectedBody := range []string{"first", "second"} {
+		delivery := receive(t, deliveries)
+		if string(delivery.Body()) != expectedBody || delivery.Attempts() != 0 {
+			t.Errorf("expected %q with no attempts, got %q with %d", expectedBody, delivery.Body(), delivery.Attempts())
+		}
+		if err := delivery.Ack(); err != nil {
+			t.Errorf("can not ack: %s", err)
+		}
+	}
+}
+
+func TestMemoryBrokerRetry(t *testing.T) {
+	baseDelay := 20 * time.Millisecond
+	broker := ratingbroker.NewMemoryBroker(&ratingbroker.RetryPolicy{MaxAttempts: 3, BaseDelay: baseDelay})
+	defer broker.Close()
+	done := make(chan struct{})
+	defer close(done)
+	deliveries, err := broker.Consume(testQueue, done)
+	if err != nil {
+		t.Fatalf("can not consume: %s", err)
+	}
+	err = broker.Publish(testQueue, []byte("event"))
+	if err != nil {
+		t.Fatalf("can not publish: %s", err)
+	}
+
+	delivery := receive(t, deliveries)
+	// the delay doubles with every attempt
+	for attempt, expectedDelay := range []time.Duration{baseDelay, 2 * baseDelay} {
+		retriedAt := time.Now()
+		deadLettered, err := delivery.Retry(errors.New("failed"))
+		if err != nil || deadLettered {
+			t.Fatalf("attempt %d: expected retry, got dead-lettered %t and error %v", attempt+1, deadLettered, err)
+		}
+		delivery = receive(t, deliveries)
+		if delay := time.Since(retriedAt); delay < expectedDelay {
+			t.Errorf("attempt %d: expected retry after %s, got it after %s", attempt+1, expectedDelay, delay)
+		}
+		if delivery.Attempts() != uint32(attempt+1) {
+			t.Errorf("expected %d attempts, got %d", attempt+1, delivery.Attempts())
+		}
+	}
+	if delay := (&ratingbroker.RetryPolicy{BaseDelay: time.Second}).RetryDelay(4); delay != 8*time.Second {
+		t.Errorf("expected retry delay 8s on attempt 4, got %s", delay)
+	}
+}
+
+func TestMemoryBrokerDeadLetter(t *testing.T) {
+	broker := ratingbroker.NewMemoryBroker(&ratingbroker.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	defer broker.Close()
+	done := make(chan struct{})
+	defer close(done)
+	deliveries, err := broker.Consume(testQueue, done)
+	if err != nil {
+		t.Fatalf("can not consume: %s", err)
+	}
+	err = broker.Publish(testQueue, []byte("event"))
+	if err != nil {
+		t.Fatalf("can not publish: %s", err)
+	}
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		delivery := receive(t, deliveries)
+		deadLettered, err := delivery.Retry(errors.New("failed"))
+		if err != nil {
+			t.Fatalf("can not retry: %s", err)
+		}
+		if deadLettered != (attempt == 3) {
+			t.Fatalf("attempt %d: unexpected dead-lettered %t", attempt, deadLettered)
+		}
+	}
+	deadLetters := broker.DeadLetters(testQueue)
+	if len(deadLetters) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(deadLetters))
+	}
+	if string(deadLetters[0].Body) != "event" || deadLetters[0].Attempts != 3 || deadLetters[0].LastError != "failed" {
+		t.Errorf("unexpected dead letter %+v", deadLetters[0])
+	}
+	select {
+	case delivery := <-deliveries:
+		t.Errorf("dead-lettered event is delivered again: %q", delivery.Body())
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// the events which can never be processed skip the retries
+	err = broker.Publish(testQueue, []byte("broken"))
+	if err != nil {
+		t.Fatalf("can not publish: %s", err)
+	}
+	err = receive(t, deliveries).DeadLetter(errors.New("bad json"))
+	if err != nil {
+		t.Fatalf("can not dead-letter: %s", err)
+	}
+	deadLetters = broker.DeadLetters(testQueue)
+	if len(deadLetters) != 2 || deadLetters[1].Attempts != 1 || deadLetters[1].LastError != "bad json" {
+		t.Errorf("unexpected dead letters %+v", deadLetters)
+	}
+}
+
+func TestMemoryBrokerConsumeStops(t *testing.T) {
+	broker := ratingbroker.NewMemoryBroker(&ratingbroker.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	defer broker.Close()
+	done := make(chan struct{})
+	deliveries, err := broker.Consume(testQueue, done)
+	if err != nil {
+		t.Fatalf("can not consume: %s", err)
+	}
+
+	close(done)
+	select {
+	case _, ok := <-deliveries:
+		if ok {
+			t.Fatalf("delivery is given after done is closed")
+		}
+	case <-time.After(waitTimeout):
+		t.Fatalf("deliveries are not closed after done is closed")
+	}
+
+	// the messages left in the queue go to the next consumer
+	err = broker.Publish(testQueue, []byte("event"))
+	if err != nil {
+		t.Fatalf("can not publish: %s", err)
+	}
+	nextDone := make(chan struct{})
+	defer close(nextDone)
+	nextDeliveries, err := broker.Consume(testQueue, nextDone)
+	if err != nil {
+		t.Fatalf("can not consume: %s", err)
+	}
+	if delivery := receive(t, nextDeliveries); string(delivery.Body()) != "event" {
+		t.Errorf("expected %q, got %q", "event", delivery.Body())
+	}
+
+	broker.Close()
+	if _, ok := <-nextDeliveries; ok {
+		t.Errorf("delivery is given after the broker is closed")
+	}
+	if err := broker.Publish(testQueue, []byte("late")); !errors.Is(err, ratingbroker.ErrBrokerClosed) {
+		t.Errorf("expected error %s, got %v", ratingbroker.ErrBrokerClosed, err)
+	}
+}
@@ -0,0 +1,364 @@
+package ratingbroker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gomodule/redigo/redis"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	redisConsumerGroup = "service_rating"
+	// the stream is trimmed approximately, acked entries are deleted right away so only a stuck backlog is cut
+	redisStreamMaxLen = 1000000
+	redisReadBlock    = 5 * time.Second
+	// entries pending longer than this on any consumer are taken over, the consumer most likely died
+	redisClaimIdle     = time.Minute
+	redisClaimInterval = 30 * time.Second
+	redisRetryInterval = 500 * time.Millisecond
+	redisRetryBatch    = 100
+	redisMaxIdleConns  = 4
+
+	redisBodyField      = "body"
+	redisAttemptsField  = "attempts"
+	redisLastErrorField = "last_error"
+)
+
+// moveDueRetries moves the retries whose delay has passed from the sorted set back to the stream
+// in one step, so a retry is neither lost nor duplicated if the consumer dies in the middle.
+var moveDueRetries = redis.NewScript(2, `
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[3]))
+for _, entry in ipairs(due) do
+	local retry = cjson.decode(entry)
+	redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[2], '*',
+		'body', retry.body, 'attempts', retry.attempts, 'last_error', retry.last_error)
+	redis.call('ZREM', KEYS[1], entry)
+end
+return #due
+`)
+
+// RedisBroker keeps every queue in a stream read by a consumer group, so several instances of the service
+// share the messages. Failed messages wait in a sorted set scored by the time of their next attempt,
+// dead-lettered ones are added to the dead letter stream.
+type RedisBroker struct {
+	pool        *redis.Pool
+	consumer    string
+	retryPolicy *RetryPolicy
+	readCount   int
+	done        chan struct{}
+	closeOnce   sync.Once
+}
+
+type redisRetry struct {
+	ID        string `json:"id"`
+	Body      string `json:"body"`
+	Attempts  uint32 `json:"attempts"`
+	LastError string `json:"last_error"`
+}
+
+type redisEntry struct {
+	id        string
+	body      []byte
+	attempts  uint32
+	lastError string
+}
+
+func NewRedisBroker(addr string, retryPolicy *RetryPolicy, prefetch int) (*RedisBroker, error) {
+	pool := &redis.Pool{
+		MaxIdle:     redisMaxIdleConns,
+		IdleTimeout: 5 * time.Minute,
+		Dial: func() (redis.Conn, error) {
+			return redis.DialURL(addr)
+		},
+	}
+	conn := pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("PING")
+	if err != nil {
+		pool.Close()
+		return nil, err
+	}
+	consumer, err := os.Hostname()
+	if err != nil {
+		consumer = strconv.Itoa(os.Getpid())
+	}
+	return &RedisBroker{
+		pool:        pool,
+		consumer:    consumer,
+		retryPolicy: retryPolicy,
+		readCount:   prefetch,
+		done:        make(chan struct{}),
+	}, nil
+}
+
+func (b *RedisBroker) Publish(queue string, body []byte) error {
+	conn := b.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("XADD", queue, "MAXLEN", "~", redisStreamMaxLen, "*", redisBodyField, body)
+	return err
+}
+
+// Consume first gives the entries this consumer had taken before it was restarted and then the new ones.
+// The read in progress when done is closed ends within the block timeout.
+func (b *RedisBroker) Consume(queue string, done <-chan struct{}) (<-chan Delivery, error) {
+	conn := b.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("XGROUP", "CREATE", queue, redisConsumerGroup, "0", "MKSTREAM")
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil, err
+	}
+	deliveries := make(chan Delivery)
+	go b.moveRetries(queue)
+	go b.readStream(queue, deliveries, done)
+	return deliveries, nil
+}
+
+func (b *RedisBroker) Ping() error {
+	conn := b.pool.Get()
+	defer conn.Close()
+	_, err := conn.Do("PING")
+	return err
+}
+
+// QueueDepth counts the entries of the stream which are not acked yet, including the ones being processed.
+func (b *RedisBroker) QueueDepth(queue string) (int, error) {
+	conn := b.pool.Get()
+	defer conn.Close()
+	return redis.Int(conn.Do("XLEN", queue))
+}
+
+func (b *RedisBroker) Close() error {
+	b.closeOnce.Do(func() {
+		close(b.done)
+	})
+	return b.pool.Close()
+}
+
+func (b *RedisBroker) closed(done <-chan struct{}) bool {
+	select {
+	case <-b.done:
+		return true
+	case <-done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *RedisBroker) readStream(queue string, deliveries chan<- Delivery, done <-chan struct{}) {
+	defer close(deliveries)
+	conn := b.pool.Get()
+	defer conn.Close()
+	// "0" reads the entries pending on this consumer, ">" reads the never delivered ones
+	lastID := "0"
+	lastClaim := time.Now()
+	for !b.closed(done) {
+		reply, err := conn.Do("XREADGROUP", "GROUP", redisConsumerGroup, b.consumer, "COUNT", b.readCount,
+			"BLOCK", redisReadBlock.Milliseconds(), "STREAMS", queue, lastID)
+		if err != nil && !errors.Is(err, redis.ErrNil) {
+			if b.closed(done) {
+				return
+			}
+			// the connection may be broken, it is replaced before the next read
+			conn.Close()
+			time.Sleep(time.Second)
+			conn = b.pool.Get()
+			continue
+		}
+		var entries []*redisEntry
+		if reply != nil {
+			entries, err = parseStreamReply(reply)
+			if err != nil {
+				continue
+			}
+		}
+		if lastID != ">" {
+			if len(entries) == 0 {
+				lastID = ">"
+			} else {
+				// the pending entries stay pending until they are acked, so the read goes on after the last one
+				lastID = entries[len(entries)-1].id
+			}
+		}
+		if time.Since(lastClaim) > redisClaimInterval {
+			claimed, claimErr := b.claimStale(conn, queue)
+			if claimErr == nil {
+				entries = append(entries, claimed...)
+			}
+			lastClaim = time.Now()
+		}
+		for _, entry := range entries {
+			select {
+			case deliveries <- &redisDelivery{broker: b, queue: queue, entry: entry}:
+			case <-b.done:
+				return
+			}
+		}
+	}
+}
+
+func (b *RedisBroker) claimStale(conn redis.Conn, queue string) ([]*redisEntry, error) {
+	reply, err := redis.Values(conn.Do("XAUTOCLAIM", queue, redisConsumerGroup, b.consumer,
+		redisClaimIdle.Milliseconds(), "0-0", "COUNT", b.readCount))
+	if err != nil {
+		return nil, err
+	}
+	if len(reply) < 2 {
+		return nil, fmt.Errorf("unexpected XAUTOCLAIM reply of %d items", len(reply))
+	}
+	return parseEntries(reply[1])
+}
+
+func (b *RedisBroker) moveRetries(queue string) {
+	ticker := time.NewTicker(redisRetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.done:
+			return
+		case <-ticker.C:
+			conn := b.pool.Get()
+			// a failed move is tried again on the next tick
+			_, _ = moveDueRetries.Do(conn, redisRetryKey(queue), queue,
+				time.Now().UnixMilli(), redisStreamMaxLen, redisRetryBatch)
+			conn.Close()
+		}
+	}
+}
+
+func redisRetryKey(queue string) string {
+	return queue + ".retry"
+}
+
+// parseStreamReply parses the XREADGROUP reply of one stream: [[stream, [[id, [field, value, ...]], ...]]].
+func parseStreamReply(reply interface{}) ([]*redisEntry, error) {
+	streams, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(streams) == 0 {
+		return nil, nil
+	}
+	stream, err := redis.Values(streams[0], nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(stream) != 2 {
+		return nil, fmt.Errorf("unexpected stream reply of %d items", len(stream))
+	}
+	return parseEntries(stream[1])
+}
+
+func parseEntries(reply interface{}) ([]*redisEntry, error) {
+	items, err := redis.Values(reply, nil)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*redisEntry, 0, len(items))
+	for _, item := range items {
+		// entries deleted while pending come without fields
+		fields, err := redis.Values(item, nil)
+		if err != nil || len(fields) != 2 || fields[1] == nil {
+			continue
+		}
+		id, err := redis.String(fields[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		values, err := redis.StringMap(fields[1], nil)
+		if err != nil {
+			return nil, err
+		}
+		entry := &redisEntry{
+			id:        id,
+			body:      []byte(values[redisBodyField]),
+			lastError: values[redisLastErrorField],
+		}
+		if attempts, err := strconv.ParseUint(values[redisAttemptsField], 10, 32); err == nil {
+			entry.attempts = uint32(attempts)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+type redisDelivery struct {
+	broker *RedisBroker
+	queue  string
+	entry  *redisEntry
+}
+
+func (d *redisDelivery) Body() []byte {
+	return d.entry.body
+}
+
+func (d *redisDelivery) Attempts() uint32 {
+	return d.entry.attempts
+}
+
+func (d *redisDelivery) Ack() error {
+	return d.ackWith(nil)
+}
+
+func (d *redisDelivery) Retry(cause error) (bool, error) {
+	attempt := d.entry.attempts + 1
+	if !d.broker.retryPolicy.hasAttemptsAfter(attempt) {
+		return true, d.deadLetter(attempt, cause)
+	}
+	retry, err := json.Marshal(&redisRetry{
+		ID:        d.entry.id,
+		Body:      string(d.entry.body),
+		Attempts:  attempt,
+		LastError: cause.Error(),
+	})
+	if err != nil {
+		return false, err
+	}
+	readyAt := time.Now().Add(d.broker.retryPolicy.RetryDelay(attempt)).UnixMilli()
+	return false, d.ackWith(func(conn redis.Conn) error {
+		return conn.Send("ZADD", redisRetryKey(d.queue), readyAt, retry)
+	})
+}
+
+func (d *redisDelivery) DeadLetter(cause error) error {
+	return d.deadLetter(d.entry.attempts+1, cause)
+}
+
+func (d *redisDelivery) deadLetter(attempt uint32, cause error) error {
+	return d.ackWith(func(conn redis.Conn) error {
+		return conn.Send("XADD", DeadLetterQueueName(d.queue), "MAXLEN", "~", redisStreamMaxLen, "*",
+			redisBodyField, d.entry.body, redisAttemptsField, attempt, redisLastErrorField, cause.Error())
+	})
+}
+
+// ackWith acks and deletes the entry in one transaction with the command that moves the entry elsewhere.
+// The entry stays pending and is delivered again if the transaction fails.
+func (d *redisDelivery) ackWith(move func(conn redis.Conn) error) error {
+	conn := d.broker.pool.Get()
+	defer conn.Close()
+	err := conn.Send("MULTI")
+	if err != nil {
+		return err
+	}
+	if move != nil {
+		err = move(conn)
+		if err != nil {
+			return err
+		}
+	}
+	err = conn.Send("XACK", d.queue, redisConsumerGroup, d.entry.id)
+	if err != nil {
+		return err
+	}
+	err = conn.Send("XDEL", d.queue, d.entry.id)
+	if err != nil {
+		return err
+	}
+	_, err = conn.Do("EXEC")
+	return err
+}
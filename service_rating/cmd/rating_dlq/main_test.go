@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"github.com/streadway/amqp"
+	ratingbroker "kinopoisk/service_rating/broker"
+	"testing"
+)
+
+// fakeRabbitChannel gives the dead-lettered events and confirms the published ones unless failConfirms is set.
+type fakeRabbitChannel struct {
+	deadEvents   []amqp.Delivery
+	published    []amqp.Publishing
+	confirms     chan amqp.Confirmation
+	failConfirms bool
+	acked        []uint64
+	nacked       []uint64
+}
+
+func newFakeRabbitChannel(bodies ...string) *fakeRabbitChannel {
+	rabbitChan := &fakeRabbitChannel{
+		confirms: make(chan amqp.Confirmation, 1),
+	}
+	for i, body := range bodies {
+		rabbitChan.deadEvents = append(rabbitChan.deadEvents, amqp.Delivery{
+			Acknowledger: rabbitChan,
+			DeliveryTag:  uint64(i + 1),
+			Headers: amqp.Table{
+				ratingbroker.RetryCountHeader: int32(5),
+				ratingbroker.LastErrorHeader:  "db is down",
+				"x-trace":                     "trace",
+			},
+			Body: []byte(body),
+		})
+	}
+	return rabbitChan
+}
+
+func (c *fakeRabbitChannel) Get(queue string, autoAck bool) (amqp.Delivery, bool, error) {
+	if queue != deadLetterQueueName {
+		return amqp.Delivery{}, false, errors.New("unexpected queue " + queue)
+	}
+	if len(c.deadEvents) == 0 {
+		return amqp.Delivery{}, false, nil
+	}
+	event := c.deadEvents[0]
+	c.deadEvents = c.deadEvents[1:]
+	return event, true, nil
+}
+
+func (c *fakeRabbitChannel) Publish(exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	if key != ratingbroker.ChangeRatingQueueName {
+		return errors.New("unexpected queue " + key)
+	}
+	c.published = append(c.published, msg)
+	c.confirms <- amqp.Confirmation{DeliveryTag: uint64(len(c.published)), Ack: !c.failConfirms}
+	return nil
+}
+
+func (c *fakeRabbitChannel) Ack(tag uint64, multiple bool) error {
+	c.acked = append(c.acked, tag)
+	return nil
+}
+
+func (c *fakeRabbitChannel) Nack(tag uint64, multiple bool, requeue bool) error {
+	if !requeue {
+		return errors.New("dead event is dropped")
+	}
+	c.nacked = append(c.nacked, tag)
+	return nil
+}
+
+func (c *fakeRabbitChannel) Reject(tag uint64, requeue bool) error {
+	return c.Nack(tag, false, requeue)
+}
+
+func TestReplay(t *testing.T) {
+	rabbitChan := newFakeRabbitChannel(`{"EventID": "1"}`, `{"EventID": "2"}`, `{"EventID": "3"}`)
+	err := replay(rabbitChan, rabbitChan.confirms, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(rabbitChan.published) != 2 || len(rabbitChan.acked) != 2 {
+		t.Fatalf("expected 2 events replayed and acked, got %d published and %d acked", len(rabbitChan.published), len(rabbitChan.acked))
+	}
+	for _, published := range rabbitChan.published {
+		if _, ok := published.Headers[ratingbroker.RetryCountHeader]; ok {
+			t.Errorf("retry count is not reset")
+		}
+		if _, ok := published.Headers[ratingbroker.LastErrorHeader]; ok {
+			t.Errorf("last error is not removed")
+		}
+		if published.Headers["x-trace"] != "trace" {
+			t.Errorf("other headers are lost")
+		}
+	}
+	if len(rabbitChan.deadEvents) != 1 {
+		t.Errorf("expected 1 event left in the dead letter queue, got %d", len(rabbitChan.deadEvents))
+	}
+}
+
+func TestReplayNotConfirmed(t *testing.T) {
+	rabbitChan := newFakeRabbitChannel(`{"EventID": "1"}`, `{"EventID": "2"}`)
+	rabbitChan.failConfirms = true
+	err := replay(rabbitChan, rabbitChan.confirms, 10)
+	if !errors.Is(err, ratingbroker.ErrPublishNotConfirmed) {
+		t.Fatalf("expected error %s, got %v", ratingbroker.ErrPublishNotConfirmed, err)
+	}
+	if len(rabbitChan.acked) != 0 {
+		t.Errorf("event which is not confirmed is acked")
+	}
+	if len(rabbitChan.nacked) != 1 || rabbitChan.nacked[0] != 1 {
+		t.Errorf("expected the first event to be returned to the dead letter queue, got %v", rabbitChan.nacked)
+	}
+	if len(rabbitChan.published) != 1 {
+		t.Errorf("replay is not stopped on the failure, %d events published", len(rabbitChan.published))
+	}
+}
@@ -0,0 +1,147 @@
+package ratingdelivery
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+	ratingmetrics "kinopoisk/service_rating/metrics"
+	ratingserviceusecase "kinopoisk/service_rating/usecase"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+type Pinger interface {
+	Ping() error
+}
+
+// RatingHandler serves the health checks, the metrics and the admin endpoints of service_rating.
+type RatingHandler struct {
+	logger     *zap.SugaredLogger
+	db         Pinger
+	broker     Pinger
+	reconciler ratingserviceusecase.RatingReconciler
+	registry   *ratingmetrics.Registry
+	adminToken string
+	draining   int32
+}
+
+type checksResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// NewRatingHandler disables the admin endpoints if adminToken is empty.
+func NewRatingHandler(logger *zap.SugaredLogger, db, broker Pinger, reconciler ratingserviceusecase.RatingReconciler,
+	registry *ratingmetrics.Registry, adminToken string) *RatingHandler {
+	return &RatingHandler{
+		logger:     logger,
+		db:         db,
+		broker:     broker,
+		reconciler: reconciler,
+		registry:   registry,
+		adminToken: adminToken,
+	}
+}
+
+func (h *RatingHandler) Router() *mux.Router {
+	router := mux.NewRouter()
+	router.HandleFunc("/health/live", h.Live).Methods(http.MethodGet)
+	router.HandleFunc("/health/ready", h.Ready).Methods(http.MethodGet)
+	router.HandleFunc("/metrics", h.Metrics).Methods(http.MethodGet)
+	router.HandleFunc("/admin/recompute", h.Recompute).Methods(http.MethodPost)
+	return router
+}
+
+// SetDraining makes the service not ready, so no new work is routed to it while it shuts down.
+func (h *RatingHandler) SetDraining() {
+	atomic.StoreInt32(&h.draining, 1)
+}
+
+// Live only shows that the process serves requests.
+func (h *RatingHandler) Live(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, &checksResponse{Status: "ok"}, http.StatusOK)
+}
+
+// Ready checks the connections to MySQL and to the broker.
+func (h *RatingHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	checks := map[string]string{
+		"mysql":  "ok",
+		"broker": "ok",
+	}
+	status := http.StatusOK
+	if err := h.db.Ping(); err != nil {
+		checks["mysql"] = err.Error()
+		status = http.StatusServiceUnavailable
+	}
+	if err := h.broker.Ping(); err != nil {
+		checks["broker"] = err.Error()
+		status = http.StatusServiceUnavailable
+	}
+	if atomic.LoadInt32(&h.draining) == 1 {
+		checks["draining"] = "service is shutting down"
+		status = http.StatusServiceUnavailable
+	}
+	response := &checksResponse{Status: "ok", Checks: checks}
+	if status != http.StatusOK {
+		response.Status = "unavailable"
+	}
+	h.writeJSON(w, response, status)
+}
+
+func (h *RatingHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	err := h.registry.WriteText(w)
+	if err != nil {
+		h.logger.Errorf("error in writing metrics: %s", err)
+	}
+}
+
+// Recompute rebuilds the rating of the film given by film_id query parameter or of all the films without it.
+func (h *RatingHandler) Recompute(w http.ResponseWriter, r *http.Request) {
+	if !h.isAdmin(r) {
+		h.writeJSON(w, map[string]string{"message": "admin token required"}, http.StatusForbidden)
+		return
+	}
+	var filmID uint64
+	if filmIDParam := r.URL.Query().Get("film_id"); filmIDParam != "" {
+		var err error
+		filmID, err = strconv.ParseUint(filmIDParam, 10, 64)
+		if err != nil || filmID == 0 {
+			h.writeJSON(w, map[string]string{"message": "bad film id"}, http.StatusBadRequest)
+			return
+		}
+	}
+	changed, err := h.reconciler.Recompute(filmID)
+	if err != nil {
+		h.logger.Errorf("error in recomputing ratings: %s", err)
+		h.writeJSON(w, map[string]string{"message": "internal error"}, http.StatusInternalServerError)
+		return
+	}
+	h.writeJSON(w, map[string]int64{"changed_films": changed}, http.StatusOK)
+}
+
+func (h *RatingHandler) isAdmin(r *http.Request) bool {
+	if h.adminToken == "" {
+		return false
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) == 1
+}
+
+func (h *RatingHandler) writeJSON(w http.ResponseWriter, response interface{}, statusCode int) {
+	responseJSON, err := json.Marshal(response)
+	if err != nil {
+		h.logger.Errorf("error in marshalling response: %s", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_, err = w.Write(responseJSON)
+	if err != nil {
+		h.logger.Errorf("error in writing response body: %s", err)
+	}
+}
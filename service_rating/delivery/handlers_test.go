@@ -0,0 +1,126 @@
+package ratingdelivery_test
+
+import (
+	"errors"
+	"go.uber.org/zap"
+	ratingdelivery "kinopoisk/service_rating/delivery"
+	ratingmetrics "kinopoisk/service_rating/metrics"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type fakePinger struct {
+	err error
+}
+
+func (p *fakePinger) Ping() error {
+	return p.err
+}
+
+// fakeReconciler records the films it is asked to recompute.
+type fakeReconciler struct {
+	recomputed []uint64
+}
+
+func (r *fakeReconciler) Reconcile(done <-chan struct{}) {}
+
+func (r *fakeReconciler) Recompute(filmID uint64) (int64, error) {
+	r.recomputed = append(r.recomputed, filmID)
+	return 1, nil
+}
+
+func serve(handler *ratingdelivery.RatingHandler, request *http.Request) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	handler.Router().ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestReady(t *testing.T) {
+	cases := []struct {
+		name           string
+		dbErr          error
+		brokerErr      error
+		draining       bool
+		expectedStatus int
+		expectedCheck  string
+	}{
+		{name: "ready", expectedStatus: http.StatusOK, expectedCheck: `"status":"ok"`},
+		{name: "mysql is down", dbErr: errors.New("no connection"), expectedStatus: http.StatusServiceUnavailable,
+			expectedCheck: `"mysql":"no connection"`},
+		{name: "broker is down", brokerErr: errors.New("channel closed"), expectedStatus: http.StatusServiceUnavailable,
+			expectedCheck: `"broker":"channel closed"`},
+		{name: "draining", draining: true, expectedStatus: http.StatusServiceUnavailable,
+			expectedCheck: `"draining":"service is shutting down"`},
+	}
+	for _, testCase := range cases {
+		handler := ratingdelivery.NewRatingHandler(zap.NewNop().Sugar(), &fakePinger{err: testCase.dbErr},
+			&fakePinger{err: testCase.brokerErr}, &fakeReconciler{}, ratingmetrics.NewRegistry(), "")
+		if testCase.draining {
+			handler.SetDraining()
+		}
+		recorder := serve(handler, httptest.NewRequest(http.MethodGet, "/health/ready", nil))
+		if recorder.Code != testCase.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", testCase.name, testCase.expectedStatus, recorder.Code)
+		}
+		if !strings.Contains(recorder.Body.String(), testCase.expectedCheck) {
+			t.Errorf("%s: expected %s in %s", testCase.name, testCase.expectedCheck, recorder.Body.String())
+		}
+	}
+
+	// the service keeps answering that it is alive while it drains
+	handler := ratingdelivery.NewRatingHandler(zap.NewNop().Sugar(), &fakePinger{}, &fakePinger{}, &fakeReconciler{},
+		ratingmetrics.NewRegistry(), "")
+	handler.SetDraining()
+	if recorder := serve(handler, httptest.NewRequest(http.MethodGet, "/health/live", nil)); recorder.Code != http.StatusOK {
+		t.Errorf("expected live status %d while draining, got %d", http.StatusOK, recorder.Code)
+	}
+}
+
+func TestRecompute(t *testing.T) {
+	cases := []struct {
+		name               string
+		adminToken         string
+		authorization      string
+		target             string
+		expectedStatus     int
+		expectedRecomputed []uint64
+	}{
+		{name: "admin endpoints are disabled", adminToken: "", authorization: "Bearer ",
+			target: "/admin/recompute", expectedStatus: http.StatusForbidden},
+		{name: "no token", adminToken: "secret", target: "/admin/recompute", expectedStatus: http.StatusForbidden},
+		{name: "wrong token", adminToken: "secret", authorization: "Bearer secreT",
+			target: "/admin/recompute", expectedStatus: http.StatusForbidden},
+		{name: "token without bearer", adminToken: "secret", authorization: "secret",
+			target: "/admin/recompute", expectedStatus: http.StatusForbidden},
+		{name: "bad film id", adminToken: "secret", authorization: "Bearer secret",
+			target: "/admin/recompute?film_id=0", expectedStatus: http.StatusBadRequest},
+		{name: "one film", adminToken: "secret", authorization: "Bearer secret",
+			target: "/admin/recompute?film_id=7", expectedStatus: http.StatusOK, expectedRecomputed: []uint64{7}},
+		{name: "all films", adminToken: "secret", authorization: "Bearer secret",
+			target: "/admin/recompute", expectedStatus: http.StatusOK, expectedRecomputed: []uint64{0}},
+	}
+	for _, testCase := range cases {
+		reconciler := &fakeReconciler{}
+		handler := ratingdelivery.NewRatingHandler(zap.NewNop().Sugar(), &fakePinger{}, &fakePinger{}, reconciler,
+			ratingmetrics.NewRegistry(), testCase.adminToken)
+		request := httptest.NewRequest(http.MethodPost, testCase.target, nil)
+		if testCase.authorization != "" {
+			request.Header.Set("Authorization", testCase.authorization)
+		}
+		recorder := serve(handler, request)
+		if recorder.Code != testCase.expectedStatus {
+			t.Errorf("%s: expected status %d, got %d", testCase.name, testCase.expectedStatus, recorder.Code)
+		}
+		if len(reconciler.recomputed) != len(testCase.expectedRecomputed) {
+			t.Errorf("%s: expected recomputed films %v, got %v", testCase.name, testCase.expectedRecomputed, reconciler.recomputed)
+			continue
+		}
+		for i := range reconciler.recomputed {
+			if reconciler.recomputed[i] != testCase.expectedRecomputed[i] {
+				t.Errorf("%s: expected recomputed films %v, got %v", testCase.name, testCase.expectedRecomputed, reconciler.recomputed)
+			}
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package ratingmetrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultRegistry is the registry the metrics created by the package functions are added to.
+var DefaultRegistry = NewRegistry()
+
+type metric interface {
+	name() string
+	writeText(w io.Writer) error
+}
+
+// Registry writes its metrics in the Prometheus text exposition format.
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, registered := range r.metrics {
+		if registered.name() == m.name() {
+			panic("metric " + m.name() + " is registered twice")
+		}
+	}
+	r.metrics = append(r.metrics, m)
+}
+
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+	for _, m := range metrics {
+		err := m.writeText(w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHeader(w io.Writer, name, help, kind string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, escapeHelp(help), name, kind)
+	return err
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+// escapeHelp escapes a backslash and a line feed, a quote is kept as is in the help text.
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+type Counter struct {
+	metricName string
+	help       string
+	value      uint64
+}
+
+func NewCounter(name, help string) *Counter {
+	c := &Counter{metricName: name, help: help}
+	DefaultRegistry.register(c)
+	return c
+}
+
+func (c *Counter) Add(delta uint64) {
+	atomic.AddUint64(&c.value, delta)
+}
+
+func (c *Counter) Inc() {
+	c.Add(1)
+}
+
+func (c *Counter) name() string {
+	return c.metricName
+}
+
+func (c *Counter) writeText(w io.Writer) error {
+	err := writeHeader(w, c.metricName, c.help, "counter")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s %d\n", c.metricName, atomic.LoadUint64(&c.value))
+	return err
+}
+
+// CounterVec is a counter partitioned by the values of one label.
+type CounterVec struct {
+	metricName string
+	help       string
+	label      string
+	mu         sync.Mutex
+	values     map[string]uint64
+}
+
+func NewCounterVec(name, help, label string) *CounterVec {
+	c := &CounterVec{metricName: name, help: help, label: label, values: make(map[string]uint64)}
+	DefaultRegistry.register(c)
+	return c
+}
+
+func (c *CounterVec) Inc(labelValue string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[labelValue]++
+}
+
+func (c *CounterVec) name() string {
+	return c.metricName
+}
+
+func (c *CounterVec) writeText(w io.Writer) error {
+	err := writeHeader(w, c.metricName, c.help, "counter")
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	labelValues := make([]string, 0, len(c.values))
+	for labelValue := range c.values {
+		labelValues = append(labelValues, labelValue)
+	}
+	sort.Strings(labelValues)
+	lines := make([]string, 0, len(labelValues))
+	for _, labelValue := range labelValues {
+		lines = append(lines, fmt.Sprintf("%s{%s=\"%s\"} %d\n", c.metricName, c.label, escapeLabel(labelValue), c.values[labelValue]))
+	}
+	c.mu.Unlock()
+	for _, line := range lines {
+		_, err = io.WriteString(w, line)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GaugeFunc is a gauge whose value is got on every scrape. A failed gauge is left out of the scrape.
+type GaugeFunc struct {
+	metricName string
+	help       string
+	value      func() (float64, error)
+}
+
+func NewGaugeFunc(name, help string, value func() (float64, error)) *GaugeFunc {
+	g := &GaugeFunc{metricName: name, help: help, value: value}
+	DefaultRegistry.register(g)
+	return g
+}
+
+func (g *GaugeFunc) name() string {
+	return g.metricName
+}
+
+func (g *GaugeFunc) writeText(w io.Writer) error {
+	value, err := g.value()
+	if err != nil {
+		return nil
+	}
+	err = writeHeader(w, g.metricName, g.help, "gauge")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s %s\n", g.metricName, formatValue(value))
+	return err
+}
+
+// Histogram counts observations in cumulative buckets with the given upper bounds.
+type Histogram struct {
+	metricName string
+	help       string
+	bounds     []float64
+	mu         sync.Mutex
+	counts     []uint64
+	sum        float64
+	count      uint64
+}
+
+func NewHistogram(name, help string, bounds []float64) *Histogram {
+	h := &Histogram{
+		metricName: name,
+		help:       help,
+		bounds:     bounds,
+		counts:     make([]uint64, len(bounds)),
+	}
+	DefaultRegistry.register(h)
+	return h
+}
+
+func (h *Histogram) Observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i, bound := range h.bounds {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+func (h *Histogram) name() string {
+	return h.metricName
+}
+
+func (h *Histogram) writeText(w io.Writer) error {
+	err := writeHeader(w, h.metricName, h.help, "histogram")
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	var text strings.Builder
+	for i, bound := range h.bounds {
+		fmt.Fprintf(&text, "%s_bucket{le=\"%s\"} %d\n", h.metricName, formatValue(bound), h.counts[i])
+	}
+	fmt.Fprintf(&text, "%s_bucket{le=\"+Inf\"} %d\n", h.metricName, h.count)
+	fmt.Fprintf(&text, "%s_sum %s\n", h.metricName, formatValue(h.sum))
+	fmt.Fprintf(&text, "%s_count %d\n", h.metricName, h.count)
+	h.mu.Unlock()
+	_, err = io.WriteString(w, text.String())
+	return err
+}
@@ -0,0 +1,100 @@
+package ratingmetrics
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func writeRegistry(t *testing.T, metrics ...metric) string {
+	registry := NewRegistry()
+	for _, m := range metrics {
+		registry.register(m)
+	}
+	var text strings.Builder
+	err := registry.WriteText(&text)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return text.String()
+}
+
+func TestWriteText(t *testing.T) {
+	counter := &Counter{metricName: "events_total", help: "Events."}
+	counter.Add(2)
+	counter.Inc()
+	histogram := &Histogram{
+		metricName: "latency_seconds",
+		help:       "Latency.",
+		bounds:     []float64{0.1, 1},
+		counts:     make([]uint64, 2),
+	}
+	histogram.Observe(0.05)
+	histogram.Observe(0.5)
+	histogram.Observe(5)
+	gauge := &GaugeFunc{metricName: "queue_depth", help: "Depth.", value: func() (float64, error) {
+		return 7, nil
+	}}
+
+	expected := "# HELP events_total Events.\n" +
+		"# TYPE events_total counter\n" +
+		"events_total 3\n" +
+		"# HELP latency_seconds Latency.\n" +
+		"# TYPE latency_seconds histogram\n" +
+		"latency_seconds_bucket{le=\"0.1\"} 1\n" +
+		"latency_seconds_bucket{le=\"1\"} 2\n" +
+		"latency_seconds_bucket{le=\"+Inf\"} 3\n" +
+		"latency_seconds_sum 5.55\n" +
+		"latency_seconds_count 3\n" +
+		"# HELP queue_depth Depth.\n" +
+		"# TYPE queue_depth gauge\n" +
+		"queue_depth 7\n"
+	if text := writeRegistry(t, counter, histogram, gauge); text != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, text)
+	}
+}
+
+func TestWriteTextEscaping(t *testing.T) {
+	counterVec := &CounterVec{
+		metricName: "errors_total",
+		help:       "Errors by \"reason\", a \\ or a line\nfeed is escaped.",
+		label:      "reason",
+		values:     make(map[string]uint64),
+	}
+	counterVec.Inc("bad \"json\"")
+	counterVec.Inc("C:\\path")
+	counterVec.Inc("line\nfeed")
+	counterVec.Inc("bad \"json\"")
+
+	expected := "# HELP errors_total Errors by \"reason\", a \\\\ or a line\\nfeed is escaped.\n" +
+		"# TYPE errors_total counter\n" +
+		"errors_total{reason=\"C:\\\\path\"} 1\n" +
+		"errors_total{reason=\"bad \\\"json\\\"\"} 2\n" +
+		"errors_total{reason=\"line\\nfeed\"} 1\n"
+	if text := writeRegistry(t, counterVec); text != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, text)
+	}
+}
+
+func TestWriteTextFailedGauge(t *testing.T) {
+	failed := &GaugeFunc{metricName: "queue_depth", help: "Depth.", value: func() (float64, error) {
+		return 0, errors.New("broker is down")
+	}}
+	counter := &Counter{metricName: "events_total", help: "Events."}
+
+	expected := "# HELP events_total Events.\n" +
+		"# TYPE events_total counter\n" +
+		"events_total 0\n"
+	if text := writeRegistry(t, failed, counter); text != expected {
+		t.Errorf("expected the failed gauge to be left out, got\n%s", text)
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("metric registered twice does not panic")
+		}
+	}()
+	writeRegistry(t, &Counter{metricName: "events_total"}, &Counter{metricName: "events_total"})
+}
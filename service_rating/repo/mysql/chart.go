@@ -0,0 +1,101 @@
+package ratingservicerepo
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// weightedRatingUpdate sets the IMDb-style weighted rating (v*R + m*C) / (v + m),
+// where v*R is the sum of marks of the film, m is the prior votes and C is the prior mean.
+const weightedRatingUpdate = "UPDATE films SET weighted_rating = " +
+	"CASE WHEN num_of_marks + ? > 0 THEN (sum_mark + ? * ?) / (num_of_marks + ?) ELSE 0 END"
+
+type ChartsDB interface {
+	RecomputeWeightedRatings() error
+	MaterializeCharts(minVotes, chartSize uint64) error
+}
+
+// chartScope is a kind of chart variant, value is the sql expression the films are partitioned by.
+type chartScope struct {
+	name  string
+	value string
+	join  string
+}
+
+var chartScopes = []chartScope{
+	{
+		name:  "all",
+		value: "''",
+	},
+	{
+		name:  "genre",
+		value: "g.name",
+		join:  " JOIN film_genres fg ON fg.film_id = f.id JOIN genres g ON g.id = fg.genre_id",
+	},
+	{
+		name:  "country",
+		value: "f.country",
+	},
+	{
+		name:  "decade",
+		value: "CAST(FLOOR(YEAR(f.date_of_release) / 10) * 10 AS CHAR)",
+	},
+}
+
+var chartOrders = map[string]string{
+	"top":    "DESC",
+	"bottom": "ASC",
+}
+
+// RecomputeWeightedRatings is needed after the prior has been changed, usual review events keep
+// the weighted rating of the film up to date by themselves.
+func (r *RatingChangerMySQL) RecomputeWeightedRatings() error {
+	_, err := r.db.Exec(
+		weightedRatingUpdate,
+		r.prior.Votes,
+		r.prior.Mean,
+		r.prior.Votes,
+		r.prior.Votes,
+	)
+	return err
+}
+
+// MaterializeCharts rebuilds all the charts in one transaction so readers never see them half-built.
+func (r *RatingChangerMySQL) MaterializeCharts(minVotes, chartSize uint64) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM film_charts")
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	for chart, order := range chartOrders {
+		for _, scope := range chartScopes {
+			err = insertChart(tx, chart, order, scope, minVotes, chartSize)
+			if err != nil {
+				r.rollback(tx)
+				r.logger.Errorf("error in materializing %s chart by %s: %s", chart, scope.name, err)
+				return err
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func insertChart(tx *sql.Tx, chart, order string, scope chartScope, minVotes, chartSize uint64) error {
+	query := fmt.Sprintf(
+		"INSERT INTO film_charts (`chart`, `scope`, `scope_value`, `place`, `film_id`, `weighted_rating`) "+
+			"SELECT ?, ?, scope_value, place, film_id, weighted_rating FROM ("+
+			"SELECT f.id AS film_id, f.weighted_rating, %s AS scope_value, "+
+			"ROW_NUMBER() OVER (PARTITION BY %s ORDER BY f.weighted_rating %s, f.num_of_marks DESC, f.id) AS place "+
+			"FROM films f%s WHERE f.num_of_marks >= ?) ranked WHERE place <= ?",
+		scope.value,
+		scope.value,
+		order,
+		scope.join,
+	)
+	_, err := tx.Exec(query, chart, scope.name, minVotes, chartSize)
+	return err
+}
@@ -0,0 +1,109 @@
+package ratingservicerepo_test
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+	ratingservicerepo "kinopoisk/service_rating/repo/mysql"
+	"regexp"
+	"testing"
+)
+
+// weightedRatingFormula is (v*R + m*C) / (v + m) with v*R = sum_mark and v = num_of_marks,
+// a film without marks and without the prior gets 0 instead of the division by zero.
+var weightedRatingFormula = regexp.QuoteMeta("UPDATE films SET weighted_rating = " +
+	"CASE WHEN num_of_marks + ? > 0 THEN (sum_mark + ? * ?) / (num_of_marks + ?) ELSE 0 END")
+
+func TestRecomputeWeightedRatings(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := ratingservicerepo.NewRatingChangerMySQL(db, zap.NewNop().Sugar(), testPrior)
+
+	// all the films are recomputed with m = 10 prior votes of C = 6
+	mock.
+		ExpectExec(weightedRatingFormula+"$").
+		WithArgs(10, 6.0, 10, 10).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err = dbRepo.RecomputeWeightedRatings()
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+// chartQuery matches the insert of one chart: films with at least min votes, ranked by the weighted rating
+// in the order of the chart, the tie goes to the film with more votes and then to the older film.
+func chartQuery(order string) string {
+	return regexp.QuoteMeta("ROW_NUMBER() OVER (PARTITION BY ") + ".+" +
+		regexp.QuoteMeta(fmt.Sprintf(" ORDER BY f.weighted_rating %s, f.num_of_marks DESC, f.id) AS place ", order)) +
+		".+" + regexp.QuoteMeta("WHERE f.num_of_marks >= ?) ranked WHERE place <= ?")
+}
+
+func TestMaterializeCharts(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := ratingservicerepo.NewRatingChangerMySQL(db, zap.NewNop().Sugar(), testPrior)
+	// the charts are built in the map order
+	mock.MatchExpectationsInOrder(false)
+
+	mock.ExpectBegin()
+	mock.
+		ExpectExec("DELETE FROM film_charts").
+		WillReturnResult(sqlmock.NewResult(0, 40))
+	for chart, order := range map[string]string{"top": "DESC", "bottom": "ASC"} {
+		for _, scope := range []string{"all", "genre", "country", "decade"} {
+			mock.
+				ExpectExec(chartQuery(order)).
+				WithArgs(chart, scope, 50, 100).
+				WillReturnResult(sqlmock.NewResult(0, 100))
+		}
+	}
+	mock.ExpectCommit()
+
+	err = dbRepo.MaterializeCharts(50, 100)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMaterializeChartsRollback(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := ratingservicerepo.NewRatingChangerMySQL(db, zap.NewNop().Sugar(), testPrior)
+
+	// the failed chart leaves the old charts in place
+	mock.ExpectBegin()
+	mock.
+		ExpectExec("DELETE FROM film_charts").
+		WillReturnResult(sqlmock.NewResult(0, 40))
+	mock.
+		ExpectExec("INSERT INTO film_charts").
+		WillReturnError(errors.New("deadlock found"))
+	mock.ExpectRollback()
+
+	err = dbRepo.MaterializeCharts(50, 100)
+	if err == nil {
+		t.Errorf("expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
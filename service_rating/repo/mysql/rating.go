@@ -2,72 +2,339 @@ package ratingservicerepo
 
 import (
 	"database/sql"
+	"errors"
 	"go.uber.org/zap"
+	"sort"
 )
 
+// FreshMark is the lowest mark of a critic review counted as fresh.
+const FreshMark = 6
+
 type RatingChangerDB interface {
-	ChangeRatingAfterDeleteReview(oldMark uint32, reviewID uint64) error
-	ChangeRatingAfterUpdateReview(oldMark, newMark uint32, reviewID uint64) error
-	ChangeRatingAddReview(newMark uint32, reviewID uint64) error
+	ApplyRatingChangesRepo(filmID uint64, changes []*RatingChange) error
+	GetReviewFilmRepo(reviewID uint64) (uint64, error)
+}
+
+// RatingEvent identifies the review change. Events published before event ids were introduced
+// have an empty ID and may have no FilmID.
+type RatingEvent struct {
+	ID       string
+	FilmID   uint64
+	ReviewID uint64
+}
+
+// RatingChange is a change of one review of the film. OldMark is nil for an added review
+// and NewMark is nil for a deleted one. The marks of a review by a critic also count toward the critic score.
+// A change only adds and subtracts the marks it carries and never reads the current mark of the review,
+// so the changes of a film give the same result in any order: a retried event may come after the newer ones.
+type RatingChange struct {
+	Event    *RatingEvent
+	OldMark  *MarkInfo
+	NewMark  *MarkInfo
+	ByCritic bool
+}
+
+// criticDelta is the change of the critic score of the film.
+type criticDelta struct {
+	sum   int64
+	count int64
+	fresh int64
+}
+
+// MarkInfo is a mark of the review with the data needed for the film statistics.
+type MarkInfo struct {
+	Mark      uint32
+	HasText   bool
+	CreatedAt string
+	// SubScores are the marks of the rated criteria only
+	SubScores map[string]uint32
+}
+
+// criterionDelta is the change of the sum and of the number of the marks of one criterion of the film.
+type criterionDelta struct {
+	sum   int64
+	count int64
+}
+
+// RatingPrior is the prior of the weighted rating: every film is rated as if it also had
+// Votes marks equal to Mean.
+type RatingPrior struct {
+	Mean  float64
+	Votes uint64
 }
 
 type RatingChangerMySQL struct {
 	db     *sql.DB
 	logger *zap.SugaredLogger
+	prior  *RatingPrior
 }
 
-func NewRatingChangerMySQL(db *sql.DB, logger *zap.SugaredLogger) *RatingChangerMySQL {
+func NewRatingChangerMySQL(db *sql.DB, logger *zap.SugaredLogger, prior *RatingPrior) *RatingChangerMySQL {
 	return &RatingChangerMySQL{
 		db:     db,
 		logger: logger,
+		prior:  prior,
 	}
 }
 
-func (r *RatingChangerMySQL) ChangeRatingAfterDeleteReview(oldMark uint32, filmID uint64) error {
-	_, err := r.db.Exec(
+// ApplyRatingChangesRepo applies the changes of the film in one transaction with a single update of the film,
+// so a burst of reviews of one film costs one row lock. Already processed events are skipped.
+func (r *RatingChangerMySQL) ApplyRatingChangesRepo(filmID uint64, changes []*RatingChange) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	var sumDelta, countDelta int64
+	criteriaDeltas := make(map[string]*criterionDelta)
+	critic := &criticDelta{}
+	applied := 0
+	for _, change := range changes {
+		var isNew bool
+		isNew, err = r.markEventProcessed(tx, change.Event, filmID)
+		if err != nil {
+			r.rollback(tx)
+			return err
+		}
+		if !isNew {
+			continue
+		}
+		err = applyMarkChange(tx, filmID, change)
+		if err != nil {
+			r.rollback(tx)
+			r.logger.Errorf("error in changing stats of film %d: %s", filmID, err)
+			return err
+		}
+		if change.OldMark != nil {
+			sumDelta -= int64(change.OldMark.Mark)
+			countDelta--
+		}
+		if change.NewMark != nil {
+			sumDelta += int64(change.NewMark.Mark)
+			countDelta++
+		}
+		addCriteriaDeltas(criteriaDeltas, change.OldMark, -1)
+		addCriteriaDeltas(criteriaDeltas, change.NewMark, 1)
+		if change.ByCritic {
+			critic.add(change.OldMark, -1)
+			critic.add(change.NewMark, 1)
+		}
+		applied++
+	}
+	if applied == 0 {
+		r.rollback(tx)
+		return nil
+	}
+	// single-table UPDATE assignments are evaluated from left to right, so rating uses the new sum and number
+	_, err = tx.Exec(
 		`UPDATE films 
                 SET 
-                   sum_mark = sum_mark - ?,
-                num_of_marks = num_of_marks - 1,
+                   sum_mark = sum_mark + ?,
+                num_of_marks = num_of_marks + ?,
                 rating = CASE 
-                              WHEN num_of_marks > 1 THEN (sum_mark - ?) / (num_of_marks - 1)
+                              WHEN num_of_marks > 0 THEN sum_mark / num_of_marks
+                            ELSE 0
+                          END,
+                critic_sum_mark = critic_sum_mark + ?,
+                critic_num_of_marks = critic_num_of_marks + ?,
+                critic_num_fresh = critic_num_fresh + ?,
+                critic_rating = CASE
+                              WHEN critic_num_of_marks > 0 THEN critic_sum_mark / critic_num_of_marks
+                            ELSE 0
+                          END,
+                critic_fresh_percent = CASE
+                              WHEN critic_num_of_marks > 0 THEN 100 * critic_num_fresh / critic_num_of_marks
                             ELSE 0
                           END
                 WHERE id = ?`,
-		oldMark,
-		oldMark,
+		sumDelta,
+		countDelta,
+		critic.sum,
+		critic.count,
+		critic.fresh,
 		filmID,
 	)
+	if err == nil {
+		err = r.changeWeightedRating(tx, filmID)
+	}
+	if err == nil {
+		err = changeCriteriaRatings(tx, filmID, criteriaDeltas)
+	}
 	if err != nil {
-		r.logger.Errorf("error in changing rating after delete review")
+		r.rollback(tx)
+		r.logger.Errorf("error in changing rating of film %d: %s", filmID, err)
+		return err
 	}
-	return err
+	return tx.Commit()
 }
 
-func (r *RatingChangerMySQL) ChangeRatingAfterUpdateReview(oldMark, newMark uint32, reviewID uint64) error {
-	_, err := r.db.Exec(
-		"UPDATE films SET sum_mark = sum_mark + ? - ?, rating = (sum_mark + ? - ?) / num_of_marks WHERE id in (SELECT film_id from reviews WHERE id = ?)",
-		newMark,
-		oldMark,
-		newMark,
-		oldMark,
-		reviewID,
-	)
+// markEventProcessed marks the event as processed in the transaction of its change, so the event
+// is applied exactly once even if it is redelivered. It returns false if the event has been already processed.
+func (r *RatingChangerMySQL) markEventProcessed(tx *sql.Tx, event *RatingEvent, filmID uint64) (bool, error) {
+	if event.ID == "" {
+		r.logger.Warnf("rating event for review %d has no id, it can not be deduplicated", event.ReviewID)
+		return true, nil
+	}
+	res, err := tx.Exec("INSERT IGNORE INTO processed_rating_events (`event_id`, `film_id`) VALUES (?, ?)", event.ID, filmID)
+	if err != nil {
+		return false, err
+	}
+	inserted, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if inserted == 0 {
+		r.logger.Infof("rating event %s has been already processed", event.ID)
+		return false, nil
+	}
+	return true, nil
+}
+
+// GetReviewFilmRepo returns 0 if the review has been already deleted.
+func (r *RatingChangerMySQL) GetReviewFilmRepo(reviewID uint64) (uint64, error) {
+	var filmID uint64
+	err := r.db.QueryRow("SELECT film_id FROM reviews WHERE id = ?", reviewID).Scan(&filmID)
+	if errors.Is(err, sql.ErrNoRows) {
+		r.logger.Errorf("no review with id %d to change rating", reviewID)
+		return 0, nil
+	}
+	return filmID, err
+}
+
+func (r *RatingChangerMySQL) rollback(tx *sql.Tx) {
+	err := tx.Rollback()
 	if err != nil {
-		r.logger.Errorf("error in changing rating after update review: %s", err)
+		r.logger.Errorf("error in transaction rollback: %s", err)
 	}
+}
+
+// changeWeightedRating recomputes the weighted rating of the film from its already updated marks.
+func (r *RatingChangerMySQL) changeWeightedRating(tx *sql.Tx, filmID uint64) error {
+	_, err := tx.Exec(
+		weightedRatingUpdate+" WHERE id = ?",
+		r.prior.Votes,
+		r.prior.Mean,
+		r.prior.Votes,
+		r.prior.Votes,
+		filmID,
+	)
 	return err
 }
 
-func (r *RatingChangerMySQL) ChangeRatingAddReview(newMark uint32, reviewID uint64) error {
-	_, err := r.db.Exec(
-		"UPDATE films SET sum_mark = sum_mark + ?, num_of_marks = num_of_marks + 1, rating = (sum_mark + ?) / (num_of_marks + 1) WHERE id in (SELECT film_id from reviews WHERE id = ?)",
-		newMark,
-		newMark,
-		reviewID,
+// applyMarkChange changes the mark statistics of the film, the month of a review is counted when it is added
+// or deleted only.
+func applyMarkChange(tx *sql.Tx, filmID uint64, change *RatingChange) error {
+	oldMark, newMark := change.OldMark, change.NewMark
+	if oldMark != nil && newMark != nil && oldMark.Mark == newMark.Mark && oldMark.HasText == newMark.HasText {
+		return nil
+	}
+	if oldMark != nil {
+		err := changeMarkStats(tx, filmID, oldMark, -1)
+		if err == nil && newMark == nil {
+			err = changeMonthStats(tx, filmID, oldMark, -1)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if newMark != nil {
+		err := changeMarkStats(tx, filmID, newMark, 1)
+		if err == nil && oldMark == nil {
+			err = changeMonthStats(tx, filmID, newMark, 1)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changeMarkStats keeps the mark distribution, the sum of squared marks and the number of reviews with text of the film.
+func changeMarkStats(tx *sql.Tx, filmID uint64, mark *MarkInfo, delta int) error {
+	_, err := tx.Exec(
+		"INSERT INTO film_mark_distribution (`film_id`, `mark`, `num_of_marks`) VALUES (?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE num_of_marks = num_of_marks + VALUES(num_of_marks)",
+		filmID,
+		mark.Mark,
+		delta,
 	)
 	if err != nil {
-		r.logger.Errorf("error in changing rating after add review")
+		return err
 	}
+	withText := 0
+	if mark.HasText {
+		withText = delta
+	}
+	_, err = tx.Exec(
+		"INSERT INTO film_stats (`film_id`, `sum_mark_squares`, `num_with_text`) VALUES (?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE sum_mark_squares = sum_mark_squares + VALUES(sum_mark_squares), num_with_text = num_with_text + VALUES(num_with_text)",
+		filmID,
+		delta*int(mark.Mark*mark.Mark),
+		withText,
+	)
+	return err
+}
+
+// changeMonthStats counts reviews of the film by the month they were written in.
+func changeMonthStats(tx *sql.Tx, filmID uint64, mark *MarkInfo, delta int) error {
+	_, err := tx.Exec(
+		"INSERT INTO film_review_months (`film_id`, `month`, `num_of_reviews`) VALUES (?, DATE_FORMAT(COALESCE(NULLIF(?, ''), NOW()), '%Y-%m-01'), ?) "+
+			"ON DUPLICATE KEY UPDATE num_of_reviews = num_of_reviews + VALUES(num_of_reviews)",
+		filmID,
+		mark.CreatedAt,
+		delta,
+	)
 	return err
 }
+
+func (d *criticDelta) add(mark *MarkInfo, sign int64) {
+	if mark == nil {
+		return
+	}
+	d.sum += sign * int64(mark.Mark)
+	d.count += sign
+	if mark.Mark >= FreshMark {
+		d.fresh += sign
+	}
+}
+
+func addCriteriaDeltas(deltas map[string]*criterionDelta, mark *MarkInfo, sign int64) {
+	if mark == nil {
+		return
+	}
+	for criterion, subScore := range mark.SubScores {
+		delta, ok := deltas[criterion]
+		if !ok {
+			delta = &criterionDelta{}
+			deltas[criterion] = delta
+		}
+		delta.sum += sign * int64(subScore)
+		delta.count += sign
+	}
+}
+
+// changeCriteriaRatings keeps the average mark of every criterion of the film, the criteria are changed
+// in the same order by every transaction so that they do not deadlock.
+func changeCriteriaRatings(tx *sql.Tx, filmID uint64, deltas map[string]*criterionDelta) error {
+	criteria := make([]string, 0, len(deltas))
+	for criterion, delta := range deltas {
+		if delta.sum != 0 || delta.count != 0 {
+			criteria = append(criteria, criterion)
+		}
+	}
+	sort.Strings(criteria)
+	for _, criterion := range criteria {
+		_, err := tx.Exec(
+			"INSERT INTO film_criteria_ratings (`film_id`, `criterion`, `sum_mark`, `num_of_marks`, `rating`) VALUES (?, ?, ?, ?, 0) "+
+				"ON DUPLICATE KEY UPDATE sum_mark = sum_mark + VALUES(sum_mark), num_of_marks = num_of_marks + VALUES(num_of_marks), "+
+				"rating = CASE WHEN num_of_marks > 0 THEN sum_mark / num_of_marks ELSE 0 END",
+			filmID,
+			criterion,
+			deltas[criterion].sum,
+			deltas[criterion].count,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
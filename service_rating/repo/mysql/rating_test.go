@@ -0,0 +1,253 @@
+package ratingservicerepo_test
+
+import (
+	"go.uber.org/zap"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+	ratingservicerepo "kinopoisk/service_rating/repo/mysql"
+	"regexp"
+	"testing"
+)
+
+var testPrior = &ratingservicerepo.RatingPrior{
+	Mean:  6,
+	Votes: 10,
+}
+
+func TestApplyRatingChangesDuplicateEvent(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := ratingservicerepo.NewRatingChangerMySQL(db, zap.NewNop().Sugar(), testPrior)
+
+	// the only event has been already processed, the film is not changed
+	mock.ExpectBegin()
+	mock.
+		ExpectExec("INSERT IGNORE INTO processed_rating_events").
+		WithArgs("event-1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = dbRepo.ApplyRatingChangesRepo(1, []*ratingservicerepo.RatingChange{
+		{
+			Event:   &ratingservicerepo.RatingEvent{ID: "event-1", FilmID: 1, ReviewID: 1},
+			NewMark: &ratingservicerepo.MarkInfo{Mark: 8},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+
+	// the redelivered event is skipped, only the new one changes the marks of the film
+	mock.ExpectBegin()
+	mock.
+		ExpectExec("INSERT IGNORE INTO processed_rating_events").
+		WithArgs("event-1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.
+		ExpectExec("INSERT IGNORE INTO processed_rating_events").
+		WithArgs("event-2", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_mark_distribution").
+		WithArgs(1, 5, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_stats").
+		WithArgs(1, 25, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_review_months").
+		WithArgs(1, "", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("UPDATE films").
+		WithArgs(5, 1, 0, 0, 0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("UPDATE films SET weighted_rating").
+		WithArgs(10, 6.0, 10, 10, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = dbRepo.ApplyRatingChangesRepo(1, []*ratingservicerepo.RatingChange{
+		{
+			Event:   &ratingservicerepo.RatingEvent{ID: "event-1", FilmID: 1, ReviewID: 1},
+			NewMark: &ratingservicerepo.MarkInfo{Mark: 8},
+		},
+		{
+			Event:   &ratingservicerepo.RatingEvent{ID: "event-2", FilmID: 1, ReviewID: 2},
+			NewMark: &ratingservicerepo.MarkInfo{Mark: 5},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+}
+
+func TestFixRatingDrift(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := ratingservicerepo.NewRatingChangerMySQL(db, zap.NewNop().Sugar(), testPrior)
+	drift := &ratingservicerepo.RatingDrift{
+		FilmID:             1,
+		SumMark:            20,
+		NumOfMarks:         3,
+		ExpectedSumMark:    15,
+		ExpectedNumOfMarks: 2,
+	}
+
+	// the counters have changed since the drift was found
+	mock.ExpectBegin()
+	mock.
+		ExpectExec("UPDATE films SET sum_mark").
+		WithArgs(15, 2, 2, 15, 2, 1, 20, 3).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	fixed, err := dbRepo.FixRatingDriftRepo(drift)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if fixed {
+		t.Errorf("expected the drift not to be fixed")
+		return
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+
+	// the counters and the mark statistics of the film are rebuilt together
+	mock.ExpectBegin()
+	mock.
+		ExpectExec("UPDATE films SET sum_mark").
+		WithArgs(15, 2, 2, 15, 2, 1, 20, 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("UPDATE films SET weighted_rating").
+		WithArgs(10, 6.0, 10, 10, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, statsQuery := range []string{
+		"DELETE FROM film_mark_distribution",
+		"INSERT INTO film_mark_distribution",
+		"DELETE FROM film_stats",
+		"INSERT INTO film_stats",
+		"DELETE FROM film_review_months",
+		"INSERT INTO film_review_months",
+		"DELETE FROM film_criteria_ratings",
+		"INSERT INTO film_criteria_ratings",
+	} {
+		mock.
+			ExpectExec(regexp.QuoteMeta(statsQuery)).
+			WithArgs(1, 1).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	mock.ExpectCommit()
+
+	fixed, err = dbRepo.FixRatingDriftRepo(drift)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if !fixed {
+		t.Errorf("expected the drift to be fixed")
+		return
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+}
+
+func TestApplyRatingChangesNetDelta(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := ratingservicerepo.NewRatingChangerMySQL(db, zap.NewNop().Sugar(), testPrior)
+
+	// the update of the review 1 comes before its retried addition, the film gets +3 and one mark anyway
+	mock.ExpectBegin()
+	mock.
+		ExpectExec("INSERT IGNORE INTO processed_rating_events").
+		WithArgs("event-2", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_mark_distribution").
+		WithArgs(1, 8, -1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_stats").
+		WithArgs(1, -64, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_mark_distribution").
+		WithArgs(1, 3, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_stats").
+		WithArgs(1, 9, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT IGNORE INTO processed_rating_events").
+		WithArgs("event-1", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_mark_distribution").
+		WithArgs(1, 8, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_stats").
+		WithArgs(1, 64, 0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("INSERT INTO film_review_months").
+		WithArgs(1, "", 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("UPDATE films").
+		WithArgs(3, 1, 0, 0, 0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec("UPDATE films SET weighted_rating").
+		WithArgs(10, 6.0, 10, 10, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err = dbRepo.ApplyRatingChangesRepo(1, []*ratingservicerepo.RatingChange{
+		{
+			Event:   &ratingservicerepo.RatingEvent{ID: "event-2", FilmID: 1, ReviewID: 1},
+			OldMark: &ratingservicerepo.MarkInfo{Mark: 8},
+			NewMark: &ratingservicerepo.MarkInfo{Mark: 3},
+		},
+		{
+			Event:   &ratingservicerepo.RatingEvent{ID: "event-1", FilmID: 1, ReviewID: 1},
+			NewMark: &ratingservicerepo.MarkInfo{Mark: 8},
+		},
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+}
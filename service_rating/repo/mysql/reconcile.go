@@ -0,0 +1,180 @@
+package ratingservicerepo
+
+import (
+	"database/sql"
+	"time"
+)
+
+type RatingReconcilerDB interface {
+	FindRatingDriftRepo() ([]*RatingDrift, error)
+	FixRatingDriftRepo(drift *RatingDrift) (bool, error)
+	DeleteProcessedEventsRepo(olderThan time.Duration) (int64, error)
+	RecomputeRatingsRepo(filmID uint64) (int64, error)
+}
+
+// RatingDrift is a film whose counters differ from the ones recomputed from its published reviews.
+type RatingDrift struct {
+	FilmID             uint64
+	SumMark            int64
+	NumOfMarks         int64
+	ExpectedSumMark    int64
+	ExpectedNumOfMarks int64
+}
+
+func (r *RatingChangerMySQL) FindRatingDriftRepo() ([]*RatingDrift, error) {
+	rows, err := r.db.Query(
+		"SELECT f.id, f.sum_mark, f.num_of_marks, COALESCE(rv.sum_mark, 0), COALESCE(rv.num_of_marks, 0) FROM films f " +
+			"LEFT JOIN (SELECT film_id, SUM(mark) AS sum_mark, COUNT(*) AS num_of_marks FROM reviews WHERE status = 'published' GROUP BY film_id) rv " +
+			"ON rv.film_id = f.id WHERE f.sum_mark <> COALESCE(rv.sum_mark, 0) OR f.num_of_marks <> COALESCE(rv.num_of_marks, 0)",
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	drifts := make([]*RatingDrift, 0)
+	for rows.Next() {
+		drift := &RatingDrift{}
+		err = rows.Scan(&drift.FilmID, &drift.SumMark, &drift.NumOfMarks, &drift.ExpectedSumMark, &drift.ExpectedNumOfMarks)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts, nil
+}
+
+// FixRatingDriftRepo overwrites the counters of the film only if they have not changed since the drift was found,
+// it returns false otherwise. The mark statistics of the film have drifted along with the counters,
+// so they are rebuilt from the published reviews in the same transaction.
+func (r *RatingChangerMySQL) FixRatingDriftRepo(drift *RatingDrift) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	res, err := tx.Exec(
+		"UPDATE films SET sum_mark = ?, num_of_marks = ?, rating = CASE WHEN ? > 0 THEN ? / ? ELSE 0 END "+
+			"WHERE id = ? AND sum_mark = ? AND num_of_marks = ?",
+		drift.ExpectedSumMark,
+		drift.ExpectedNumOfMarks,
+		drift.ExpectedNumOfMarks,
+		drift.ExpectedSumMark,
+		drift.ExpectedNumOfMarks,
+		drift.FilmID,
+		drift.SumMark,
+		drift.NumOfMarks,
+	)
+	if err != nil {
+		r.rollback(tx)
+		return false, err
+	}
+	fixed, err := res.RowsAffected()
+	if err != nil {
+		r.rollback(tx)
+		return false, err
+	}
+	if fixed == 0 {
+		r.rollback(tx)
+		return false, nil
+	}
+	err = r.changeWeightedRating(tx, drift.FilmID)
+	for i := 0; err == nil && i < len(recomputeStats); i++ {
+		_, err = tx.Exec(recomputeStats[i], drift.FilmID, drift.FilmID)
+	}
+	if err != nil {
+		r.rollback(tx)
+		return false, err
+	}
+	return true, tx.Commit()
+}
+
+func (r *RatingChangerMySQL) DeleteProcessedEventsRepo(olderThan time.Duration) (int64, error) {
+	res, err := r.db.Exec("DELETE FROM processed_rating_events WHERE processed_at < NOW() - INTERVAL ? SECOND", int64(olderThan.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// recomputeStats rebuilds the mark statistics of the films from their published reviews,
+// every query takes the film id twice: 0 selects all the films.
+var recomputeStats = []string{
+	"DELETE FROM film_mark_distribution WHERE (? = 0 OR film_id = ?)",
+	"INSERT INTO film_mark_distribution (`film_id`, `mark`, `num_of_marks`) " +
+		"SELECT film_id, mark, COUNT(*) FROM reviews WHERE status = 'published' AND (? = 0 OR film_id = ?) GROUP BY film_id, mark",
+	"DELETE FROM film_stats WHERE (? = 0 OR film_id = ?)",
+	"INSERT INTO film_stats (`film_id`, `sum_mark_squares`, `num_with_text`) " +
+		"SELECT film_id, SUM(mark * mark), SUM(COALESCE(comment, '') <> '') FROM reviews " +
+		"WHERE status = 'published' AND (? = 0 OR film_id = ?) GROUP BY film_id",
+	"DELETE FROM film_review_months WHERE (? = 0 OR film_id = ?)",
+	"INSERT INTO film_review_months (`film_id`, `month`, `num_of_reviews`) " +
+		"SELECT film_id, DATE_FORMAT(created_at, '%Y-%m-01') AS month, COUNT(*) FROM reviews " +
+		"WHERE status = 'published' AND (? = 0 OR film_id = ?) GROUP BY film_id, month",
+	"DELETE FROM film_criteria_ratings WHERE (? = 0 OR film_id = ?)",
+	"INSERT INTO film_criteria_ratings (`film_id`, `criterion`, `sum_mark`, `num_of_marks`, `rating`) " +
+		"SELECT film_id, criterion, SUM(mark), COUNT(*), AVG(mark) FROM (" +
+		"SELECT film_id, status, 'story' AS criterion, story_mark AS mark FROM reviews WHERE story_mark IS NOT NULL " +
+		"UNION ALL SELECT film_id, status, 'acting', acting_mark FROM reviews WHERE acting_mark IS NOT NULL " +
+		"UNION ALL SELECT film_id, status, 'visuals', visuals_mark FROM reviews WHERE visuals_mark IS NOT NULL " +
+		"UNION ALL SELECT film_id, status, 'music', music_mark FROM reviews WHERE music_mark IS NOT NULL" +
+		") sub_scores WHERE status = 'published' AND (? = 0 OR film_id = ?) GROUP BY film_id, criterion",
+}
+
+// RecomputeRatingsRepo rebuilds the rating, the critic score, the weighted rating and the mark statistics of the film
+// from its published reviews, of all the films if filmID is 0. It returns the number of the changed films.
+// Events of the reviews written during the recompute but still in the queue are applied on top of it,
+// the reconciler fixes such films later.
+func (r *RatingChangerMySQL) RecomputeRatingsRepo(filmID uint64) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec(
+		"UPDATE films f LEFT JOIN (SELECT film_id, SUM(mark) AS sum_mark, COUNT(*) AS num_of_marks, "+
+			"SUM(IF(by_critic, mark, 0)) AS critic_sum_mark, SUM(by_critic) AS critic_num_of_marks, SUM(by_critic AND mark >= ?) AS critic_num_fresh "+
+			"FROM reviews WHERE status = 'published' AND (? = 0 OR film_id = ?) GROUP BY film_id) rv ON rv.film_id = f.id "+
+			"SET f.sum_mark = COALESCE(rv.sum_mark, 0), f.num_of_marks = COALESCE(rv.num_of_marks, 0), "+
+			"f.rating = CASE WHEN rv.num_of_marks > 0 THEN rv.sum_mark / rv.num_of_marks ELSE 0 END, "+
+			"f.critic_sum_mark = COALESCE(rv.critic_sum_mark, 0), f.critic_num_of_marks = COALESCE(rv.critic_num_of_marks, 0), "+
+			"f.critic_num_fresh = COALESCE(rv.critic_num_fresh, 0), "+
+			"f.critic_rating = CASE WHEN rv.critic_num_of_marks > 0 THEN rv.critic_sum_mark / rv.critic_num_of_marks ELSE 0 END, "+
+			"f.critic_fresh_percent = CASE WHEN rv.critic_num_of_marks > 0 THEN 100 * rv.critic_num_fresh / rv.critic_num_of_marks ELSE 0 END "+
+			"WHERE (? = 0 OR f.id = ?)",
+		FreshMark,
+		filmID,
+		filmID,
+		filmID,
+		filmID,
+	)
+	if err != nil {
+		r.rollback(tx)
+		return 0, err
+	}
+	changed, err := res.RowsAffected()
+	if err != nil {
+		r.rollback(tx)
+		return 0, err
+	}
+	_, err = tx.Exec(
+		weightedRatingUpdate+" WHERE (? = 0 OR id = ?)",
+		r.prior.Votes,
+		r.prior.Mean,
+		r.prior.Votes,
+		r.prior.Votes,
+		filmID,
+		filmID,
+	)
+	for i := 0; err == nil && i < len(recomputeStats); i++ {
+		_, err = tx.Exec(recomputeStats[i], filmID, filmID)
+	}
+	if err != nil {
+		r.rollback(tx)
+		r.logger.Errorf("error in recomputing ratings: %s", err)
+		return 0, err
+	}
+	return changed, tx.Commit()
+}
@@ -0,0 +1,60 @@
+package ratingserviceusecase
+
+import (
+	"go.uber.org/zap"
+	ratingservicerepo "kinopoisk/service_rating/repo/mysql"
+	"time"
+)
+
+type ChartsMaterializer interface {
+	MaterializeCharts(done <-chan struct{})
+}
+
+type ChartsMaterializerApp struct {
+	logger    *zap.SugaredLogger
+	chartsDB  ratingservicerepo.ChartsDB
+	interval  time.Duration
+	minVotes  uint64
+	chartSize uint64
+}
+
+func NewChartsMaterializerApp(logger *zap.SugaredLogger, chartsDB ratingservicerepo.ChartsDB, interval time.Duration,
+	minVotes, chartSize uint64) *ChartsMaterializerApp {
+	return &ChartsMaterializerApp{
+		logger:    logger,
+		chartsDB:  chartsDB,
+		interval:  interval,
+		minVotes:  minVotes,
+		chartSize: chartSize,
+	}
+}
+
+// MaterializeCharts recomputes weighted ratings once on start, since the prior may have been changed,
+// and then rebuilds the charts every interval until done is closed.
+func (c *ChartsMaterializerApp) MaterializeCharts(done <-chan struct{}) {
+	err := c.chartsDB.RecomputeWeightedRatings()
+	if err != nil {
+		c.logger.Errorf("error in recomputing weighted ratings: %s", err)
+	}
+	c.rebuildCharts()
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			c.rebuildCharts()
+		}
+	}
+}
+
+func (c *ChartsMaterializerApp) rebuildCharts() {
+	start := time.Now()
+	err := c.chartsDB.MaterializeCharts(c.minVotes, c.chartSize)
+	if err != nil {
+		c.logger.Errorf("error in materializing charts: %s", err)
+		return
+	}
+	c.logger.Infof("charts materialized in %s", time.Since(start))
+}
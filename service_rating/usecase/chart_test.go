@@ -0,0 +1,88 @@
+package ratingserviceusecase_test
+
+import (
+	"errors"
+	"go.uber.org/zap"
+	ratingserviceusecase "kinopoisk/service_rating/usecase"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeChartsDB records the calls, the charts of every materialization are sent to materialized.
+type fakeChartsDB struct {
+	mu           sync.Mutex
+	recomputeErr error
+	calls        []string
+	materialized chan [2]uint64
+}
+
+func (f *fakeChartsDB) RecomputeWeightedRatings() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, "recompute")
+	return f.recomputeErr
+}
+
+func (f *fakeChartsDB) MaterializeCharts(minVotes, chartSize uint64) error {
+	f.mu.Lock()
+	f.calls = append(f.calls, "materialize")
+	f.mu.Unlock()
+	f.materialized <- [2]uint64{minVotes, chartSize}
+	return nil
+}
+
+func TestMaterializeCharts(t *testing.T) {
+	cases := []struct {
+		name         string
+		recomputeErr error
+	}{
+		{name: "recomputed ratings"},
+		// the charts are built from the old weighted ratings
+		{name: "failed recompute", recomputeErr: errors.New("deadlock found")},
+	}
+	for _, testCase := range cases {
+		chartsDB := &fakeChartsDB{recomputeErr: testCase.recomputeErr, materialized: make(chan [2]uint64)}
+		app := ratingserviceusecase.NewChartsMaterializerApp(zap.NewNop().Sugar(), chartsDB, time.Millisecond, 50, 100)
+		done := make(chan struct{})
+		stopped := make(chan struct{})
+		go func() {
+			app.MaterializeCharts(done)
+			close(stopped)
+		}()
+
+		// the charts are built on start and then every interval
+		for i := 0; i < 2; i++ {
+			select {
+			case args := <-chartsDB.materialized:
+				if args != [2]uint64{50, 100} {
+					t.Errorf("%s: expected min votes 50 and chart size 100, got %v", testCase.name, args)
+				}
+			case <-time.After(waitTimeout):
+				t.Fatalf("%s: charts are not materialized", testCase.name)
+			}
+		}
+		close(done)
+		// the tick which may have come meanwhile is let through
+		for waiting := true; waiting; {
+			select {
+			case <-chartsDB.materialized:
+			case <-stopped:
+				waiting = false
+			case <-time.After(waitTimeout):
+				t.Fatalf("%s: materializer is not stopped", testCase.name)
+			}
+		}
+
+		chartsDB.mu.Lock()
+		if len(chartsDB.calls) < 3 || chartsDB.calls[0] != "recompute" || chartsDB.calls[1] != "materialize" {
+			t.Errorf("%s: expected recompute before the charts, got %v", testCase.name, chartsDB.calls)
+		}
+		for _, call := range chartsDB.calls[1:] {
+			if call == "recompute" {
+				t.Errorf("%s: weighted ratings are recomputed again: %v", testCase.name, chartsDB.calls)
+			}
+		}
+		chartsDB.mu.Unlock()
+	}
+}
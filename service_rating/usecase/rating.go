@@ -2,70 +2,253 @@ package ratingserviceusecase
 
 import (
 	"encoding/json"
-	"github.com/streadway/amqp"
+	"fmt"
 	"go.uber.org/zap"
+	ratingbroker "kinopoisk/service_rating/broker"
+	ratingmetrics "kinopoisk/service_rating/metrics"
 	ratingservicerepo "kinopoisk/service_rating/repo/mysql"
+	"sync"
+	"time"
+)
+
+// MaxCoalescedEvents is the max number of events a worker applies at once.
+const MaxCoalescedEvents = 100
+
+var (
+	eventsConsumed = ratingmetrics.NewCounter("rating_events_consumed_total", "Rating events taken from the queue.")
+	eventsApplied  = ratingmetrics.NewCounter("rating_events_applied_total", "Rating events applied to the films.")
+	eventsFailed   = ratingmetrics.NewCounterVec("rating_events_failed_total",
+		"Rating events which failed, by what happened to them: retry or dead_letter.", "outcome")
+	eventLatency = ratingmetrics.NewHistogram("rating_event_processing_seconds",
+		"Time from taking a rating event from the queue to applying it, including the coalesce window.",
+		[]float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10})
 )
 
 type ChangeRatingInfo struct {
+	EventID    string
 	ChangeType string
 	ReviewID   uint64
 	OldMark    uint32
 	NewMark    uint32
 	FilmID     uint64
+	OldHasText bool
+	NewHasText bool
+	CreatedAt  string
+	// events published before sub-scores were introduced have none
+	OldSubScores map[string]uint32
+	NewSubScores map[string]uint32
+	ByCritic     bool
 }
 
 type RatingChanger interface {
-	ChangeRating(tasks <-chan amqp.Delivery)
+	ChangeRating(tasks <-chan ratingbroker.Delivery)
 }
 
 type RatingChangerApp struct {
 	logger           *zap.SugaredLogger
 	changeRatingRepo ratingservicerepo.RatingChangerDB
+	workers          int
+	coalesceWindow   time.Duration
 }
 
-func NewRatingChangerApp(logger *zap.SugaredLogger, changeRatingRepo ratingservicerepo.RatingChangerDB) *RatingChangerApp {
+type ratingTask struct {
+	delivery   ratingbroker.Delivery
+	receivedAt time.Time
+	filmID     uint64
+	change     *ratingservicerepo.RatingChange
+}
+
+func NewRatingChangerApp(logger *zap.SugaredLogger, changeRatingRepo ratingservicerepo.RatingChangerDB, workers int,
+	coalesceWindow time.Duration) *RatingChangerApp {
 	return &RatingChangerApp{
 		logger:           logger,
 		changeRatingRepo: changeRatingRepo,
+		workers:          workers,
+		coalesceWindow:   coalesceWindow,
 	}
 }
 
-func (r *RatingChangerApp) ChangeRating(tasks <-chan amqp.Delivery) {
+// ChangeRating shards the tasks by film between the workers, so the tasks of one film are applied
+// in the order they came. A failed task goes back to the queue and may be applied after the newer tasks
+// of its film, which is fine because the changes of the film add up in any order.
+// It returns when tasks is closed and the workers have applied everything they got.
+func (r *RatingChangerApp) ChangeRating(tasks <-chan ratingbroker.Delivery) {
+	shards := make([]chan *ratingTask, r.workers)
+	wg := &sync.WaitGroup{}
+	for i := range shards {
+		shards[i] = make(chan *ratingTask, MaxCoalescedEvents)
+		wg.Add(1)
+		go func(shard <-chan *ratingTask) {
+			defer wg.Done()
+			r.applyTasks(shard)
+		}(shards[i])
+	}
 	for taskItem := range tasks {
-		r.logger.Infof("incoming task %+v\n", taskItem)
-		task := &ChangeRatingInfo{}
-		err := json.Unmarshal(taskItem.Body, task)
+		task := r.parseTask(taskItem)
+		if task == nil {
+			continue
+		}
+		shards[task.filmID%uint64(r.workers)] <- task
+	}
+	for _, shard := range shards {
+		close(shard)
+	}
+	wg.Wait()
+}
+
+// parseTask returns nil if the task has been already handled: it is dead-lettered or has nothing to apply.
+func (r *RatingChangerApp) parseTask(taskItem ratingbroker.Delivery) *ratingTask {
+	r.logger.Infof("incoming task %s, attempts %d\n", taskItem.Body(), taskItem.Attempts())
+	eventsConsumed.Inc()
+	receivedAt := time.Now()
+	task := &ChangeRatingInfo{}
+	err := json.Unmarshal(taskItem.Body(), task)
+	if err != nil {
+		r.logger.Errorf("cant unpack json: %s", err)
+		r.deadLetter(taskItem, err)
+		return nil
+	}
+
+	oldMark := &ratingservicerepo.MarkInfo{
+		Mark:      task.OldMark,
+		HasText:   task.OldHasText,
+		CreatedAt: task.CreatedAt,
+		SubScores: task.OldSubScores,
+	}
+	newMark := &ratingservicerepo.MarkInfo{
+		Mark:      task.NewMark,
+		HasText:   task.NewHasText,
+		CreatedAt: task.CreatedAt,
+		SubScores: task.NewSubScores,
+	}
+	change := &ratingservicerepo.RatingChange{
+		Event: &ratingservicerepo.RatingEvent{
+			ID:       task.EventID,
+			FilmID:   task.FilmID,
+			ReviewID: task.ReviewID,
+		},
+		ByCritic: task.ByCritic,
+	}
+	switch task.ChangeType {
+	case "Add":
+		change.NewMark = newMark
+	case "Update":
+		change.OldMark = oldMark
+		change.NewMark = newMark
+	case "Delete":
+		change.OldMark = oldMark
+	default:
+		r.logger.Errorf("unknown task: %s", task.ChangeType)
+		r.deadLetter(taskItem, fmt.Errorf("unknown change type %q", task.ChangeType))
+		return nil
+	}
+
+	filmID := task.FilmID
+	if filmID == 0 {
+		filmID, err = r.changeRatingRepo.GetReviewFilmRepo(task.ReviewID)
 		if err != nil {
-			r.logger.Errorf("cant unpack json: %s", err)
-			err = taskItem.Ack(false)
-			if err != nil {
-				r.logger.Errorf("error with ack: %s", err)
+			r.retryLater(taskItem, err)
+			return nil
+		}
+		if filmID == 0 {
+			r.ack(taskItem)
+			return nil
+		}
+	}
+	return &ratingTask{
+		delivery:   taskItem,
+		receivedAt: receivedAt,
+		filmID:     filmID,
+		change:     change,
+	}
+}
+
+// applyTasks collects the tasks coming within the coalesce window and applies the tasks of every film at once.
+func (r *RatingChangerApp) applyTasks(shard <-chan *ratingTask) {
+	for task := range shard {
+		batch := []*ratingTask{task}
+		window := time.NewTimer(r.coalesceWindow)
+		closed := false
+	collect:
+		for len(batch) < MaxCoalescedEvents {
+			select {
+			case next, ok := <-shard:
+				if !ok {
+					closed = true
+					break collect
+				}
+				batch = append(batch, next)
+			case <-window.C:
+				break collect
 			}
-			continue
 		}
+		window.Stop()
+		r.applyBatch(batch)
+		if closed {
+			return
+		}
+	}
+}
 
-		switch task.ChangeType {
-		case "Add":
-			err = r.changeRatingRepo.ChangeRatingAddReview(task.NewMark, task.ReviewID)
-		case "Update":
-			err = r.changeRatingRepo.ChangeRatingAfterUpdateReview(task.OldMark, task.NewMark, task.ReviewID)
-		case "Delete":
-			err = r.changeRatingRepo.ChangeRatingAfterDeleteReview(task.OldMark, task.FilmID)
-		default:
-			r.logger.Errorf("unknown task: %s", task.ChangeType)
+func (r *RatingChangerApp) applyBatch(batch []*ratingTask) {
+	var films []uint64
+	filmTasks := make(map[uint64][]*ratingTask)
+	for _, task := range batch {
+		if _, ok := filmTasks[task.filmID]; !ok {
+			films = append(films, task.filmID)
 		}
-		if err != nil {
-			err = taskItem.Nack(false, true) // Возвращаем сообщение в очередь в случае ошибки
+		filmTasks[task.filmID] = append(filmTasks[task.filmID], task)
+	}
+	for _, filmID := range films {
+		tasks := filmTasks[filmID]
+		changes := make([]*ratingservicerepo.RatingChange, 0, len(tasks))
+		for _, task := range tasks {
+			changes = append(changes, task.change)
+		}
+		err := r.changeRatingRepo.ApplyRatingChangesRepo(filmID, changes)
+		for _, task := range tasks {
 			if err != nil {
-				r.logger.Errorf("error with nack: %s", err)
+				r.retryLater(task.delivery, err)
+				continue
 			}
-			continue
+			eventsApplied.Inc()
+			eventLatency.Observe(time.Since(task.receivedAt).Seconds())
+			r.ack(task.delivery)
 		}
-		err = taskItem.Ack(false)
-		if err != nil {
-			r.logger.Errorf("error with ack: %s", err)
+	}
+}
 
-		}
+func (r *RatingChangerApp) ack(task ratingbroker.Delivery) {
+	err := task.Ack()
+	if err != nil {
+		r.logger.Errorf("error with ack: %s", err)
+	}
+}
+
+// retryLater returns the failed task to the queue after a delay or dead-letters it if there are no attempts left.
+func (r *RatingChangerApp) retryLater(task ratingbroker.Delivery, taskErr error) {
+	attempt := task.Attempts() + 1
+	deadLettered, err := task.Retry(taskErr)
+	if err != nil {
+		r.logger.Errorf("can not retry task: %s", err)
+		return
+	}
+	if deadLettered {
+		eventsFailed.Inc("dead_letter")
+		r.logger.Errorf("task is dead-lettered after %d attempts: %s", attempt, taskErr)
+		return
+	}
+	eventsFailed.Inc("retry")
+	r.logger.Warnf("task failed on attempt %d, will be retried: %s", attempt, taskErr)
+}
+
+// deadLetter moves the task which can never be processed straight to the dead letter queue.
+func (r *RatingChangerApp) deadLetter(task ratingbroker.Delivery, taskErr error) {
+	err := task.DeadLetter(taskErr)
+	if err != nil {
+		r.logger.Errorf("can not dead-letter task: %s", err)
+		return
 	}
+	eventsFailed.Inc("dead_letter")
+	r.logger.Errorf("task is dead-lettered: %s", taskErr)
 }
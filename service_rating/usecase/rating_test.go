@@ -0,0 +1,343 @@
+package ratingserviceusecase_test
+
+import (
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	ratingbroker "kinopoisk/service_rating/broker"
+	ratingservicerepo "kinopoisk/service_rating/repo/mysql"
+	ratingserviceusecase "kinopoisk/service_rating/usecase"
+	"sync"
+	"testing"
+	"time"
+)
+
+const waitTimeout = 5 * time.Second
+
+// fakeRatingRepo records the applied changes of every film, it fails all the changes while failErr is set
+// and the changes of the events from failOnce once.
+type fakeRatingRepo struct {
+	mu       sync.Mutex
+	failErr  error
+	failOnce map[string]bool
+	calls    int
+	batches  [][]string
+	applied  map[uint64][]*ratingservicerepo.RatingChange
+}
+
+func newFakeRatingRepo() *fakeRatingRepo {
+	return &fakeRatingRepo{
+		failOnce: make(map[string]bool),
+		applied:  make(map[uint64][]*ratingservicerepo.RatingChange),
+	}
+}
+
+func (f *fakeRatingRepo) ApplyRatingChangesRepo(filmID uint64, changes []*ratingservicerepo.RatingChange) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.failErr != nil {
+		return f.failErr
+	}
+	batch := make([]string, 0, len(changes))
+	for _, change := range changes {
+		if f.failOnce[change.Event.ID] {
+			delete(f.failOnce, change.Event.ID)
+			return errors.New("deadlock found")
+		}
+		batch = append(batch, change.Event.ID)
+	}
+	f.batches = append(f.batches, batch)
+	f.applied[filmID] = append(f.applied[filmID], changes...)
+	return nil
+}
+
+func (f *fakeRatingRepo) GetReviewFilmRepo(reviewID uint64) (uint64, error) {
+	return 0, nil
+}
+
+func (f *fakeRatingRepo) setFailErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failErr = err
+}
+
+func (f *fakeRatingRepo) appliedEvents(filmID uint64) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	events := make([]string, 0, len(f.applied[filmID]))
+	for _, change := range f.applied[filmID] {
+		events = append(events, change.Event.ID)
+	}
+	return events
+}
+
+// markSum is the sum of the marks of the film after the applied changes.
+func (f *fakeRatingRepo) markSum(filmID uint64) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var sum int64
+	for _, change := range f.applied[filmID] {
+		if change.OldMark != nil {
+			sum -= int64(change.OldMark.Mark)
+		}
+		if change.NewMark != nil {
+			sum += int64(change.NewMark.Mark)
+		}
+	}
+	return sum
+}
+
+// fakeDelivery records what has been done with the task.
+type fakeDelivery struct {
+	mu      sync.Mutex
+	body    []byte
+	outcome string
+}
+
+func (d *fakeDelivery) Body() []byte {
+	return d.body
+}
+
+func (d *fakeDelivery) Attempts() uint32 {
+	return 0
+}
+
+func (d *fakeDelivery) Ack() error {
+	return d.setOutcome("ack")
+}
+
+func (d *fakeDelivery) Retry(cause error) (bool, error) {
+	return false, d.setOutcome("retry")
+}
+
+func (d *fakeDelivery) DeadLetter(cause error) error {
+	return d.setOutcome("dead_letter")
+}
+
+func (d *fakeDelivery) setOutcome(outcome string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.outcome != "" {
+		return fmt.Errorf("task is already handled with %s", d.outcome)
+	}
+	d.outcome = outcome
+	return nil
+}
+
+func (d *fakeDelivery) getOutcome() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.outcome
+}
+
+func ratingEvent(eventID string, filmID uint64, mark uint32) []byte {
+	return []byte(fmt.Sprintf(`{"EventID": %q, "ChangeType": "Add", "ReviewID": 1, "NewMark": %d, "FilmID": %d}`, eventID, mark, filmID))
+}
+
+// waitFor polls the condition until it holds or the wait times out.
+func waitFor(t *testing.T, what string, condition func() bool) {
+	deadline := time.Now().Add(waitTimeout)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// startChangeRating consumes the queue of the broker until the returned stop function is called,
+// stop returns when all the consumed events are handled.
+func startChangeRating(broker ratingbroker.Broker, ratingChanger *ratingserviceusecase.RatingChangerApp) (func(), error) {
+	done := make(chan struct{})
+	tasks, err := broker.Consume(ratingbroker.ChangeRatingQueueName, done)
+	if err != nil {
+		return nil, err
+	}
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ratingChanger.ChangeRating(tasks)
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}, nil
+}
+
+func TestChangeRatingRetryDeadLetterReplay(t *testing.T) {
+	broker := ratingbroker.NewMemoryBroker(&ratingbroker.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+	})
+	defer broker.Close()
+	ratingRepo := newFakeRatingRepo()
+	ratingRepo.setFailErr(errors.New("db is down"))
+	ratingChanger := ratingserviceusecase.NewRatingChangerApp(zap.NewNop().Sugar(), ratingRepo, 2, 0)
+	stop, err := startChangeRating(broker, ratingChanger)
+	if err != nil {
+		t.Fatalf("can not consume: %s", err)
+	}
+	defer stop()
+
+	err = broker.Publish(ratingbroker.ChangeRatingQueueName, ratingEvent("event-1", 1, 8))
+	if err != nil {
+		t.Fatalf("can not publish: %s", err)
+	}
+	waitFor(t, "dead letter", func() bool {
+		return len(broker.DeadLetters(ratingbroker.ChangeRatingQueueName)) == 1
+	})
+	deadLetter := broker.DeadLetters(ratingbroker.ChangeRatingQueueName)[0]
+	if deadLetter.Attempts != 3 || deadLetter.LastError != "db is down" {
+		t.Errorf("expected dead letter after 3 attempts with the last error, got %d attempts and %q", deadLetter.Attempts, deadLetter.LastError)
+	}
+	ratingRepo.mu.Lock()
+	calls := ratingRepo.calls
+	ratingRepo.mu.Unlock()
+	if calls != 3 {
+		t.Errorf("expected 3 attempts to apply the event, got %d", calls)
+	}
+
+	// the dead-lettered event is replayed once the database is back
+	ratingRepo.setFailErr(nil)
+	err = broker.Publish(ratingbroker.ChangeRatingQueueName, deadLetter.Body)
+	if err != nil {
+		t.Fatalf("can not replay: %s", err)
+	}
+	waitFor(t, "replayed event", func() bool {
+		return len(ratingRepo.appliedEvents(1)) == 1
+	})
+}
+
+func updateEvent(eventID string, filmID uint64, oldMark, newMark uint32) []byte {
+	return []byte(fmt.Sprintf(`{"EventID": %q, "ChangeType": "Update", "ReviewID": 1, "OldMark": %d, "NewMark": %d, "FilmID": %d}`,
+		eventID, oldMark, newMark, filmID))
+}
+
+func TestChangeRatingFilmOrder(t *testing.T) {
+	ratingRepo := newFakeRatingRepo()
+	ratingChanger := ratingserviceusecase.NewRatingChangerApp(zap.NewNop().Sugar(), ratingRepo, 2, time.Millisecond)
+	tasks := make(chan ratingbroker.Delivery)
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ratingChanger.ChangeRating(tasks)
+	}()
+
+	var expected []string
+	for i := 0; i < 50; i++ {
+		eventID := fmt.Sprintf("event-%d", i)
+		// the films 1 and 2 go to different workers
+		filmID := uint64(1 + i%2)
+		if filmID == 1 {
+			expected = append(expected, eventID)
+		}
+		tasks <- &fakeDelivery{body: ratingEvent(eventID, filmID, uint32(1+i%10))}
+	}
+	close(tasks)
+	<-finished
+
+	applied := ratingRepo.appliedEvents(1)
+	if len(applied) != len(expected) {
+		t.Fatalf("expected %d events of the film applied, got %d", len(expected), len(applied))
+	}
+	for i := range expected {
+		if applied[i] != expected[i] {
+			t.Fatalf("events of the film are applied out of order: %v", applied)
+		}
+	}
+}
+
+func TestChangeRatingCoalesce(t *testing.T) {
+	ratingRepo := newFakeRatingRepo()
+	// the window is long enough for all the events to come
+	ratingChanger := ratingserviceusecase.NewRatingChangerApp(zap.NewNop().Sugar(), ratingRepo, 1, time.Second)
+	tasks := make(chan ratingbroker.Delivery, 3)
+	tasks <- &fakeDelivery{body: ratingEvent("event-1", 1, 8)}
+	tasks <- &fakeDelivery{body: updateEvent("event-2", 1, 8, 3)}
+	tasks <- &fakeDelivery{body: ratingEvent("event-3", 2, 5)}
+	close(tasks)
+	ratingChanger.ChangeRating(tasks)
+
+	if len(ratingRepo.batches) != 2 {
+		t.Fatalf("expected one batch per film, got %v", ratingRepo.batches)
+	}
+	if len(ratingRepo.batches[0]) != 2 || ratingRepo.batches[0][0] != "event-1" || ratingRepo.batches[0][1] != "event-2" {
+		t.Errorf("expected the events of the film to be applied at once, got %v", ratingRepo.batches[0])
+	}
+	if sum := ratingRepo.markSum(1); sum != 3 {
+		t.Errorf("expected net mark sum 3, got %d", sum)
+	}
+}
+
+func TestChangeRatingDrainsBeforeExit(t *testing.T) {
+	ratingRepo := newFakeRatingRepo()
+	// the window never ends by itself, the events are applied because the tasks are closed
+	ratingChanger := ratingserviceusecase.NewRatingChangerApp(zap.NewNop().Sugar(), ratingRepo, 4, time.Hour)
+	tasks := make(chan ratingbroker.Delivery, 10)
+	deliveries := make([]*fakeDelivery, 0, 10)
+	for i := 0; i < 10; i++ {
+		delivery := &fakeDelivery{body: ratingEvent(fmt.Sprintf("event-%d", i), uint64(1+i%5), 7)}
+		deliveries = append(deliveries, delivery)
+		tasks <- delivery
+	}
+	close(tasks)
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ratingChanger.ChangeRating(tasks)
+	}()
+	select {
+	case <-finished:
+	case <-time.After(waitTimeout):
+		t.Fatalf("ChangeRating does not return after the tasks are closed")
+	}
+	for i, delivery := range deliveries {
+		if outcome := delivery.getOutcome(); outcome != "ack" {
+			t.Errorf("task %d: expected ack before exit, got %q", i, outcome)
+		}
+	}
+}
+
+func TestChangeRatingRetriedEventAfterNewer(t *testing.T) {
+	broker := ratingbroker.NewMemoryBroker(&ratingbroker.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   10 * time.Millisecond,
+	})
+	defer broker.Close()
+	ratingRepo := newFakeRatingRepo()
+	ratingRepo.failOnce["event-1"] = true
+	ratingChanger := ratingserviceusecase.NewRatingChangerApp(zap.NewNop().Sugar(), ratingRepo, 1, 0)
+	stop, err := startChangeRating(broker, ratingChanger)
+	if err != nil {
+		t.Fatalf("can not consume: %s", err)
+	}
+	defer stop()
+
+	err = broker.Publish(ratingbroker.ChangeRatingQueueName, ratingEvent("event-1", 1, 8))
+	if err != nil {
+		t.Fatalf("can not publish: %s", err)
+	}
+	waitFor(t, "failed attempt", func() bool {
+		ratingRepo.mu.Lock()
+		defer ratingRepo.mu.Unlock()
+		return ratingRepo.calls == 1
+	})
+	err = broker.Publish(ratingbroker.ChangeRatingQueueName, updateEvent("event-2", 1, 8, 3))
+	if err != nil {
+		t.Fatalf("can not publish: %s", err)
+	}
+	waitFor(t, "retried event", func() bool {
+		return len(ratingRepo.appliedEvents(1)) == 2
+	})
+
+	// the update is applied before the review is added, the film gets the same marks anyway
+	applied := ratingRepo.appliedEvents(1)
+	if applied[0] != "event-2" || applied[1] != "event-1" {
+		t.Errorf("expected the retried event after the newer one, got %v", applied)
+	}
+	if sum := ratingRepo.markSum(1); sum != 3 {
+		t.Errorf("expected mark sum 3, got %d", sum)
+	}
+}
@@ -0,0 +1,106 @@
+package ratingserviceusecase
+
+import (
+	"go.uber.org/zap"
+	ratingservicerepo "kinopoisk/service_rating/repo/mysql"
+	"time"
+)
+
+type RatingReconciler interface {
+	Reconcile(done <-chan struct{})
+	Recompute(filmID uint64) (int64, error)
+}
+
+type RatingReconcilerApp struct {
+	logger            *zap.SugaredLogger
+	reconcilerDB      ratingservicerepo.RatingReconcilerDB
+	interval          time.Duration
+	processedEventTTL time.Duration
+	suspectedDrifts   map[uint64]ratingservicerepo.RatingDrift
+}
+
+func NewRatingReconcilerApp(logger *zap.SugaredLogger, reconcilerDB ratingservicerepo.RatingReconcilerDB, interval,
+	processedEventTTL time.Duration) *RatingReconcilerApp {
+	return &RatingReconcilerApp{
+		logger:            logger,
+		reconcilerDB:      reconcilerDB,
+		interval:          interval,
+		processedEventTTL: processedEventTTL,
+		suspectedDrifts:   make(map[uint64]ratingservicerepo.RatingDrift),
+	}
+}
+
+// Reconcile compares film counters with the published reviews every interval until done is closed.
+func (r *RatingReconcilerApp) Reconcile(done <-chan struct{}) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			r.reconcileOnce()
+		}
+	}
+}
+
+// reconcileOnce reports every drift but fixes only the drifts seen unchanged in the previous run too:
+// a fresh drift is usually a review whose rating event is still in the queue.
+func (r *RatingReconcilerApp) reconcileOnce() {
+	drifts, err := r.reconcilerDB.FindRatingDriftRepo()
+	if err != nil {
+		r.logger.Errorf("error in finding rating drift: %s", err)
+		return
+	}
+	currentDrifts := make(map[uint64]ratingservicerepo.RatingDrift, len(drifts))
+	var fixedNumber int
+	for _, drift := range drifts {
+		r.logger.Warnw("rating drift",
+			"film_id", drift.FilmID,
+			"sum_mark", drift.SumMark,
+			"num_of_marks", drift.NumOfMarks,
+			"expected_sum_mark", drift.ExpectedSumMark,
+			"expected_num_of_marks", drift.ExpectedNumOfMarks,
+		)
+		if previousDrift, ok := r.suspectedDrifts[drift.FilmID]; !ok || previousDrift != *drift {
+			currentDrifts[drift.FilmID] = *drift
+			continue
+		}
+		isFixed, err := r.reconcilerDB.FixRatingDriftRepo(drift)
+		if err != nil {
+			r.logger.Errorf("error in fixing rating drift of film %d: %s", drift.FilmID, err)
+			currentDrifts[drift.FilmID] = *drift
+			continue
+		}
+		if isFixed {
+			fixedNumber++
+			r.logger.Infof("rating of film %d is recomputed from reviews", drift.FilmID)
+		}
+	}
+	r.suspectedDrifts = currentDrifts
+	r.logger.Infof("rating reconciliation: %d films drifted, %d fixed", len(drifts), fixedNumber)
+
+	deleted, err := r.reconcilerDB.DeleteProcessedEventsRepo(r.processedEventTTL)
+	if err != nil {
+		r.logger.Errorf("error in deleting old processed events: %s", err)
+		return
+	}
+	if deleted > 0 {
+		r.logger.Infof("%d old processed rating events deleted", deleted)
+	}
+}
+
+// Recompute rebuilds the rating and the statistics of the film from its reviews right away,
+// of all the films if filmID is 0.
+func (r *RatingReconcilerApp) Recompute(filmID uint64) (int64, error) {
+	changed, err := r.reconcilerDB.RecomputeRatingsRepo(filmID)
+	if err != nil {
+		return 0, err
+	}
+	if filmID == 0 {
+		r.logger.Infof("ratings of all films are recomputed, %d changed", changed)
+	} else {
+		r.logger.Infof("rating of film %d is recomputed", filmID)
+	}
+	return changed, nil
+}
@@ -1,8 +1,17 @@
 package errorreview
 
-import "errors"
+import (
+	"errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
 
 var (
-	ErrorNoReview = errors.New("user has not got review with such id")
-	ErrorNoLogger = errors.New("there is no logger in context")
+	ErrorNoReview  = errors.New("user has not got review with such id")
+	ErrorNoLogger  = errors.New("there is no logger in context")
+	ErrorNoComment = errors.New("there is no comment with such id")
+	ErrorNoUser    = errors.New("there is no user with such id")
+
+	ErrorBadPageToken        = status.Error(codes.InvalidArgument, "bad page token")
+	ErrorBadModerationAction = status.Error(codes.InvalidArgument, "unknown moderation action")
 )
@@ -0,0 +1,76 @@
+package reviewfilter
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const regexpPrefix = "re:"
+
+//go:embed words.txt
+var defaultWords string
+
+// ProfanityFilter holds reviews which contain words from its list for moderation.
+type ProfanityFilter struct {
+	patterns []*regexp.Regexp
+}
+
+// NewDefaultProfanityFilter makes filter with the built-in russian and english word list.
+func NewDefaultProfanityFilter() (*ProfanityFilter, error) {
+	return NewProfanityFilter(strings.NewReader(defaultWords))
+}
+
+// NewProfanityFilterFromFile makes filter with the word list from file.
+func NewProfanityFilterFromFile(path string) (*ProfanityFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return NewProfanityFilter(file)
+}
+
+// NewProfanityFilter reads word list with one entry per line: a plain word is matched as a separate word,
+// an entry with "re:" prefix is a regular expression. Empty lines and lines starting with "#" are skipped.
+func NewProfanityFilter(list io.Reader) (*ProfanityFilter, error) {
+	filter := &ProfanityFilter{}
+	scanner := bufio.NewScanner(list)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		var expr string
+		if strings.HasPrefix(entry, regexpPrefix) {
+			expr = strings.TrimPrefix(entry, regexpPrefix)
+		} else {
+			expr = `(^|[^\p{L}\p{N}])` + regexp.QuoteMeta(entry) + `($|[^\p{L}\p{N}])`
+		}
+		pattern, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return nil, fmt.Errorf("bad filter entry on line %d: %w", lineNumber, err)
+		}
+		filter.patterns = append(filter.patterns, pattern)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+// IsSuspicious reports whether the text matches any entry of the list.
+func (f *ProfanityFilter) IsSuspicious(text string) bool {
+	for _, pattern := range f.patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package reviewfilter_test
+
+import (
+	reviewfilter "kinopoisk/service_review/filter"
+	"strings"
+	"testing"
+)
+
+func TestDefaultProfanityFilter(t *testing.T) {
+	filter, err := reviewfilter.NewDefaultProfanityFilter()
+	if err != nil {
+		t.Fatalf("can not load default list: %s", err)
+	}
+	cases := []struct {
+		name     string
+		text     string
+		expected bool
+	}{
+		{name: "clean english", text: "Great acting and a sharp script", expected: false},
+		{name: "clean russian", text: "Отличный фильм, пересмотрю еще раз", expected: false},
+		{name: "plain word", text: "what a shitty ending", expected: true},
+		{name: "plain word with punctuation", text: "The plot is bullshit!", expected: true},
+		{name: "regexp with stretched letters", text: "fuuuck this movie", expected: true},
+		{name: "regexp at the start of a russian word", text: "Это пиздец", expected: true},
+		{name: "russian prefixed word", text: "режиссер заебал", expected: true},
+		{name: "upper case", text: "SHIT", expected: true},
+		{name: "mixed case russian", text: "СуКа", expected: true},
+		// near-misses: the word is a part of a longer clean word
+		{name: "word inside another word", text: "shittake mushrooms", expected: false},
+		{name: "regexp not at the start of a word", text: "Scunthorpe United", expected: false},
+		{name: "bastard inside a word", text: "bastardization of the novel", expected: false},
+		{name: "russian near-miss", text: "страхуй себя", expected: false},
+		{name: "digits glued to the word", text: "shit2", expected: false},
+	}
+	for _, testCase := range cases {
+		if suspicious := filter.IsSuspicious(testCase.text); suspicious != testCase.expected {
+			t.Errorf("%s: expected %t for %q, got %t", testCase.name, testCase.expected, testCase.text, suspicious)
+		}
+	}
+}
+
+func TestNewProfanityFilter(t *testing.T) {
+	filter, err := reviewfilter.NewProfanityFilter(strings.NewReader("# comment\n\n  spoiler  \nre:^bad\\d+$\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	cases := []struct {
+		text     string
+		expected bool
+	}{
+		{text: "no spoilers here", expected: false},
+		{text: "big Spoiler ahead", expected: true},
+		{text: "BAD42", expected: true},
+		{text: "bad", expected: false},
+		{text: "comment", expected: false},
+	}
+	for _, testCase := range cases {
+		if suspicious := filter.IsSuspicious(testCase.text); suspicious != testCase.expected {
+			t.Errorf("expected %t for %q, got %t", testCase.expected, testCase.text, suspicious)
+		}
+	}
+
+	_, err = reviewfilter.NewProfanityFilter(strings.NewReader("word\nre:(unclosed\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected error on line 2, got %v", err)
+	}
+}
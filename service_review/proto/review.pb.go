@@ -20,6 +20,379 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ReviewSort int32
+
+const (
+	ReviewSort_SORT_NEWEST       ReviewSort = 0
+	ReviewSort_SORT_OLDEST       ReviewSort = 1
+	ReviewSort_SORT_HIGHEST_MARK ReviewSort = 2
+	ReviewSort_SORT_LOWEST_MARK  ReviewSort = 3
+	ReviewSort_SORT_MOST_HELPFUL ReviewSort = 4
+)
+
+// Enum value maps for ReviewSort.
+var (
+	ReviewSort_name = map[int32]string{
+		0: "SORT_NEWEST",
+		1: "SORT_OLDEST",
+		2: "SORT_HIGHEST_MARK",
+		3: "SORT_LOWEST_MARK",
+		4: "SORT_MOST_HELPFUL",
+	}
+	ReviewSort_value = map[string]int32{
+		"SORT_NEWEST":       0,
+		"SORT_OLDEST":       1,
+		"SORT_HIGHEST_MARK": 2,
+		"SORT_LOWEST_MARK":  3,
+		"SORT_MOST_HELPFUL": 4,
+	}
+)
+
+func (x ReviewSort) Enum() *ReviewSort {
+	p := new(ReviewSort)
+	*p = x
+	return p
+}
+
+func (x ReviewSort) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReviewSort) Descriptor() protoreflect.EnumDescriptor {
+	return file_review_proto_enumTypes[0].Descriptor()
+}
+
+func (ReviewSort) Type() protoreflect.EnumType {
+	return &file_review_proto_enumTypes[0]
+}
+
+func (x ReviewSort) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReviewSort.Descriptor instead.
+func (ReviewSort) EnumDescriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{0}
+}
+
+type ReviewStatus int32
+
+const (
+	ReviewStatus_REVIEW_PUBLISHED ReviewStatus = 0
+	ReviewStatus_REVIEW_PENDING   ReviewStatus = 1
+	ReviewStatus_REVIEW_REJECTED  ReviewStatus = 2
+	ReviewStatus_REVIEW_HIDDEN    ReviewStatus = 3
+)
+
+// Enum value maps for ReviewStatus.
+var (
+	ReviewStatus_name = map[int32]string{
+		0: "REVIEW_PUBLISHED",
+		1: "REVIEW_PENDING",
+		2: "REVIEW_REJECTED",
+		3: "REVIEW_HIDDEN",
+	}
+	ReviewStatus_value = map[string]int32{
+		"REVIEW_PUBLISHED": 0,
+		"REVIEW_PENDING":   1,
+		"REVIEW_REJECTED":  2,
+		"REVIEW_HIDDEN":    3,
+	}
+)
+
+func (x ReviewStatus) Enum() *ReviewStatus {
+	p := new(ReviewStatus)
+	*p = x
+	return p
+}
+
+func (x ReviewStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReviewStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_review_proto_enumTypes[1].Descriptor()
+}
+
+func (ReviewStatus) Type() protoreflect.EnumType {
+	return &file_review_proto_enumTypes[1]
+}
+
+func (x ReviewStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReviewStatus.Descriptor instead.
+func (ReviewStatus) EnumDescriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{1}
+}
+
+type VoteStatus int32
+
+const (
+	VoteStatus_VOTE_OK         VoteStatus = 0
+	VoteStatus_VOTE_NO_REVIEW  VoteStatus = 1
+	VoteStatus_VOTE_OWN_REVIEW VoteStatus = 2
+	VoteStatus_VOTE_NOT_FOUND  VoteStatus = 3
+)
+
+// Enum value maps for VoteStatus.
+var (
+	VoteStatus_name = map[int32]string{
+		0: "VOTE_OK",
+		1: "VOTE_NO_REVIEW",
+		2: "VOTE_OWN_REVIEW",
+		3: "VOTE_NOT_FOUND",
+	}
+	VoteStatus_value = map[string]int32{
+		"VOTE_OK":         0,
+		"VOTE_NO_REVIEW":  1,
+		"VOTE_OWN_REVIEW": 2,
+		"VOTE_NOT_FOUND":  3,
+	}
+)
+
+func (x VoteStatus) Enum() *VoteStatus {
+	p := new(VoteStatus)
+	*p = x
+	return p
+}
+
+func (x VoteStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (VoteStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_review_proto_enumTypes[2].Descriptor()
+}
+
+func (VoteStatus) Type() protoreflect.EnumType {
+	return &file_review_proto_enumTypes[2]
+}
+
+func (x VoteStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use VoteStatus.Descriptor instead.
+func (VoteStatus) EnumDescriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{2}
+}
+
+type CommentStatus int32
+
+const (
+	CommentStatus_COMMENT_OK        CommentStatus = 0
+	CommentStatus_COMMENT_NO_REVIEW CommentStatus = 1
+	CommentStatus_COMMENT_NO_PARENT CommentStatus = 2
+	CommentStatus_COMMENT_TOO_DEEP  CommentStatus = 3
+	CommentStatus_COMMENT_NOT_FOUND CommentStatus = 4
+	CommentStatus_COMMENT_DELETED   CommentStatus = 5
+)
+
+// Enum value maps for CommentStatus.
+var (
+	CommentStatus_name = map[int32]string{
+		0: "COMMENT_OK",
+		1: "COMMENT_NO_REVIEW",
+		2: "COMMENT_NO_PARENT",
+		3: "COMMENT_TOO_DEEP",
+		4: "COMMENT_NOT_FOUND",
+		5: "COMMENT_DELETED",
+	}
+	CommentStatus_value = map[string]int32{
+		"COMMENT_OK":        0,
+		"COMMENT_NO_REVIEW": 1,
+		"COMMENT_NO_PARENT": 2,
+		"COMMENT_TOO_DEEP":  3,
+		"COMMENT_NOT_FOUND": 4,
+		"COMMENT_DELETED":   5,
+	}
+)
+
+func (x CommentStatus) Enum() *CommentStatus {
+	p := new(CommentStatus)
+	*p = x
+	return p
+}
+
+func (x CommentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CommentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_review_proto_enumTypes[3].Descriptor()
+}
+
+func (CommentStatus) Type() protoreflect.EnumType {
+	return &file_review_proto_enumTypes[3]
+}
+
+func (x CommentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CommentStatus.Descriptor instead.
+func (CommentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{3}
+}
+
+type ModerationStatus int32
+
+const (
+	ModerationStatus_MODERATION_OK               ModerationStatus = 0
+	ModerationStatus_MODERATION_NO_REVIEW        ModerationStatus = 1
+	ModerationStatus_MODERATION_OWN_REVIEW       ModerationStatus = 2
+	ModerationStatus_MODERATION_ALREADY_REPORTED ModerationStatus = 3
+)
+
+// Enum value maps for ModerationStatus.
+var (
+	ModerationStatus_name = map[int32]string{
+		0: "MODERATION_OK",
+		1: "MODERATION_NO_REVIEW",
+		2: "MODERATION_OWN_REVIEW",
+		3: "MODERATION_ALREADY_REPORTED",
+	}
+	ModerationStatus_value = map[string]int32{
+		"MODERATION_OK":               0,
+		"MODERATION_NO_REVIEW":        1,
+		"MODERATION_OWN_REVIEW":       2,
+		"MODERATION_ALREADY_REPORTED": 3,
+	}
+)
+
+func (x ModerationStatus) Enum() *ModerationStatus {
+	p := new(ModerationStatus)
+	*p = x
+	return p
+}
+
+func (x ModerationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ModerationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_review_proto_enumTypes[4].Descriptor()
+}
+
+func (ModerationStatus) Type() protoreflect.EnumType {
+	return &file_review_proto_enumTypes[4]
+}
+
+func (x ModerationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ModerationStatus.Descriptor instead.
+func (ModerationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{4}
+}
+
+type ModerationAction int32
+
+const (
+	ModerationAction_ACTION_APPROVE        ModerationAction = 0
+	ModerationAction_ACTION_REJECT         ModerationAction = 1
+	ModerationAction_ACTION_HIDE           ModerationAction = 2
+	ModerationAction_ACTION_MARK_SPOILER   ModerationAction = 3
+	ModerationAction_ACTION_UNMARK_SPOILER ModerationAction = 4
+)
+
+// Enum value maps for ModerationAction.
+var (
+	ModerationAction_name = map[int32]string{
+		0: "ACTION_APPROVE",
+		1: "ACTION_REJECT",
+		2: "ACTION_HIDE",
+		3: "ACTION_MARK_SPOILER",
+		4: "ACTION_UNMARK_SPOILER",
+	}
+	ModerationAction_value = map[string]int32{
+		"ACTION_APPROVE":        0,
+		"ACTION_REJECT":         1,
+		"ACTION_HIDE":           2,
+		"ACTION_MARK_SPOILER":   3,
+		"ACTION_UNMARK_SPOILER": 4,
+	}
+)
+
+func (x ModerationAction) Enum() *ModerationAction {
+	p := new(ModerationAction)
+	*p = x
+	return p
+}
+
+func (x ModerationAction) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ModerationAction) Descriptor() protoreflect.EnumDescriptor {
+	return file_review_proto_enumTypes[5].Descriptor()
+}
+
+func (ModerationAction) Type() protoreflect.EnumType {
+	return &file_review_proto_enumTypes[5]
+}
+
+func (x ModerationAction) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ModerationAction.Descriptor instead.
+func (ModerationAction) EnumDescriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{5}
+}
+
+type ProfileStatus int32
+
+const (
+	ProfileStatus_PROFILE_OK        ProfileStatus = 0
+	ProfileStatus_PROFILE_NOT_FOUND ProfileStatus = 1
+	ProfileStatus_PROFILE_PRIVATE   ProfileStatus = 2
+)
+
+// Enum value maps for ProfileStatus.
+var (
+	ProfileStatus_name = map[int32]string{
+		0: "PROFILE_OK",
+		1: "PROFILE_NOT_FOUND",
+		2: "PROFILE_PRIVATE",
+	}
+	ProfileStatus_value = map[string]int32{
+		"PROFILE_OK":        0,
+		"PROFILE_NOT_FOUND": 1,
+		"PROFILE_PRIVATE":   2,
+	}
+)
+
+func (x ProfileStatus) Enum() *ProfileStatus {
+	p := new(ProfileStatus)
+	*p = x
+	return p
+}
+
+func (x ProfileStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProfileStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_review_proto_enumTypes[6].Descriptor()
+}
+
+func (ProfileStatus) Type() protoreflect.EnumType {
+	return &file_review_proto_enumTypes[6]
+}
+
+func (x ProfileStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProfileStatus.Descriptor instead.
+func (ProfileStatus) EnumDescriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{6}
+}
+
 type UserID struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -276,11 +649,22 @@ type Review struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ID      *ReviewID `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
-	Mark    uint32    `protobuf:"varint,2,opt,name=mark,proto3" json:"mark,omitempty"`
-	Comment string    `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
-	Author  *User     `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
-	FilmID  *FilmID   `protobuf:"bytes,5,opt,name=filmID,proto3" json:"filmID,omitempty"`
+	ID             *ReviewID    `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Mark           uint32       `protobuf:"varint,2,opt,name=mark,proto3" json:"mark,omitempty"`
+	Comment        string       `protobuf:"bytes,3,opt,name=comment,proto3" json:"comment,omitempty"`
+	Author         *User        `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	FilmID         *FilmID      `protobuf:"bytes,5,opt,name=filmID,proto3" json:"filmID,omitempty"`
+	CreatedAt      string       `protobuf:"bytes,6,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt      string       `protobuf:"bytes,7,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	IsEdited       bool         `protobuf:"varint,8,opt,name=isEdited,proto3" json:"isEdited,omitempty"`
+	HelpfulVotes   uint32       `protobuf:"varint,9,opt,name=helpfulVotes,proto3" json:"helpfulVotes,omitempty"`
+	UnhelpfulVotes uint32       `protobuf:"varint,10,opt,name=unhelpfulVotes,proto3" json:"unhelpfulVotes,omitempty"`
+	Status         ReviewStatus `protobuf:"varint,11,opt,name=status,proto3,enum=review.ReviewStatus" json:"status,omitempty"`
+	IsSpoiler      bool         `protobuf:"varint,12,opt,name=isSpoiler,proto3" json:"isSpoiler,omitempty"`
+	SpoilersMasked bool         `protobuf:"varint,13,opt,name=spoilersMasked,proto3" json:"spoilersMasked,omitempty"`
+	SubScores      *SubScores   `protobuf:"bytes,14,opt,name=subScores,proto3" json:"subScores,omitempty"`
+	// byCritic is set if the author had the critic role when the review was written
+	ByCritic bool `protobuf:"varint,15,opt,name=byCritic,proto3" json:"byCritic,omitempty"`
 }
 
 func (x *Review) Reset() {
@@ -350,16 +734,90 @@ func (x *Review) GetFilmID() *FilmID {
 	return nil
 }
 
-type Reviews struct {
+func (x *Review) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Review) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Review) GetIsEdited() bool {
+	if x != nil {
+		return x.IsEdited
+	}
+	return false
+}
+
+func (x *Review) GetHelpfulVotes() uint32 {
+	if x != nil {
+		return x.HelpfulVotes
+	}
+	return 0
+}
+
+func (x *Review) GetUnhelpfulVotes() uint32 {
+	if x != nil {
+		return x.UnhelpfulVotes
+	}
+	return 0
+}
+
+func (x *Review) GetStatus() ReviewStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReviewStatus_REVIEW_PUBLISHED
+}
+
+func (x *Review) GetIsSpoiler() bool {
+	if x != nil {
+		return x.IsSpoiler
+	}
+	return false
+}
+
+func (x *Review) GetSpoilersMasked() bool {
+	if x != nil {
+		return x.SpoilersMasked
+	}
+	return false
+}
+
+func (x *Review) GetSubScores() *SubScores {
+	if x != nil {
+		return x.SubScores
+	}
+	return nil
+}
+
+func (x *Review) GetByCritic() bool {
+	if x != nil {
+		return x.ByCritic
+	}
+	return false
+}
+
+// SubScores are optional marks 1-10 of the review by criterion, 0 means the criterion is not rated.
+type SubScores struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Reviews []*Review `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	Story   uint32 `protobuf:"varint,1,opt,name=story,proto3" json:"story,omitempty"`
+	Acting  uint32 `protobuf:"varint,2,opt,name=acting,proto3" json:"acting,omitempty"`
+	Visuals uint32 `protobuf:"varint,3,opt,name=visuals,proto3" json:"visuals,omitempty"`
+	Music   uint32 `protobuf:"varint,4,opt,name=music,proto3" json:"music,omitempty"`
 }
 
-func (x *Reviews) Reset() {
-	*x = Reviews{}
+func (x *SubScores) Reset() {
+	*x = SubScores{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_review_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -367,13 +825,13 @@ func (x *Reviews) Reset() {
 	}
 }
 
-func (x *Reviews) String() string {
+func (x *SubScores) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Reviews) ProtoMessage() {}
+func (*SubScores) ProtoMessage() {}
 
-func (x *Reviews) ProtoReflect() protoreflect.Message {
+func (x *SubScores) ProtoReflect() protoreflect.Message {
 	mi := &file_review_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -385,30 +843,52 @@ func (x *Reviews) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Reviews.ProtoReflect.Descriptor instead.
-func (*Reviews) Descriptor() ([]byte, []int) {
+// Deprecated: Use SubScores.ProtoReflect.Descriptor instead.
+func (*SubScores) Descriptor() ([]byte, []int) {
 	return file_review_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *Reviews) GetReviews() []*Review {
+func (x *SubScores) GetStory() uint32 {
 	if x != nil {
-		return x.Reviews
+		return x.Story
 	}
-	return nil
+	return 0
 }
 
-type NewReviewData struct {
+func (x *SubScores) GetActing() uint32 {
+	if x != nil {
+		return x.Acting
+	}
+	return 0
+}
+
+func (x *SubScores) GetVisuals() uint32 {
+	if x != nil {
+		return x.Visuals
+	}
+	return 0
+}
+
+func (x *SubScores) GetMusic() uint32 {
+	if x != nil {
+		return x.Music
+	}
+	return 0
+}
+
+type ReviewRevision struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Review *Review `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
-	FilmID *FilmID `protobuf:"bytes,2,opt,name=filmID,proto3" json:"filmID,omitempty"`
-	UserID *UserID `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	Mark       uint32 `protobuf:"varint,1,opt,name=mark,proto3" json:"mark,omitempty"`
+	Comment    string `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	CreatedAt  string `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	ReplacedAt string `protobuf:"bytes,4,opt,name=replacedAt,proto3" json:"replacedAt,omitempty"`
 }
 
-func (x *NewReviewData) Reset() {
-	*x = NewReviewData{}
+func (x *ReviewRevision) Reset() {
+	*x = ReviewRevision{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_review_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -416,13 +896,13 @@ func (x *NewReviewData) Reset() {
 	}
 }
 
-func (x *NewReviewData) String() string {
+func (x *ReviewRevision) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NewReviewData) ProtoMessage() {}
+func (*ReviewRevision) ProtoMessage() {}
 
-func (x *NewReviewData) ProtoReflect() protoreflect.Message {
+func (x *ReviewRevision) ProtoReflect() protoreflect.Message {
 	mi := &file_review_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -434,43 +914,50 @@ func (x *NewReviewData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NewReviewData.ProtoReflect.Descriptor instead.
-func (*NewReviewData) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReviewRevision.ProtoReflect.Descriptor instead.
+func (*ReviewRevision) Descriptor() ([]byte, []int) {
 	return file_review_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *NewReviewData) GetReview() *Review {
+func (x *ReviewRevision) GetMark() uint32 {
 	if x != nil {
-		return x.Review
+		return x.Mark
 	}
-	return nil
+	return 0
 }
 
-func (x *NewReviewData) GetFilmID() *FilmID {
+func (x *ReviewRevision) GetComment() string {
 	if x != nil {
-		return x.FilmID
+		return x.Comment
 	}
-	return nil
+	return ""
 }
 
-func (x *NewReviewData) GetUserID() *UserID {
+func (x *ReviewRevision) GetCreatedAt() string {
 	if x != nil {
-		return x.UserID
+		return x.CreatedAt
 	}
-	return nil
+	return ""
 }
 
-type DeleteReviewData struct {
+func (x *ReviewRevision) GetReplacedAt() string {
+	if x != nil {
+		return x.ReplacedAt
+	}
+	return ""
+}
+
+type ReviewHistoryRequest struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	ReviewID *ReviewID `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
-	UserID   *UserID   `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	ReviewID       *ReviewID `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	RevealSpoilers bool      `protobuf:"varint,2,opt,name=revealSpoilers,proto3" json:"revealSpoilers,omitempty"`
 }
 
-func (x *DeleteReviewData) Reset() {
-	*x = DeleteReviewData{}
+func (x *ReviewHistoryRequest) Reset() {
+	*x = ReviewHistoryRequest{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_review_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -478,13 +965,13 @@ func (x *DeleteReviewData) Reset() {
 	}
 }
 
-func (x *DeleteReviewData) String() string {
+func (x *ReviewHistoryRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteReviewData) ProtoMessage() {}
+func (*ReviewHistoryRequest) ProtoMessage() {}
 
-func (x *DeleteReviewData) ProtoReflect() protoreflect.Message {
+func (x *ReviewHistoryRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_review_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -496,36 +983,36 @@ func (x *DeleteReviewData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteReviewData.ProtoReflect.Descriptor instead.
-func (*DeleteReviewData) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReviewHistoryRequest.ProtoReflect.Descriptor instead.
+func (*ReviewHistoryRequest) Descriptor() ([]byte, []int) {
 	return file_review_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *DeleteReviewData) GetReviewID() *ReviewID {
+func (x *ReviewHistoryRequest) GetReviewID() *ReviewID {
 	if x != nil {
 		return x.ReviewID
 	}
 	return nil
 }
 
-func (x *DeleteReviewData) GetUserID() *UserID {
+func (x *ReviewHistoryRequest) GetRevealSpoilers() bool {
 	if x != nil {
-		return x.UserID
+		return x.RevealSpoilers
 	}
-	return nil
+	return false
 }
 
-type UpdateReviewData struct {
+type ReviewHistory struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Review *Review `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
-	UserID *UserID `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	Review    *Review           `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	Revisions []*ReviewRevision `protobuf:"bytes,2,rep,name=revisions,proto3" json:"revisions,omitempty"`
 }
 
-func (x *UpdateReviewData) Reset() {
-	*x = UpdateReviewData{}
+func (x *ReviewHistory) Reset() {
+	*x = ReviewHistory{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_review_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -533,13 +1020,13 @@ func (x *UpdateReviewData) Reset() {
 	}
 }
 
-func (x *UpdateReviewData) String() string {
+func (x *ReviewHistory) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateReviewData) ProtoMessage() {}
+func (*ReviewHistory) ProtoMessage() {}
 
-func (x *UpdateReviewData) ProtoReflect() protoreflect.Message {
+func (x *ReviewHistory) ProtoReflect() protoreflect.Message {
 	mi := &file_review_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -551,211 +1038,2810 @@ func (x *UpdateReviewData) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateReviewData.ProtoReflect.Descriptor instead.
-func (*UpdateReviewData) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReviewHistory.ProtoReflect.Descriptor instead.
+func (*ReviewHistory) Descriptor() ([]byte, []int) {
 	return file_review_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UpdateReviewData) GetReview() *Review {
+func (x *ReviewHistory) GetReview() *Review {
 	if x != nil {
 		return x.Review
 	}
 	return nil
 }
 
-func (x *UpdateReviewData) GetUserID() *UserID {
+func (x *ReviewHistory) GetRevisions() []*ReviewRevision {
 	if x != nil {
-		return x.UserID
+		return x.Revisions
 	}
 	return nil
 }
 
-var File_review_proto protoreflect.FileDescriptor
+type Reviews struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-var file_review_proto_rawDesc = []byte{
-	0x0a, 0x0c, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
-	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x22, 0x18, 0x0a, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44,
-	0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44,
-	0x22, 0x42, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73,
-	0x65, 0x72, 0x49, 0x44, 0x52, 0x02, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72,
-	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72,
-	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x18, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x12, 0x0e,
-	0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x22, 0x1a,
-	0x0a, 0x08, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x22, 0x53, 0x0a, 0x0b, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x44,
-	0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65,
-	0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
-	0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x22,
-	0xa6, 0x01, 0x0a, 0x06, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x20, 0x0a, 0x02, 0x49, 0x44,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e,
-	0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x52, 0x02, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04,
-	0x6d, 0x61, 0x72, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x61, 0x72, 0x6b,
-	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x06, 0x61, 0x75,
-	0x74, 0x68, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x72, 0x65, 0x76,
-	0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
-	0x12, 0x26, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x46, 0x69, 0x6c, 0x6d, 0x49, 0x44,
-	0x52, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x22, 0x33, 0x0a, 0x07, 0x52, 0x65, 0x76, 0x69,
-	0x65, 0x77, 0x73, 0x12, 0x28, 0x0a, 0x07, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65,
-	0x76, 0x69, 0x65, 0x77, 0x52, 0x07, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x22, 0x87, 0x01,
-	0x0a, 0x0d, 0x4e, 0x65, 0x77, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61, 0x12,
-	0x26, 0x0a, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52,
-	0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x26, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49,
-	0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
-	0x2e, 0x46, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x12,
-	0x26, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52,
-	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0x68, 0x0a, 0x10, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x08, 0x72,
-	0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
-	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x52,
-	0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x06, 0x75, 0x73, 0x65,
-	0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69,
-	0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
-	0x44, 0x22, 0x62, 0x0a, 0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69, 0x65,
-	0x77, 0x44, 0x61, 0x74, 0x61, 0x12, 0x26, 0x0a, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52,
-	0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x26, 0x0a,
-	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
-	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06, 0x75,
-	0x73, 0x65, 0x72, 0x49, 0x44, 0x32, 0xed, 0x01, 0x0a, 0x0b, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
-	0x4d, 0x61, 0x6b, 0x65, 0x72, 0x12, 0x31, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x6d,
-	0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x12, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
-	0x2e, 0x46, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x1a, 0x0f, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
-	0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x12, 0x32, 0x0a, 0x09, 0x4e, 0x65, 0x77, 0x52,
-	0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4e,
-	0x65, 0x77, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x0e, 0x2e, 0x72,
-	0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x3d, 0x0a, 0x0c,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x18, 0x2e, 0x72,
-	0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69,
-	0x65, 0x77, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x13, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e,
-	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x38, 0x0a, 0x0c, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x18, 0x2e, 0x72, 0x65,
-	0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69, 0x65,
-	0x77, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52,
-	0x65, 0x76, 0x69, 0x65, 0x77, 0x42, 0x0b, 0x5a, 0x09, 0x2e, 0x2f, 0x3b, 0x72, 0x65, 0x76, 0x69,
-	0x65, 0x77, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	Reviews       []*Review `protobuf:"bytes,1,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	NextPageToken string    `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
 }
 
-var (
-	file_review_proto_rawDescOnce sync.Once
-	file_review_proto_rawDescData = file_review_proto_rawDesc
-)
+func (x *Reviews) Reset() {
+	*x = Reviews{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
 
-func file_review_proto_rawDescGZIP() []byte {
-	file_review_proto_rawDescOnce.Do(func() {
-		file_review_proto_rawDescData = protoimpl.X.CompressGZIP(file_review_proto_rawDescData)
-	})
-	return file_review_proto_rawDescData
+func (x *Reviews) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-var file_review_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
-var file_review_proto_goTypes = []interface{}{
-	(*UserID)(nil),           // 0: review.UserID
-	(*User)(nil),             // 1: review.User
-	(*FilmID)(nil),           // 2: review.FilmID
-	(*ReviewID)(nil),         // 3: review.ReviewID
-	(*DeletedData)(nil),      // 4: review.DeletedData
-	(*Review)(nil),           // 5: review.Review
-	(*Reviews)(nil),          // 6: review.Reviews
-	(*NewReviewData)(nil),    // 7: review.NewReviewData
-	(*DeleteReviewData)(nil), // 8: review.DeleteReviewData
-	(*UpdateReviewData)(nil), // 9: review.UpdateReviewData
+func (*Reviews) ProtoMessage() {}
+
+func (x *Reviews) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
-var file_review_proto_depIdxs = []int32{
-	0,  // 0: review.User.ID:type_name -> review.UserID
-	5,  // 1: review.DeletedData.review:type_name -> review.Review
-	3,  // 2: review.Review.ID:type_name -> review.ReviewID
-	1,  // 3: review.Review.author:type_name -> review.User
-	2,  // 4: review.Review.filmID:type_name -> review.FilmID
-	5,  // 5: review.Reviews.reviews:type_name -> review.Review
-	5,  // 6: review.NewReviewData.review:type_name -> review.Review
-	2,  // 7: review.NewReviewData.filmID:type_name -> review.FilmID
-	0,  // 8: review.NewReviewData.userID:type_name -> review.UserID
-	3,  // 9: review.DeleteReviewData.reviewID:type_name -> review.ReviewID
-	0,  // 10: review.DeleteReviewData.userID:type_name -> review.UserID
-	5,  // 11: review.UpdateReviewData.review:type_name -> review.Review
-	0,  // 12: review.UpdateReviewData.userID:type_name -> review.UserID
-	2,  // 13: review.ReviewMaker.GetFilmReviews:input_type -> review.FilmID
-	7,  // 14: review.ReviewMaker.NewReview:input_type -> review.NewReviewData
-	8,  // 15: review.ReviewMaker.DeleteReview:input_type -> review.DeleteReviewData
-	9,  // 16: review.ReviewMaker.UpdateReview:input_type -> review.UpdateReviewData
-	6,  // 17: review.ReviewMaker.GetFilmReviews:output_type -> review.Reviews
-	5,  // 18: review.ReviewMaker.NewReview:output_type -> review.Review
-	4,  // 19: review.ReviewMaker.DeleteReview:output_type -> review.DeletedData
-	5,  // 20: review.ReviewMaker.UpdateReview:output_type -> review.Review
-	17, // [17:21] is the sub-list for method output_type
-	13, // [13:17] is the sub-list for method input_type
-	13, // [13:13] is the sub-list for extension type_name
-	13, // [13:13] is the sub-list for extension extendee
-	0,  // [0:13] is the sub-list for field type_name
+
+// Deprecated: Use Reviews.ProtoReflect.Descriptor instead.
+func (*Reviews) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{10}
 }
 
-func init() { file_review_proto_init() }
-func file_review_proto_init() {
-	if File_review_proto != nil {
-		return
+func (x *Reviews) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
 	}
-	if !protoimpl.UnsafeEnabled {
-		file_review_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UserID); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_review_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*User); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
-		}
-		file_review_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FilmID); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+	return nil
+}
+
+func (x *Reviews) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type FilmReviewsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	FilmID         *FilmID    `protobuf:"bytes,1,opt,name=filmID,proto3" json:"filmID,omitempty"`
+	Sort           ReviewSort `protobuf:"varint,2,opt,name=sort,proto3,enum=review.ReviewSort" json:"sort,omitempty"`
+	PageSize       uint32     `protobuf:"varint,3,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken      string     `protobuf:"bytes,4,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	RevealSpoilers bool       `protobuf:"varint,5,opt,name=revealSpoilers,proto3" json:"revealSpoilers,omitempty"`
+	CriticsOnly    bool       `protobuf:"varint,6,opt,name=criticsOnly,proto3" json:"criticsOnly,omitempty"`
+}
+
+func (x *FilmReviewsRequest) Reset() {
+	*x = FilmReviewsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FilmReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FilmReviewsRequest) ProtoMessage() {}
+
+func (x *FilmReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_review_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReviewID); i {
-			case 0:
-				return &v.state
-			case 1:
-				return &v.sizeCache
-			case 2:
-				return &v.unknownFields
-			default:
-				return nil
-			}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FilmReviewsRequest.ProtoReflect.Descriptor instead.
+func (*FilmReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *FilmReviewsRequest) GetFilmID() *FilmID {
+	if x != nil {
+		return x.FilmID
+	}
+	return nil
+}
+
+func (x *FilmReviewsRequest) GetSort() ReviewSort {
+	if x != nil {
+		return x.Sort
+	}
+	return ReviewSort_SORT_NEWEST
+}
+
+func (x *FilmReviewsRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *FilmReviewsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *FilmReviewsRequest) GetRevealSpoilers() bool {
+	if x != nil {
+		return x.RevealSpoilers
+	}
+	return false
+}
+
+func (x *FilmReviewsRequest) GetCriticsOnly() bool {
+	if x != nil {
+		return x.CriticsOnly
+	}
+	return false
+}
+
+type NewReviewData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Review *Review `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	FilmID *FilmID `protobuf:"bytes,2,opt,name=filmID,proto3" json:"filmID,omitempty"`
+	UserID *UserID `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+}
+
+func (x *NewReviewData) Reset() {
+	*x = NewReviewData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewReviewData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewReviewData) ProtoMessage() {}
+
+func (x *NewReviewData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
 		}
-		file_review_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeletedData); i {
-			case 0:
-				return &v.state
-			case 1:
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewReviewData.ProtoReflect.Descriptor instead.
+func (*NewReviewData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *NewReviewData) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+func (x *NewReviewData) GetFilmID() *FilmID {
+	if x != nil {
+		return x.FilmID
+	}
+	return nil
+}
+
+func (x *NewReviewData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+type DeleteReviewData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReviewID *ReviewID `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	UserID   *UserID   `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+}
+
+func (x *DeleteReviewData) Reset() {
+	*x = DeleteReviewData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteReviewData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteReviewData) ProtoMessage() {}
+
+func (x *DeleteReviewData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteReviewData.ProtoReflect.Descriptor instead.
+func (*DeleteReviewData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteReviewData) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *DeleteReviewData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+type UpdateReviewData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Review *Review `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	UserID *UserID `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+}
+
+func (x *UpdateReviewData) Reset() {
+	*x = UpdateReviewData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateReviewData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateReviewData) ProtoMessage() {}
+
+func (x *UpdateReviewData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateReviewData.ProtoReflect.Descriptor instead.
+func (*UpdateReviewData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *UpdateReviewData) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+func (x *UpdateReviewData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+type VoteData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReviewID  *ReviewID `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	UserID    *UserID   `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	IsHelpful bool      `protobuf:"varint,3,opt,name=isHelpful,proto3" json:"isHelpful,omitempty"`
+}
+
+func (x *VoteData) Reset() {
+	*x = VoteData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VoteData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteData) ProtoMessage() {}
+
+func (x *VoteData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteData.ProtoReflect.Descriptor instead.
+func (*VoteData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *VoteData) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *VoteData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+func (x *VoteData) GetIsHelpful() bool {
+	if x != nil {
+		return x.IsHelpful
+	}
+	return false
+}
+
+type DeleteVoteData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReviewID *ReviewID `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	UserID   *UserID   `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+}
+
+func (x *DeleteVoteData) Reset() {
+	*x = DeleteVoteData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteVoteData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteVoteData) ProtoMessage() {}
+
+func (x *DeleteVoteData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteVoteData.ProtoReflect.Descriptor instead.
+func (*DeleteVoteData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *DeleteVoteData) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *DeleteVoteData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+type VoteResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status VoteStatus `protobuf:"varint,1,opt,name=status,proto3,enum=review.VoteStatus" json:"status,omitempty"`
+	Review *Review    `protobuf:"bytes,2,opt,name=review,proto3" json:"review,omitempty"`
+}
+
+func (x *VoteResult) Reset() {
+	*x = VoteResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VoteResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoteResult) ProtoMessage() {}
+
+func (x *VoteResult) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoteResult.ProtoReflect.Descriptor instead.
+func (*VoteResult) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *VoteResult) GetStatus() VoteStatus {
+	if x != nil {
+		return x.Status
+	}
+	return VoteStatus_VOTE_OK
+}
+
+func (x *VoteResult) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type CommentID struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID uint64 `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
+}
+
+func (x *CommentID) Reset() {
+	*x = CommentID{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommentID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentID) ProtoMessage() {}
+
+func (x *CommentID) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentID.ProtoReflect.Descriptor instead.
+func (*CommentID) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CommentID) GetID() uint64 {
+	if x != nil {
+		return x.ID
+	}
+	return 0
+}
+
+type Comment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID        *CommentID `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	ReviewID  *ReviewID  `protobuf:"bytes,2,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	ParentID  *CommentID `protobuf:"bytes,3,opt,name=parentID,proto3" json:"parentID,omitempty"`
+	Author    *User      `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	Text      string     `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Depth     uint32     `protobuf:"varint,6,opt,name=depth,proto3" json:"depth,omitempty"`
+	IsDeleted bool       `protobuf:"varint,7,opt,name=isDeleted,proto3" json:"isDeleted,omitempty"`
+	CreatedAt string     `protobuf:"bytes,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt string     `protobuf:"bytes,9,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+}
+
+func (x *Comment) Reset() {
+	*x = Comment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Comment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comment) ProtoMessage() {}
+
+func (x *Comment) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comment.ProtoReflect.Descriptor instead.
+func (*Comment) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Comment) GetID() *CommentID {
+	if x != nil {
+		return x.ID
+	}
+	return nil
+}
+
+func (x *Comment) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *Comment) GetParentID() *CommentID {
+	if x != nil {
+		return x.ParentID
+	}
+	return nil
+}
+
+func (x *Comment) GetAuthor() *User {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *Comment) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *Comment) GetDepth() uint32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *Comment) GetIsDeleted() bool {
+	if x != nil {
+		return x.IsDeleted
+	}
+	return false
+}
+
+func (x *Comment) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Comment) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type CommentResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  CommentStatus `protobuf:"varint,1,opt,name=status,proto3,enum=review.CommentStatus" json:"status,omitempty"`
+	Comment *Comment      `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+}
+
+func (x *CommentResult) Reset() {
+	*x = CommentResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CommentResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentResult) ProtoMessage() {}
+
+func (x *CommentResult) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentResult.ProtoReflect.Descriptor instead.
+func (*CommentResult) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CommentResult) GetStatus() CommentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CommentStatus_COMMENT_OK
+}
+
+func (x *CommentResult) GetComment() *Comment {
+	if x != nil {
+		return x.Comment
+	}
+	return nil
+}
+
+type NewCommentData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReviewID *ReviewID  `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	ParentID *CommentID `protobuf:"bytes,2,opt,name=parentID,proto3" json:"parentID,omitempty"`
+	UserID   *UserID    `protobuf:"bytes,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	Text     string     `protobuf:"bytes,4,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *NewCommentData) Reset() {
+	*x = NewCommentData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NewCommentData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewCommentData) ProtoMessage() {}
+
+func (x *NewCommentData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewCommentData.ProtoReflect.Descriptor instead.
+func (*NewCommentData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *NewCommentData) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *NewCommentData) GetParentID() *CommentID {
+	if x != nil {
+		return x.ParentID
+	}
+	return nil
+}
+
+func (x *NewCommentData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+func (x *NewCommentData) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type UpdateCommentData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommentID *CommentID `protobuf:"bytes,1,opt,name=commentID,proto3" json:"commentID,omitempty"`
+	UserID    *UserID    `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	Text      string     `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *UpdateCommentData) Reset() {
+	*x = UpdateCommentData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UpdateCommentData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCommentData) ProtoMessage() {}
+
+func (x *UpdateCommentData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCommentData.ProtoReflect.Descriptor instead.
+func (*UpdateCommentData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateCommentData) GetCommentID() *CommentID {
+	if x != nil {
+		return x.CommentID
+	}
+	return nil
+}
+
+func (x *UpdateCommentData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+func (x *UpdateCommentData) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type DeleteCommentData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	CommentID *CommentID `protobuf:"bytes,1,opt,name=commentID,proto3" json:"commentID,omitempty"`
+	UserID    *UserID    `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+}
+
+func (x *DeleteCommentData) Reset() {
+	*x = DeleteCommentData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DeleteCommentData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCommentData) ProtoMessage() {}
+
+func (x *DeleteCommentData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCommentData.ProtoReflect.Descriptor instead.
+func (*DeleteCommentData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteCommentData) GetCommentID() *CommentID {
+	if x != nil {
+		return x.CommentID
+	}
+	return nil
+}
+
+func (x *DeleteCommentData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+type ReviewCommentsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReviewID  *ReviewID `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	PageSize  uint32    `protobuf:"varint,2,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken string    `protobuf:"bytes,3,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+}
+
+func (x *ReviewCommentsRequest) Reset() {
+	*x = ReviewCommentsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReviewCommentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewCommentsRequest) ProtoMessage() {}
+
+func (x *ReviewCommentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewCommentsRequest.ProtoReflect.Descriptor instead.
+func (*ReviewCommentsRequest) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ReviewCommentsRequest) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *ReviewCommentsRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ReviewCommentsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type Comments struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status        CommentStatus `protobuf:"varint,1,opt,name=status,proto3,enum=review.CommentStatus" json:"status,omitempty"`
+	Comments      []*Comment    `protobuf:"bytes,2,rep,name=comments,proto3" json:"comments,omitempty"`
+	NextPageToken string        `protobuf:"bytes,3,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+}
+
+func (x *Comments) Reset() {
+	*x = Comments{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Comments) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Comments) ProtoMessage() {}
+
+func (x *Comments) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Comments.ProtoReflect.Descriptor instead.
+func (*Comments) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Comments) GetStatus() CommentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return CommentStatus_COMMENT_OK
+}
+
+func (x *Comments) GetComments() []*Comment {
+	if x != nil {
+		return x.Comments
+	}
+	return nil
+}
+
+func (x *Comments) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ReportData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReviewID *ReviewID `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	UserID   *UserID   `protobuf:"bytes,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	Reason   string    `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *ReportData) Reset() {
+	*x = ReportData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportData) ProtoMessage() {}
+
+func (x *ReportData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportData.ProtoReflect.Descriptor instead.
+func (*ReportData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ReportData) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *ReportData) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+func (x *ReportData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ReportResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status ModerationStatus `protobuf:"varint,1,opt,name=status,proto3,enum=review.ModerationStatus" json:"status,omitempty"`
+}
+
+func (x *ReportResult) Reset() {
+	*x = ReportResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReportResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReportResult) ProtoMessage() {}
+
+func (x *ReportResult) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReportResult.ProtoReflect.Descriptor instead.
+func (*ReportResult) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ReportResult) GetStatus() ModerationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ModerationStatus_MODERATION_OK
+}
+
+type ReviewReport struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Author    *User  `protobuf:"bytes,1,opt,name=author,proto3" json:"author,omitempty"`
+	Reason    string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt string `protobuf:"bytes,3,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+}
+
+func (x *ReviewReport) Reset() {
+	*x = ReviewReport{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ReviewReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReviewReport) ProtoMessage() {}
+
+func (x *ReviewReport) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReviewReport.ProtoReflect.Descriptor instead.
+func (*ReviewReport) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ReviewReport) GetAuthor() *User {
+	if x != nil {
+		return x.Author
+	}
+	return nil
+}
+
+func (x *ReviewReport) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReviewReport) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ModerationQueueRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PageSize  uint32 `protobuf:"varint,1,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken string `protobuf:"bytes,2,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+}
+
+func (x *ModerationQueueRequest) Reset() {
+	*x = ModerationQueueRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModerationQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationQueueRequest) ProtoMessage() {}
+
+func (x *ModerationQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationQueueRequest.ProtoReflect.Descriptor instead.
+func (*ModerationQueueRequest) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ModerationQueueRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ModerationQueueRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ModerationQueueItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Review  *Review         `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	Reports []*ReviewReport `protobuf:"bytes,2,rep,name=reports,proto3" json:"reports,omitempty"`
+}
+
+func (x *ModerationQueueItem) Reset() {
+	*x = ModerationQueueItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModerationQueueItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationQueueItem) ProtoMessage() {}
+
+func (x *ModerationQueueItem) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationQueueItem.ProtoReflect.Descriptor instead.
+func (*ModerationQueueItem) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ModerationQueueItem) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+func (x *ModerationQueueItem) GetReports() []*ReviewReport {
+	if x != nil {
+		return x.Reports
+	}
+	return nil
+}
+
+type ModerationQueue struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Items         []*ModerationQueueItem `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+}
+
+func (x *ModerationQueue) Reset() {
+	*x = ModerationQueue{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModerationQueue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationQueue) ProtoMessage() {}
+
+func (x *ModerationQueue) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationQueue.ProtoReflect.Descriptor instead.
+func (*ModerationQueue) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ModerationQueue) GetItems() []*ModerationQueueItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ModerationQueue) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type ModerationData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReviewID    *ReviewID        `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	ModeratorID *UserID          `protobuf:"bytes,2,opt,name=moderatorID,proto3" json:"moderatorID,omitempty"`
+	Action      ModerationAction `protobuf:"varint,3,opt,name=action,proto3,enum=review.ModerationAction" json:"action,omitempty"`
+	Reason      string           `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+}
+
+func (x *ModerationData) Reset() {
+	*x = ModerationData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[32]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModerationData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationData) ProtoMessage() {}
+
+func (x *ModerationData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[32]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationData.ProtoReflect.Descriptor instead.
+func (*ModerationData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ModerationData) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *ModerationData) GetModeratorID() *UserID {
+	if x != nil {
+		return x.ModeratorID
+	}
+	return nil
+}
+
+func (x *ModerationData) GetAction() ModerationAction {
+	if x != nil {
+		return x.Action
+	}
+	return ModerationAction_ACTION_APPROVE
+}
+
+func (x *ModerationData) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SpoilerFlagData struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ReviewID    *ReviewID `protobuf:"bytes,1,opt,name=reviewID,proto3" json:"reviewID,omitempty"`
+	ModeratorID *UserID   `protobuf:"bytes,2,opt,name=moderatorID,proto3" json:"moderatorID,omitempty"`
+	IsSpoiler   bool      `protobuf:"varint,3,opt,name=isSpoiler,proto3" json:"isSpoiler,omitempty"`
+}
+
+func (x *SpoilerFlagData) Reset() {
+	*x = SpoilerFlagData{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[33]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SpoilerFlagData) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SpoilerFlagData) ProtoMessage() {}
+
+func (x *SpoilerFlagData) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[33]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SpoilerFlagData.ProtoReflect.Descriptor instead.
+func (*SpoilerFlagData) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SpoilerFlagData) GetReviewID() *ReviewID {
+	if x != nil {
+		return x.ReviewID
+	}
+	return nil
+}
+
+func (x *SpoilerFlagData) GetModeratorID() *UserID {
+	if x != nil {
+		return x.ModeratorID
+	}
+	return nil
+}
+
+func (x *SpoilerFlagData) GetIsSpoiler() bool {
+	if x != nil {
+		return x.IsSpoiler
+	}
+	return false
+}
+
+type ModerationResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status ModerationStatus `protobuf:"varint,1,opt,name=status,proto3,enum=review.ModerationStatus" json:"status,omitempty"`
+	Review *Review          `protobuf:"bytes,2,opt,name=review,proto3" json:"review,omitempty"`
+}
+
+func (x *ModerationResult) Reset() {
+	*x = ModerationResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[34]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModerationResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationResult) ProtoMessage() {}
+
+func (x *ModerationResult) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[34]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationResult.ProtoReflect.Descriptor instead.
+func (*ModerationResult) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *ModerationResult) GetStatus() ModerationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ModerationStatus_MODERATION_OK
+}
+
+func (x *ModerationResult) GetReview() *Review {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type ModerationLogEntry struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Moderator *User            `protobuf:"bytes,1,opt,name=moderator,proto3" json:"moderator,omitempty"`
+	Action    ModerationAction `protobuf:"varint,2,opt,name=action,proto3,enum=review.ModerationAction" json:"action,omitempty"`
+	OldStatus ReviewStatus     `protobuf:"varint,3,opt,name=oldStatus,proto3,enum=review.ReviewStatus" json:"oldStatus,omitempty"`
+	NewStatus ReviewStatus     `protobuf:"varint,4,opt,name=newStatus,proto3,enum=review.ReviewStatus" json:"newStatus,omitempty"`
+	Reason    string           `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt string           `protobuf:"bytes,6,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+}
+
+func (x *ModerationLogEntry) Reset() {
+	*x = ModerationLogEntry{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[35]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModerationLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationLogEntry) ProtoMessage() {}
+
+func (x *ModerationLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[35]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationLogEntry.ProtoReflect.Descriptor instead.
+func (*ModerationLogEntry) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ModerationLogEntry) GetModerator() *User {
+	if x != nil {
+		return x.Moderator
+	}
+	return nil
+}
+
+func (x *ModerationLogEntry) GetAction() ModerationAction {
+	if x != nil {
+		return x.Action
+	}
+	return ModerationAction_ACTION_APPROVE
+}
+
+func (x *ModerationLogEntry) GetOldStatus() ReviewStatus {
+	if x != nil {
+		return x.OldStatus
+	}
+	return ReviewStatus_REVIEW_PUBLISHED
+}
+
+func (x *ModerationLogEntry) GetNewStatus() ReviewStatus {
+	if x != nil {
+		return x.NewStatus
+	}
+	return ReviewStatus_REVIEW_PUBLISHED
+}
+
+func (x *ModerationLogEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ModerationLogEntry) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ModerationLog struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status  ModerationStatus      `protobuf:"varint,1,opt,name=status,proto3,enum=review.ModerationStatus" json:"status,omitempty"`
+	Entries []*ModerationLogEntry `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+}
+
+func (x *ModerationLog) Reset() {
+	*x = ModerationLog{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[36]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ModerationLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationLog) ProtoMessage() {}
+
+func (x *ModerationLog) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[36]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationLog.ProtoReflect.Descriptor instead.
+func (*ModerationLog) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ModerationLog) GetStatus() ModerationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ModerationStatus_MODERATION_OK
+}
+
+func (x *ModerationLog) GetEntries() []*ModerationLogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type UserProfileRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID         *UserID `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	RequesterID    *UserID `protobuf:"bytes,2,opt,name=requesterID,proto3" json:"requesterID,omitempty"`
+	RevealSpoilers bool    `protobuf:"varint,3,opt,name=revealSpoilers,proto3" json:"revealSpoilers,omitempty"`
+}
+
+func (x *UserProfileRequest) Reset() {
+	*x = UserProfileRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[37]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserProfileRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserProfileRequest) ProtoMessage() {}
+
+func (x *UserProfileRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[37]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserProfileRequest.ProtoReflect.Descriptor instead.
+func (*UserProfileRequest) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *UserProfileRequest) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+func (x *UserProfileRequest) GetRequesterID() *UserID {
+	if x != nil {
+		return x.RequesterID
+	}
+	return nil
+}
+
+func (x *UserProfileRequest) GetRevealSpoilers() bool {
+	if x != nil {
+		return x.RevealSpoilers
+	}
+	return false
+}
+
+type UserProfile struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status        ProfileStatus `protobuf:"varint,1,opt,name=status,proto3,enum=review.ProfileStatus" json:"status,omitempty"`
+	User          *User         `protobuf:"bytes,2,opt,name=user,proto3" json:"user,omitempty"`
+	JoinedAt      string        `protobuf:"bytes,3,opt,name=joinedAt,proto3" json:"joinedAt,omitempty"`
+	IsPrivate     bool          `protobuf:"varint,4,opt,name=isPrivate,proto3" json:"isPrivate,omitempty"`
+	ReviewsCount  uint32        `protobuf:"varint,5,opt,name=reviewsCount,proto3" json:"reviewsCount,omitempty"`
+	AverageMark   float64       `protobuf:"fixed64,6,opt,name=averageMark,proto3" json:"averageMark,omitempty"`
+	RecentReviews []*Review     `protobuf:"bytes,7,rep,name=recentReviews,proto3" json:"recentReviews,omitempty"`
+}
+
+func (x *UserProfile) Reset() {
+	*x = UserProfile{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[38]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserProfile) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserProfile) ProtoMessage() {}
+
+func (x *UserProfile) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[38]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserProfile.ProtoReflect.Descriptor instead.
+func (*UserProfile) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *UserProfile) GetStatus() ProfileStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProfileStatus_PROFILE_OK
+}
+
+func (x *UserProfile) GetUser() *User {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *UserProfile) GetJoinedAt() string {
+	if x != nil {
+		return x.JoinedAt
+	}
+	return ""
+}
+
+func (x *UserProfile) GetIsPrivate() bool {
+	if x != nil {
+		return x.IsPrivate
+	}
+	return false
+}
+
+func (x *UserProfile) GetReviewsCount() uint32 {
+	if x != nil {
+		return x.ReviewsCount
+	}
+	return 0
+}
+
+func (x *UserProfile) GetAverageMark() float64 {
+	if x != nil {
+		return x.AverageMark
+	}
+	return 0
+}
+
+func (x *UserProfile) GetRecentReviews() []*Review {
+	if x != nil {
+		return x.RecentReviews
+	}
+	return nil
+}
+
+type UserReviewsRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UserID         *UserID `protobuf:"bytes,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	RequesterID    *UserID `protobuf:"bytes,2,opt,name=requesterID,proto3" json:"requesterID,omitempty"`
+	PageSize       uint32  `protobuf:"varint,3,opt,name=pageSize,proto3" json:"pageSize,omitempty"`
+	PageToken      string  `protobuf:"bytes,4,opt,name=pageToken,proto3" json:"pageToken,omitempty"`
+	RevealSpoilers bool    `protobuf:"varint,5,opt,name=revealSpoilers,proto3" json:"revealSpoilers,omitempty"`
+}
+
+func (x *UserReviewsRequest) Reset() {
+	*x = UserReviewsRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[39]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserReviewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserReviewsRequest) ProtoMessage() {}
+
+func (x *UserReviewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[39]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserReviewsRequest.ProtoReflect.Descriptor instead.
+func (*UserReviewsRequest) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *UserReviewsRequest) GetUserID() *UserID {
+	if x != nil {
+		return x.UserID
+	}
+	return nil
+}
+
+func (x *UserReviewsRequest) GetRequesterID() *UserID {
+	if x != nil {
+		return x.RequesterID
+	}
+	return nil
+}
+
+func (x *UserReviewsRequest) GetPageSize() uint32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *UserReviewsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *UserReviewsRequest) GetRevealSpoilers() bool {
+	if x != nil {
+		return x.RevealSpoilers
+	}
+	return false
+}
+
+type UserReviews struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Status        ProfileStatus `protobuf:"varint,1,opt,name=status,proto3,enum=review.ProfileStatus" json:"status,omitempty"`
+	Reviews       []*Review     `protobuf:"bytes,2,rep,name=reviews,proto3" json:"reviews,omitempty"`
+	NextPageToken string        `protobuf:"bytes,3,opt,name=nextPageToken,proto3" json:"nextPageToken,omitempty"`
+}
+
+func (x *UserReviews) Reset() {
+	*x = UserReviews{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_review_proto_msgTypes[40]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserReviews) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserReviews) ProtoMessage() {}
+
+func (x *UserReviews) ProtoReflect() protoreflect.Message {
+	mi := &file_review_proto_msgTypes[40]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserReviews.ProtoReflect.Descriptor instead.
+func (*UserReviews) Descriptor() ([]byte, []int) {
+	return file_review_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *UserReviews) GetStatus() ProfileStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ProfileStatus_PROFILE_OK
+}
+
+func (x *UserReviews) GetReviews() []*Review {
+	if x != nil {
+		return x.Reviews
+	}
+	return nil
+}
+
+func (x *UserReviews) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+var File_review_proto protoreflect.FileDescriptor
+
+var file_review_proto_rawDesc = []byte{
+	0x0a, 0x0c, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06,
+	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x22, 0x18, 0x0a, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44,
+	0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44,
+	0x22, 0x42, 0x0a, 0x04, 0x55, 0x73, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x49, 0x44, 0x52, 0x02, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72,
+	0x6e, 0x61, 0x6d, 0x65, 0x22, 0x18, 0x0a, 0x06, 0x46, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x12, 0x0e,
+	0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x22, 0x1a,
+	0x0a, 0x08, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x22, 0x53, 0x0a, 0x0b, 0x44, 0x65,
+	0x6c, 0x65, 0x74, 0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x44,
+	0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73,
+	0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x64, 0x12, 0x26, 0x0a, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x22,
+	0x8b, 0x04, 0x0a, 0x06, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x20, 0x0a, 0x02, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e,
+	0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x52, 0x02, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04,
+	0x6d, 0x61, 0x72, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x61, 0x72, 0x6b,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x24, 0x0a, 0x06, 0x61, 0x75,
+	0x74, 0x68, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
+	0x12, 0x26, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x46, 0x69, 0x6c, 0x6d, 0x49, 0x44,
+	0x52, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74,
+	0x65, 0x64, 0x41, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x73, 0x45, 0x64, 0x69, 0x74, 0x65, 0x64,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x73, 0x45, 0x64, 0x69, 0x74, 0x65, 0x64,
+	0x12, 0x22, 0x0a, 0x0c, 0x68, 0x65, 0x6c, 0x70, 0x66, 0x75, 0x6c, 0x56, 0x6f, 0x74, 0x65, 0x73,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x68, 0x65, 0x6c, 0x70, 0x66, 0x75, 0x6c, 0x56,
+	0x6f, 0x74, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x75, 0x6e, 0x68, 0x65, 0x6c, 0x70, 0x66, 0x75,
+	0x6c, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x75, 0x6e,
+	0x68, 0x65, 0x6c, 0x70, 0x66, 0x75, 0x6c, 0x56, 0x6f, 0x74, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x06,
+	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73,
+	0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69,
+	0x73, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x70, 0x6f, 0x69,
+	0x6c, 0x65, 0x72, 0x73, 0x4d, 0x61, 0x73, 0x6b, 0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0e, 0x73, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x4d, 0x61, 0x73, 0x6b, 0x65, 0x64,
+	0x12, 0x2f, 0x0a, 0x09, 0x73, 0x75, 0x62, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x53, 0x75, 0x62,
+	0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x52, 0x09, 0x73, 0x75, 0x62, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x62, 0x79, 0x43, 0x72, 0x69, 0x74, 0x69, 0x63, 0x18, 0x0f, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x62, 0x79, 0x43, 0x72, 0x69, 0x74, 0x69, 0x63, 0x22, 0x69, 0x0a,
+	0x09, 0x53, 0x75, 0x62, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74,
+	0x6f, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x73, 0x74, 0x6f, 0x72, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x69, 0x73, 0x75,
+	0x61, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x69, 0x73, 0x75, 0x61,
+	0x6c, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x75, 0x73, 0x69, 0x63, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0d, 0x52, 0x05, 0x6d, 0x75, 0x73, 0x69, 0x63, 0x22, 0x7c, 0x0a, 0x0e, 0x52, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x61,
+	0x72, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61,
+	0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x72, 0x65, 0x70, 0x6c, 0x61, 0x63,
+	0x65, 0x64, 0x41, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x70, 0x6c,
+	0x61, 0x63, 0x65, 0x64, 0x41, 0x74, 0x22, 0x6c, 0x0a, 0x14, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2c,
+	0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x49, 0x44, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e,
+	0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x53, 0x70, 0x6f, 0x69,
+	0x6c, 0x65, 0x72, 0x73, 0x22, 0x6d, 0x0a, 0x0d, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x48, 0x69,
+	0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x26, 0x0a, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x34, 0x0a,
+	0x09, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x52, 0x65, 0x76, 0x69, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x72, 0x65, 0x76, 0x69, 0x73, 0x69,
+	0x6f, 0x6e, 0x73, 0x22, 0x59, 0x0a, 0x07, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x12, 0x28,
+	0x0a, 0x07, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52,
+	0x07, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x65, 0x78, 0x74,
+	0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0xe8,
+	0x01, 0x0a, 0x12, 0x46, 0x69, 0x6c, 0x6d, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x26, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x46,
+	0x69, 0x6c, 0x6d, 0x49, 0x44, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x12, 0x26, 0x0a,
+	0x04, 0x73, 0x6f, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x53, 0x6f, 0x72, 0x74, 0x52,
+	0x04, 0x73, 0x6f, 0x72, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a,
+	0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x26, 0x0a, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72,
+	0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x53,
+	0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x72, 0x69, 0x74, 0x69,
+	0x63, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x63, 0x72,
+	0x69, 0x74, 0x69, 0x63, 0x73, 0x4f, 0x6e, 0x6c, 0x79, 0x22, 0x87, 0x01, 0x0a, 0x0d, 0x4e, 0x65,
+	0x77, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61, 0x12, 0x26, 0x0a, 0x06, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x06, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x12, 0x26, 0x0a, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x46, 0x69, 0x6c,
+	0x6d, 0x49, 0x44, 0x52, 0x06, 0x66, 0x69, 0x6c, 0x6d, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x22, 0x68, 0x0a, 0x10, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x52, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0x62, 0x0a,
+	0x10, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74,
+	0x61, 0x12, 0x26, 0x0a, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x52, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x26, 0x0a, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x22, 0x7e, 0x0a, 0x08, 0x56, 0x6f, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a,
+	0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49,
+	0x44, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x48, 0x65, 0x6c, 0x70, 0x66, 0x75, 0x6c,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x48, 0x65, 0x6c, 0x70, 0x66, 0x75,
+	0x6c, 0x22, 0x66, 0x0a, 0x0e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49,
+	0x44, 0x12, 0x26, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0x60, 0x0a, 0x0a, 0x56, 0x6f, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x2a, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x2e, 0x56, 0x6f, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x52, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x22, 0x1b, 0x0a, 0x09, 0x43,
+	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x49, 0x44, 0x22, 0xb3, 0x02, 0x0a, 0x07, 0x43, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x21, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x44, 0x52, 0x02, 0x49, 0x44, 0x12, 0x2c, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x52, 0x08, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x2d, 0x0a, 0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49,
+	0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x52, 0x08, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x49, 0x44, 0x12, 0x24, 0x0a, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x64, 0x65, 0x70, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x64,
+	0x65, 0x70, 0x74, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65,
+	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18,
+	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x69,
+	0x0a, 0x0d, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x29,
+	0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0f, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0xa9, 0x01, 0x0a, 0x0e, 0x4e, 0x65,
+	0x77, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x08,
+	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10,
+	0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44,
+	0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x2d, 0x0a, 0x08, 0x70, 0x61,
+	0x72, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x52,
+	0x08, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x06, 0x75, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49,
+	0x44, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x80, 0x01, 0x0a, 0x11, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2f, 0x0a, 0x09, 0x63,
+	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49,
+	0x44, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x06,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06, 0x75, 0x73,
+	0x65, 0x72, 0x49, 0x44, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x6c, 0x0a, 0x11, 0x44, 0x65, 0x6c, 0x65,
+	0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2f, 0x0a,
+	0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x11, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x49, 0x44, 0x52, 0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x44, 0x12, 0x26,
+	0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e,
+	0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x06,
+	0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x22, 0x7f, 0x0a, 0x15, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x2c, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x49, 0x44, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x1a, 0x0a,
+	0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x70, 0x61, 0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x67,
+	0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61,
+	0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x8c, 0x01, 0x0a, 0x08, 0x43, 0x6f, 0x6d, 0x6d,
+	0x65, 0x6e, 0x74, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f,
+	0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61,
+	0x74, 0x75, 0x73, 0x12, 0x2b, 0x0a, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43,
+	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67,
+	0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x7a, 0x0a, 0x0a, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e,
+	0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x49, 0x44, 0x12, 0x26, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72,
+	0x49, 0x44, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x22, 0x40, 0x0a, 0x0c, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x30, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x22, 0x6a, 0x0a, 0x0c, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x12, 0x24, 0x0a, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65,
+	0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
+	0x22, 0x52, 0x0a, 0x16, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x51, 0x75,
+	0x65, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61,
+	0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x61,
+	0x67, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x22, 0x6d, 0x0a, 0x13, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x26, 0x0a, 0x06, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x06, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x12, 0x2e, 0x0a, 0x07, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18, 0x02,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x07, 0x72, 0x65, 0x70, 0x6f,
+	0x72, 0x74, 0x73, 0x22, 0x6a, 0x0a, 0x0f, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x31, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d,
+	0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x65, 0x78,
+	0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x22,
+	0xba, 0x01, 0x0a, 0x0e, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44, 0x61,
+	0x74, 0x61, 0x12, 0x2c, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44,
+	0x12, 0x30, 0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55,
+	0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x49, 0x44, 0x12, 0x30, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x8f, 0x01, 0x0a,
+	0x0f, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x46, 0x6c, 0x61, 0x67, 0x44, 0x61, 0x74, 0x61,
+	0x12, 0x2c, 0x0a, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x49, 0x44, 0x52, 0x08, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x49, 0x44, 0x12, 0x30,
+	0x0a, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65,
+	0x72, 0x49, 0x44, 0x52, 0x0b, 0x6d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x49, 0x44,
+	0x12, 0x1c, 0x0a, 0x09, 0x69, 0x73, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x22, 0x6c,
+	0x0a, 0x10, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x30, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x26, 0x0a, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x52, 0x06, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x22, 0x90, 0x02, 0x0a,
+	0x12, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x12, 0x2a, 0x0a, 0x09, 0x6d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x09, 0x6d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x12,
+	0x30, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x32, 0x0a, 0x09, 0x6f, 0x6c, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x09, 0x6f, 0x6c, 0x64, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x32, 0x0a, 0x09, 0x6e, 0x65, 0x77, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x09,
+	0x6e, 0x65, 0x77, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x65, 0x61,
+	0x73, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22,
+	0x77, 0x0a, 0x0d, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67,
+	0x12, 0x30, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x12, 0x34, 0x0a, 0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52,
+	0x07, 0x65, 0x6e, 0x74, 0x72, 0x69, 0x65, 0x73, 0x22, 0x96, 0x01, 0x0a, 0x12, 0x55, 0x73, 0x65,
+	0x72, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x26, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52,
+	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x30, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x0b, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x76,
+	0x65, 0x61, 0x6c, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72,
+	0x73, 0x22, 0x94, 0x02, 0x0a, 0x0b, 0x55, 0x73, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x12, 0x2d, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x20, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x04, 0x75, 0x73,
+	0x65, 0x72, 0x12, 0x1a, 0x0a, 0x08, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1c,
+	0x0a, 0x09, 0x69, 0x73, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x09, 0x69, 0x73, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x12, 0x22, 0x0a, 0x0c,
+	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x0c, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x12, 0x20, 0x0a, 0x0b, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x4d, 0x61, 0x72, 0x6b, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0b, 0x61, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x4d, 0x61,
+	0x72, 0x6b, 0x12, 0x34, 0x0a, 0x0d, 0x72, 0x65, 0x63, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x0d, 0x72, 0x65, 0x63, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x22, 0xd0, 0x01, 0x0a, 0x12, 0x55, 0x73, 0x65,
+	0x72, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x26, 0x0a, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52,
+	0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x44, 0x12, 0x30, 0x0a, 0x0b, 0x72, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x49, 0x44, 0x52, 0x0b, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x49, 0x44, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x67,
+	0x65, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x70, 0x61, 0x67,
+	0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x67, 0x65, 0x54, 0x6f,
+	0x6b, 0x65, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x72, 0x65, 0x76, 0x65, 0x61, 0x6c, 0x53, 0x70, 0x6f,
+	0x69, 0x6c, 0x65, 0x72, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0e, 0x72, 0x65, 0x76,
+	0x65, 0x61, 0x6c, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x73, 0x22, 0x8c, 0x01, 0x0a, 0x0b,
+	0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x12, 0x2d, 0x0a, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x53, 0x74, 0x61, 0x74,
+	0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x28, 0x0a, 0x07, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x52, 0x07, 0x72, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x73, 0x12, 0x24, 0x0a, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x50, 0x61, 0x67, 0x65,
+	0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6e, 0x65, 0x78,
+	0x74, 0x50, 0x61, 0x67, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x2a, 0x72, 0x0a, 0x0a, 0x52, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x53, 0x6f, 0x72, 0x74, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x4f, 0x52, 0x54,
+	0x5f, 0x4e, 0x45, 0x57, 0x45, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x4f, 0x52,
+	0x54, 0x5f, 0x4f, 0x4c, 0x44, 0x45, 0x53, 0x54, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x4f,
+	0x52, 0x54, 0x5f, 0x48, 0x49, 0x47, 0x48, 0x45, 0x53, 0x54, 0x5f, 0x4d, 0x41, 0x52, 0x4b, 0x10,
+	0x02, 0x12, 0x14, 0x0a, 0x10, 0x53, 0x4f, 0x52, 0x54, 0x5f, 0x4c, 0x4f, 0x57, 0x45, 0x53, 0x54,
+	0x5f, 0x4d, 0x41, 0x52, 0x4b, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x53, 0x4f, 0x52, 0x54, 0x5f,
+	0x4d, 0x4f, 0x53, 0x54, 0x5f, 0x48, 0x45, 0x4c, 0x50, 0x46, 0x55, 0x4c, 0x10, 0x04, 0x2a, 0x60,
+	0x0a, 0x0c, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x14,
+	0x0a, 0x10, 0x52, 0x45, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x53, 0x48,
+	0x45, 0x44, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x52, 0x45, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x50,
+	0x45, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x52, 0x45, 0x56, 0x49,
+	0x45, 0x57, 0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x02, 0x12, 0x11, 0x0a,
+	0x0d, 0x52, 0x45, 0x56, 0x49, 0x45, 0x57, 0x5f, 0x48, 0x49, 0x44, 0x44, 0x45, 0x4e, 0x10, 0x03,
+	0x2a, 0x56, 0x0a, 0x0a, 0x56, 0x6f, 0x74, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0b,
+	0x0a, 0x07, 0x56, 0x4f, 0x54, 0x45, 0x5f, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x12, 0x0a, 0x0e, 0x56,
+	0x4f, 0x54, 0x45, 0x5f, 0x4e, 0x4f, 0x5f, 0x52, 0x45, 0x56, 0x49, 0x45, 0x57, 0x10, 0x01, 0x12,
+	0x13, 0x0a, 0x0f, 0x56, 0x4f, 0x54, 0x45, 0x5f, 0x4f, 0x57, 0x4e, 0x5f, 0x52, 0x45, 0x56, 0x49,
+	0x45, 0x57, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x56, 0x4f, 0x54, 0x45, 0x5f, 0x4e, 0x4f, 0x54,
+	0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x03, 0x2a, 0x8f, 0x01, 0x0a, 0x0d, 0x43, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x0a, 0x43, 0x4f,
+	0x4d, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x4f,
+	0x4d, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4e, 0x4f, 0x5f, 0x52, 0x45, 0x56, 0x49, 0x45, 0x57, 0x10,
+	0x01, 0x12, 0x15, 0x0a, 0x11, 0x43, 0x4f, 0x4d, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4e, 0x4f, 0x5f,
+	0x50, 0x41, 0x52, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x4d, 0x4d,
+	0x45, 0x4e, 0x54, 0x5f, 0x54, 0x4f, 0x4f, 0x5f, 0x44, 0x45, 0x45, 0x50, 0x10, 0x03, 0x12, 0x15,
+	0x0a, 0x11, 0x43, 0x4f, 0x4d, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f,
+	0x55, 0x4e, 0x44, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x43, 0x4f, 0x4d, 0x4d, 0x45, 0x4e, 0x54,
+	0x5f, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x05, 0x2a, 0x7b, 0x0a, 0x10, 0x4d, 0x6f,
+	0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x11,
+	0x0a, 0x0d, 0x4d, 0x4f, 0x44, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x4b, 0x10,
+	0x00, 0x12, 0x18, 0x0a, 0x14, 0x4d, 0x4f, 0x44, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x4e, 0x4f, 0x5f, 0x52, 0x45, 0x56, 0x49, 0x45, 0x57, 0x10, 0x01, 0x12, 0x19, 0x0a, 0x15, 0x4d,
+	0x4f, 0x44, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4f, 0x57, 0x4e, 0x5f, 0x52, 0x45,
+	0x56, 0x49, 0x45, 0x57, 0x10, 0x02, 0x12, 0x1f, 0x0a, 0x1b, 0x4d, 0x4f, 0x44, 0x45, 0x52, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x4c, 0x52, 0x45, 0x41, 0x44, 0x59, 0x5f, 0x52, 0x45, 0x50,
+	0x4f, 0x52, 0x54, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x7e, 0x0a, 0x10, 0x4d, 0x6f, 0x64, 0x65, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x0e, 0x41,
+	0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x45, 0x10, 0x00, 0x12,
+	0x11, 0x0a, 0x0d, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54,
+	0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x48, 0x49, 0x44,
+	0x45, 0x10, 0x02, 0x12, 0x17, 0x0a, 0x13, 0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x41,
+	0x52, 0x4b, 0x5f, 0x53, 0x50, 0x4f, 0x49, 0x4c, 0x45, 0x52, 0x10, 0x03, 0x12, 0x19, 0x0a, 0x15,
+	0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x4d, 0x41, 0x52, 0x4b, 0x5f, 0x53, 0x50,
+	0x4f, 0x49, 0x4c, 0x45, 0x52, 0x10, 0x04, 0x2a, 0x4b, 0x0a, 0x0d, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x52, 0x4f, 0x46,
+	0x49, 0x4c, 0x45, 0x5f, 0x4f, 0x4b, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x52, 0x4f, 0x46,
+	0x49, 0x4c, 0x45, 0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x01, 0x12,
+	0x13, 0x0a, 0x0f, 0x50, 0x52, 0x4f, 0x46, 0x49, 0x4c, 0x45, 0x5f, 0x50, 0x52, 0x49, 0x56, 0x41,
+	0x54, 0x45, 0x10, 0x02, 0x32, 0x8e, 0x09, 0x0a, 0x0b, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x4d,
+	0x61, 0x6b, 0x65, 0x72, 0x12, 0x3d, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x46, 0x69, 0x6c, 0x6d, 0x52,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x12, 0x1a, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e,
+	0x46, 0x69, 0x6c, 0x6d, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x0f, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x73, 0x12, 0x32, 0x0a, 0x09, 0x4e, 0x65, 0x77, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x12, 0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4e, 0x65, 0x77, 0x52, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x3d, 0x0a, 0x0c, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74,
+	0x61, 0x1a, 0x13, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x64, 0x44, 0x61, 0x74, 0x61, 0x12, 0x38, 0x0a, 0x0c, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e,
+	0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x44, 0x61, 0x74, 0x61,
+	0x1a, 0x0e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x12, 0x47, 0x0a, 0x10, 0x47, 0x65, 0x74, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x0a, 0x56, 0x6f, 0x74,
+	0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x2e, 0x56, 0x6f, 0x74, 0x65, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x12, 0x2e, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x2e, 0x56, 0x6f, 0x74, 0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x38, 0x0a,
+	0x0a, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x12, 0x16, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x56, 0x6f, 0x74, 0x65, 0x44,
+	0x61, 0x74, 0x61, 0x1a, 0x12, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x56, 0x6f, 0x74,
+	0x65, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3b, 0x0a, 0x0a, 0x41, 0x64, 0x64, 0x43, 0x6f,
+	0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x16, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4e,
+	0x65, 0x77, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x15, 0x2e,
+	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x41, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f,
+	0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x44, 0x61, 0x74, 0x61,
+	0x1a, 0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e,
+	0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x41, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x65, 0x74,
+	0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x2e, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x44,
+	0x61, 0x74, 0x61, 0x1a, 0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d,
+	0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x44, 0x0a, 0x11, 0x47, 0x65,
+	0x74, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x12,
+	0x1d, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x43,
+	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10,
+	0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x73,
+	0x12, 0x38, 0x0a, 0x0c, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77,
+	0x12, 0x12, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74,
+	0x44, 0x61, 0x74, 0x61, 0x1a, 0x14, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x4d, 0x0a, 0x12, 0x47, 0x65,
+	0x74, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x51, 0x75, 0x65, 0x75, 0x65,
+	0x12, 0x1e, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x17, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x51, 0x75, 0x65, 0x75, 0x65, 0x12, 0x42, 0x0a, 0x0e, 0x4d, 0x6f, 0x64,
+	0x65, 0x72, 0x61, 0x74, 0x65, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x12, 0x16, 0x2e, 0x72, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x44,
+	0x61, 0x74, 0x61, 0x1a, 0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x3b, 0x0a,
+	0x10, 0x47, 0x65, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f,
+	0x67, 0x12, 0x10, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x52, 0x65, 0x76, 0x69, 0x65,
+	0x77, 0x49, 0x44, 0x1a, 0x15, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d, 0x6f, 0x64,
+	0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x6f, 0x67, 0x12, 0x43, 0x0a, 0x0e, 0x53, 0x65,
+	0x74, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x46, 0x6c, 0x61, 0x67, 0x12, 0x17, 0x2e, 0x72,
+	0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x53, 0x70, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x46, 0x6c, 0x61,
+	0x67, 0x44, 0x61, 0x74, 0x61, 0x1a, 0x18, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x4d,
+	0x6f, 0x64, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12,
+	0x41, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x66, 0x69, 0x6c,
+	0x65, 0x12, 0x1a, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x50,
+	0x72, 0x6f, 0x66, 0x69, 0x6c, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x13, 0x2e,
+	0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x50, 0x72, 0x6f, 0x66, 0x69,
+	0x6c, 0x65, 0x12, 0x41, 0x0a, 0x0e, 0x47, 0x65, 0x74, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65, 0x76,
+	0x69, 0x65, 0x77, 0x73, 0x12, 0x1a, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73,
+	0x65, 0x72, 0x52, 0x65, 0x76, 0x69, 0x65, 0x77, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x13, 0x2e, 0x72, 0x65, 0x76, 0x69, 0x65, 0x77, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x52, 0x65,
+	0x76, 0x69, 0x65, 0x77, 0x73, 0x42, 0x0b, 0x5a, 0x09, 0x2e, 0x2f, 0x3b, 0x72, 0x65, 0x76, 0x69,
+	0x65, 0x77, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_review_proto_rawDescOnce sync.Once
+	file_review_proto_rawDescData = file_review_proto_rawDesc
+)
+
+func file_review_proto_rawDescGZIP() []byte {
+	file_review_proto_rawDescOnce.Do(func() {
+		file_review_proto_rawDescData = protoimpl.X.CompressGZIP(file_review_proto_rawDescData)
+	})
+	return file_review_proto_rawDescData
+}
+
+var file_review_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_review_proto_msgTypes = make([]protoimpl.MessageInfo, 41)
+var file_review_proto_goTypes = []interface{}{
+	(ReviewSort)(0),                // 0: review.ReviewSort
+	(ReviewStatus)(0),              // 1: review.ReviewStatus
+	(VoteStatus)(0),                // 2: review.VoteStatus
+	(CommentStatus)(0),             // 3: review.CommentStatus
+	(ModerationStatus)(0),          // 4: review.ModerationStatus
+	(ModerationAction)(0),          // 5: review.ModerationAction
+	(ProfileStatus)(0),             // 6: review.ProfileStatus
+	(*UserID)(nil),                 // 7: review.UserID
+	(*User)(nil),                   // 8: review.User
+	(*FilmID)(nil),                 // 9: review.FilmID
+	(*ReviewID)(nil),               // 10: review.ReviewID
+	(*DeletedData)(nil),            // 11: review.DeletedData
+	(*Review)(nil),                 // 12: review.Review
+	(*SubScores)(nil),              // 13: review.SubScores
+	(*ReviewRevision)(nil),         // 14: review.ReviewRevision
+	(*ReviewHistoryRequest)(nil),   // 15: review.ReviewHistoryRequest
+	(*ReviewHistory)(nil),          // 16: review.ReviewHistory
+	(*Reviews)(nil),                // 17: review.Reviews
+	(*FilmReviewsRequest)(nil),     // 18: review.FilmReviewsRequest
+	(*NewReviewData)(nil),          // 19: review.NewReviewData
+	(*DeleteReviewData)(nil),       // 20: review.DeleteReviewData
+	(*UpdateReviewData)(nil),       // 21: review.UpdateReviewData
+	(*VoteData)(nil),               // 22: review.VoteData
+	(*DeleteVoteData)(nil),         // 23: review.DeleteVoteData
+	(*VoteResult)(nil),             // 24: review.VoteResult
+	(*CommentID)(nil),              // 25: review.CommentID
+	(*Comment)(nil),                // 26: review.Comment
+	(*CommentResult)(nil),          // 27: review.CommentResult
+	(*NewCommentData)(nil),         // 28: review.NewCommentData
+	(*UpdateCommentData)(nil),      // 29: review.UpdateCommentData
+	(*DeleteCommentData)(nil),      // 30: review.DeleteCommentData
+	(*ReviewCommentsRequest)(nil),  // 31: review.ReviewCommentsRequest
+	(*Comments)(nil),               // 32: review.Comments
+	(*ReportData)(nil),             // 33: review.ReportData
+	(*ReportResult)(nil),           // 34: review.ReportResult
+	(*ReviewReport)(nil),           // 35: review.ReviewReport
+	(*ModerationQueueRequest)(nil), // 36: review.ModerationQueueRequest
+	(*ModerationQueueItem)(nil),    // 37: review.ModerationQueueItem
+	(*ModerationQueue)(nil),        // 38: review.ModerationQueue
+	(*ModerationData)(nil),         // 39: review.ModerationData
+	(*SpoilerFlagData)(nil),        // 40: review.SpoilerFlagData
+	(*ModerationResult)(nil),       // 41: review.ModerationResult
+	(*ModerationLogEntry)(nil),     // 42: review.ModerationLogEntry
+	(*ModerationLog)(nil),          // 43: review.ModerationLog
+	(*UserProfileRequest)(nil),     // 44: review.UserProfileRequest
+	(*UserProfile)(nil),            // 45: review.UserProfile
+	(*UserReviewsRequest)(nil),     // 46: review.UserReviewsRequest
+	(*UserReviews)(nil),            // 47: review.UserReviews
+}
+var file_review_proto_depIdxs = []int32{
+	7,  // 0: review.User.ID:type_name -> review.UserID
+	12, // 1: review.DeletedData.review:type_name -> review.Review
+	10, // 2: review.Review.ID:type_name -> review.ReviewID
+	8,  // 3: review.Review.author:type_name -> review.User
+	9,  // 4: review.Review.filmID:type_name -> review.FilmID
+	1,  // 5: review.Review.status:type_name -> review.ReviewStatus
+	13, // 6: review.Review.subScores:type_name -> review.SubScores
+	10, // 7: review.ReviewHistoryRequest.reviewID:type_name -> review.ReviewID
+	12, // 8: review.ReviewHistory.review:type_name -> review.Review
+	14, // 9: review.ReviewHistory.revisions:type_name -> review.ReviewRevision
+	12, // 10: review.Reviews.reviews:type_name -> review.Review
+	9,  // 11: review.FilmReviewsRequest.filmID:type_name -> review.FilmID
+	0,  // 12: review.FilmReviewsRequest.sort:type_name -> review.ReviewSort
+	12, // 13: review.NewReviewData.review:type_name -> review.Review
+	9,  // 14: review.NewReviewData.filmID:type_name -> review.FilmID
+	7,  // 15: review.NewReviewData.userID:type_name -> review.UserID
+	10, // 16: review.DeleteReviewData.reviewID:type_name -> review.ReviewID
+	7,  // 17: review.DeleteReviewData.userID:type_name -> review.UserID
+	12, // 18: review.UpdateReviewData.review:type_name -> review.Review
+	7,  // 19: review.UpdateReviewData.userID:type_name -> review.UserID
+	10, // 20: review.VoteData.reviewID:type_name -> review.ReviewID
+	7,  // 21: review.VoteData.userID:type_name -> review.UserID
+	10, // 22: review.DeleteVoteData.reviewID:type_name -> review.ReviewID
+	7,  // 23: review.DeleteVoteData.userID:type_name -> review.UserID
+	2,  // 24: review.VoteResult.status:type_name -> review.VoteStatus
+	12, // 25: review.VoteResult.review:type_name -> review.Review
+	25, // 26: review.Comment.ID:type_name -> review.CommentID
+	10, // 27: review.Comment.reviewID:type_name -> review.ReviewID
+	25, // 28: review.Comment.parentID:type_name -> review.CommentID
+	8,  // 29: review.Comment.author:type_name -> review.User
+	3,  // 30: review.CommentResult.status:type_name -> review.CommentStatus
+	26, // 31: review.CommentResult.comment:type_name -> review.Comment
+	10, // 32: review.NewCommentData.reviewID:type_name -> review.ReviewID
+	25, // 33: review.NewCommentData.parentID:type_name -> review.CommentID
+	7,  // 34: review.NewCommentData.userID:type_name -> review.UserID
+	25, // 35: review.UpdateCommentData.commentID:type_name -> review.CommentID
+	7,  // 36: review.UpdateCommentData.userID:type_name -> review.UserID
+	25, // 37: review.DeleteCommentData.commentID:type_name -> review.CommentID
+	7,  // 38: review.DeleteCommentData.userID:type_name -> review.UserID
+	10, // 39: review.ReviewCommentsRequest.reviewID:type_name -> review.ReviewID
+	3,  // 40: review.Comments.status:type_name -> review.CommentStatus
+	26, // 41: review.Comments.comments:type_name -> review.Comment
+	10, // 42: review.ReportData.reviewID:type_name -> review.ReviewID
+	7,  // 43: review.ReportData.userID:type_name -> review.UserID
+	4,  // 44: review.ReportResult.status:type_name -> review.ModerationStatus
+	8,  // 45: review.ReviewReport.author:type_name -> review.User
+	12, // 46: review.ModerationQueueItem.review:type_name -> review.Review
+	35, // 47: review.ModerationQueueItem.reports:type_name -> review.ReviewReport
+	37, // 48: review.ModerationQueue.items:type_name -> review.ModerationQueueItem
+	10, // 49: review.ModerationData.reviewID:type_name -> review.ReviewID
+	7,  // 50: review.ModerationData.moderatorID:type_name -> review.UserID
+	5,  // 51: review.ModerationData.action:type_name -> review.ModerationAction
+	10, // 52: review.SpoilerFlagData.reviewID:type_name -> review.ReviewID
+	7,  // 53: review.SpoilerFlagData.moderatorID:type_name -> review.UserID
+	4,  // 54: review.ModerationResult.status:type_name -> review.ModerationStatus
+	12, // 55: review.ModerationResult.review:type_name -> review.Review
+	8,  // 56: review.ModerationLogEntry.moderator:type_name -> review.User
+	5,  // 57: review.ModerationLogEntry.action:type_name -> review.ModerationAction
+	1,  // 58: review.ModerationLogEntry.oldStatus:type_name -> review.ReviewStatus
+	1,  // 59: review.ModerationLogEntry.newStatus:type_name -> review.ReviewStatus
+	4,  // 60: review.ModerationLog.status:type_name -> review.ModerationStatus
+	42, // 61: review.ModerationLog.entries:type_name -> review.ModerationLogEntry
+	7,  // 62: review.UserProfileRequest.userID:type_name -> review.UserID
+	7,  // 63: review.UserProfileRequest.requesterID:type_name -> review.UserID
+	6,  // 64: review.UserProfile.status:type_name -> review.ProfileStatus
+	8,  // 65: review.UserProfile.user:type_name -> review.User
+	12, // 66: review.UserProfile.recentReviews:type_name -> review.Review
+	7,  // 67: review.UserReviewsRequest.userID:type_name -> review.UserID
+	7,  // 68: review.UserReviewsRequest.requesterID:type_name -> review.UserID
+	6,  // 69: review.UserReviews.status:type_name -> review.ProfileStatus
+	12, // 70: review.UserReviews.reviews:type_name -> review.Review
+	18, // 71: review.ReviewMaker.GetFilmReviews:input_type -> review.FilmReviewsRequest
+	19, // 72: review.ReviewMaker.NewReview:input_type -> review.NewReviewData
+	20, // 73: review.ReviewMaker.DeleteReview:input_type -> review.DeleteReviewData
+	21, // 74: review.ReviewMaker.UpdateReview:input_type -> review.UpdateReviewData
+	15, // 75: review.ReviewMaker.GetReviewHistory:input_type -> review.ReviewHistoryRequest
+	22, // 76: review.ReviewMaker.VoteReview:input_type -> review.VoteData
+	23, // 77: review.ReviewMaker.DeleteVote:input_type -> review.DeleteVoteData
+	28, // 78: review.ReviewMaker.AddComment:input_type -> review.NewCommentData
+	29, // 79: review.ReviewMaker.UpdateComment:input_type -> review.UpdateCommentData
+	30, // 80: review.ReviewMaker.DeleteComment:input_type -> review.DeleteCommentData
+	31, // 81: review.ReviewMaker.GetReviewComments:input_type -> review.ReviewCommentsRequest
+	33, // 82: review.ReviewMaker.ReportReview:input_type -> review.ReportData
+	36, // 83: review.ReviewMaker.GetModerationQueue:input_type -> review.ModerationQueueRequest
+	39, // 84: review.ReviewMaker.ModerateReview:input_type -> review.ModerationData
+	10, // 85: review.ReviewMaker.GetModerationLog:input_type -> review.ReviewID
+	40, // 86: review.ReviewMaker.SetSpoilerFlag:input_type -> review.SpoilerFlagData
+	44, // 87: review.ReviewMaker.GetUserProfile:input_type -> review.UserProfileRequest
+	46, // 88: review.ReviewMaker.GetUserReviews:input_type -> review.UserReviewsRequest
+	17, // 89: review.ReviewMaker.GetFilmReviews:output_type -> review.Reviews
+	12, // 90: review.ReviewMaker.NewReview:output_type -> review.Review
+	11, // 91: review.ReviewMaker.DeleteReview:output_type -> review.DeletedData
+	12, // 92: review.ReviewMaker.UpdateReview:output_type -> review.Review
+	16, // 93: review.ReviewMaker.GetReviewHistory:output_type -> review.ReviewHistory
+	24, // 94: review.ReviewMaker.VoteReview:output_type -> review.VoteResult
+	24, // 95: review.ReviewMaker.DeleteVote:output_type -> review.VoteResult
+	27, // 96: review.ReviewMaker.AddComment:output_type -> review.CommentResult
+	27, // 97: review.ReviewMaker.UpdateComment:output_type -> review.CommentResult
+	27, // 98: review.ReviewMaker.DeleteComment:output_type -> review.CommentResult
+	32, // 99: review.ReviewMaker.GetReviewComments:output_type -> review.Comments
+	34, // 100: review.ReviewMaker.ReportReview:output_type -> review.ReportResult
+	38, // 101: review.ReviewMaker.GetModerationQueue:output_type -> review.ModerationQueue
+	41, // 102: review.ReviewMaker.ModerateReview:output_type -> review.ModerationResult
+	43, // 103: review.ReviewMaker.GetModerationLog:output_type -> review.ModerationLog
+	41, // 104: review.ReviewMaker.SetSpoilerFlag:output_type -> review.ModerationResult
+	45, // 105: review.ReviewMaker.GetUserProfile:output_type -> review.UserProfile
+	47, // 106: review.ReviewMaker.GetUserReviews:output_type -> review.UserReviews
+	89, // [89:107] is the sub-list for method output_type
+	71, // [71:89] is the sub-list for method input_type
+	71, // [71:71] is the sub-list for extension type_name
+	71, // [71:71] is the sub-list for extension extendee
+	0,  // [0:71] is the sub-list for field type_name
+}
+
+func init() { file_review_proto_init() }
+func file_review_proto_init() {
+	if File_review_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_review_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*User); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilmID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReviewID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeletedData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Review); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SubScores); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReviewRevision); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReviewHistoryRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReviewHistory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Reviews); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FilmReviewsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
 				return &v.sizeCache
 			case 2:
 				return &v.unknownFields
@@ -763,8 +3849,8 @@ func file_review_proto_init() {
 				return nil
 			}
 		}
-		file_review_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Review); i {
+		file_review_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewReviewData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -775,8 +3861,8 @@ func file_review_proto_init() {
 				return nil
 			}
 		}
-		file_review_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*Reviews); i {
+		file_review_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteReviewData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -787,8 +3873,8 @@ func file_review_proto_init() {
 				return nil
 			}
 		}
-		file_review_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*NewReviewData); i {
+		file_review_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateReviewData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -799,8 +3885,8 @@ func file_review_proto_init() {
 				return nil
 			}
 		}
-		file_review_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DeleteReviewData); i {
+		file_review_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VoteData); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -811,8 +3897,296 @@ func file_review_proto_init() {
 				return nil
 			}
 		}
-		file_review_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*UpdateReviewData); i {
+		file_review_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteVoteData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VoteResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommentID); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Comment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CommentResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NewCommentData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UpdateCommentData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DeleteCommentData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReviewCommentsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Comments); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReportResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ReviewReport); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModerationQueueRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModerationQueueItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModerationQueue); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModerationData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SpoilerFlagData); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[34].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModerationResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[35].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModerationLogEntry); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[36].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ModerationLog); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[37].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserProfileRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[38].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserProfile); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[39].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserReviewsRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_review_proto_msgTypes[40].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserReviews); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -829,13 +4203,14 @@ func file_review_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_review_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   10,
+			NumEnums:      7,
+			NumMessages:   41,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_review_proto_goTypes,
 		DependencyIndexes: file_review_proto_depIdxs,
+		EnumInfos:         file_review_proto_enumTypes,
 		MessageInfos:      file_review_proto_msgTypes,
 	}.Build()
 	File_review_proto = out.File
@@ -18,10 +18,24 @@ const _ = grpc.SupportPackageIsVersion7
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type ReviewMakerClient interface {
-	GetFilmReviews(ctx context.Context, in *FilmID, opts ...grpc.CallOption) (*Reviews, error)
+	GetFilmReviews(ctx context.Context, in *FilmReviewsRequest, opts ...grpc.CallOption) (*Reviews, error)
 	NewReview(ctx context.Context, in *NewReviewData, opts ...grpc.CallOption) (*Review, error)
 	DeleteReview(ctx context.Context, in *DeleteReviewData, opts ...grpc.CallOption) (*DeletedData, error)
 	UpdateReview(ctx context.Context, in *UpdateReviewData, opts ...grpc.CallOption) (*Review, error)
+	GetReviewHistory(ctx context.Context, in *ReviewHistoryRequest, opts ...grpc.CallOption) (*ReviewHistory, error)
+	VoteReview(ctx context.Context, in *VoteData, opts ...grpc.CallOption) (*VoteResult, error)
+	DeleteVote(ctx context.Context, in *DeleteVoteData, opts ...grpc.CallOption) (*VoteResult, error)
+	AddComment(ctx context.Context, in *NewCommentData, opts ...grpc.CallOption) (*CommentResult, error)
+	UpdateComment(ctx context.Context, in *UpdateCommentData, opts ...grpc.CallOption) (*CommentResult, error)
+	DeleteComment(ctx context.Context, in *DeleteCommentData, opts ...grpc.CallOption) (*CommentResult, error)
+	GetReviewComments(ctx context.Context, in *ReviewCommentsRequest, opts ...grpc.CallOption) (*Comments, error)
+	ReportReview(ctx context.Context, in *ReportData, opts ...grpc.CallOption) (*ReportResult, error)
+	GetModerationQueue(ctx context.Context, in *ModerationQueueRequest, opts ...grpc.CallOption) (*ModerationQueue, error)
+	ModerateReview(ctx context.Context, in *ModerationData, opts ...grpc.CallOption) (*ModerationResult, error)
+	GetModerationLog(ctx context.Context, in *ReviewID, opts ...grpc.CallOption) (*ModerationLog, error)
+	SetSpoilerFlag(ctx context.Context, in *SpoilerFlagData, opts ...grpc.CallOption) (*ModerationResult, error)
+	GetUserProfile(ctx context.Context, in *UserProfileRequest, opts ...grpc.CallOption) (*UserProfile, error)
+	GetUserReviews(ctx context.Context, in *UserReviewsRequest, opts ...grpc.CallOption) (*UserReviews, error)
 }
 
 type reviewMakerClient struct {
@@ -32,7 +46,7 @@ func NewReviewMakerClient(cc grpc.ClientConnInterface) ReviewMakerClient {
 	return &reviewMakerClient{cc}
 }
 
-func (c *reviewMakerClient) GetFilmReviews(ctx context.Context, in *FilmID, opts ...grpc.CallOption) (*Reviews, error) {
+func (c *reviewMakerClient) GetFilmReviews(ctx context.Context, in *FilmReviewsRequest, opts ...grpc.CallOption) (*Reviews, error) {
 	out := new(Reviews)
 	err := c.cc.Invoke(ctx, "/review.ReviewMaker/GetFilmReviews", in, out, opts...)
 	if err != nil {
@@ -68,14 +82,154 @@ func (c *reviewMakerClient) UpdateReview(ctx context.Context, in *UpdateReviewDa
 	return out, nil
 }
 
+func (c *reviewMakerClient) GetReviewHistory(ctx context.Context, in *ReviewHistoryRequest, opts ...grpc.CallOption) (*ReviewHistory, error) {
+	out := new(ReviewHistory)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/GetReviewHistory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) VoteReview(ctx context.Context, in *VoteData, opts ...grpc.CallOption) (*VoteResult, error) {
+	out := new(VoteResult)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/VoteReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) DeleteVote(ctx context.Context, in *DeleteVoteData, opts ...grpc.CallOption) (*VoteResult, error) {
+	out := new(VoteResult)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/DeleteVote", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) AddComment(ctx context.Context, in *NewCommentData, opts ...grpc.CallOption) (*CommentResult, error) {
+	out := new(CommentResult)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/AddComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) UpdateComment(ctx context.Context, in *UpdateCommentData, opts ...grpc.CallOption) (*CommentResult, error) {
+	out := new(CommentResult)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/UpdateComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) DeleteComment(ctx context.Context, in *DeleteCommentData, opts ...grpc.CallOption) (*CommentResult, error) {
+	out := new(CommentResult)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/DeleteComment", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) GetReviewComments(ctx context.Context, in *ReviewCommentsRequest, opts ...grpc.CallOption) (*Comments, error) {
+	out := new(Comments)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/GetReviewComments", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) ReportReview(ctx context.Context, in *ReportData, opts ...grpc.CallOption) (*ReportResult, error) {
+	out := new(ReportResult)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/ReportReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) GetModerationQueue(ctx context.Context, in *ModerationQueueRequest, opts ...grpc.CallOption) (*ModerationQueue, error) {
+	out := new(ModerationQueue)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/GetModerationQueue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) ModerateReview(ctx context.Context, in *ModerationData, opts ...grpc.CallOption) (*ModerationResult, error) {
+	out := new(ModerationResult)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/ModerateReview", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) GetModerationLog(ctx context.Context, in *ReviewID, opts ...grpc.CallOption) (*ModerationLog, error) {
+	out := new(ModerationLog)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/GetModerationLog", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) SetSpoilerFlag(ctx context.Context, in *SpoilerFlagData, opts ...grpc.CallOption) (*ModerationResult, error) {
+	out := new(ModerationResult)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/SetSpoilerFlag", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) GetUserProfile(ctx context.Context, in *UserProfileRequest, opts ...grpc.CallOption) (*UserProfile, error) {
+	out := new(UserProfile)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/GetUserProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *reviewMakerClient) GetUserReviews(ctx context.Context, in *UserReviewsRequest, opts ...grpc.CallOption) (*UserReviews, error) {
+	out := new(UserReviews)
+	err := c.cc.Invoke(ctx, "/review.ReviewMaker/GetUserReviews", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ReviewMakerServer is the server API for ReviewMaker service.
 // All implementations must embed UnimplementedReviewMakerServer
 // for forward compatibility
 type ReviewMakerServer interface {
-	GetFilmReviews(context.Context, *FilmID) (*Reviews, error)
+	GetFilmReviews(context.Context, *FilmReviewsRequest) (*Reviews, error)
 	NewReview(context.Context, *NewReviewData) (*Review, error)
 	DeleteReview(context.Context, *DeleteReviewData) (*DeletedData, error)
 	UpdateReview(context.Context, *UpdateReviewData) (*Review, error)
+	GetReviewHistory(context.Context, *ReviewHistoryRequest) (*ReviewHistory, error)
+	VoteReview(context.Context, *VoteData) (*VoteResult, error)
+	DeleteVote(context.Context, *DeleteVoteData) (*VoteResult, error)
+	AddComment(context.Context, *NewCommentData) (*CommentResult, error)
+	UpdateComment(context.Context, *UpdateCommentData) (*CommentResult, error)
+	DeleteComment(context.Context, *DeleteCommentData) (*CommentResult, error)
+	GetReviewComments(context.Context, *ReviewCommentsRequest) (*Comments, error)
+	ReportReview(context.Context, *ReportData) (*ReportResult, error)
+	GetModerationQueue(context.Context, *ModerationQueueRequest) (*ModerationQueue, error)
+	ModerateReview(context.Context, *ModerationData) (*ModerationResult, error)
+	GetModerationLog(context.Context, *ReviewID) (*ModerationLog, error)
+	SetSpoilerFlag(context.Context, *SpoilerFlagData) (*ModerationResult, error)
+	GetUserProfile(context.Context, *UserProfileRequest) (*UserProfile, error)
+	GetUserReviews(context.Context, *UserReviewsRequest) (*UserReviews, error)
 	mustEmbedUnimplementedReviewMakerServer()
 }
 
@@ -83,7 +237,7 @@ type ReviewMakerServer interface {
 type UnimplementedReviewMakerServer struct {
 }
 
-func (UnimplementedReviewMakerServer) GetFilmReviews(context.Context, *FilmID) (*Reviews, error) {
+func (UnimplementedReviewMakerServer) GetFilmReviews(context.Context, *FilmReviewsRequest) (*Reviews, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetFilmReviews not implemented")
 }
 func (UnimplementedReviewMakerServer) NewReview(context.Context, *NewReviewData) (*Review, error) {
@@ -95,6 +249,48 @@ func (UnimplementedReviewMakerServer) DeleteReview(context.Context, *DeleteRevie
 func (UnimplementedReviewMakerServer) UpdateReview(context.Context, *UpdateReviewData) (*Review, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateReview not implemented")
 }
+func (UnimplementedReviewMakerServer) GetReviewHistory(context.Context, *ReviewHistoryRequest) (*ReviewHistory, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviewHistory not implemented")
+}
+func (UnimplementedReviewMakerServer) VoteReview(context.Context, *VoteData) (*VoteResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoteReview not implemented")
+}
+func (UnimplementedReviewMakerServer) DeleteVote(context.Context, *DeleteVoteData) (*VoteResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteVote not implemented")
+}
+func (UnimplementedReviewMakerServer) AddComment(context.Context, *NewCommentData) (*CommentResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddComment not implemented")
+}
+func (UnimplementedReviewMakerServer) UpdateComment(context.Context, *UpdateCommentData) (*CommentResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateComment not implemented")
+}
+func (UnimplementedReviewMakerServer) DeleteComment(context.Context, *DeleteCommentData) (*CommentResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteComment not implemented")
+}
+func (UnimplementedReviewMakerServer) GetReviewComments(context.Context, *ReviewCommentsRequest) (*Comments, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReviewComments not implemented")
+}
+func (UnimplementedReviewMakerServer) ReportReview(context.Context, *ReportData) (*ReportResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReportReview not implemented")
+}
+func (UnimplementedReviewMakerServer) GetModerationQueue(context.Context, *ModerationQueueRequest) (*ModerationQueue, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModerationQueue not implemented")
+}
+func (UnimplementedReviewMakerServer) ModerateReview(context.Context, *ModerationData) (*ModerationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateReview not implemented")
+}
+func (UnimplementedReviewMakerServer) GetModerationLog(context.Context, *ReviewID) (*ModerationLog, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetModerationLog not implemented")
+}
+func (UnimplementedReviewMakerServer) SetSpoilerFlag(context.Context, *SpoilerFlagData) (*ModerationResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetSpoilerFlag not implemented")
+}
+func (UnimplementedReviewMakerServer) GetUserProfile(context.Context, *UserProfileRequest) (*UserProfile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserProfile not implemented")
+}
+func (UnimplementedReviewMakerServer) GetUserReviews(context.Context, *UserReviewsRequest) (*UserReviews, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUserReviews not implemented")
+}
 func (UnimplementedReviewMakerServer) mustEmbedUnimplementedReviewMakerServer() {}
 
 // UnsafeReviewMakerServer may be embedded to opt out of forward compatibility for this service.
@@ -109,7 +305,7 @@ func RegisterReviewMakerServer(s grpc.ServiceRegistrar, srv ReviewMakerServer) {
 }
 
 func _ReviewMaker_GetFilmReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(FilmID)
+	in := new(FilmReviewsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -121,7 +317,7 @@ func _ReviewMaker_GetFilmReviews_Handler(srv interface{}, ctx context.Context, d
 		FullMethod: "/review.ReviewMaker/GetFilmReviews",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ReviewMakerServer).GetFilmReviews(ctx, req.(*FilmID))
+		return srv.(ReviewMakerServer).GetFilmReviews(ctx, req.(*FilmReviewsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -180,6 +376,258 @@ func _ReviewMaker_UpdateReview_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ReviewMaker_GetReviewHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).GetReviewHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/GetReviewHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).GetReviewHistory(ctx, req.(*ReviewHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_VoteReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoteData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).VoteReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/VoteReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).VoteReview(ctx, req.(*VoteData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_DeleteVote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteVoteData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).DeleteVote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/DeleteVote",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).DeleteVote(ctx, req.(*DeleteVoteData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_AddComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewCommentData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).AddComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/AddComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).AddComment(ctx, req.(*NewCommentData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_UpdateComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCommentData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).UpdateComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/UpdateComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).UpdateComment(ctx, req.(*UpdateCommentData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_DeleteComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCommentData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).DeleteComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/DeleteComment",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).DeleteComment(ctx, req.(*DeleteCommentData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_GetReviewComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).GetReviewComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/GetReviewComments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).GetReviewComments(ctx, req.(*ReviewCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_ReportReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReportData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).ReportReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/ReportReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).ReportReview(ctx, req.(*ReportData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_GetModerationQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerationQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).GetModerationQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/GetModerationQueue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).GetModerationQueue(ctx, req.(*ModerationQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_ModerateReview_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerationData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).ModerateReview(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/ModerateReview",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).ModerateReview(ctx, req.(*ModerationData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_GetModerationLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReviewID)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).GetModerationLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/GetModerationLog",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).GetModerationLog(ctx, req.(*ReviewID))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_SetSpoilerFlag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpoilerFlagData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).SetSpoilerFlag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/SetSpoilerFlag",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).SetSpoilerFlag(ctx, req.(*SpoilerFlagData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_GetUserProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).GetUserProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/GetUserProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).GetUserProfile(ctx, req.(*UserProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ReviewMaker_GetUserReviews_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserReviewsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ReviewMakerServer).GetUserReviews(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/review.ReviewMaker/GetUserReviews",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ReviewMakerServer).GetUserReviews(ctx, req.(*UserReviewsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ReviewMaker_ServiceDesc is the grpc.ServiceDesc for ReviewMaker service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -203,6 +651,62 @@ var ReviewMaker_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateReview",
 			Handler:    _ReviewMaker_UpdateReview_Handler,
 		},
+		{
+			MethodName: "GetReviewHistory",
+			Handler:    _ReviewMaker_GetReviewHistory_Handler,
+		},
+		{
+			MethodName: "VoteReview",
+			Handler:    _ReviewMaker_VoteReview_Handler,
+		},
+		{
+			MethodName: "DeleteVote",
+			Handler:    _ReviewMaker_DeleteVote_Handler,
+		},
+		{
+			MethodName: "AddComment",
+			Handler:    _ReviewMaker_AddComment_Handler,
+		},
+		{
+			MethodName: "UpdateComment",
+			Handler:    _ReviewMaker_UpdateComment_Handler,
+		},
+		{
+			MethodName: "DeleteComment",
+			Handler:    _ReviewMaker_DeleteComment_Handler,
+		},
+		{
+			MethodName: "GetReviewComments",
+			Handler:    _ReviewMaker_GetReviewComments_Handler,
+		},
+		{
+			MethodName: "ReportReview",
+			Handler:    _ReviewMaker_ReportReview_Handler,
+		},
+		{
+			MethodName: "GetModerationQueue",
+			Handler:    _ReviewMaker_GetModerationQueue_Handler,
+		},
+		{
+			MethodName: "ModerateReview",
+			Handler:    _ReviewMaker_ModerateReview_Handler,
+		},
+		{
+			MethodName: "GetModerationLog",
+			Handler:    _ReviewMaker_GetModerationLog_Handler,
+		},
+		{
+			MethodName: "SetSpoilerFlag",
+			Handler:    _ReviewMaker_SetSpoilerFlag_Handler,
+		},
+		{
+			MethodName: "GetUserProfile",
+			Handler:    _ReviewMaker_GetUserProfile_Handler,
+		},
+		{
+			MethodName: "GetUserReviews",
+			Handler:    _ReviewMaker_GetUserReviews_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "review.proto",
@@ -0,0 +1,174 @@
+package reviewservicerepo
+
+import (
+	"database/sql"
+	"errors"
+	errorreview "kinopoisk/service_review/error"
+	review "kinopoisk/service_review/proto"
+	"strings"
+)
+
+const commentColumns = "c.id, c.review_id, c.parent_id, c.user_id, u.username, c.text, c.depth, c.is_deleted, c.created_at, c.updated_at"
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func (r *ReviewRepoMySQL) GetCommentByIDRepo(commentID uint64) (*review.Comment, error) {
+	row := r.db.QueryRow("SELECT "+commentColumns+" FROM review_comments c JOIN users u ON c.user_id = u.id WHERE c.id = ?", commentID)
+	comment, err := scanComment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errorreview.ErrorNoComment
+	}
+	if err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// NewCommentRepo adds a comment to the review; replies inherit the thread of their parent and are one level deeper.
+func (r *ReviewRepoMySQL) NewCommentRepo(reviewID, parentID, userID uint64, text string) (*review.Comment, error) {
+	var res sql.Result
+	var err error
+	if parentID == 0 {
+		res, err = r.db.Exec(
+			"INSERT INTO review_comments (`review_id`, `user_id`, `text`) VALUES (?, ?, ?)",
+			reviewID,
+			userID,
+			text,
+		)
+	} else {
+		res, err = r.db.Exec(
+			"INSERT INTO review_comments (`review_id`, `parent_id`, `thread_id`, `user_id`, `depth`, `text`) SELECT review_id, id, COALESCE(thread_id, id), ?, depth + 1, ? FROM review_comments WHERE id = ?",
+			userID,
+			text,
+			parentID,
+		)
+	}
+	if err != nil {
+		return nil, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+	return r.GetCommentByIDRepo(uint64(id))
+}
+
+// UpdateCommentRepo changes the text of the comment unless it is deleted or written by another user, then it
+// returns ErrorNoComment. MySQL does not count the row which keeps its text as affected, so the comment is read
+// back to tell such an edit from a missed one.
+func (r *ReviewRepoMySQL) UpdateCommentRepo(commentID, userID uint64, text string) (*review.Comment, error) {
+	res, err := r.db.Exec(
+		"UPDATE review_comments SET text = ? WHERE id = ? AND user_id = ? AND is_deleted = FALSE",
+		text,
+		commentID,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	comment, err := r.GetCommentByIDRepo(commentID)
+	if err != nil {
+		return nil, err
+	}
+	if updated == 0 && (comment.GetAuthor().GetID().GetID() != userID || comment.GetIsDeleted() || comment.GetText() != text) {
+		return nil, errorreview.ErrorNoComment
+	}
+	return comment, nil
+}
+
+// DeleteCommentRepo leaves a tombstone instead of the comment, so the replies keep their place in the thread.
+// It returns ErrorNoComment if the comment is already deleted or written by another user.
+func (r *ReviewRepoMySQL) DeleteCommentRepo(commentID, userID uint64) (*review.Comment, error) {
+	res, err := r.db.Exec(
+		"UPDATE review_comments SET text = '', is_deleted = TRUE WHERE id = ? AND user_id = ? AND is_deleted = FALSE",
+		commentID,
+		userID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if deleted == 0 {
+		return nil, errorreview.ErrorNoComment
+	}
+	return r.GetCommentByIDRepo(commentID)
+}
+
+func (r *ReviewRepoMySQL) GetReviewThreadsRepo(reviewID, limit, offset uint64) ([]*review.Comment, error) {
+	rows, err := r.db.Query(
+		"SELECT "+commentColumns+" FROM review_comments c JOIN users u ON c.user_id = u.id WHERE c.review_id = ? AND c.parent_id IS NULL ORDER BY c.created_at, c.id LIMIT ? OFFSET ?",
+		reviewID,
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanComments(rows)
+}
+
+func (r *ReviewRepoMySQL) GetThreadsRepliesRepo(threadIDs []uint64) ([]*review.Comment, error) {
+	if len(threadIDs) == 0 {
+		return []*review.Comment{}, nil
+	}
+	args := make([]interface{}, len(threadIDs))
+	for i, threadID := range threadIDs {
+		args[i] = threadID
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(threadIDs)), ", ")
+	rows, err := r.db.Query(
+		"SELECT "+commentColumns+" FROM review_comments c JOIN users u ON c.user_id = u.id WHERE c.thread_id IN ("+placeholders+") ORDER BY c.created_at, c.id",
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return r.scanComments(rows)
+}
+
+func (r *ReviewRepoMySQL) scanComments(rows *sql.Rows) ([]*review.Comment, error) {
+	defer func(rows *sql.Rows) {
+		err := rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	comments := []*review.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+func scanComment(row rowScanner) (*review.Comment, error) {
+	comment := &review.Comment{
+		ID:       &review.CommentID{},
+		ReviewID: &review.ReviewID{},
+		Author: &review.User{
+			ID: &review.UserID{},
+		},
+	}
+	var parentID sql.NullInt64
+	err := row.Scan(&comment.ID.ID, &comment.ReviewID.ID, &parentID, &comment.Author.ID.ID, &comment.Author.Username,
+		&comment.Text, &comment.Depth, &comment.IsDeleted, &comment.CreatedAt, &comment.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if parentID.Valid {
+		comment.ParentID = &review.CommentID{ID: uint64(parentID.Int64)}
+	}
+	return comment, nil
+}
@@ -0,0 +1,105 @@
+package reviewservicerepo_test
+
+import (
+	"go.uber.org/zap"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+	errorreview "kinopoisk/service_review/error"
+	reviewservicerepo "kinopoisk/service_review/repo/mysql"
+	"regexp"
+	"testing"
+)
+
+func expectComment(mock sqlmock.Sqlmock, userID uint64, text string, isDeleted bool) {
+	mock.
+		ExpectQuery(regexp.QuoteMeta("FROM review_comments c JOIN users u ON c.user_id = u.id WHERE c.id = ?")).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "review_id", "parent_id", "user_id", "username", "text", "depth",
+			"is_deleted", "created_at", "updated_at"}).
+			AddRow(1, 2, nil, userID, "author", text, 0, isDeleted, "2024-01-01 00:00:00", "2024-01-01 00:00:00"))
+}
+
+func TestUpdateCommentRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := reviewservicerepo.NewReviewRepoMySQL(db, zap.NewNop().Sugar())
+	updateQuery := regexp.QuoteMeta("UPDATE review_comments SET text = ? WHERE id = ? AND user_id = ? AND is_deleted = FALSE")
+
+	cases := []struct {
+		name        string
+		updated     int64
+		userID      uint64
+		text        string
+		isDeleted   bool
+		expectedErr error
+	}{
+		{name: "updated", updated: 1, userID: 10, text: "new text"},
+		// MySQL does not count the row which keeps its text
+		{name: "same text", updated: 0, userID: 10, text: "new text"},
+		{name: "deleted meanwhile", updated: 0, userID: 10, text: "", isDeleted: true, expectedErr: errorreview.ErrorNoComment},
+		{name: "another user", updated: 0, userID: 11, text: "old text", expectedErr: errorreview.ErrorNoComment},
+	}
+	for _, testCase := range cases {
+		mock.
+			ExpectExec(updateQuery).
+			WithArgs("new text", 1, 10).
+			WillReturnResult(sqlmock.NewResult(0, testCase.updated))
+		expectComment(mock, testCase.userID, testCase.text, testCase.isDeleted)
+
+		comment, err := dbRepo.UpdateCommentRepo(1, 10, "new text")
+		if err != testCase.expectedErr {
+			t.Errorf("%s: expected error %v, got %v", testCase.name, testCase.expectedErr, err)
+		}
+		if testCase.expectedErr == nil && comment.GetText() != "new text" {
+			t.Errorf("%s: expected text %q, got %q", testCase.name, "new text", comment.GetText())
+		}
+		if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+			t.Errorf("%s: there were unfulfilled expectations: %s", testCase.name, err)
+		}
+	}
+}
+
+func TestDeleteCommentRepo(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := reviewservicerepo.NewReviewRepoMySQL(db, zap.NewNop().Sugar())
+	deleteQuery := regexp.QuoteMeta("UPDATE review_comments SET text = '', is_deleted = TRUE WHERE id = ? AND user_id = ? AND is_deleted = FALSE")
+
+	mock.
+		ExpectExec(deleteQuery).
+		WithArgs(1, 10).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectComment(mock, 10, "", true)
+
+	comment, err := dbRepo.DeleteCommentRepo(1, 10)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if !comment.GetIsDeleted() {
+		t.Errorf("expected tombstone, got %v", comment)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+
+	// the comment is deleted already or written by another user
+	mock.
+		ExpectExec(deleteQuery).
+		WithArgs(1, 10).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	_, err = dbRepo.DeleteCommentRepo(1, 10)
+	if err != errorreview.ErrorNoComment {
+		t.Errorf("expected error %s, got %v", errorreview.ErrorNoComment, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
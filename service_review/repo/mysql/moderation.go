@@ -0,0 +1,252 @@
+package reviewservicerepo
+
+import (
+	"database/sql"
+	review "kinopoisk/service_review/proto"
+)
+
+var reviewStatusNames = map[review.ReviewStatus]string{
+	review.ReviewStatus_REVIEW_PUBLISHED: "published",
+	review.ReviewStatus_REVIEW_PENDING:   "pending",
+	review.ReviewStatus_REVIEW_REJECTED:  "rejected",
+	review.ReviewStatus_REVIEW_HIDDEN:    "hidden",
+}
+
+var reviewStatuses = map[string]review.ReviewStatus{
+	"published": review.ReviewStatus_REVIEW_PUBLISHED,
+	"pending":   review.ReviewStatus_REVIEW_PENDING,
+	"rejected":  review.ReviewStatus_REVIEW_REJECTED,
+	"hidden":    review.ReviewStatus_REVIEW_HIDDEN,
+}
+
+var moderationActionNames = map[review.ModerationAction]string{
+	review.ModerationAction_ACTION_APPROVE: "approve",
+	review.ModerationAction_ACTION_REJECT:  "reject",
+	review.ModerationAction_ACTION_HIDE:    "hide",
+
+	review.ModerationAction_ACTION_MARK_SPOILER:   "mark_spoiler",
+	review.ModerationAction_ACTION_UNMARK_SPOILER: "unmark_spoiler",
+}
+
+var moderationActions = map[string]review.ModerationAction{
+	"approve": review.ModerationAction_ACTION_APPROVE,
+	"reject":  review.ModerationAction_ACTION_REJECT,
+	"hide":    review.ModerationAction_ACTION_HIDE,
+
+	"mark_spoiler":   review.ModerationAction_ACTION_MARK_SPOILER,
+	"unmark_spoiler": review.ModerationAction_ACTION_UNMARK_SPOILER,
+}
+
+// ReportReviewRepo returns false if the user has already got an open report on the review.
+// A resolved report of the user is reopened with the new reason.
+func (r *ReviewRepoMySQL) ReportReviewRepo(reviewID, userID uint64, reason string) (bool, error) {
+	res, err := r.db.Exec(
+		"INSERT INTO review_reports (`review_id`, `user_id`, `reason`) VALUES (?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE reason = IF(is_resolved, VALUES(reason), reason), created_at = IF(is_resolved, CURRENT_TIMESTAMP, created_at), is_resolved = FALSE",
+		reviewID,
+		userID,
+		reason,
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected != 0, nil
+}
+
+// GetModerationQueueRepo returns pending reviews and reviews with open reports, the oldest first.
+func (r *ReviewRepoMySQL) GetModerationQueueRepo(limit, offset uint64) ([]*review.Review, error) {
+	reviews := []*review.Review{}
+	rows, err := r.db.Query(
+		"SELECT r.id, r.mark, r.comment, r.film_id, r.user_id, u.username, r.created_at, r.updated_at, r.is_edited, r.helpful_votes, r.unhelpful_votes, r.status, r.is_spoiler, r.by_critic, "+subScoresColumns+" "+
+			"FROM reviews r JOIN users u ON r.user_id = u.id "+
+			"WHERE r.status = ? OR EXISTS (SELECT 1 FROM review_reports rr WHERE rr.review_id = r.id AND rr.is_resolved = FALSE) "+
+			"ORDER BY r.created_at, r.id LIMIT ? OFFSET ?",
+		reviewStatusNames[review.ReviewStatus_REVIEW_PENDING],
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	for rows.Next() {
+		queuedReview := &review.Review{
+			ID:     &review.ReviewID{},
+			FilmID: &review.FilmID{},
+			Author: &review.User{
+				ID: &review.UserID{},
+			},
+		}
+		var comment sql.NullString
+		var status string
+		subScores := &review.SubScores{}
+		err = rows.Scan(&queuedReview.ID.ID, &queuedReview.Mark, &comment, &queuedReview.FilmID.ID, &queuedReview.Author.ID.ID, &queuedReview.Author.Username,
+			&queuedReview.CreatedAt, &queuedReview.UpdatedAt, &queuedReview.IsEdited, &queuedReview.HelpfulVotes, &queuedReview.UnhelpfulVotes, &status, &queuedReview.IsSpoiler, &queuedReview.ByCritic,
+			&subScores.Story, &subScores.Acting, &subScores.Visuals, &subScores.Music)
+		if err != nil {
+			return nil, err
+		}
+		queuedReview.Comment = comment.String
+		queuedReview.SubScores = ratedSubScores(subScores)
+		queuedReview.Status = reviewStatuses[status]
+		reviews = append(reviews, queuedReview)
+	}
+	return reviews, nil
+}
+
+func (r *ReviewRepoMySQL) GetOpenReportsRepo(reviewID uint64) ([]*review.ReviewReport, error) {
+	reports := []*review.ReviewReport{}
+	rows, err := r.db.Query(
+		"SELECT rr.user_id, u.username, rr.reason, rr.created_at FROM review_reports rr JOIN users u ON rr.user_id = u.id WHERE rr.review_id = ? AND rr.is_resolved = FALSE ORDER BY rr.id",
+		reviewID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	for rows.Next() {
+		report := &review.ReviewReport{
+			Author: &review.User{
+				ID: &review.UserID{},
+			},
+		}
+		err = rows.Scan(&report.Author.ID.ID, &report.Author.Username, &report.Reason, &report.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// ModerateReviewRepo changes status of the review, writes the decision to the moderation log
+// and resolves all open reports on the review in one transaction.
+func (r *ReviewRepoMySQL) ModerateReviewRepo(moderatedReview *review.Review, moderatorID uint64, action review.ModerationAction,
+	newStatus review.ReviewStatus, reason string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		"UPDATE reviews SET status = ?, updated_at = updated_at WHERE id = ?",
+		reviewStatusNames[newStatus],
+		moderatedReview.ID.ID,
+	)
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	_, err = tx.Exec(
+		"INSERT INTO review_moderation_log (`review_id`, `moderator_id`, `action`, `old_status`, `new_status`, `reason`) VALUES (?, ?, ?, ?, ?, ?)",
+		moderatedReview.ID.ID,
+		moderatorID,
+		moderationActionNames[action],
+		reviewStatusNames[moderatedReview.Status],
+		reviewStatusNames[newStatus],
+		reason,
+	)
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	_, err = tx.Exec("UPDATE review_reports SET is_resolved = TRUE WHERE review_id = ? AND is_resolved = FALSE", moderatedReview.ID.ID)
+	if err == nil {
+		err = writeRatingEvent(tx, moderatedReview, &review.Review{
+			ID:        moderatedReview.GetID(),
+			Mark:      moderatedReview.GetMark(),
+			Comment:   moderatedReview.GetComment(),
+			FilmID:    moderatedReview.GetFilmID(),
+			CreatedAt: moderatedReview.GetCreatedAt(),
+			Status:    newStatus,
+			SubScores: moderatedReview.GetSubScores(),
+			ByCritic:  moderatedReview.GetByCritic(),
+		})
+	}
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	return tx.Commit()
+}
+
+// SetSpoilerFlagRepo changes spoiler flag of the review and writes it to the moderation log, status of the review stays the same.
+func (r *ReviewRepoMySQL) SetSpoilerFlagRepo(flaggedReview *review.Review, moderatorID uint64, isSpoiler bool) error {
+	action := review.ModerationAction_ACTION_UNMARK_SPOILER
+	if isSpoiler {
+		action = review.ModerationAction_ACTION_MARK_SPOILER
+	}
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		"UPDATE reviews SET is_spoiler = ?, updated_at = updated_at WHERE id = ?",
+		isSpoiler,
+		flaggedReview.ID.ID,
+	)
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	_, err = tx.Exec(
+		"INSERT INTO review_moderation_log (`review_id`, `moderator_id`, `action`, `old_status`, `new_status`, `reason`) VALUES (?, ?, ?, ?, ?, '')",
+		flaggedReview.ID.ID,
+		moderatorID,
+		moderationActionNames[action],
+		reviewStatusNames[flaggedReview.Status],
+		reviewStatusNames[flaggedReview.Status],
+	)
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ReviewRepoMySQL) GetModerationLogRepo(reviewID uint64) ([]*review.ModerationLogEntry, error) {
+	entries := []*review.ModerationLogEntry{}
+	rows, err := r.db.Query(
+		"SELECT l.moderator_id, u.username, l.action, l.old_status, l.new_status, l.reason, l.created_at FROM review_moderation_log l JOIN users u ON l.moderator_id = u.id WHERE l.review_id = ? ORDER BY l.id",
+		reviewID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	for rows.Next() {
+		entry := &review.ModerationLogEntry{
+			Moderator: &review.User{
+				ID: &review.UserID{},
+			},
+		}
+		var action, oldStatus, newStatus string
+		err = rows.Scan(&entry.Moderator.ID.ID, &entry.Moderator.Username, &action, &oldStatus, &newStatus, &entry.Reason, &entry.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		entry.Action = moderationActions[action]
+		entry.OldStatus = reviewStatuses[oldStatus]
+		entry.NewStatus = reviewStatuses[newStatus]
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
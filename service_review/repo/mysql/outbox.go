@@ -0,0 +1,199 @@
+package reviewservicerepo
+
+import (
+	"database/sql"
+	"encoding/json"
+	"github.com/google/uuid"
+	ratingbroker "kinopoisk/service_rating/broker"
+	review "kinopoisk/service_review/proto"
+	"time"
+)
+
+type OutboxRepo interface {
+	GetUnsentEventsRepo(limit uint64) ([]*OutboxEvent, error)
+	MarkEventSentRepo(id uint64) error
+	MarkEventFailedRepo(id uint64, retryAfter time.Duration, publishErr error) error
+	GetOutboxLagRepo() (*OutboxLag, error)
+	DeleteSentEventsRepo(olderThan time.Duration) (int64, error)
+}
+
+type ChangeRatingInfo struct {
+	EventID    string
+	ChangeType string
+	ReviewID   uint64
+	OldMark    uint32
+	NewMark    uint32
+	FilmID     uint64
+	OldHasText bool
+	NewHasText bool
+	CreatedAt  string
+	// sub-scores by criterion name, criteria which are not rated are left out
+	OldSubScores map[string]uint32 `json:",omitempty"`
+	NewSubScores map[string]uint32 `json:",omitempty"`
+	// ByCritic counts the review toward the critic score of the film
+	ByCritic bool `json:",omitempty"`
+}
+
+type OutboxEvent struct {
+	ID       uint64
+	Queue    string
+	Payload  []byte
+	Attempts uint32
+}
+
+// OutboxLag describes the events which have not been published yet.
+type OutboxLag struct {
+	Pending           uint64
+	OldestPendingSecs float64
+}
+
+// newRatingEvent makes the rating event for the review change, only published reviews count toward the film rating.
+// oldReview is nil for a new review and newReview is nil for a deleted one. It returns nil if the rating is not changed.
+func newRatingEvent(oldReview, newReview *review.Review) *ChangeRatingInfo {
+	wasCounted := oldReview != nil && oldReview.GetStatus() == review.ReviewStatus_REVIEW_PUBLISHED
+	isCounted := newReview != nil && newReview.GetStatus() == review.ReviewStatus_REVIEW_PUBLISHED
+	var changeRatingInfo *ChangeRatingInfo
+	switch {
+	case wasCounted && isCounted:
+		changeRatingInfo = &ChangeRatingInfo{
+			ChangeType: "Update",
+			OldMark:    oldReview.Mark,
+			NewMark:    newReview.Mark,
+			ReviewID:   newReview.ID.ID,
+			FilmID:     oldReview.GetFilmID().GetID(),
+			OldHasText: oldReview.GetComment() != "",
+			NewHasText: newReview.GetComment() != "",
+			CreatedAt:  newReview.GetCreatedAt(),
+
+			OldSubScores: subScoresByCriterion(oldReview.GetSubScores()),
+			NewSubScores: subScoresByCriterion(newReview.GetSubScores()),
+			ByCritic:     oldReview.GetByCritic(),
+		}
+	case wasCounted:
+		changeRatingInfo = &ChangeRatingInfo{
+			ChangeType: "Delete",
+			OldMark:    oldReview.Mark,
+			ReviewID:   oldReview.ID.ID,
+			FilmID:     oldReview.GetFilmID().GetID(),
+			OldHasText: oldReview.GetComment() != "",
+			CreatedAt:  oldReview.GetCreatedAt(),
+
+			OldSubScores: subScoresByCriterion(oldReview.GetSubScores()),
+			ByCritic:     oldReview.GetByCritic(),
+		}
+	case isCounted:
+		changeRatingInfo = &ChangeRatingInfo{
+			ChangeType: "Add",
+			NewMark:    newReview.Mark,
+			ReviewID:   newReview.ID.ID,
+			FilmID:     newReview.GetFilmID().GetID(),
+			NewHasText: newReview.GetComment() != "",
+			CreatedAt:  newReview.GetCreatedAt(),
+
+			NewSubScores: subScoresByCriterion(newReview.GetSubScores()),
+			ByCritic:     newReview.GetByCritic(),
+		}
+	default:
+		return nil
+	}
+	changeRatingInfo.EventID = uuid.New().String()
+	return changeRatingInfo
+}
+
+func subScoresByCriterion(subScores *review.SubScores) map[string]uint32 {
+	byCriterion := make(map[string]uint32)
+	for criterion, mark := range map[string]uint32{
+		"story":   subScores.GetStory(),
+		"acting":  subScores.GetActing(),
+		"visuals": subScores.GetVisuals(),
+		"music":   subScores.GetMusic(),
+	} {
+		if mark != 0 {
+			byCriterion[criterion] = mark
+		}
+	}
+	if len(byCriterion) == 0 {
+		return nil
+	}
+	return byCriterion
+}
+
+// writeRatingEvent puts the rating event into the outbox in the transaction of the review change,
+// so the event is published if and only if the change is committed.
+func writeRatingEvent(tx *sql.Tx, oldReview, newReview *review.Review) error {
+	changeRatingInfo := newRatingEvent(oldReview, newReview)
+	if changeRatingInfo == nil {
+		return nil
+	}
+	payload, err := json.Marshal(changeRatingInfo)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		"INSERT INTO review_outbox (`event_id`, `queue`, `payload`) VALUES (?, ?, ?)",
+		changeRatingInfo.EventID,
+		ratingbroker.ChangeRatingQueueName,
+		payload,
+	)
+	return err
+}
+
+func (r *ReviewRepoMySQL) GetUnsentEventsRepo(limit uint64) ([]*OutboxEvent, error) {
+	rows, err := r.db.Query(
+		"SELECT id, queue, payload, attempts FROM review_outbox WHERE sent_at IS NULL AND next_attempt_at <= NOW() ORDER BY id LIMIT ?",
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	events := make([]*OutboxEvent, 0)
+	for rows.Next() {
+		event := &OutboxEvent{}
+		err = rows.Scan(&event.ID, &event.Queue, &event.Payload, &event.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (r *ReviewRepoMySQL) MarkEventSentRepo(id uint64) error {
+	_, err := r.db.Exec("UPDATE review_outbox SET sent_at = NOW(), attempts = attempts + 1, last_error = NULL WHERE id = ?", id)
+	return err
+}
+
+func (r *ReviewRepoMySQL) MarkEventFailedRepo(id uint64, retryAfter time.Duration, publishErr error) error {
+	_, err := r.db.Exec(
+		"UPDATE review_outbox SET attempts = attempts + 1, next_attempt_at = NOW() + INTERVAL ? SECOND, last_error = ? WHERE id = ?",
+		int64(retryAfter.Seconds()),
+		publishErr.Error(),
+		id,
+	)
+	return err
+}
+
+func (r *ReviewRepoMySQL) GetOutboxLagRepo() (*OutboxLag, error) {
+	lag := &OutboxLag{}
+	err := r.db.
+		QueryRow("SELECT COUNT(*), COALESCE(TIMESTAMPDIFF(SECOND, MIN(created_at), NOW()), 0) FROM review_outbox WHERE sent_at IS NULL").
+		Scan(&lag.Pending, &lag.OldestPendingSecs)
+	if err != nil {
+		return nil, err
+	}
+	return lag, nil
+}
+
+func (r *ReviewRepoMySQL) DeleteSentEventsRepo(olderThan time.Duration) (int64, error) {
+	res, err := r.db.Exec("DELETE FROM review_outbox WHERE sent_at < NOW() - INTERVAL ? SECOND", int64(olderThan.Seconds()))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
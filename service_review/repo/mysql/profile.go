@@ -0,0 +1,75 @@
+package reviewservicerepo
+
+import (
+	"database/sql"
+	"errors"
+	errorreview "kinopoisk/service_review/error"
+	review "kinopoisk/service_review/proto"
+)
+
+// GetUserProfileRepo counts only published reviews of the user.
+func (r *ReviewRepoMySQL) GetUserProfileRepo(userID uint64) (*review.UserProfile, error) {
+	profile := &review.UserProfile{
+		User: &review.User{
+			ID: &review.UserID{},
+		},
+	}
+	err := r.db.
+		QueryRow(
+			"SELECT u.id, u.username, u.created_at, u.is_private, COUNT(r.id), COALESCE(AVG(r.mark), 0) FROM users u "+
+				"LEFT JOIN reviews r ON r.user_id = u.id AND r.status = ? WHERE u.id = ? GROUP BY u.id, u.username, u.created_at, u.is_private",
+			reviewStatusNames[review.ReviewStatus_REVIEW_PUBLISHED],
+			userID,
+		).
+		Scan(&profile.User.ID.ID, &profile.User.Username, &profile.JoinedAt, &profile.IsPrivate, &profile.ReviewsCount, &profile.AverageMark)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errorreview.ErrorNoUser
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// GetUserReviewsRepo returns published reviews of the user, the newest first.
+func (r *ReviewRepoMySQL) GetUserReviewsRepo(userID, limit, offset uint64) ([]*review.Review, error) {
+	reviews := []*review.Review{}
+	rows, err := r.db.Query(
+		"SELECT r.id, r.mark, r.comment, r.film_id, r.user_id, u.username, r.created_at, r.updated_at, r.is_edited, r.helpful_votes, r.unhelpful_votes, r.is_spoiler, r.by_critic, "+subScoresColumns+" "+
+			"FROM reviews r JOIN users u ON r.user_id = u.id WHERE r.user_id = ? AND r.status = ? ORDER BY r.created_at DESC, r.id DESC LIMIT ? OFFSET ?",
+		userID,
+		reviewStatusNames[review.ReviewStatus_REVIEW_PUBLISHED],
+		limit,
+		offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	for rows.Next() {
+		userReview := &review.Review{
+			ID:     &review.ReviewID{},
+			FilmID: &review.FilmID{},
+			Author: &review.User{
+				ID: &review.UserID{},
+			},
+		}
+		var comment sql.NullString
+		subScores := &review.SubScores{}
+		err = rows.Scan(&userReview.ID.ID, &userReview.Mark, &comment, &userReview.FilmID.ID, &userReview.Author.ID.ID, &userReview.Author.Username,
+			&userReview.CreatedAt, &userReview.UpdatedAt, &userReview.IsEdited, &userReview.HelpfulVotes, &userReview.UnhelpfulVotes, &userReview.IsSpoiler, &userReview.ByCritic,
+			&subScores.Story, &subScores.Acting, &subScores.Visuals, &subScores.Music)
+		if err != nil {
+			return nil, err
+		}
+		userReview.Comment = comment.String
+		userReview.SubScores = ratedSubScores(subScores)
+		reviews = append(reviews, userReview)
+	}
+	return reviews, nil
+}
@@ -4,17 +4,41 @@ import (
 	"database/sql"
 	"errors"
 	"go.uber.org/zap"
+	authpermission "kinopoisk/service_auth/permission"
 	errorreview "kinopoisk/service_review/error"
 	review "kinopoisk/service_review/proto"
 )
 
 type ReviewRepo interface {
-	GetFilmReviewsRepo(filmID uint64) ([]*review.Review, error)
+	GetFilmReviewsRepo(filmID uint64, sort review.ReviewSort, criticsOnly bool, limit, offset uint64) ([]*review.Review, error)
 	NewReviewRepo(newReview *review.Review, filmID, userID uint64) (*review.Review, error)
-	DeleteReviewRepo(reviewID uint64) (bool, error)
-	UpdateReviewRepo(reviewToUpdate *review.Review) (*review.Review, error)
+	DeleteReviewRepo(deletedReview *review.Review) (bool, error)
+	UpdateReviewRepo(reviewToUpdate *review.Review, userID uint64, statusAfterEdit func(oldStatus review.ReviewStatus) review.ReviewStatus) (*review.Review, error)
 	GetReviewByFilmUser(filmID, userID uint64) (uint64, error)
 	GetUserReviewByID(reviewID, userID uint64) (*review.Review, error)
+	GetReviewByIDRepo(reviewID uint64) (*review.Review, error)
+	GetReviewRevisionsRepo(reviewID uint64) ([]*review.ReviewRevision, error)
+	VoteReviewRepo(reviewID, userID uint64, isHelpful bool) error
+	DeleteVoteRepo(reviewID, userID uint64) (bool, error)
+	GetCommentByIDRepo(commentID uint64) (*review.Comment, error)
+	NewCommentRepo(reviewID, parentID, userID uint64, text string) (*review.Comment, error)
+	UpdateCommentRepo(commentID, userID uint64, text string) (*review.Comment, error)
+	DeleteCommentRepo(commentID, userID uint64) (*review.Comment, error)
+	GetReviewThreadsRepo(reviewID, limit, offset uint64) ([]*review.Comment, error)
+	GetThreadsRepliesRepo(threadIDs []uint64) ([]*review.Comment, error)
+	ReportReviewRepo(reviewID, userID uint64, reason string) (bool, error)
+	GetModerationQueueRepo(limit, offset uint64) ([]*review.Review, error)
+	GetOpenReportsRepo(reviewID uint64) ([]*review.ReviewReport, error)
+	ModerateReviewRepo(moderatedReview *review.Review, moderatorID uint64, action review.ModerationAction, newStatus review.ReviewStatus, reason string) error
+	GetModerationLogRepo(reviewID uint64) ([]*review.ModerationLogEntry, error)
+	SetSpoilerFlagRepo(flaggedReview *review.Review, moderatorID uint64, isSpoiler bool) error
+	GetUserProfileRepo(userID uint64) (*review.UserProfile, error)
+	GetUserReviewsRepo(userID, limit, offset uint64) ([]*review.Review, error)
+}
+
+// queryRower is either *sql.DB or *sql.Tx.
+type queryRower interface {
+	QueryRow(query string, args ...any) *sql.Row
 }
 
 type ReviewRepoMySQL struct {
@@ -29,9 +53,33 @@ func NewReviewRepoMySQL(db *sql.DB, logger *zap.SugaredLogger) *ReviewRepoMySQL
 	}
 }
 
-func (r *ReviewRepoMySQL) GetFilmReviewsRepo(filmID uint64) ([]*review.Review, error) {
+// subScoresColumns reads the sub-scores of the review r, a criterion which is not rated is read as 0.
+const subScoresColumns = "COALESCE(r.story_mark, 0), COALESCE(r.acting_mark, 0), COALESCE(r.visuals_mark, 0), COALESCE(r.music_mark, 0)"
+
+var reviewSortOrders = map[review.ReviewSort]string{
+	review.ReviewSort_SORT_NEWEST:       "r.created_at DESC, r.id DESC",
+	review.ReviewSort_SORT_OLDEST:       "r.created_at ASC, r.id ASC",
+	review.ReviewSort_SORT_HIGHEST_MARK: "r.mark DESC, r.created_at DESC, r.id DESC",
+	review.ReviewSort_SORT_LOWEST_MARK:  "r.mark ASC, r.created_at DESC, r.id DESC",
+	review.ReviewSort_SORT_MOST_HELPFUL: "r.helpful_votes - r.unhelpful_votes DESC, r.helpful_votes DESC, r.created_at DESC, r.id DESC",
+}
+
+// GetFilmReviewsRepo returns only the reviews written by critics if criticsOnly is set.
+func (r *ReviewRepoMySQL) GetFilmReviewsRepo(filmID uint64, sort review.ReviewSort, criticsOnly bool, limit, offset uint64) ([]*review.Review, error) {
+	orderBy, ok := reviewSortOrders[sort]
+	if !ok {
+		orderBy = reviewSortOrders[review.ReviewSort_SORT_NEWEST]
+	}
 	reviews := []*review.Review{}
-	rows, err := r.db.Query("SELECT r.id, r.mark, r.comment, r.user_id, u.username FROM reviews r JOIN users u ON r.user_id = u.id WHERE r.film_id = ?", filmID)
+	rows, err := r.db.Query(
+		"SELECT r.id, r.mark, r.comment, r.user_id, u.username, r.created_at, r.updated_at, r.is_edited, r.helpful_votes, r.unhelpful_votes, r.is_spoiler, r.by_critic, "+
+			subScoresColumns+" FROM reviews r JOIN users u ON r.user_id = u.id WHERE r.film_id = ? AND r.status = ? AND (? = FALSE OR r.by_critic) ORDER BY "+orderBy+" LIMIT ? OFFSET ?",
+		filmID,
+		reviewStatusNames[review.ReviewStatus_REVIEW_PUBLISHED],
+		criticsOnly,
+		limit,
+		offset,
+	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -50,79 +98,213 @@ func (r *ReviewRepoMySQL) GetFilmReviewsRepo(filmID uint64) ([]*review.Review, e
 		newReview.Author = &review.User{
 			ID: &review.UserID{},
 		}
-		err = rows.Scan(&newReview.ID.ID, &newReview.Mark, &newReview.Comment, &newReview.Author.ID.ID, &newReview.Author.Username)
+		subScores := &review.SubScores{}
+		err = rows.Scan(&newReview.ID.ID, &newReview.Mark, &newReview.Comment, &newReview.Author.ID.ID, &newReview.Author.Username,
+			&newReview.CreatedAt, &newReview.UpdatedAt, &newReview.IsEdited, &newReview.HelpfulVotes, &newReview.UnhelpfulVotes, &newReview.IsSpoiler, &newReview.ByCritic,
+			&subScores.Story, &subScores.Acting, &subScores.Visuals, &subScores.Music)
 		if err != nil {
 			return nil, err
 		}
+		newReview.SubScores = ratedSubScores(subScores)
 		reviews = append(reviews, newReview)
 	}
 	return reviews, nil
 
 }
 
+// NewReviewRepo marks the review as written by a critic if the author has the critic role now.
 func (r *ReviewRepoMySQL) NewReviewRepo(newReview *review.Review, filmID, userID uint64) (*review.Review, error) {
-	res, err := r.db.Exec(
-		"INSERT INTO reviews (`mark`, `comment`, `user_id`, `film_id`) VALUES (?, ?, ?, ?)",
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	res, err := tx.Exec(
+		"INSERT INTO reviews (`mark`, `comment`, `user_id`, `film_id`, `status`, `is_spoiler`, `story_mark`, `acting_mark`, `visuals_mark`, `music_mark`, `by_critic`) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, EXISTS(SELECT 1 FROM user_roles WHERE user_id = ? AND role = ?))",
 		newReview.Mark,
 		newReview.Comment,
 		userID,
 		filmID,
+		reviewStatusNames[newReview.Status],
+		newReview.IsSpoiler,
+		subScoreValue(newReview.GetSubScores().GetStory()),
+		subScoreValue(newReview.GetSubScores().GetActing()),
+		subScoreValue(newReview.GetSubScores().GetVisuals()),
+		subScoreValue(newReview.GetSubScores().GetMusic()),
+		userID,
+		authpermission.CriticRole,
 	)
 	if err != nil {
+		r.rollback(tx)
 		return nil, err
 	}
 	id, err := res.LastInsertId()
 	if err != nil {
+		r.rollback(tx)
 		return nil, err
 	}
 	newReview.ID = &review.ReviewID{
 		ID: uint64(id),
 	}
-	err = r.getReviewAuthor(newReview)
+	newReview.FilmID = &review.FilmID{
+		ID: filmID,
+	}
+	err = r.getReviewDetails(tx, newReview)
+	if err == nil {
+		err = writeRatingEvent(tx, nil, newReview)
+	}
+	if err != nil {
+		r.rollback(tx)
+		return nil, err
+	}
+	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
-
 	return newReview, nil
-
 }
 
-func (r *ReviewRepoMySQL) DeleteReviewRepo(reviewID uint64) (bool, error) {
-	_, err := r.db.Exec(
+func (r *ReviewRepoMySQL) DeleteReviewRepo(deletedReview *review.Review) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	_, err = tx.Exec(
 		"DELETE FROM reviews WHERE id = ?",
-		reviewID,
+		deletedReview.ID.ID,
 	)
+	if err == nil {
+		err = writeRatingEvent(tx, deletedReview, nil)
+	}
+	if err != nil {
+		r.rollback(tx)
+		return false, err
+	}
+	err = tx.Commit()
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-func (r *ReviewRepoMySQL) UpdateReviewRepo(reviewToUpdate *review.Review) (*review.Review, error) {
-	_, err := r.db.Exec(
-		"UPDATE reviews SET mark = ?, comment = ? where id = ?",
+// UpdateReviewRepo keeps the replaced mark and comment as a revision if any of them is changed.
+// The review of the user is locked first, the status is set by statusAfterEdit from the locked status and
+// the rating event is made from the locked mark, so a concurrent moderation is not lost. Sub-scores are always
+// set from reviewToUpdate. The author can only set the spoiler flag, so an edit does not clear the flag set
+// by a moderator. It returns errorreview.ErrorNoReview if the user has no such review.
+func (r *ReviewRepoMySQL) UpdateReviewRepo(reviewToUpdate *review.Review, userID uint64,
+	statusAfterEdit func(oldStatus review.ReviewStatus) review.ReviewStatus) (*review.Review, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	oldReview, err := getUserReview(tx, reviewToUpdate.ID.ID, userID, true)
+	if err != nil {
+		r.rollback(tx)
+		return nil, err
+	}
+	reviewToUpdate.Status = statusAfterEdit(oldReview.GetStatus())
+	res, err := tx.Exec(
+		"INSERT INTO review_revisions (`review_id`, `mark`, `comment`, `created_at`) SELECT id, mark, comment, updated_at FROM reviews WHERE id = ? AND (mark <> ? OR NOT (comment <=> ?))",
+		reviewToUpdate.ID.ID,
 		reviewToUpdate.Mark,
 		reviewToUpdate.Comment,
-		reviewToUpdate.ID.ID,
 	)
 	if err != nil {
+		r.rollback(tx)
 		return nil, err
 	}
-	err = r.getReviewAuthor(reviewToUpdate)
+	revisionsAdded, err := res.RowsAffected()
+	if err != nil {
+		r.rollback(tx)
+		return nil, err
+	}
+	if revisionsAdded != 0 {
+		_, err = tx.Exec(
+			"UPDATE reviews SET mark = ?, comment = ?, is_edited = TRUE, status = ?, is_spoiler = is_spoiler OR ? where id = ?",
+			reviewToUpdate.Mark,
+			reviewToUpdate.Comment,
+			reviewStatusNames[reviewToUpdate.Status],
+			reviewToUpdate.IsSpoiler,
+			reviewToUpdate.ID.ID,
+		)
+	} else {
+		_, err = tx.Exec(
+			"UPDATE reviews SET status = ?, is_spoiler = is_spoiler OR ?, updated_at = updated_at where id = ?",
+			reviewStatusNames[reviewToUpdate.Status],
+			reviewToUpdate.IsSpoiler,
+			reviewToUpdate.ID.ID,
+		)
+	}
+	if err == nil {
+		_, err = tx.Exec(
+			"UPDATE reviews SET story_mark = ?, acting_mark = ?, visuals_mark = ?, music_mark = ?, updated_at = updated_at where id = ?",
+			subScoreValue(reviewToUpdate.GetSubScores().GetStory()),
+			subScoreValue(reviewToUpdate.GetSubScores().GetActing()),
+			subScoreValue(reviewToUpdate.GetSubScores().GetVisuals()),
+			subScoreValue(reviewToUpdate.GetSubScores().GetMusic()),
+			reviewToUpdate.ID.ID,
+		)
+	}
+	if err == nil {
+		reviewToUpdate.FilmID = oldReview.GetFilmID()
+		err = r.getReviewDetails(tx, reviewToUpdate)
+	}
+	if err == nil {
+		err = writeRatingEvent(tx, oldReview, reviewToUpdate)
+	}
+	if err != nil {
+		r.rollback(tx)
+		return nil, err
+	}
+	err = tx.Commit()
 	if err != nil {
 		return nil, err
 	}
 	return reviewToUpdate, nil
 }
 
-func (r *ReviewRepoMySQL) getReviewAuthor(reviewToUpdate *review.Review) error {
+func (r *ReviewRepoMySQL) rollback(tx *sql.Tx) {
+	err := tx.Rollback()
+	if err != nil {
+		r.logger.Errorf("error in transaction rollback: %s", err)
+	}
+}
+
+func (r *ReviewRepoMySQL) getReviewDetails(q queryRower, reviewToUpdate *review.Review) error {
 	reviewToUpdate.Author = &review.User{
 		ID: &review.UserID{},
 	}
-	err := r.db.
-		QueryRow("SELECT u.id, u.username from users u JOIN reviews r on u.id = r.user_id WHERE r.id = ?", reviewToUpdate.ID.ID).
-		Scan(&reviewToUpdate.Author.ID.ID, &reviewToUpdate.Author.Username)
-	return err
+	var status string
+	subScores := &review.SubScores{}
+	err := q.
+		QueryRow("SELECT u.id, u.username, r.created_at, r.updated_at, r.is_edited, r.helpful_votes, r.unhelpful_votes, r.status, r.is_spoiler, r.by_critic, "+
+			subScoresColumns+" from users u JOIN reviews r on u.id = r.user_id WHERE r.id = ?", reviewToUpdate.ID.ID).
+		Scan(&reviewToUpdate.Author.ID.ID, &reviewToUpdate.Author.Username, &reviewToUpdate.CreatedAt, &reviewToUpdate.UpdatedAt, &reviewToUpdate.IsEdited,
+			&reviewToUpdate.HelpfulVotes, &reviewToUpdate.UnhelpfulVotes, &status, &reviewToUpdate.IsSpoiler, &reviewToUpdate.ByCritic,
+			&subScores.Story, &subScores.Acting, &subScores.Visuals, &subScores.Music)
+	if err != nil {
+		return err
+	}
+	reviewToUpdate.Status = reviewStatuses[status]
+	reviewToUpdate.SubScores = ratedSubScores(subScores)
+	return nil
+}
+
+// ratedSubScores returns nil if none of the criteria is rated.
+func ratedSubScores(subScores *review.SubScores) *review.SubScores {
+	if subScores.Story == 0 && subScores.Acting == 0 && subScores.Visuals == 0 && subScores.Music == 0 {
+		return nil
+	}
+	return subScores
+}
+
+// subScoreValue stores a criterion which is not rated as NULL, so it is left out of the film averages.
+func subScoreValue(mark uint32) interface{} {
+	if mark == 0 {
+		return nil
+	}
+	return mark
 }
 
 func (r *ReviewRepoMySQL) GetReviewByFilmUser(filmID, userID uint64) (uint64, error) {
@@ -141,12 +323,26 @@ func (r *ReviewRepoMySQL) GetReviewByFilmUser(filmID, userID uint64) (uint64, er
 }
 
 func (r *ReviewRepoMySQL) GetUserReviewByID(reviewID, userID uint64) (*review.Review, error) {
+	return getUserReview(r.db, reviewID, userID, false)
+}
+
+// getUserReview returns errorreview.ErrorNoReview if the user has no such review. With forUpdate the review
+// is locked until the end of the transaction.
+func getUserReview(q queryRower, reviewID, userID uint64, forUpdate bool) (*review.Review, error) {
 	foundReview := &review.Review{}
 	foundReview.ID = &review.ReviewID{}
 	foundReview.FilmID = &review.FilmID{}
-	err := r.db.
-		QueryRow("SELECT id, mark, film_id from reviews WHERE id = ? AND user_id = ?", reviewID, userID).
-		Scan(&foundReview.ID.ID, &foundReview.Mark, &foundReview.FilmID.ID)
+	var status string
+	var comment sql.NullString
+	subScores := &review.SubScores{}
+	query := "SELECT r.id, r.mark, r.comment, r.film_id, r.status, r.created_at, r.by_critic, " + subScoresColumns + " from reviews r WHERE r.id = ? AND r.user_id = ?"
+	if forUpdate {
+		query += " FOR UPDATE"
+	}
+	err := q.
+		QueryRow(query, reviewID, userID).
+		Scan(&foundReview.ID.ID, &foundReview.Mark, &comment, &foundReview.FilmID.ID, &status, &foundReview.CreatedAt, &foundReview.ByCritic,
+			&subScores.Story, &subScores.Acting, &subScores.Visuals, &subScores.Music)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, errorreview.ErrorNoReview
@@ -154,5 +350,162 @@ func (r *ReviewRepoMySQL) GetUserReviewByID(reviewID, userID uint64) (*review.Re
 	if err != nil {
 		return nil, err
 	}
+	foundReview.Comment = comment.String
+	foundReview.Status = reviewStatuses[status]
+	foundReview.SubScores = ratedSubScores(subScores)
 	return foundReview, nil
 }
+
+func (r *ReviewRepoMySQL) GetReviewByIDRepo(reviewID uint64) (*review.Review, error) {
+	foundReview := &review.Review{
+		ID:     &review.ReviewID{},
+		FilmID: &review.FilmID{},
+	}
+	var comment sql.NullString
+	err := r.db.
+		QueryRow("SELECT id, mark, comment, film_id from reviews WHERE id = ?", reviewID).
+		Scan(&foundReview.ID.ID, &foundReview.Mark, &comment, &foundReview.FilmID.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errorreview.ErrorNoReview
+	}
+	if err != nil {
+		return nil, err
+	}
+	foundReview.Comment = comment.String
+	err = r.getReviewDetails(r.db, foundReview)
+	if err != nil {
+		return nil, err
+	}
+	return foundReview, nil
+}
+
+func (r *ReviewRepoMySQL) GetReviewRevisionsRepo(reviewID uint64) ([]*review.ReviewRevision, error) {
+	revisions := []*review.ReviewRevision{}
+	rows, err := r.db.Query("SELECT mark, comment, created_at, replaced_at FROM review_revisions WHERE review_id = ? ORDER BY id", reviewID)
+	if err != nil {
+		return nil, err
+	}
+	defer func(rows *sql.Rows) {
+		err = rows.Close()
+		if err != nil {
+			r.logger.Errorf("error in closing db rows")
+		}
+	}(rows)
+	for rows.Next() {
+		revision := &review.ReviewRevision{}
+		var comment sql.NullString
+		err = rows.Scan(&revision.Mark, &comment, &revision.CreatedAt, &revision.ReplacedAt)
+		if err != nil {
+			return nil, err
+		}
+		revision.Comment = comment.String
+		revisions = append(revisions, revision)
+	}
+	return revisions, nil
+}
+
+// VoteReviewRepo adds or changes the user's vote and keeps vote counters of the review in sync.
+func (r *ReviewRepoMySQL) VoteReviewRepo(reviewID, userID uint64, isHelpful bool) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	oldVote, hasVote, err := r.lockReviewVote(tx, reviewID, userID)
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	if hasVote && oldVote == isHelpful {
+		r.rollback(tx)
+		return nil
+	}
+	if hasVote {
+		_, err = tx.Exec("UPDATE review_votes SET is_helpful = ? WHERE review_id = ? AND user_id = ?", isHelpful, reviewID, userID)
+	} else {
+		_, err = tx.Exec("INSERT INTO review_votes (`review_id`, `user_id`, `is_helpful`) VALUES (?, ?, ?)", reviewID, userID, isHelpful)
+	}
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	helpfulDelta, unhelpfulDelta := voteDelta(isHelpful, 1)
+	if hasVote {
+		oldHelpfulDelta, oldUnhelpfulDelta := voteDelta(oldVote, -1)
+		helpfulDelta += oldHelpfulDelta
+		unhelpfulDelta += oldUnhelpfulDelta
+	}
+	err = changeVoteCounters(tx, reviewID, helpfulDelta, unhelpfulDelta)
+	if err != nil {
+		r.rollback(tx)
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *ReviewRepoMySQL) DeleteVoteRepo(reviewID, userID uint64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	oldVote, hasVote, err := r.lockReviewVote(tx, reviewID, userID)
+	if err != nil || !hasVote {
+		r.rollback(tx)
+		return false, err
+	}
+	_, err = tx.Exec("DELETE FROM review_votes WHERE review_id = ? AND user_id = ?", reviewID, userID)
+	if err != nil {
+		r.rollback(tx)
+		return false, err
+	}
+	helpfulDelta, unhelpfulDelta := voteDelta(oldVote, -1)
+	err = changeVoteCounters(tx, reviewID, helpfulDelta, unhelpfulDelta)
+	if err != nil {
+		r.rollback(tx)
+		return false, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// lockReviewVote locks the review row, so concurrent votes can not break its counters.
+func (r *ReviewRepoMySQL) lockReviewVote(tx *sql.Tx, reviewID, userID uint64) (bool, bool, error) {
+	var lockedID uint64
+	err := tx.QueryRow("SELECT id FROM reviews WHERE id = ? FOR UPDATE", reviewID).Scan(&lockedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, errorreview.ErrorNoReview
+	}
+	if err != nil {
+		return false, false, err
+	}
+	var isHelpful bool
+	err = tx.
+		QueryRow("SELECT is_helpful FROM review_votes WHERE review_id = ? AND user_id = ?", reviewID, userID).
+		Scan(&isHelpful)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, false, nil
+	}
+	if err != nil {
+		return false, false, err
+	}
+	return isHelpful, true, nil
+}
+
+func voteDelta(isHelpful bool, delta int) (int, int) {
+	if isHelpful {
+		return delta, 0
+	}
+	return 0, delta
+}
+
+func changeVoteCounters(tx *sql.Tx, reviewID uint64, helpfulDelta, unhelpfulDelta int) error {
+	_, err := tx.Exec(
+		"UPDATE reviews SET helpful_votes = helpful_votes + ?, unhelpful_votes = unhelpful_votes + ?, updated_at = updated_at WHERE id = ?",
+		helpfulDelta,
+		unhelpfulDelta,
+		reviewID,
+	)
+	return err
+}
@@ -0,0 +1,138 @@
+package reviewservicerepo_test
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"go.uber.org/zap"
+	sqlmock "gopkg.in/DATA-DOG/go-sqlmock.v1"
+	errorreview "kinopoisk/service_review/error"
+	review "kinopoisk/service_review/proto"
+	reviewservicerepo "kinopoisk/service_review/repo/mysql"
+	"regexp"
+	"testing"
+)
+
+// ratingEventPayload matches the outbox payload of the rating event with the marks.
+type ratingEventPayload struct {
+	changeType string
+	oldMark    uint32
+	newMark    uint32
+}
+
+func (p ratingEventPayload) Match(value driver.Value) bool {
+	payload, ok := value.([]byte)
+	if !ok {
+		return false
+	}
+	changeRatingInfo := &reviewservicerepo.ChangeRatingInfo{}
+	if json.Unmarshal(payload, changeRatingInfo) != nil {
+		return false
+	}
+	return changeRatingInfo.ChangeType == p.changeType && changeRatingInfo.OldMark == p.oldMark &&
+		changeRatingInfo.NewMark == p.newMark
+}
+
+func expectLockedReview(mock sqlmock.Sqlmock, status string, mark uint32) {
+	mock.
+		ExpectQuery(regexp.QuoteMeta("from reviews r WHERE r.id = ? AND r.user_id = ? FOR UPDATE")).
+		WithArgs(1, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "mark", "comment", "film_id", "status", "created_at", "by_critic",
+			"story", "acting", "visuals", "music"}).
+			AddRow(1, mark, "old comment", 5, status, "2024-01-01 00:00:00", false, 0, 0, 0, 0))
+}
+
+func expectReviewEdit(mock sqlmock.Sqlmock, status string) {
+	mock.
+		ExpectExec("INSERT INTO review_revisions").
+		WithArgs(1, 8, "new comment").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.
+		ExpectExec(regexp.QuoteMeta("UPDATE reviews SET mark = ?, comment = ?, is_edited = TRUE, status = ?")).
+		WithArgs(8, "new comment", status, false, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectExec(regexp.QuoteMeta("UPDATE reviews SET story_mark = ?")).
+		WithArgs(nil, nil, nil, nil, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.
+		ExpectQuery("SELECT u.id, u.username").
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username", "created_at", "updated_at", "is_edited", "helpful_votes",
+			"unhelpful_votes", "status", "is_spoiler", "by_critic", "story", "acting", "visuals", "music"}).
+			AddRow(10, "author", "2024-01-01 00:00:00", "2024-01-02 00:00:00", true, 0, 0, status, false, false, 0, 0, 0, 0))
+}
+
+func TestUpdateReviewRepoLockedReview(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("can not create mock")
+	}
+	defer db.Close()
+	dbRepo := reviewservicerepo.NewReviewRepoMySQL(db, zap.NewNop().Sugar())
+	var lockedStatus review.ReviewStatus
+	statusAfterEdit := func(oldStatus review.ReviewStatus) review.ReviewStatus {
+		lockedStatus = oldStatus
+		if oldStatus == review.ReviewStatus_REVIEW_HIDDEN {
+			return review.ReviewStatus_REVIEW_PENDING
+		}
+		return review.ReviewStatus_REVIEW_PUBLISHED
+	}
+
+	// a moderator has hidden the review after the author opened it: the review goes to moderation
+	// and the mark, already taken out of the rating by the moderation, is not taken out again
+	mock.ExpectBegin()
+	expectLockedReview(mock, "hidden", 7)
+	expectReviewEdit(mock, "pending")
+	mock.ExpectCommit()
+
+	updatedReview, err := dbRepo.UpdateReviewRepo(&review.Review{ID: &review.ReviewID{ID: 1}, Mark: 8, Comment: "new comment"},
+		10, statusAfterEdit)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if lockedStatus != review.ReviewStatus_REVIEW_HIDDEN || updatedReview.Status != review.ReviewStatus_REVIEW_PENDING {
+		t.Errorf("expected status from the locked hidden review, got %s from %s", updatedReview.Status, lockedStatus)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+
+	// the published review changes the rating by the locked mark
+	mock.ExpectBegin()
+	expectLockedReview(mock, "published", 7)
+	expectReviewEdit(mock, "published")
+	mock.
+		ExpectExec("INSERT INTO review_outbox").
+		WithArgs(sqlmock.AnyArg(), "change_rating", ratingEventPayload{changeType: "Update", oldMark: 7, newMark: 8}).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	_, err = dbRepo.UpdateReviewRepo(&review.Review{ID: &review.ReviewID{ID: 1}, Mark: 8, Comment: "new comment"},
+		10, statusAfterEdit)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+		return
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+		return
+	}
+
+	// the review of another user is not changed
+	mock.ExpectBegin()
+	mock.
+		ExpectQuery(regexp.QuoteMeta("FOR UPDATE")).
+		WithArgs(1, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectRollback()
+
+	_, err = dbRepo.UpdateReviewRepo(&review.Review{ID: &review.ReviewID{ID: 1}, Mark: 8}, 11, statusAfterEdit)
+	if err != errorreview.ErrorNoReview {
+		t.Errorf("expected error %s, got %v", errorreview.ErrorNoReview, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil { // nolint govet
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
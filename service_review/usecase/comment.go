@@ -0,0 +1,224 @@
+package reviewserviceusecse
+
+import (
+	"context"
+	"errors"
+	errorauth "kinopoisk/service_auth/error"
+	errorreview "kinopoisk/service_review/error"
+	"kinopoisk/service_review/interceptor"
+	review "kinopoisk/service_review/proto"
+)
+
+const (
+	DefaultMaxCommentDepth = 5
+	defaultThreadsPageSize = 20
+	maxThreadsPageSize     = 100
+)
+
+func (rs *ReviewGRPCServer) AddComment(ctx context.Context, in *review.NewCommentData) (*review.CommentResult, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.CommentResult{}, errorauth.ErrorNoLogger
+	}
+	rs.mu.RLock()
+	commentedReview, err := rs.ReviewRepo.GetReviewByIDRepo(in.GetReviewID().GetID())
+	rs.mu.RUnlock()
+	if errors.Is(err, errorreview.ErrorNoReview) || (err == nil && !canSeeReview(ctx, commentedReview, in.GetUserID().GetID())) {
+		logger.Errorf("no review with id %d", in.GetReviewID().GetID())
+		return &review.CommentResult{Status: review.CommentStatus_COMMENT_NO_REVIEW}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in getting review by id: %s", err)
+		return &review.CommentResult{}, err
+	}
+	parentID := in.GetParentID().GetID()
+	if parentID != 0 {
+		var parent *review.Comment
+		rs.mu.RLock()
+		parent, err = rs.ReviewRepo.GetCommentByIDRepo(parentID)
+		rs.mu.RUnlock()
+		if errors.Is(err, errorreview.ErrorNoComment) || (err == nil && parent.GetReviewID().GetID() != in.GetReviewID().GetID()) {
+			logger.Errorf("no comment with id %d for review %d", parentID, in.GetReviewID().GetID())
+			return &review.CommentResult{Status: review.CommentStatus_COMMENT_NO_PARENT}, nil
+		}
+		if err != nil {
+			logger.Errorf("error in getting parent comment: %s", err)
+			return &review.CommentResult{}, err
+		}
+		if parent.GetDepth()+1 > rs.maxCommentDepth {
+			logger.Errorf("comment %d is too deep to reply", parentID)
+			return &review.CommentResult{Status: review.CommentStatus_COMMENT_TOO_DEEP}, nil
+		}
+	}
+	rs.mu.Lock()
+	newComment, err := rs.ReviewRepo.NewCommentRepo(in.GetReviewID().GetID(), parentID, in.GetUserID().GetID(), in.GetText())
+	rs.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in adding comment: %s", err)
+		return &review.CommentResult{}, err
+	}
+	return &review.CommentResult{Comment: newComment}, nil
+}
+
+func (rs *ReviewGRPCServer) UpdateComment(ctx context.Context, in *review.UpdateCommentData) (*review.CommentResult, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.CommentResult{}, errorauth.ErrorNoLogger
+	}
+	status, err := rs.checkCommentAuthor(in.GetCommentID().GetID(), in.GetUserID().GetID())
+	if err != nil {
+		logger.Errorf("error in getting comment by id: %s", err)
+		return &review.CommentResult{}, err
+	}
+	if status != review.CommentStatus_COMMENT_OK {
+		logger.Errorf("user %d can not edit comment %d: %s", in.GetUserID().GetID(), in.GetCommentID().GetID(), status)
+		return &review.CommentResult{Status: status}, nil
+	}
+	rs.mu.Lock()
+	updatedComment, err := rs.ReviewRepo.UpdateCommentRepo(in.GetCommentID().GetID(), in.GetUserID().GetID(), in.GetText())
+	rs.mu.Unlock()
+	if errors.Is(err, errorreview.ErrorNoComment) {
+		// the comment has been deleted after the check
+		status, err = rs.checkCommentAuthor(in.GetCommentID().GetID(), in.GetUserID().GetID())
+		if err != nil {
+			logger.Errorf("error in getting comment by id: %s", err)
+			return &review.CommentResult{}, err
+		}
+		if status == review.CommentStatus_COMMENT_OK {
+			status = review.CommentStatus_COMMENT_NOT_FOUND
+		}
+		logger.Errorf("user %d can not edit comment %d: %s", in.GetUserID().GetID(), in.GetCommentID().GetID(), status)
+		return &review.CommentResult{Status: status}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in updating comment: %s", err)
+		return &review.CommentResult{}, err
+	}
+	return &review.CommentResult{Comment: updatedComment}, nil
+}
+
+func (rs *ReviewGRPCServer) DeleteComment(ctx context.Context, in *review.DeleteCommentData) (*review.CommentResult, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.CommentResult{}, errorauth.ErrorNoLogger
+	}
+	status, err := rs.checkCommentAuthor(in.GetCommentID().GetID(), in.GetUserID().GetID())
+	if err != nil {
+		logger.Errorf("error in getting comment by id: %s", err)
+		return &review.CommentResult{}, err
+	}
+	if status != review.CommentStatus_COMMENT_OK {
+		logger.Errorf("user %d can not delete comment %d: %s", in.GetUserID().GetID(), in.GetCommentID().GetID(), status)
+		return &review.CommentResult{Status: status}, nil
+	}
+	rs.mu.Lock()
+	deletedComment, err := rs.ReviewRepo.DeleteCommentRepo(in.GetCommentID().GetID(), in.GetUserID().GetID())
+	rs.mu.Unlock()
+	if errors.Is(err, errorreview.ErrorNoComment) {
+		// the comment has been deleted after the check
+		status, err = rs.checkCommentAuthor(in.GetCommentID().GetID(), in.GetUserID().GetID())
+		if err != nil {
+			logger.Errorf("error in getting comment by id: %s", err)
+			return &review.CommentResult{}, err
+		}
+		if status == review.CommentStatus_COMMENT_OK {
+			status = review.CommentStatus_COMMENT_NOT_FOUND
+		}
+		logger.Errorf("user %d can not delete comment %d: %s", in.GetUserID().GetID(), in.GetCommentID().GetID(), status)
+		return &review.CommentResult{Status: status}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in deleting comment: %s", err)
+		return &review.CommentResult{}, err
+	}
+	return &review.CommentResult{Comment: hideDeletedComment(deletedComment)}, nil
+}
+
+// GetReviewComments pages over top level comments and returns each of them with all its replies.
+func (rs *ReviewGRPCServer) GetReviewComments(ctx context.Context, in *review.ReviewCommentsRequest) (*review.Comments, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.Comments{}, errorauth.ErrorNoLogger
+	}
+	offset, err := decodePageToken(in.GetPageToken())
+	if err != nil {
+		logger.Errorf("bad page token %q: %s", in.GetPageToken(), err)
+		return &review.Comments{}, errorreview.ErrorBadPageToken
+	}
+	pageSize := uint64(in.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultThreadsPageSize
+	}
+	if pageSize > maxThreadsPageSize {
+		pageSize = maxThreadsPageSize
+	}
+	rs.mu.RLock()
+	commentedReview, err := rs.ReviewRepo.GetReviewByIDRepo(in.GetReviewID().GetID())
+	rs.mu.RUnlock()
+	if errors.Is(err, errorreview.ErrorNoReview) || (err == nil && !canSeeReview(ctx, commentedReview, 0)) {
+		logger.Errorf("no review with id %d", in.GetReviewID().GetID())
+		return &review.Comments{Status: review.CommentStatus_COMMENT_NO_REVIEW}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in getting review by id: %s", err)
+		return &review.Comments{}, err
+	}
+	rs.mu.RLock()
+	threads, err := rs.ReviewRepo.GetReviewThreadsRepo(in.GetReviewID().GetID(), pageSize+1, offset)
+	rs.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting review comments: %s", err)
+		return &review.Comments{}, err
+	}
+	nextPageToken := ""
+	if uint64(len(threads)) > pageSize {
+		threads = threads[:pageSize]
+		nextPageToken = encodePageToken(offset + pageSize)
+	}
+	threadIDs := make([]uint64, len(threads))
+	for i, thread := range threads {
+		threadIDs[i] = thread.GetID().GetID()
+	}
+	rs.mu.RLock()
+	replies, err := rs.ReviewRepo.GetThreadsRepliesRepo(threadIDs)
+	rs.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting comment replies: %s", err)
+		return &review.Comments{}, err
+	}
+	comments := make([]*review.Comment, 0, len(threads)+len(replies))
+	for _, comment := range append(threads, replies...) {
+		comments = append(comments, hideDeletedComment(comment))
+	}
+	return &review.Comments{
+		Comments:      comments,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (rs *ReviewGRPCServer) checkCommentAuthor(commentID, userID uint64) (review.CommentStatus, error) {
+	rs.mu.RLock()
+	comment, err := rs.ReviewRepo.GetCommentByIDRepo(commentID)
+	rs.mu.RUnlock()
+	if errors.Is(err, errorreview.ErrorNoComment) {
+		return review.CommentStatus_COMMENT_NOT_FOUND, nil
+	}
+	if err != nil {
+		return review.CommentStatus_COMMENT_OK, err
+	}
+	if comment.GetAuthor().GetID().GetID() != userID {
+		return review.CommentStatus_COMMENT_NOT_FOUND, nil
+	}
+	if comment.GetIsDeleted() {
+		return review.CommentStatus_COMMENT_DELETED, nil
+	}
+	return review.CommentStatus_COMMENT_OK, nil
+}
+
+func hideDeletedComment(comment *review.Comment) *review.Comment {
+	if comment.GetIsDeleted() {
+		comment.Author = nil
+		comment.Text = ""
+	}
+	return comment
+}
@@ -0,0 +1,313 @@
+package reviewserviceusecse_test
+
+import (
+	"context"
+	"google.golang.org/protobuf/proto"
+	auth "kinopoisk/service_auth/proto"
+	errorreview "kinopoisk/service_review/error"
+	review "kinopoisk/service_review/proto"
+	reviewserviceusecase "kinopoisk/service_review/usecase"
+	"testing"
+)
+
+func (f *fakeReviewRepo) GetCommentByIDRepo(commentID uint64) (*review.Comment, error) {
+	if commentID == 0 || commentID > uint64(len(f.comments)) {
+		return nil, errorreview.ErrorNoComment
+	}
+	return proto.Clone(f.comments[commentID-1]).(*review.Comment), nil
+}
+
+func (f *fakeReviewRepo) NewCommentRepo(reviewID, parentID, userID uint64, text string) (*review.Comment, error) {
+	newComment := &review.Comment{
+		ID:       &review.CommentID{ID: uint64(len(f.comments) + 1)},
+		ReviewID: &review.ReviewID{ID: reviewID},
+		Author:   &review.User{ID: &review.UserID{ID: userID}},
+		Text:     text,
+	}
+	if parentID != 0 {
+		newComment.ParentID = &review.CommentID{ID: parentID}
+		newComment.Depth = f.comments[parentID-1].GetDepth() + 1
+	}
+	f.comments = append(f.comments, newComment)
+	return f.GetCommentByIDRepo(newComment.GetID().GetID())
+}
+
+// changeComment applies the change the way the guarded UPDATE of the repo does it.
+func (f *fakeReviewRepo) changeComment(commentID, userID uint64, change func(comment *review.Comment)) (*review.Comment, error) {
+	if f.beforeCommentChange != nil {
+		f.beforeCommentChange()
+	}
+	if commentID == 0 || commentID > uint64(len(f.comments)) {
+		return nil, errorreview.ErrorNoComment
+	}
+	comment := f.comments[commentID-1]
+	if comment.GetAuthor().GetID().GetID() != userID || comment.GetIsDeleted() {
+		return nil, errorreview.ErrorNoComment
+	}
+	change(comment)
+	return f.GetCommentByIDRepo(commentID)
+}
+
+func (f *fakeReviewRepo) UpdateCommentRepo(commentID, userID uint64, text string) (*review.Comment, error) {
+	return f.changeComment(commentID, userID, func(comment *review.Comment) {
+		comment.Text = text
+	})
+}
+
+func (f *fakeReviewRepo) DeleteCommentRepo(commentID, userID uint64) (*review.Comment, error) {
+	return f.changeComment(commentID, userID, func(comment *review.Comment) {
+		comment.Text = ""
+		comment.IsDeleted = true
+	})
+}
+
+func (f *fakeReviewRepo) GetReviewThreadsRepo(reviewID, limit, offset uint64) ([]*review.Comment, error) {
+	threads := []*review.Comment{}
+	for _, comment := range f.comments {
+		if comment.GetReviewID().GetID() == reviewID && comment.GetParentID() == nil {
+			threads = append(threads, proto.Clone(comment).(*review.Comment))
+		}
+	}
+	if offset > uint64(len(threads)) {
+		offset = uint64(len(threads))
+	}
+	threads = threads[offset:]
+	if limit < uint64(len(threads)) {
+		threads = threads[:limit]
+	}
+	return threads, nil
+}
+
+func (f *fakeReviewRepo) GetThreadsRepliesRepo(threadIDs []uint64) ([]*review.Comment, error) {
+	replies := []*review.Comment{}
+	for _, comment := range f.comments {
+		if comment.GetParentID() == nil {
+			continue
+		}
+		threadID := comment.GetParentID().GetID()
+		for f.comments[threadID-1].GetParentID() != nil {
+			threadID = f.comments[threadID-1].GetParentID().GetID()
+		}
+		for _, id := range threadIDs {
+			if id == threadID {
+				replies = append(replies, proto.Clone(comment).(*review.Comment))
+			}
+		}
+	}
+	return replies, nil
+}
+
+func addComment(t *testing.T, rs *reviewserviceusecase.ReviewGRPCServer, userID, reviewID, parentID uint64) *review.CommentResult {
+	reply, err := callAs(t, &auth.User{ID: userID}, "/review.ReviewMaker/AddComment", func(ctx context.Context) (interface{}, error) {
+		return rs.AddComment(ctx, &review.NewCommentData{
+			ReviewID: &review.ReviewID{ID: reviewID},
+			ParentID: &review.CommentID{ID: parentID},
+			UserID:   &review.UserID{ID: userID},
+			Text:     "comment",
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return reply.(*review.CommentResult)
+}
+
+func getReviewComments(t *testing.T, rs *reviewserviceusecase.ReviewGRPCServer, pageSize uint32, pageToken string) *review.Comments {
+	reply, err := callAs(t, nil, "/review.ReviewMaker/GetReviewComments", func(ctx context.Context) (interface{}, error) {
+		return rs.GetReviewComments(ctx, &review.ReviewCommentsRequest{
+			ReviewID:  &review.ReviewID{ID: 2},
+			PageSize:  pageSize,
+			PageToken: pageToken,
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	return reply.(*review.Comments)
+}
+
+func commentIDs(comments []*review.Comment) []uint64 {
+	ids := make([]uint64, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.GetID().GetID()
+	}
+	return ids
+}
+
+func TestGetReviewCommentsThreads(t *testing.T) {
+	rs := reviewserviceusecase.NewReviewGRPCServer(newFakeReviewRepo(), reviewserviceusecase.DefaultMaxCommentDepth, nil)
+	addComment(t, rs, 30, 2, 0) // 1
+	addComment(t, rs, 31, 2, 1) // 2
+	addComment(t, rs, 30, 2, 0) // 3
+	addComment(t, rs, 32, 2, 2) // 4
+	addComment(t, rs, 31, 2, 3) // 5
+
+	// the page holds top level comments, each of them comes with all the replies of its thread
+	firstPage := getReviewComments(t, rs, 1, "")
+	if ids := commentIDs(firstPage.GetComments()); len(ids) != 3 || ids[0] != 1 || ids[1] != 2 || ids[2] != 4 {
+		t.Errorf("expected thread 1 with replies 2 and 4, got %v", ids)
+	}
+	if firstPage.GetComments()[2].GetDepth() != 2 {
+		t.Errorf("expected depth 2 of the reply to the reply, got %d", firstPage.GetComments()[2].GetDepth())
+	}
+	if firstPage.GetNextPageToken() == "" {
+		t.Fatalf("expected next page token")
+	}
+	secondPage := getReviewComments(t, rs, 1, firstPage.GetNextPageToken())
+	if ids := commentIDs(secondPage.GetComments()); len(ids) != 2 || ids[0] != 3 || ids[1] != 5 {
+		t.Errorf("expected thread 3 with reply 5, got %v", ids)
+	}
+	if secondPage.GetNextPageToken() != "" {
+		t.Errorf("expected no next page token on the last page, got %q", secondPage.GetNextPageToken())
+	}
+
+	_, err := callAs(t, nil, "/review.ReviewMaker/GetReviewComments", func(ctx context.Context) (interface{}, error) {
+		return rs.GetReviewComments(ctx, &review.ReviewCommentsRequest{ReviewID: &review.ReviewID{ID: 2}, PageToken: "bad token"})
+	})
+	if err != errorreview.ErrorBadPageToken {
+		t.Errorf("expected error %s, got %v", errorreview.ErrorBadPageToken, err)
+	}
+}
+
+func TestAddComment(t *testing.T) {
+	reviewRepo := newFakeReviewRepo()
+	rs := reviewserviceusecase.NewReviewGRPCServer(reviewRepo, 2, nil)
+	addComment(t, rs, 30, 2, 0) // 1, depth 0
+	addComment(t, rs, 30, 2, 1) // 2, depth 1
+	addComment(t, rs, 30, 2, 2) // 3, depth 2
+	addComment(t, rs, 10, 1, 0) // 4, on the hidden review of the author
+
+	cases := []struct {
+		name           string
+		userID         uint64
+		reviewID       uint64
+		parentID       uint64
+		expectedStatus review.CommentStatus
+	}{
+		{name: "reply within depth limit", userID: 31, reviewID: 2, parentID: 2, expectedStatus: review.CommentStatus_COMMENT_OK},
+		{name: "reply over depth limit", userID: 31, reviewID: 2, parentID: 3, expectedStatus: review.CommentStatus_COMMENT_TOO_DEEP},
+		{name: "unknown parent", userID: 31, reviewID: 2, parentID: 100, expectedStatus: review.CommentStatus_COMMENT_NO_PARENT},
+		{name: "parent from another review", userID: 10, reviewID: 2, parentID: 4, expectedStatus: review.CommentStatus_COMMENT_NO_PARENT},
+		{name: "unknown review", userID: 31, reviewID: 100, expectedStatus: review.CommentStatus_COMMENT_NO_REVIEW},
+		{name: "hidden review of another user", userID: 31, reviewID: 1, expectedStatus: review.CommentStatus_COMMENT_NO_REVIEW},
+		{name: "hidden review of the author", userID: 10, reviewID: 1, expectedStatus: review.CommentStatus_COMMENT_OK},
+	}
+	for _, testCase := range cases {
+		commentsBefore := len(reviewRepo.comments)
+		result := addComment(t, rs, testCase.userID, testCase.reviewID, testCase.parentID)
+		if result.GetStatus() != testCase.expectedStatus {
+			t.Errorf("%s: expected status %s, got %s", testCase.name, testCase.expectedStatus, result.GetStatus())
+		}
+		if added := len(reviewRepo.comments) != commentsBefore; added != (testCase.expectedStatus == review.CommentStatus_COMMENT_OK) {
+			t.Errorf("%s: comment added %t with status %s", testCase.name, added, result.GetStatus())
+		}
+	}
+}
+
+func TestUpdateAndDeleteComment(t *testing.T) {
+	cases := []struct {
+		name           string
+		userID         uint64
+		commentID      uint64
+		deleted        bool
+		expectedStatus review.CommentStatus
+	}{
+		{name: "author", userID: 30, commentID: 1, expectedStatus: review.CommentStatus_COMMENT_OK},
+		{name: "another user", userID: 31, commentID: 1, expectedStatus: review.CommentStatus_COMMENT_NOT_FOUND},
+		{name: "unknown comment", userID: 30, commentID: 100, expectedStatus: review.CommentStatus_COMMENT_NOT_FOUND},
+		{name: "deleted comment", userID: 30, commentID: 1, deleted: true, expectedStatus: review.CommentStatus_COMMENT_DELETED},
+	}
+	for _, testCase := range cases {
+		for _, method := range []string{"UpdateComment", "DeleteComment"} {
+			reviewRepo := newFakeReviewRepo()
+			rs := reviewserviceusecase.NewReviewGRPCServer(reviewRepo, reviewserviceusecase.DefaultMaxCommentDepth, nil)
+			addComment(t, rs, 30, 2, 0)
+			reviewRepo.comments[0].IsDeleted = testCase.deleted
+			reply, err := callAs(t, &auth.User{ID: testCase.userID}, "/review.ReviewMaker/"+method, func(ctx context.Context) (interface{}, error) {
+				if method == "UpdateComment" {
+					return rs.UpdateComment(ctx, &review.UpdateCommentData{
+						CommentID: &review.CommentID{ID: testCase.commentID},
+						UserID:    &review.UserID{ID: testCase.userID},
+						Text:      "edited",
+					})
+				}
+				return rs.DeleteComment(ctx, &review.DeleteCommentData{
+					CommentID: &review.CommentID{ID: testCase.commentID},
+					UserID:    &review.UserID{ID: testCase.userID},
+				})
+			})
+			if err != nil {
+				t.Errorf("%s, %s: unexpected error: %s", testCase.name, method, err)
+				continue
+			}
+			result := reply.(*review.CommentResult)
+			if result.GetStatus() != testCase.expectedStatus {
+				t.Errorf("%s, %s: expected status %s, got %s", testCase.name, method, testCase.expectedStatus, result.GetStatus())
+			}
+			changed := reviewRepo.comments[0].GetText() != "comment" || reviewRepo.comments[0].GetIsDeleted() != testCase.deleted
+			if changed != (testCase.expectedStatus == review.CommentStatus_COMMENT_OK) {
+				t.Errorf("%s, %s: comment changed %t with status %s", testCase.name, method, changed, result.GetStatus())
+			}
+		}
+	}
+}
+
+func TestDeletedCommentTombstone(t *testing.T) {
+	reviewRepo := newFakeReviewRepo()
+	rs := reviewserviceusecase.NewReviewGRPCServer(reviewRepo, reviewserviceusecase.DefaultMaxCommentDepth, nil)
+	addComment(t, rs, 30, 2, 0)
+	addComment(t, rs, 31, 2, 1)
+
+	reply, err := callAs(t, &auth.User{ID: 30}, "/review.ReviewMaker/DeleteComment", func(ctx context.Context) (interface{}, error) {
+		return rs.DeleteComment(ctx, &review.DeleteCommentData{
+			CommentID: &review.CommentID{ID: 1},
+			UserID:    &review.UserID{ID: 30},
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if deletedComment := reply.(*review.CommentResult).GetComment(); !deletedComment.GetIsDeleted() || deletedComment.GetAuthor() != nil {
+		t.Errorf("expected tombstone without author, got %v", deletedComment)
+	}
+
+	// the reply keeps its place in the thread under the tombstone
+	comments := getReviewComments(t, rs, 0, "").GetComments()
+	if ids := commentIDs(comments); len(ids) != 2 || ids[0] != 1 || ids[1] != 2 {
+		t.Fatalf("expected tombstone 1 with reply 2, got %v", ids)
+	}
+	if !comments[0].GetIsDeleted() || comments[0].GetText() != "" || comments[0].GetAuthor() != nil {
+		t.Errorf("expected tombstone without text and author, got %v", comments[0])
+	}
+	if comments[1].GetIsDeleted() || comments[1].GetAuthor().GetID().GetID() != 31 {
+		t.Errorf("expected reply of user 31, got %v", comments[1])
+	}
+}
+
+func TestUpdateCommentDeletedMeanwhile(t *testing.T) {
+	reviewRepo := newFakeReviewRepo()
+	rs := reviewserviceusecase.NewReviewGRPCServer(reviewRepo, reviewserviceusecase.DefaultMaxCommentDepth, nil)
+	addComment(t, rs, 30, 2, 0)
+	// the comment is deleted by another request of the author after the usecase has checked it
+	reviewRepo.beforeCommentChange = func() {
+		reviewRepo.comments[0].Text = ""
+		reviewRepo.comments[0].IsDeleted = true
+	}
+
+	reply, err := callAs(t, &auth.User{ID: 30}, "/review.ReviewMaker/UpdateComment", func(ctx context.Context) (interface{}, error) {
+		return rs.UpdateComment(ctx, &review.UpdateCommentData{
+			CommentID: &review.CommentID{ID: 1},
+			UserID:    &review.UserID{ID: 30},
+			Text:      "edited",
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if status := reply.(*review.CommentResult).GetStatus(); status != review.CommentStatus_COMMENT_DELETED {
+		t.Errorf("expected status %s, got %s", review.CommentStatus_COMMENT_DELETED, status)
+	}
+	if reviewRepo.comments[0].GetText() != "" {
+		t.Errorf("text of the deleted comment is changed to %q", reviewRepo.comments[0].GetText())
+	}
+}
@@ -0,0 +1,164 @@
+package reviewserviceusecse
+
+import (
+	"context"
+	"errors"
+	errorauth "kinopoisk/service_auth/error"
+	errorreview "kinopoisk/service_review/error"
+	"kinopoisk/service_review/interceptor"
+	review "kinopoisk/service_review/proto"
+)
+
+const (
+	defaultModerationPageSize = 20
+	maxModerationPageSize     = 100
+)
+
+var moderatedStatuses = map[review.ModerationAction]review.ReviewStatus{
+	review.ModerationAction_ACTION_APPROVE: review.ReviewStatus_REVIEW_PUBLISHED,
+	review.ModerationAction_ACTION_REJECT:  review.ReviewStatus_REVIEW_REJECTED,
+	review.ModerationAction_ACTION_HIDE:    review.ReviewStatus_REVIEW_HIDDEN,
+}
+
+func (rs *ReviewGRPCServer) ReportReview(ctx context.Context, in *review.ReportData) (*review.ReportResult, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.ReportResult{}, errorauth.ErrorNoLogger
+	}
+	rs.mu.RLock()
+	reportedReview, err := rs.ReviewRepo.GetReviewByIDRepo(in.GetReviewID().GetID())
+	rs.mu.RUnlock()
+	if errors.Is(err, errorreview.ErrorNoReview) {
+		logger.Errorf("no review with id %d", in.GetReviewID().GetID())
+		return &review.ReportResult{Status: review.ModerationStatus_MODERATION_NO_REVIEW}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in getting review by id: %s", err)
+		return &review.ReportResult{}, err
+	}
+	if reportedReview.GetAuthor().GetID().GetID() == in.GetUserID().GetID() {
+		logger.Errorf("user %d can not report own review %d", in.GetUserID().GetID(), in.GetReviewID().GetID())
+		return &review.ReportResult{Status: review.ModerationStatus_MODERATION_OWN_REVIEW}, nil
+	}
+	rs.mu.Lock()
+	isReported, err := rs.ReviewRepo.ReportReviewRepo(in.GetReviewID().GetID(), in.GetUserID().GetID(), in.GetReason())
+	rs.mu.Unlock()
+	if err != nil {
+		logger.Errorf("error in reporting review: %s", err)
+		return &review.ReportResult{}, err
+	}
+	if !isReported {
+		return &review.ReportResult{Status: review.ModerationStatus_MODERATION_ALREADY_REPORTED}, nil
+	}
+	return &review.ReportResult{Status: review.ModerationStatus_MODERATION_OK}, nil
+}
+
+func (rs *ReviewGRPCServer) GetModerationQueue(ctx context.Context, in *review.ModerationQueueRequest) (*review.ModerationQueue, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.ModerationQueue{}, errorauth.ErrorNoLogger
+	}
+	offset, err := decodePageToken(in.GetPageToken())
+	if err != nil {
+		logger.Errorf("bad page token %q: %s", in.GetPageToken(), err)
+		return &review.ModerationQueue{}, errorreview.ErrorBadPageToken
+	}
+	pageSize := uint64(in.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultModerationPageSize
+	}
+	if pageSize > maxModerationPageSize {
+		pageSize = maxModerationPageSize
+	}
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	reviews, err := rs.ReviewRepo.GetModerationQueueRepo(pageSize+1, offset)
+	if err != nil {
+		logger.Errorf("error in getting moderation queue: %s", err)
+		return &review.ModerationQueue{}, err
+	}
+	nextPageToken := ""
+	if uint64(len(reviews)) > pageSize {
+		reviews = reviews[:pageSize]
+		nextPageToken = encodePageToken(offset + pageSize)
+	}
+	items := make([]*review.ModerationQueueItem, 0, len(reviews))
+	for _, queuedReview := range reviews {
+		reports, err := rs.ReviewRepo.GetOpenReportsRepo(queuedReview.GetID().GetID())
+		if err != nil {
+			logger.Errorf("error in getting reports of review %d: %s", queuedReview.GetID().GetID(), err)
+			return &review.ModerationQueue{}, err
+		}
+		items = append(items, &review.ModerationQueueItem{
+			Review:  queuedReview,
+			Reports: reports,
+		})
+	}
+	return &review.ModerationQueue{
+		Items:         items,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// ModerateReview applies the moderator decision; approved reviews start and hidden or rejected ones stop
+// counting toward the film rating.
+func (rs *ReviewGRPCServer) ModerateReview(ctx context.Context, in *review.ModerationData) (*review.ModerationResult, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.ModerationResult{}, errorauth.ErrorNoLogger
+	}
+	newStatus, ok := moderatedStatuses[in.GetAction()]
+	if !ok {
+		logger.Errorf("unknown moderation action %d", in.GetAction())
+		return &review.ModerationResult{}, errorreview.ErrorBadModerationAction
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	oldReview, err := rs.ReviewRepo.GetReviewByIDRepo(in.GetReviewID().GetID())
+	if errors.Is(err, errorreview.ErrorNoReview) {
+		logger.Errorf("no review with id %d", in.GetReviewID().GetID())
+		return &review.ModerationResult{Status: review.ModerationStatus_MODERATION_NO_REVIEW}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in getting review by id: %s", err)
+		return &review.ModerationResult{}, err
+	}
+	err = rs.ReviewRepo.ModerateReviewRepo(oldReview, in.GetModeratorID().GetID(), in.GetAction(), newStatus, in.GetReason())
+	if err != nil {
+		logger.Errorf("error in moderating review: %s", err)
+		return &review.ModerationResult{}, err
+	}
+	moderatedReview, err := rs.ReviewRepo.GetReviewByIDRepo(in.GetReviewID().GetID())
+	if err != nil {
+		logger.Errorf("error in getting moderated review: %s", err)
+		return &review.ModerationResult{}, err
+	}
+	return &review.ModerationResult{
+		Status: review.ModerationStatus_MODERATION_OK,
+		Review: moderatedReview,
+	}, nil
+}
+
+func (rs *ReviewGRPCServer) GetModerationLog(ctx context.Context, in *review.ReviewID) (*review.ModerationLog, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.ModerationLog{}, errorauth.ErrorNoLogger
+	}
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	_, err = rs.ReviewRepo.GetReviewByIDRepo(in.GetID())
+	if errors.Is(err, errorreview.ErrorNoReview) {
+		logger.Errorf("no review with id %d", in.GetID())
+		return &review.ModerationLog{Status: review.ModerationStatus_MODERATION_NO_REVIEW}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in getting review by id: %s", err)
+		return &review.ModerationLog{}, err
+	}
+	entries, err := rs.ReviewRepo.GetModerationLogRepo(in.GetID())
+	if err != nil {
+		logger.Errorf("error in getting moderation log: %s", err)
+		return &review.ModerationLog{}, err
+	}
+	return &review.ModerationLog{Entries: entries}, nil
+}
@@ -0,0 +1,144 @@
+package reviewserviceusecse
+
+import (
+	"errors"
+	"go.uber.org/zap"
+	ratingbroker "kinopoisk/service_rating/broker"
+	ratingmetrics "kinopoisk/service_rating/metrics"
+	reviewservicerepo "kinopoisk/service_review/repo/mysql"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	outboxBatchSize     = 100
+	outboxMinRetryDelay = time.Second
+	outboxMaxRetryDelay = 5 * time.Minute
+	outboxSentEventTTL  = 7 * 24 * time.Hour
+	outboxCleanupPeriod = time.Hour
+)
+
+var errNoOutboxLag = errors.New("outbox lag is not measured yet")
+
+var (
+	// outboxLag is the lag the relay has measured last, the gauges are left out of the scrape until the first one
+	outboxLag           atomic.Pointer[reviewservicerepo.OutboxLag]
+	outboxPendingEvents = ratingmetrics.NewGaugeFunc("review_outbox_pending_events",
+		"Outbox events which are not published yet.", measuredOutboxLag(func(lag *reviewservicerepo.OutboxLag) float64 {
+			return float64(lag.Pending)
+		}))
+	outboxLagSeconds = ratingmetrics.NewGaugeFunc("review_outbox_lag_seconds",
+		"Age of the oldest outbox event which is not published yet.", measuredOutboxLag(func(lag *reviewservicerepo.OutboxLag) float64 {
+			return lag.OldestPendingSecs
+		}))
+	outboxPublished     = ratingmetrics.NewCounter("review_outbox_published_total", "Outbox events confirmed by the broker.")
+	outboxPublishErrors = ratingmetrics.NewCounter("review_outbox_publish_errors_total", "Outbox events not confirmed by the broker.")
+)
+
+// OutboxRelay publishes the events written to the outbox together with review changes.
+type OutboxRelay struct {
+	logger     *zap.SugaredLogger
+	outboxRepo reviewservicerepo.OutboxRepo
+	publisher  ratingbroker.Publisher
+	interval   time.Duration
+}
+
+func NewOutboxRelay(logger *zap.SugaredLogger, outboxRepo reviewservicerepo.OutboxRepo, publisher ratingbroker.Publisher,
+	interval time.Duration) *OutboxRelay {
+	return &OutboxRelay{
+		logger:     logger,
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+		interval:   interval,
+	}
+}
+
+// Run polls the outbox every interval until done is closed.
+func (o *OutboxRelay) Run(done <-chan struct{}) {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+	lastCleanup := time.Now()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			o.relayEvents()
+			o.updateLag()
+			if time.Since(lastCleanup) > outboxCleanupPeriod {
+				o.deleteSentEvents()
+				lastCleanup = time.Now()
+			}
+		}
+	}
+}
+
+// relayEvents publishes the ready events in the order they were written. The batch stops on the first failure:
+// the broker is most likely unavailable and the rest of the events would fail too.
+func (o *OutboxRelay) relayEvents() {
+	events, err := o.outboxRepo.GetUnsentEventsRepo(outboxBatchSize)
+	if err != nil {
+		o.logger.Errorf("error in getting unsent outbox events: %s", err)
+		return
+	}
+	for _, event := range events {
+		err = o.publisher.Publish(event.Queue, event.Payload)
+		if err != nil {
+			outboxPublishErrors.Inc()
+			retryAfter := outboxRetryDelay(event.Attempts)
+			o.logger.Errorf("error in publishing outbox event %d, retry in %s: %s", event.ID, retryAfter, err)
+			err = o.outboxRepo.MarkEventFailedRepo(event.ID, retryAfter, err)
+			if err != nil {
+				o.logger.Errorf("error in marking outbox event %d failed: %s", event.ID, err)
+			}
+			return
+		}
+		outboxPublished.Inc()
+		// the event is published again if it is not marked sent, consumers deduplicate it by its id
+		err = o.outboxRepo.MarkEventSentRepo(event.ID)
+		if err != nil {
+			o.logger.Errorf("error in marking outbox event %d sent: %s", event.ID, err)
+			return
+		}
+	}
+}
+
+func (o *OutboxRelay) updateLag() {
+	lag, err := o.outboxRepo.GetOutboxLagRepo()
+	if err != nil {
+		o.logger.Errorf("error in getting outbox lag: %s", err)
+		return
+	}
+	outboxLag.Store(lag)
+}
+
+func measuredOutboxLag(value func(lag *reviewservicerepo.OutboxLag) float64) func() (float64, error) {
+	return func() (float64, error) {
+		lag := outboxLag.Load()
+		if lag == nil {
+			return 0, errNoOutboxLag
+		}
+		return value(lag), nil
+	}
+}
+
+func (o *OutboxRelay) deleteSentEvents() {
+	deleted, err := o.outboxRepo.DeleteSentEventsRepo(outboxSentEventTTL)
+	if err != nil {
+		o.logger.Errorf("error in deleting sent outbox events: %s", err)
+		return
+	}
+	o.logger.Infof("%d sent outbox events deleted", deleted)
+}
+
+// outboxRetryDelay doubles the delay with every failed attempt.
+func outboxRetryDelay(attempts uint32) time.Duration {
+	delay := outboxMinRetryDelay
+	for i := uint32(0); i < attempts && delay < outboxMaxRetryDelay; i++ {
+		delay *= 2
+	}
+	if delay > outboxMaxRetryDelay {
+		delay = outboxMaxRetryDelay
+	}
+	return delay
+}
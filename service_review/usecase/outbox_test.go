@@ -0,0 +1,234 @@
+package reviewserviceusecse
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"go.uber.org/zap"
+	ratingbroker "kinopoisk/service_rating/broker"
+	ratingmetrics "kinopoisk/service_rating/metrics"
+	reviewservicerepo "kinopoisk/service_review/repo/mysql"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeOutboxRepo keeps the outbox rows in memory, a failed row is not ready until the test makes it ready.
+type fakeOutboxRepo struct {
+	events     []*reviewservicerepo.OutboxEvent
+	sent       map[uint64]bool
+	retryAfter map[uint64]time.Duration
+	waiting    map[uint64]bool
+	markErr    error
+	lagSecs    float64
+}
+
+func newFakeOutboxRepo(payloads ...string) *fakeOutboxRepo {
+	repo := &fakeOutboxRepo{
+		sent:       make(map[uint64]bool),
+		retryAfter: make(map[uint64]time.Duration),
+		waiting:    make(map[uint64]bool),
+	}
+	for i, payload := range payloads {
+		repo.events = append(repo.events, &reviewservicerepo.OutboxEvent{
+			ID:      uint64(i + 1),
+			Queue:   "change_rating",
+			Payload: []byte(payload),
+		})
+	}
+	return repo
+}
+
+func (f *fakeOutboxRepo) GetUnsentEventsRepo(limit uint64) ([]*reviewservicerepo.OutboxEvent, error) {
+	events := make([]*reviewservicerepo.OutboxEvent, 0)
+	for _, event := range f.events {
+		if f.sent[event.ID] || f.waiting[event.ID] || uint64(len(events)) == limit {
+			continue
+		}
+		copied := *event
+		events = append(events, &copied)
+	}
+	return events, nil
+}
+
+func (f *fakeOutboxRepo) MarkEventSentRepo(id uint64) error {
+	if f.markErr != nil {
+		return f.markErr
+	}
+	f.sent[id] = true
+	return nil
+}
+
+func (f *fakeOutboxRepo) MarkEventFailedRepo(id uint64, retryAfter time.Duration, publishErr error) error {
+	for _, event := range f.events {
+		if event.ID == id {
+			event.Attempts++
+		}
+	}
+	f.retryAfter[id] = retryAfter
+	f.waiting[id] = true
+	return nil
+}
+
+func (f *fakeOutboxRepo) GetOutboxLagRepo() (*reviewservicerepo.OutboxLag, error) {
+	lag := &reviewservicerepo.OutboxLag{OldestPendingSecs: f.lagSecs}
+	for _, event := range f.events {
+		if !f.sent[event.ID] {
+			lag.Pending++
+		}
+	}
+	return lag, nil
+}
+
+func (f *fakeOutboxRepo) DeleteSentEventsRepo(olderThan time.Duration) (int64, error) {
+	return 0, nil
+}
+
+// fakePublisher fails the publishing of the payloads from fail as a broker which has not confirmed them.
+type fakePublisher struct {
+	published []string
+	fail      map[string]bool
+}
+
+func (p *fakePublisher) Publish(queue string, body []byte) error {
+	if p.fail[string(body)] {
+		return ratingbroker.ErrPublishNotConfirmed
+	}
+	p.published = append(p.published, string(body))
+	return nil
+}
+
+func TestRelayEventsNotConfirmed(t *testing.T) {
+	outboxRepo := newFakeOutboxRepo("first", "second", "third")
+	publisher := &fakePublisher{fail: map[string]bool{"second": true}}
+	relay := NewOutboxRelay(zap.NewNop().Sugar(), outboxRepo, publisher, time.Second)
+
+	relay.relayEvents()
+	if !outboxRepo.sent[1] {
+		t.Errorf("confirmed event is not marked sent")
+	}
+	if outboxRepo.sent[2] {
+		t.Errorf("event which is not confirmed is marked sent")
+	}
+	if outboxRepo.sent[3] || len(publisher.published) != 1 {
+		t.Errorf("batch is not stopped on the failure, published %v", publisher.published)
+	}
+	if outboxRepo.events[1].Attempts != 1 || outboxRepo.retryAfter[2] != outboxMinRetryDelay {
+		t.Errorf("expected 1 attempt and retry in %s, got %d attempts and retry in %s",
+			outboxMinRetryDelay, outboxRepo.events[1].Attempts, outboxRepo.retryAfter[2])
+	}
+}
+
+func TestRelayEventsBackoff(t *testing.T) {
+	outboxRepo := newFakeOutboxRepo("event")
+	publisher := &fakePublisher{fail: map[string]bool{"event": true}}
+	relay := NewOutboxRelay(zap.NewNop().Sugar(), outboxRepo, publisher, time.Second)
+
+	expectedDelays := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second}
+	for i, expectedDelay := range expectedDelays {
+		relay.relayEvents()
+		if outboxRepo.events[0].Attempts != uint32(i+1) {
+			t.Errorf("expected %d attempts, got %d", i+1, outboxRepo.events[0].Attempts)
+		}
+		if outboxRepo.retryAfter[1] != expectedDelay {
+			t.Errorf("attempt %d: expected retry in %s, got %s", i+1, expectedDelay, outboxRepo.retryAfter[1])
+		}
+		// the retry delay has passed
+		outboxRepo.waiting[1] = false
+	}
+	if outboxRepo.sent[1] {
+		t.Errorf("event which is not confirmed is marked sent")
+	}
+	if delay := outboxRetryDelay(100); delay != outboxMaxRetryDelay {
+		t.Errorf("expected retry delay to stop growing at %s, got %s", outboxMaxRetryDelay, delay)
+	}
+}
+
+func TestRelayEventsRepublishedAfterCrash(t *testing.T) {
+	outboxRepo := newFakeOutboxRepo("event")
+	outboxRepo.markErr = errors.New("connection lost")
+	publisher := &fakePublisher{}
+	relay := NewOutboxRelay(zap.NewNop().Sugar(), outboxRepo, publisher, time.Second)
+
+	// the event is published, but the relay fails before it is marked sent
+	relay.relayEvents()
+	if outboxRepo.sent[1] {
+		t.Fatalf("event is marked sent")
+	}
+	outboxRepo.markErr = nil
+	relay.relayEvents()
+	if !outboxRepo.sent[1] {
+		t.Errorf("event is not marked sent after it is published again")
+	}
+	// the consumer skips the second copy by the event id
+	if len(publisher.published) != 2 {
+		t.Errorf("expected the event to be published twice, got %v", publisher.published)
+	}
+}
+
+// scrapeOutboxMetrics returns the values of the outbox metrics in the scrape, a metric left out of it is missing.
+func scrapeOutboxMetrics(t *testing.T) map[string]float64 {
+	var text bytes.Buffer
+	err := ratingmetrics.DefaultRegistry.WriteText(&text)
+	if err != nil {
+		t.Fatalf("can not write metrics: %s", err)
+	}
+	values := make(map[string]float64)
+	scanner := bufio.NewScanner(&text)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 || !strings.HasPrefix(fields[0], "review_outbox_") {
+			continue
+		}
+		value, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			t.Fatalf("bad value in %q: %s", scanner.Text(), err)
+		}
+		values[fields[0]] = value
+	}
+	return values
+}
+
+func TestOutboxMetrics(t *testing.T) {
+	outboxLag.Store(nil)
+	before := scrapeOutboxMetrics(t)
+	if _, ok := before["review_outbox_lag_seconds"]; ok {
+		t.Errorf("lag is scraped before it is measured")
+	}
+
+	outboxRepo := newFakeOutboxRepo("first", "second", "third")
+	outboxRepo.lagSecs = 12.5
+	publisher := &fakePublisher{fail: map[string]bool{"second": true}}
+	relay := NewOutboxRelay(zap.NewNop().Sugar(), outboxRepo, publisher, time.Second)
+	relay.relayEvents()
+	relay.updateLag()
+
+	after := scrapeOutboxMetrics(t)
+	if after["review_outbox_pending_events"] != 2 || after["review_outbox_lag_seconds"] != 12.5 {
+		t.Errorf("expected 2 pending events and lag 12.5, got %v", after)
+	}
+	if published := after["review_outbox_published_total"] - before["review_outbox_published_total"]; published != 1 {
+		t.Errorf("expected 1 published event, got %v", published)
+	}
+	if failed := after["review_outbox_publish_errors_total"] - before["review_outbox_publish_errors_total"]; failed != 1 {
+		t.Errorf("expected 1 publish error, got %v", failed)
+	}
+}
+
+func TestOutboxRelayRunStops(t *testing.T) {
+	outboxRepo := newFakeOutboxRepo("event")
+	relay := NewOutboxRelay(zap.NewNop().Sugar(), outboxRepo, &fakePublisher{}, time.Millisecond)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		relay.Run(done)
+		close(stopped)
+	}()
+	close(done)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("relay is not stopped")
+	}
+}
@@ -0,0 +1,110 @@
+package reviewserviceusecse
+
+import (
+	"context"
+	"errors"
+	errorauth "kinopoisk/service_auth/error"
+	errorreview "kinopoisk/service_review/error"
+	"kinopoisk/service_review/interceptor"
+	review "kinopoisk/service_review/proto"
+)
+
+const (
+	recentReviewsNumber    = 5
+	defaultUserReviewsPage = 20
+	maxUserReviewsPageSize = 100
+)
+
+func (rs *ReviewGRPCServer) GetUserProfile(ctx context.Context, in *review.UserProfileRequest) (*review.UserProfile, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.UserProfile{}, errorauth.ErrorNoLogger
+	}
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	profile, status, err := rs.getVisibleProfile(in.GetUserID().GetID(), in.GetRequesterID().GetID())
+	if err != nil {
+		logger.Errorf("error in getting user profile: %s", err)
+		return &review.UserProfile{}, err
+	}
+	if status != review.ProfileStatus_PROFILE_OK {
+		logger.Errorf("profile of user %d is not available: %s", in.GetUserID().GetID(), status)
+		return &review.UserProfile{Status: status}, nil
+	}
+	recentReviews, err := rs.ReviewRepo.GetUserReviewsRepo(in.GetUserID().GetID(), recentReviewsNumber, 0)
+	if err != nil {
+		logger.Errorf("error in getting recent user reviews: %s", err)
+		return &review.UserProfile{}, err
+	}
+	if !in.GetRevealSpoilers() {
+		for _, userReview := range recentReviews {
+			maskSpoilers(userReview)
+		}
+	}
+	profile.RecentReviews = recentReviews
+	return profile, nil
+}
+
+func (rs *ReviewGRPCServer) GetUserReviews(ctx context.Context, in *review.UserReviewsRequest) (*review.UserReviews, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.UserReviews{}, errorauth.ErrorNoLogger
+	}
+	offset, err := decodePageToken(in.GetPageToken())
+	if err != nil {
+		logger.Errorf("bad page token %q: %s", in.GetPageToken(), err)
+		return &review.UserReviews{}, errorreview.ErrorBadPageToken
+	}
+	pageSize := uint64(in.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultUserReviewsPage
+	}
+	if pageSize > maxUserReviewsPageSize {
+		pageSize = maxUserReviewsPageSize
+	}
+	rs.mu.RLock()
+	defer rs.mu.RUnlock()
+	_, status, err := rs.getVisibleProfile(in.GetUserID().GetID(), in.GetRequesterID().GetID())
+	if err != nil {
+		logger.Errorf("error in getting user profile: %s", err)
+		return &review.UserReviews{}, err
+	}
+	if status != review.ProfileStatus_PROFILE_OK {
+		logger.Errorf("reviews of user %d are not available: %s", in.GetUserID().GetID(), status)
+		return &review.UserReviews{Status: status}, nil
+	}
+	reviews, err := rs.ReviewRepo.GetUserReviewsRepo(in.GetUserID().GetID(), pageSize+1, offset)
+	if err != nil {
+		logger.Errorf("error in getting user reviews: %s", err)
+		return &review.UserReviews{}, err
+	}
+	nextPageToken := ""
+	if uint64(len(reviews)) > pageSize {
+		reviews = reviews[:pageSize]
+		nextPageToken = encodePageToken(offset + pageSize)
+	}
+	if !in.GetRevealSpoilers() {
+		for _, userReview := range reviews {
+			maskSpoilers(userReview)
+		}
+	}
+	return &review.UserReviews{
+		Reviews:       reviews,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// getVisibleProfile hides private profiles from everyone except their owners.
+func (rs *ReviewGRPCServer) getVisibleProfile(userID, requesterID uint64) (*review.UserProfile, review.ProfileStatus, error) {
+	profile, err := rs.ReviewRepo.GetUserProfileRepo(userID)
+	if errors.Is(err, errorreview.ErrorNoUser) {
+		return nil, review.ProfileStatus_PROFILE_NOT_FOUND, nil
+	}
+	if err != nil {
+		return nil, review.ProfileStatus_PROFILE_OK, err
+	}
+	if profile.GetIsPrivate() && userID != requesterID {
+		return nil, review.ProfileStatus_PROFILE_PRIVATE, nil
+	}
+	return profile, review.ProfileStatus_PROFILE_OK, nil
+}
@@ -0,0 +1,190 @@
+package reviewserviceusecse_test
+
+import (
+	"context"
+	auth "kinopoisk/service_auth/proto"
+	errorreview "kinopoisk/service_review/error"
+	review "kinopoisk/service_review/proto"
+	reviewserviceusecase "kinopoisk/service_review/usecase"
+	"sort"
+	"testing"
+)
+
+func (f *fakeReviewRepo) GetUserProfileRepo(userID uint64) (*review.UserProfile, error) {
+	profile, ok := f.profiles[userID]
+	if !ok {
+		return nil, errorreview.ErrorNoUser
+	}
+	return &review.UserProfile{User: profile.User, IsPrivate: profile.IsPrivate}, nil
+}
+
+// GetUserReviewsRepo returns the reviews of the user, the newest first.
+func (f *fakeReviewRepo) GetUserReviewsRepo(userID, limit, offset uint64) ([]*review.Review, error) {
+	reviewIDs := []uint64{}
+	for id, userReview := range f.reviews {
+		if userReview.GetAuthor().GetID().GetID() == userID {
+			reviewIDs = append(reviewIDs, id)
+		}
+	}
+	sort.Slice(reviewIDs, func(i, j int) bool { return reviewIDs[i] > reviewIDs[j] })
+	reviews := []*review.Review{}
+	for i := offset; i < uint64(len(reviewIDs)) && i < offset+limit; i++ {
+		userReview, _ := f.GetReviewByIDRepo(reviewIDs[i])
+		reviews = append(reviews, userReview)
+	}
+	return reviews, nil
+}
+
+func newProfileTestServer() *reviewserviceusecase.ReviewGRPCServer {
+	reviewRepo := newFakeReviewRepo()
+	reviewRepo.reviews[3] = &review.Review{
+		ID:        &review.ReviewID{ID: 3},
+		Mark:      6,
+		Comment:   "he dies",
+		Author:    &review.User{ID: &review.UserID{ID: 10}, Username: "author"},
+		Status:    review.ReviewStatus_REVIEW_PUBLISHED,
+		IsSpoiler: true,
+	}
+	reviewRepo.reviews[4] = &review.Review{
+		ID:      &review.ReviewID{ID: 4},
+		Mark:    5,
+		Comment: "so so",
+		Author:  &review.User{ID: &review.UserID{ID: 40}, Username: "private"},
+		Status:  review.ReviewStatus_REVIEW_PUBLISHED,
+	}
+	reviewRepo.profiles = map[uint64]*review.UserProfile{
+		10: {User: &review.User{ID: &review.UserID{ID: 10}, Username: "author"}},
+		40: {User: &review.User{ID: &review.UserID{ID: 40}, Username: "private"}, IsPrivate: true},
+	}
+	return reviewserviceusecase.NewReviewGRPCServer(reviewRepo, reviewserviceusecase.DefaultMaxCommentDepth, nil)
+}
+
+func TestGetUserProfile(t *testing.T) {
+	rs := newProfileTestServer()
+
+	cases := []struct {
+		name            string
+		userID          uint64
+		requesterID     uint64
+		expectedStatus  review.ProfileStatus
+		expectedReviews int
+	}{
+		{name: "public profile, anonymous", userID: 10, requesterID: 0, expectedStatus: review.ProfileStatus_PROFILE_OK, expectedReviews: 3},
+		{name: "public profile, other user", userID: 10, requesterID: 40, expectedStatus: review.ProfileStatus_PROFILE_OK, expectedReviews: 3},
+		{name: "private profile, anonymous", userID: 40, requesterID: 0, expectedStatus: review.ProfileStatus_PROFILE_PRIVATE},
+		{name: "private profile, other user", userID: 40, requesterID: 10, expectedStatus: review.ProfileStatus_PROFILE_PRIVATE},
+		{name: "private profile, owner", userID: 40, requesterID: 40, expectedStatus: review.ProfileStatus_PROFILE_OK, expectedReviews: 1},
+		{name: "unknown user", userID: 100, requesterID: 10, expectedStatus: review.ProfileStatus_PROFILE_NOT_FOUND},
+	}
+	for _, testCase := range cases {
+		var user *auth.User
+		if testCase.requesterID != 0 {
+			user = &auth.User{ID: testCase.requesterID}
+		}
+		reply, err := callAs(t, user, "/review.ReviewMaker/GetUserProfile", func(ctx context.Context) (interface{}, error) {
+			return rs.GetUserProfile(ctx, &review.UserProfileRequest{
+				UserID:      &review.UserID{ID: testCase.userID},
+				RequesterID: &review.UserID{ID: testCase.requesterID},
+			})
+		})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		profile := reply.(*review.UserProfile)
+		if profile.GetStatus() != testCase.expectedStatus {
+			t.Errorf("%s: expected status %s, got %s", testCase.name, testCase.expectedStatus, profile.GetStatus())
+		}
+		if testCase.expectedStatus != review.ProfileStatus_PROFILE_OK && profile.GetUser() != nil {
+			t.Errorf("%s: user of the unavailable profile is returned", testCase.name)
+		}
+		if len(profile.GetRecentReviews()) != testCase.expectedReviews {
+			t.Errorf("%s: expected %d recent reviews, got %d", testCase.name, testCase.expectedReviews, len(profile.GetRecentReviews()))
+		}
+	}
+}
+
+func TestGetUserReviews(t *testing.T) {
+	rs := newProfileTestServer()
+	getUserReviews := func(userID, requesterID uint64, pageToken string, revealSpoilers bool) (*review.UserReviews, error) {
+		reply, err := callAs(t, nil, "/review.ReviewMaker/GetUserReviews", func(ctx context.Context) (interface{}, error) {
+			return rs.GetUserReviews(ctx, &review.UserReviewsRequest{
+				UserID:         &review.UserID{ID: userID},
+				RequesterID:    &review.UserID{ID: requesterID},
+				PageSize:       2,
+				PageToken:      pageToken,
+				RevealSpoilers: revealSpoilers,
+			})
+		})
+		if err != nil {
+			return nil, err
+		}
+		return reply.(*review.UserReviews), nil
+	}
+
+	firstPage, err := getUserReviews(10, 0, "", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ids := reviewIDs(firstPage.GetReviews()); len(ids) != 2 || ids[0] != 3 || ids[1] != 2 {
+		t.Errorf("expected reviews 3 and 2 on the first page, got %v", ids)
+	}
+	if firstPage.GetReviews()[0].GetComment() != "[spoiler]" {
+		t.Errorf("expected masked spoiler, got %q", firstPage.GetReviews()[0].GetComment())
+	}
+	if firstPage.GetNextPageToken() == "" {
+		t.Fatalf("expected next page token")
+	}
+	secondPage, err := getUserReviews(10, 0, firstPage.GetNextPageToken(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if ids := reviewIDs(secondPage.GetReviews()); len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("expected review 1 on the second page, got %v", ids)
+	}
+	if secondPage.GetNextPageToken() != "" {
+		t.Errorf("expected no next page token on the last page, got %q", secondPage.GetNextPageToken())
+	}
+
+	revealed, err := getUserReviews(10, 0, "", true)
+	if err != nil || revealed.GetReviews()[0].GetComment() != "he dies" {
+		t.Errorf("expected revealed spoiler, got %v and error %v", revealed, err)
+	}
+
+	if _, err = getUserReviews(10, 0, "bad token", false); err != errorreview.ErrorBadPageToken {
+		t.Errorf("expected error %s, got %v", errorreview.ErrorBadPageToken, err)
+	}
+
+	cases := []struct {
+		name           string
+		userID         uint64
+		requesterID    uint64
+		expectedStatus review.ProfileStatus
+	}{
+		{name: "private profile, anonymous", userID: 40, requesterID: 0, expectedStatus: review.ProfileStatus_PROFILE_PRIVATE},
+		{name: "private profile, other user", userID: 40, requesterID: 10, expectedStatus: review.ProfileStatus_PROFILE_PRIVATE},
+		{name: "private profile, owner", userID: 40, requesterID: 40, expectedStatus: review.ProfileStatus_PROFILE_OK},
+		{name: "unknown user", userID: 100, requesterID: 0, expectedStatus: review.ProfileStatus_PROFILE_NOT_FOUND},
+	}
+	for _, testCase := range cases {
+		userReviews, err := getUserReviews(testCase.userID, testCase.requesterID, "", false)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		if userReviews.GetStatus() != testCase.expectedStatus {
+			t.Errorf("%s: expected status %s, got %s", testCase.name, testCase.expectedStatus, userReviews.GetStatus())
+		}
+		if shown := len(userReviews.GetReviews()) != 0; shown != (testCase.expectedStatus == review.ProfileStatus_PROFILE_OK) {
+			t.Errorf("%s: reviews shown %t with status %s", testCase.name, shown, userReviews.GetStatus())
+		}
+	}
+}
+
+func reviewIDs(reviews []*review.Review) []uint64 {
+	ids := make([]uint64, len(reviews))
+	for i, userReview := range reviews {
+		ids[i] = userReview.GetID().GetID()
+	}
+	return ids
+}
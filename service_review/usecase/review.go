@@ -2,59 +2,81 @@ package reviewserviceusecse
 
 import (
 	"context"
-	"encoding/json"
+	"encoding/base64"
 	"errors"
-	"github.com/streadway/amqp"
 	errorauth "kinopoisk/service_auth/error"
+	authpermission "kinopoisk/service_auth/permission"
 	errorreview "kinopoisk/service_review/error"
+	reviewfilter "kinopoisk/service_review/filter"
 	"kinopoisk/service_review/interceptor"
 	review "kinopoisk/service_review/proto"
 	reviewservicerepo "kinopoisk/service_review/repo/mysql"
+	"strconv"
 	"sync"
 )
 
 const (
-	ChangeRatingQueueName = "change_rating"
+	defaultReviewsPageSize = 20
+	maxReviewsPageSize     = 100
 )
 
 type ReviewGRPCServer struct {
 	review.UnimplementedReviewMakerServer
-	ReviewRepo reviewservicerepo.ReviewRepo
-	mu         *sync.RWMutex
-	rabbitChan *amqp.Channel
+	ReviewRepo      reviewservicerepo.ReviewRepo
+	mu              *sync.RWMutex
+	maxCommentDepth uint32
+	profanityFilter *reviewfilter.ProfanityFilter
 }
 
-type ChangeRatingInfo struct {
-	ChangeType string
-	ReviewID   uint64
-	OldMark    uint32
-	NewMark    uint32
-	FilmID     uint64
-}
-
-func NewReviewGRPCServer(reviewRepo reviewservicerepo.ReviewRepo, rabbitChan *amqp.Channel) *ReviewGRPCServer {
+func NewReviewGRPCServer(reviewRepo reviewservicerepo.ReviewRepo, maxCommentDepth uint32,
+	profanityFilter *reviewfilter.ProfanityFilter) *ReviewGRPCServer {
 	return &ReviewGRPCServer{
 		UnimplementedReviewMakerServer: review.UnimplementedReviewMakerServer{},
 		ReviewRepo:                     reviewRepo,
 		mu:                             &sync.RWMutex{},
-		rabbitChan:                     rabbitChan,
+		maxCommentDepth:                maxCommentDepth,
+		profanityFilter:                profanityFilter,
 	}
 }
 
-func (rs *ReviewGRPCServer) GetFilmReviews(ctx context.Context, in *review.FilmID) (*review.Reviews, error) {
+func (rs *ReviewGRPCServer) GetFilmReviews(ctx context.Context, in *review.FilmReviewsRequest) (*review.Reviews, error) {
 	logger, err := interceptor.GetLoggerFromContext(ctx)
 	if err != nil {
 		return &review.Reviews{}, errorauth.ErrorNoLogger
 	}
+	offset, err := decodePageToken(in.GetPageToken())
+	if err != nil {
+		logger.Errorf("bad page token %q: %s", in.GetPageToken(), err)
+		return &review.Reviews{}, errorreview.ErrorBadPageToken
+	}
+	pageSize := uint64(in.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultReviewsPageSize
+	}
+	if pageSize > maxReviewsPageSize {
+		pageSize = maxReviewsPageSize
+	}
 	rs.mu.RLock()
-	reviews, err := rs.ReviewRepo.GetFilmReviewsRepo(in.GetID())
+	// one extra review is requested to find out if there is a next page
+	reviews, err := rs.ReviewRepo.GetFilmReviewsRepo(in.GetFilmID().GetID(), in.GetSort(), in.GetCriticsOnly(), pageSize+1, offset)
 	rs.mu.RUnlock()
 	if err != nil {
 		logger.Errorf("error in getting film reviews: %s", err)
 		return &review.Reviews{}, err
 	}
+	nextPageToken := ""
+	if uint64(len(reviews)) > pageSize {
+		reviews = reviews[:pageSize]
+		nextPageToken = encodePageToken(offset + pageSize)
+	}
+	if !in.GetRevealSpoilers() {
+		for _, filmReview := range reviews {
+			maskSpoilers(filmReview)
+		}
+	}
 	return &review.Reviews{
-		Reviews: reviews,
+		Reviews:       reviews,
+		NextPageToken: nextPageToken,
 	}, nil
 }
 
@@ -76,6 +98,7 @@ func (rs *ReviewGRPCServer) NewReview(ctx context.Context, in *review.NewReviewD
 			return &review.Review{}, err
 		}
 	}
+	in.Review.Status = rs.reviewStatusAfterEdit(in.GetReview().GetComment(), review.ReviewStatus_REVIEW_PUBLISHED)
 	rs.mu.Lock()
 	newReview, err := rs.ReviewRepo.NewReviewRepo(in.GetReview(), in.GetFilmID().ID, in.GetUserID().ID)
 	rs.mu.Unlock()
@@ -83,15 +106,6 @@ func (rs *ReviewGRPCServer) NewReview(ctx context.Context, in *review.NewReviewD
 		logger.Errorf("error in adding new review: %s", err)
 		return &review.Review{}, err
 	}
-	changeRatingInfo := &ChangeRatingInfo{
-		ChangeType: "Add",
-		NewMark:    newReview.Mark,
-		ReviewID:   newReview.ID.ID,
-	}
-	err = rs.putChangeRatingTaskToQueue(changeRatingInfo)
-	if err != nil {
-		logger.Errorf("error in changing rating: %s", err)
-	}
 	return newReview, nil
 }
 
@@ -116,7 +130,7 @@ func (rs *ReviewGRPCServer) DeleteReview(ctx context.Context, in *review.DeleteR
 		}, err
 	}
 	rs.mu.Lock()
-	isDeleted, err := rs.ReviewRepo.DeleteReviewRepo(rev.ID.ID)
+	isDeleted, err := rs.ReviewRepo.DeleteReviewRepo(rev)
 	rs.mu.Unlock()
 	if err != nil {
 		logger.Errorf("error in deleting review: %s", err)
@@ -124,16 +138,6 @@ func (rs *ReviewGRPCServer) DeleteReview(ctx context.Context, in *review.DeleteR
 			IsDeleted: false,
 		}, err
 	}
-
-	changeRatingInfo := &ChangeRatingInfo{
-		ChangeType: "Delete",
-		OldMark:    rev.Mark,
-		FilmID:     rev.FilmID.ID,
-	}
-	err = rs.putChangeRatingTaskToQueue(changeRatingInfo)
-	if err != nil {
-		logger.Errorf("error in changing rating: %s", err)
-	}
 	return &review.DeletedData{
 		IsDeleted: isDeleted,
 		Review:    rev,
@@ -145,51 +149,195 @@ func (rs *ReviewGRPCServer) UpdateReview(ctx context.Context, in *review.UpdateR
 	if err != nil {
 		return &review.Review{}, errorauth.ErrorNoLogger
 	}
-	rs.mu.RLock()
-	oldReview, err := rs.ReviewRepo.GetUserReviewByID(in.Review.ID.ID, in.UserID.ID)
-	rs.mu.RUnlock()
+	comment := in.GetReview().GetComment()
+	rs.mu.Lock()
+	updatedReview, err := rs.ReviewRepo.UpdateReviewRepo(in.Review, in.UserID.ID, func(oldStatus review.ReviewStatus) review.ReviewStatus {
+		return rs.reviewStatusAfterEdit(comment, oldStatus)
+	})
+	rs.mu.Unlock()
 	if errors.Is(err, errorreview.ErrorNoReview) {
 		logger.Errorf("no review with id %d and userID %d", in.Review.ID.ID, in.UserID.ID)
 		return &review.Review{}, nil
 	}
 	if err != nil {
-		logger.Errorf("error in getting review by id and user id: %s", err)
+		logger.Errorf("error in updating review: %s", err)
 		return &review.Review{}, err
 	}
+	return updatedReview, nil
+}
+
+func (rs *ReviewGRPCServer) GetReviewHistory(ctx context.Context, in *review.ReviewHistoryRequest) (*review.ReviewHistory, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.ReviewHistory{}, errorauth.ErrorNoLogger
+	}
+	rs.mu.RLock()
+	currentReview, err := rs.ReviewRepo.GetReviewByIDRepo(in.GetReviewID().GetID())
+	rs.mu.RUnlock()
+	if errors.Is(err, errorreview.ErrorNoReview) {
+		logger.Errorf("no review with id %d", in.GetReviewID().GetID())
+		return &review.ReviewHistory{}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in getting review by id: %s", err)
+		return &review.ReviewHistory{}, err
+	}
+	if !canSeeReview(ctx, currentReview, 0) {
+		logger.Infof("review %d is %s and is not shown", in.GetReviewID().GetID(), currentReview.GetStatus())
+		return &review.ReviewHistory{}, nil
+	}
+	rs.mu.RLock()
+	revisions, err := rs.ReviewRepo.GetReviewRevisionsRepo(in.GetReviewID().GetID())
+	rs.mu.RUnlock()
+	if err != nil {
+		logger.Errorf("error in getting review revisions: %s", err)
+		return &review.ReviewHistory{}, err
+	}
+	if !in.GetRevealSpoilers() {
+		for _, revision := range revisions {
+			maskRevisionSpoilers(revision, currentReview.GetIsSpoiler())
+		}
+		maskSpoilers(currentReview)
+	}
+	return &review.ReviewHistory{
+		Review:    currentReview,
+		Revisions: revisions,
+	}, nil
+}
+
+func (rs *ReviewGRPCServer) VoteReview(ctx context.Context, in *review.VoteData) (*review.VoteResult, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.VoteResult{}, errorauth.ErrorNoLogger
+	}
+	status, err := rs.checkCanVote(ctx, in.GetReviewID().GetID(), in.GetUserID().GetID())
+	if err != nil {
+		logger.Errorf("error in getting review to vote: %s", err)
+		return &review.VoteResult{}, err
+	}
+	if status != review.VoteStatus_VOTE_OK {
+		logger.Errorf("user %d can not vote for review %d: %s", in.GetUserID().GetID(), in.GetReviewID().GetID(), status)
+		return &review.VoteResult{Status: status}, nil
+	}
 	rs.mu.Lock()
-	updatedReview, err := rs.ReviewRepo.UpdateReviewRepo(in.Review)
+	err = rs.ReviewRepo.VoteReviewRepo(in.GetReviewID().GetID(), in.GetUserID().GetID(), in.GetIsHelpful())
 	rs.mu.Unlock()
+	if errors.Is(err, errorreview.ErrorNoReview) {
+		return &review.VoteResult{Status: review.VoteStatus_VOTE_NO_REVIEW}, nil
+	}
 	if err != nil {
-		logger.Errorf("error in updating review: %s", err)
-		return &review.Review{}, err
+		logger.Errorf("error in voting for review: %s", err)
+		return &review.VoteResult{}, err
 	}
-	changeRatingInfo := &ChangeRatingInfo{
-		ChangeType: "Update",
-		OldMark:    oldReview.Mark,
-		NewMark:    updatedReview.Mark,
-		ReviewID:   in.Review.ID.ID,
+	return rs.getVotedReview(ctx, in.GetReviewID().GetID(), in.GetUserID().GetID())
+}
+
+func (rs *ReviewGRPCServer) DeleteVote(ctx context.Context, in *review.DeleteVoteData) (*review.VoteResult, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.VoteResult{}, errorauth.ErrorNoLogger
 	}
-	err = rs.putChangeRatingTaskToQueue(changeRatingInfo)
+	status, err := rs.checkCanVote(ctx, in.GetReviewID().GetID(), in.GetUserID().GetID())
 	if err != nil {
-		logger.Errorf("error in changing rating: %s", err)
+		logger.Errorf("error in getting review to delete vote: %s", err)
+		return &review.VoteResult{}, err
 	}
-	return updatedReview, nil
+	if status == review.VoteStatus_VOTE_NO_REVIEW {
+		return &review.VoteResult{Status: status}, nil
+	}
+	rs.mu.Lock()
+	isDeleted, err := rs.ReviewRepo.DeleteVoteRepo(in.GetReviewID().GetID(), in.GetUserID().GetID())
+	rs.mu.Unlock()
+	if errors.Is(err, errorreview.ErrorNoReview) {
+		return &review.VoteResult{Status: review.VoteStatus_VOTE_NO_REVIEW}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in deleting vote: %s", err)
+		return &review.VoteResult{}, err
+	}
+	if !isDeleted {
+		return &review.VoteResult{Status: review.VoteStatus_VOTE_NOT_FOUND}, nil
+	}
+	return rs.getVotedReview(ctx, in.GetReviewID().GetID(), in.GetUserID().GetID())
+}
+
+func (rs *ReviewGRPCServer) checkCanVote(ctx context.Context, reviewID, userID uint64) (review.VoteStatus, error) {
+	rs.mu.RLock()
+	votedReview, err := rs.ReviewRepo.GetReviewByIDRepo(reviewID)
+	rs.mu.RUnlock()
+	if errors.Is(err, errorreview.ErrorNoReview) {
+		return review.VoteStatus_VOTE_NO_REVIEW, nil
+	}
+	if err != nil {
+		return review.VoteStatus_VOTE_OK, err
+	}
+	if !canSeeReview(ctx, votedReview, userID) {
+		return review.VoteStatus_VOTE_NO_REVIEW, nil
+	}
+	if votedReview.GetAuthor().GetID().GetID() == userID {
+		return review.VoteStatus_VOTE_OWN_REVIEW, nil
+	}
+	return review.VoteStatus_VOTE_OK, nil
 }
 
-func (rs *ReviewGRPCServer) putChangeRatingTaskToQueue(chInfo *ChangeRatingInfo) error {
-	data, err := json.Marshal(chInfo)
-	if err != nil {
-		return err
-	}
-	err = rs.rabbitChan.Publish(
-		"",
-		ChangeRatingQueueName,
-		false,
-		false,
-		amqp.Publishing{
-			DeliveryMode: amqp.Persistent,
-			ContentType:  "text/plain",
-			Body:         data,
-		})
-	return err
+func (rs *ReviewGRPCServer) getVotedReview(ctx context.Context, reviewID, userID uint64) (*review.VoteResult, error) {
+	rs.mu.RLock()
+	votedReview, err := rs.ReviewRepo.GetReviewByIDRepo(reviewID)
+	rs.mu.RUnlock()
+	if errors.Is(err, errorreview.ErrorNoReview) || (err == nil && !canSeeReview(ctx, votedReview, userID)) {
+		return &review.VoteResult{Status: review.VoteStatus_VOTE_NO_REVIEW}, nil
+	}
+	if err != nil {
+		return &review.VoteResult{}, err
+	}
+	return &review.VoteResult{
+		Status: review.VoteStatus_VOTE_OK,
+		Review: votedReview,
+	}, nil
+}
+
+// canSeeReview reports if the review is shown to the user: the reviews which are not published are seen
+// only by their authors and the moderators. The user is the one from the request or, if it is 0,
+// the one of the access token passed by the gateway.
+func canSeeReview(ctx context.Context, shownReview *review.Review, userID uint64) bool {
+	if shownReview.GetStatus() == review.ReviewStatus_REVIEW_PUBLISHED {
+		return true
+	}
+	tokenUser := authpermission.UserFromContext(ctx)
+	if tokenUser != nil {
+		if authpermission.HasPermission(tokenUser.GetPermissions(), authpermission.ModerateReviews) {
+			return true
+		}
+		if userID == 0 {
+			userID = tokenUser.GetID()
+		}
+	}
+	return userID != 0 && userID == shownReview.GetAuthor().GetID().GetID()
+}
+
+// reviewStatusAfterEdit holds reviews with suspicious text for moderation.
+// Rejected and hidden reviews go back to the moderation queue after any edit.
+func (rs *ReviewGRPCServer) reviewStatusAfterEdit(comment string, oldStatus review.ReviewStatus) review.ReviewStatus {
+	if rs.profanityFilter != nil && rs.profanityFilter.IsSuspicious(comment) {
+		return review.ReviewStatus_REVIEW_PENDING
+	}
+	if oldStatus == review.ReviewStatus_REVIEW_REJECTED || oldStatus == review.ReviewStatus_REVIEW_HIDDEN {
+		return review.ReviewStatus_REVIEW_PENDING
+	}
+	return review.ReviewStatus_REVIEW_PUBLISHED
+}
+
+func encodePageToken(offset uint64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(offset, 10)))
+}
+
+func decodePageToken(token string) (uint64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	offset, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(string(offset), 10, 64)
 }
@@ -0,0 +1,300 @@
+package reviewserviceusecse_test
+
+import (
+	"context"
+	"github.com/dgrijalva/jwt-go"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	authpermission "kinopoisk/service_auth/permission"
+	auth "kinopoisk/service_auth/proto"
+	errorreview "kinopoisk/service_review/error"
+	reviewfilter "kinopoisk/service_review/filter"
+	"kinopoisk/service_review/interceptor"
+	review "kinopoisk/service_review/proto"
+	reviewservicerepo "kinopoisk/service_review/repo/mysql"
+	reviewserviceusecase "kinopoisk/service_review/usecase"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("test secret")
+
+// fakeReviewRepo keeps the reviews in memory, the methods the tests do not need panic on the nil interface.
+type fakeReviewRepo struct {
+	reviewservicerepo.ReviewRepo
+	reviews   map[uint64]*review.Review
+	revisions map[uint64][]*review.ReviewRevision
+	comments  []*review.Comment
+	profiles  map[uint64]*review.UserProfile
+	// beforeCommentChange runs before the comment is changed, after the usecase has checked it
+	beforeCommentChange func()
+}
+
+func (f *fakeReviewRepo) GetReviewByIDRepo(reviewID uint64) (*review.Review, error) {
+	foundReview, ok := f.reviews[reviewID]
+	if !ok {
+		return nil, errorreview.ErrorNoReview
+	}
+	return &review.Review{
+		ID:        foundReview.ID,
+		Mark:      foundReview.Mark,
+		Comment:   foundReview.Comment,
+		Author:    foundReview.Author,
+		Status:    foundReview.Status,
+		IsSpoiler: foundReview.IsSpoiler,
+	}, nil
+}
+
+func (f *fakeReviewRepo) GetReviewRevisionsRepo(reviewID uint64) ([]*review.ReviewRevision, error) {
+	revisions := make([]*review.ReviewRevision, 0, len(f.revisions[reviewID]))
+	for _, revision := range f.revisions[reviewID] {
+		revisions = append(revisions, &review.ReviewRevision{
+			Mark:    revision.Mark,
+			Comment: revision.Comment,
+		})
+	}
+	return revisions, nil
+}
+
+// UpdateReviewRepo decides the status from the stored review, the way the repo does it from the locked row.
+func (f *fakeReviewRepo) UpdateReviewRepo(reviewToUpdate *review.Review, userID uint64,
+	statusAfterEdit func(oldStatus review.ReviewStatus) review.ReviewStatus) (*review.Review, error) {
+	storedReview, ok := f.reviews[reviewToUpdate.GetID().GetID()]
+	if !ok || storedReview.GetAuthor().GetID().GetID() != userID {
+		return nil, errorreview.ErrorNoReview
+	}
+	storedReview.Mark = reviewToUpdate.GetMark()
+	storedReview.Comment = reviewToUpdate.GetComment()
+	storedReview.Status = statusAfterEdit(storedReview.GetStatus())
+	return f.GetReviewByIDRepo(storedReview.GetID().GetID())
+}
+
+func (f *fakeReviewRepo) VoteReviewRepo(reviewID, userID uint64, isHelpful bool) error {
+	return nil
+}
+
+func newFakeReviewRepo() *fakeReviewRepo {
+	return &fakeReviewRepo{
+		reviews: map[uint64]*review.Review{
+			1: {
+				ID:      &review.ReviewID{ID: 1},
+				Mark:    3,
+				Comment: "rude words",
+				Author:  &review.User{ID: &review.UserID{ID: 10}, Username: "author"},
+				Status:  review.ReviewStatus_REVIEW_HIDDEN,
+			},
+			2: {
+				ID:      &review.ReviewID{ID: 2},
+				Mark:    9,
+				Comment: "great film",
+				Author:  &review.User{ID: &review.UserID{ID: 10}, Username: "author"},
+				Status:  review.ReviewStatus_REVIEW_PUBLISHED,
+			},
+		},
+		revisions: map[uint64][]*review.ReviewRevision{
+			1: {{Mark: 2, Comment: "even ruder words"}},
+			2: {{Mark: 8, Comment: "good film"}},
+		},
+	}
+}
+
+// callAs calls the method through the permission interceptor the way the gateway does it, with the access
+// token of the user, or without a token for nil.
+func callAs(t *testing.T, user *auth.User, method string, call func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ctx := context.WithValue(context.Background(), interceptor.MyLoggerKey, zap.NewNop().Sugar())
+	if user != nil {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"user": user,
+			"exp":  time.Now().Add(time.Hour).Unix(),
+		}).SignedString(testSecret)
+		if err != nil {
+			t.Fatalf("can not sign token: %s", err)
+		}
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+	}
+	unaryInterceptor := authpermission.UnaryInterceptor(testSecret, map[string]string{})
+	return unaryInterceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(ctx)
+		})
+}
+
+func TestGetReviewHistoryOfHiddenReview(t *testing.T) {
+	rs := reviewserviceusecase.NewReviewGRPCServer(newFakeReviewRepo(), reviewserviceusecase.DefaultMaxCommentDepth, nil)
+	moderator := &auth.User{ID: 20, Permissions: []string{authpermission.ModerateReviews}}
+
+	cases := []struct {
+		name          string
+		user          *auth.User
+		reviewID      uint64
+		expectedShown bool
+	}{
+		{name: "anonymous user, hidden review", user: nil, reviewID: 1, expectedShown: false},
+		{name: "other user, hidden review", user: &auth.User{ID: 30}, reviewID: 1, expectedShown: false},
+		{name: "author, hidden review", user: &auth.User{ID: 10}, reviewID: 1, expectedShown: true},
+		{name: "moderator, hidden review", user: moderator, reviewID: 1, expectedShown: true},
+		{name: "anonymous user, published review", user: nil, reviewID: 2, expectedShown: true},
+	}
+	for _, testCase := range cases {
+		reply, err := callAs(t, testCase.user, "/review.ReviewMaker/GetReviewHistory", func(ctx context.Context) (interface{}, error) {
+			return rs.GetReviewHistory(ctx, &review.ReviewHistoryRequest{ReviewID: &review.ReviewID{ID: testCase.reviewID}})
+		})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		history := reply.(*review.ReviewHistory)
+		if shown := history.GetReview() != nil; shown != testCase.expectedShown {
+			t.Errorf("%s: expected shown %t, got %t", testCase.name, testCase.expectedShown, shown)
+			continue
+		}
+		if !testCase.expectedShown && len(history.GetRevisions()) != 0 {
+			t.Errorf("%s: revisions of the hidden review are returned", testCase.name)
+		}
+	}
+}
+
+func TestUpdateReviewStatus(t *testing.T) {
+	filter, err := reviewfilter.NewDefaultProfanityFilter()
+	if err != nil {
+		t.Fatalf("can not load default list: %s", err)
+	}
+
+	cases := []struct {
+		name           string
+		reviewID       uint64
+		userID         uint64
+		comment        string
+		expectedStatus review.ReviewStatus
+		expectedEmpty  bool
+	}{
+		{name: "clean edit of published review", reviewID: 2, userID: 10, comment: "still great",
+			expectedStatus: review.ReviewStatus_REVIEW_PUBLISHED},
+		{name: "rude edit of published review", reviewID: 2, userID: 10, comment: "SHIT film",
+			expectedStatus: review.ReviewStatus_REVIEW_PENDING},
+		{name: "clean edit of hidden review", reviewID: 1, userID: 10, comment: "polite words",
+			expectedStatus: review.ReviewStatus_REVIEW_PENDING},
+		{name: "review of another user", reviewID: 2, userID: 30, comment: "still great", expectedEmpty: true},
+	}
+	for _, testCase := range cases {
+		reviewRepo := newFakeReviewRepo()
+		rs := reviewserviceusecase.NewReviewGRPCServer(reviewRepo, reviewserviceusecase.DefaultMaxCommentDepth, filter)
+		reply, err := callAs(t, &auth.User{ID: testCase.userID}, "/review.ReviewMaker/UpdateReview", func(ctx context.Context) (interface{}, error) {
+			return rs.UpdateReview(ctx, &review.UpdateReviewData{
+				Review: &review.Review{ID: &review.ReviewID{ID: testCase.reviewID}, Mark: 7, Comment: testCase.comment},
+				UserID: &review.UserID{ID: testCase.userID},
+			})
+		})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		updatedReview := reply.(*review.Review)
+		if testCase.expectedEmpty {
+			if updatedReview.GetID() != nil || reviewRepo.reviews[testCase.reviewID].GetComment() == testCase.comment {
+				t.Errorf("%s: review of another user is changed", testCase.name)
+			}
+			continue
+		}
+		if updatedReview.GetStatus() != testCase.expectedStatus {
+			t.Errorf("%s: expected status %s, got %s", testCase.name, testCase.expectedStatus, updatedReview.GetStatus())
+		}
+	}
+}
+
+func TestGetReviewHistorySpoilers(t *testing.T) {
+	reviewRepo := newFakeReviewRepo()
+	reviewRepo.reviews[3] = &review.Review{
+		ID:      &review.ReviewID{ID: 3},
+		Mark:    8,
+		Comment: "the end is ||he dies||",
+		Author:  &review.User{ID: &review.UserID{ID: 10}, Username: "author"},
+		Status:  review.ReviewStatus_REVIEW_PUBLISHED,
+	}
+	reviewRepo.revisions[3] = []*review.ReviewRevision{{Mark: 7, Comment: "the end is ||she lives||"}}
+	reviewRepo.reviews[4] = &review.Review{
+		ID:        &review.ReviewID{ID: 4},
+		Mark:      8,
+		Comment:   "he dies",
+		Author:    &review.User{ID: &review.UserID{ID: 10}, Username: "author"},
+		Status:    review.ReviewStatus_REVIEW_PUBLISHED,
+		IsSpoiler: true,
+	}
+	reviewRepo.revisions[4] = []*review.ReviewRevision{{Mark: 7, Comment: "she lives"}}
+	rs := reviewserviceusecase.NewReviewGRPCServer(reviewRepo, reviewserviceusecase.DefaultMaxCommentDepth, nil)
+
+	cases := []struct {
+		name             string
+		reviewID         uint64
+		revealSpoilers   bool
+		expectedComment  string
+		expectedRevision string
+	}{
+		{name: "inline spoilers", reviewID: 3, expectedComment: "the end is [spoiler]", expectedRevision: "the end is [spoiler]"},
+		{name: "flagged review", reviewID: 4, expectedComment: "[spoiler]", expectedRevision: "[spoiler]"},
+		{name: "revealed spoilers", reviewID: 4, revealSpoilers: true, expectedComment: "he dies", expectedRevision: "she lives"},
+	}
+	for _, testCase := range cases {
+		reply, err := callAs(t, nil, "/review.ReviewMaker/GetReviewHistory", func(ctx context.Context) (interface{}, error) {
+			return rs.GetReviewHistory(ctx, &review.ReviewHistoryRequest{
+				ReviewID:       &review.ReviewID{ID: testCase.reviewID},
+				RevealSpoilers: testCase.revealSpoilers,
+			})
+		})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", testCase.name, err)
+			continue
+		}
+		history := reply.(*review.ReviewHistory)
+		if history.GetReview().GetComment() != testCase.expectedComment {
+			t.Errorf("%s: expected comment %q, got %q", testCase.name, testCase.expectedComment, history.GetReview().GetComment())
+		}
+		if len(history.GetRevisions()) != 1 || history.GetRevisions()[0].GetComment() != testCase.expectedRevision {
+			t.Errorf("%s: expected revision %q, got %v", testCase.name, testCase.expectedRevision, history.GetRevisions())
+		}
+	}
+}
+
+func TestCommentsAndVotesOfHiddenReview(t *testing.T) {
+	rs := reviewserviceusecase.NewReviewGRPCServer(newFakeReviewRepo(), reviewserviceusecase.DefaultMaxCommentDepth, nil)
+
+	reply, err := callAs(t, nil, "/review.ReviewMaker/GetReviewComments", func(ctx context.Context) (interface{}, error) {
+		return rs.GetReviewComments(ctx, &review.ReviewCommentsRequest{ReviewID: &review.ReviewID{ID: 1}})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if status := reply.(*review.Comments).GetStatus(); status != review.CommentStatus_COMMENT_NO_REVIEW {
+		t.Errorf("expected comments status %s, got %s", review.CommentStatus_COMMENT_NO_REVIEW, status)
+	}
+
+	reply, err = callAs(t, nil, "/review.ReviewMaker/VoteReview", func(ctx context.Context) (interface{}, error) {
+		return rs.VoteReview(ctx, &review.VoteData{
+			ReviewID:  &review.ReviewID{ID: 1},
+			UserID:    &review.UserID{ID: 30},
+			IsHelpful: true,
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	voteResult := reply.(*review.VoteResult)
+	if voteResult.GetStatus() != review.VoteStatus_VOTE_NO_REVIEW || voteResult.GetReview() != nil {
+		t.Errorf("expected vote status %s without review, got %s", review.VoteStatus_VOTE_NO_REVIEW, voteResult.GetStatus())
+	}
+
+	reply, err = callAs(t, nil, "/review.ReviewMaker/VoteReview", func(ctx context.Context) (interface{}, error) {
+		return rs.VoteReview(ctx, &review.VoteData{
+			ReviewID:  &review.ReviewID{ID: 2},
+			UserID:    &review.UserID{ID: 30},
+			IsHelpful: true,
+		})
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if status := reply.(*review.VoteResult).GetStatus(); status != review.VoteStatus_VOTE_OK {
+		t.Errorf("expected vote status %s, got %s", review.VoteStatus_VOTE_OK, status)
+	}
+}
@@ -0,0 +1,69 @@
+package reviewserviceusecse
+
+import (
+	"context"
+	"errors"
+	errorauth "kinopoisk/service_auth/error"
+	errorreview "kinopoisk/service_review/error"
+	"kinopoisk/service_review/interceptor"
+	review "kinopoisk/service_review/proto"
+	"regexp"
+)
+
+const spoilerMask = "[spoiler]"
+
+// spoilerSpan matches inline spoilers written as ||text||, they may span several lines.
+var spoilerSpan = regexp.MustCompile(`(?s)\|\|(.+?)\|\|`)
+
+// maskSpoilers hides the whole comment of a review flagged as spoiler or only its inline spoilers otherwise.
+func maskSpoilers(maskedReview *review.Review) {
+	maskedReview.Comment, maskedReview.SpoilersMasked = maskComment(maskedReview.GetComment(), maskedReview.GetIsSpoiler())
+}
+
+// maskRevisionSpoilers hides the old comments of the review the same way as its current comment.
+func maskRevisionSpoilers(revision *review.ReviewRevision, isSpoiler bool) {
+	revision.Comment, _ = maskComment(revision.GetComment(), isSpoiler)
+}
+
+func maskComment(comment string, isSpoiler bool) (string, bool) {
+	if comment == "" {
+		return comment, false
+	}
+	if isSpoiler {
+		return spoilerMask, true
+	}
+	if spoilerSpan.MatchString(comment) {
+		return spoilerSpan.ReplaceAllLiteralString(comment, spoilerMask), true
+	}
+	return comment, false
+}
+
+func (rs *ReviewGRPCServer) SetSpoilerFlag(ctx context.Context, in *review.SpoilerFlagData) (*review.ModerationResult, error) {
+	logger, err := interceptor.GetLoggerFromContext(ctx)
+	if err != nil {
+		return &review.ModerationResult{}, errorauth.ErrorNoLogger
+	}
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	flaggedReview, err := rs.ReviewRepo.GetReviewByIDRepo(in.GetReviewID().GetID())
+	if errors.Is(err, errorreview.ErrorNoReview) {
+		logger.Errorf("no review with id %d", in.GetReviewID().GetID())
+		return &review.ModerationResult{Status: review.ModerationStatus_MODERATION_NO_REVIEW}, nil
+	}
+	if err != nil {
+		logger.Errorf("error in getting review by id: %s", err)
+		return &review.ModerationResult{}, err
+	}
+	if flaggedReview.GetIsSpoiler() != in.GetIsSpoiler() {
+		err = rs.ReviewRepo.SetSpoilerFlagRepo(flaggedReview, in.GetModeratorID().GetID(), in.GetIsSpoiler())
+		if err != nil {
+			logger.Errorf("error in setting spoiler flag: %s", err)
+			return &review.ModerationResult{}, err
+		}
+		flaggedReview.IsSpoiler = in.GetIsSpoiler()
+	}
+	return &review.ModerationResult{
+		Status: review.ModerationStatus_MODERATION_OK,
+		Review: flaggedReview,
+	}, nil
+}